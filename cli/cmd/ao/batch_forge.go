@@ -133,7 +133,10 @@ func runForgeBatch(cmd *cobra.Command, args []string) error {
 
 	p := parser.NewParser()
 	p.MaxContentLength = 0
-	extractor := parser.NewExtractor()
+	extractor, err := loadForgeExtractor(cwd)
+	if err != nil {
+		return err
+	}
 
 	var acc batchForgeAccumulator
 	for i, t := range unforged {
@@ -74,6 +74,10 @@ func executeCommand(args ...string) (string, error) {
 	origFindingsPullAll := findingsPullAll
 	origFindingsPullForce := findingsPullForce
 	origFindingsRetireBy := findingsRetireBy
+	origRatchetEpicID := ratchetEpicID
+	origRatchetChainID := ratchetChainID
+	origRatchetCycle := ratchetCycle
+	origRatchetTimelineFormat := ratchetTimelineFormat
 	defer func() {
 		dryRun = origDryRun
 		verbose = origVerbose
@@ -118,6 +122,10 @@ func executeCommand(args ...string) (string, error) {
 		findingsPullAll = origFindingsPullAll
 		findingsPullForce = origFindingsPullForce
 		findingsRetireBy = origFindingsRetireBy
+		ratchetEpicID = origRatchetEpicID
+		ratchetChainID = origRatchetChainID
+		ratchetCycle = origRatchetCycle
+		ratchetTimelineFormat = origRatchetTimelineFormat
 	}()
 
 	// Reset all command-local flags to defaults before execution.
@@ -161,6 +169,10 @@ func executeCommand(args ...string) (string, error) {
 	findingsPullAll = false
 	findingsPullForce = false
 	findingsRetireBy = ""
+	ratchetEpicID = ""
+	ratchetChainID = ""
+	ratchetCycle = 0
+	ratchetTimelineFormat = ratchetTimelineFormatText
 
 	// Reset Cobra flag Changed state on all commands recursively.
 	resetFlagChangesRecursive(rootCmd)
@@ -233,12 +245,12 @@ func TestCobraCommandTreeRegistration(t *testing.T) {
 		"anti-patterns", "autodev", "badge", "batch-feedback", "completion", "config",
 		"constraint", "context", "codex", "contradict", "curate", "dedup",
 		"defrag", "demo", "doctor", "extract", "feedback", "feedback-loop",
-		"findings", "flywheel", "forge", "gate", "goals", "handoff", "hooks",
+		"findings", "flywheel", "forge", "gate", "goals", "graph", "handoff", "hooks",
 		"index", "init", "inject", "lookup", "maturity",
-		"memory", "metrics", "migrate", "mind", "mine", "notebook", "plans",
+		"memory", "memrl", "metrics", "migrate", "mind", "mine", "notebook", "plans",
 		"pool", "quick-start", "ratchet", "rpi",
 		"search", "seed", "session", "session-outcome", "status",
-		"store", "task-feedback", "task-status", "task-sync", "temper",
+		"store", "task-feedback", "task-status", "task-sync", "taxonomy", "temper",
 		"trace", "version", "vibe-check", "worktree",
 	}
 	cmdSet := make(map[string]bool)
@@ -255,12 +267,17 @@ func TestCobraCommandTreeRegistration(t *testing.T) {
 	parentExpectations := map[string][]string{
 		"autodev":    {"init", "validate", "show"},
 		"goals":      {"validate", "measure", "drift"},
-		"ratchet":    {"status", "check", "next"},
+		"maturity":   {"history"},
+		"graph":      {"query"},
+		"memrl":      {"policy", "replay"},
+		"ratchet":    {"status", "check", "next", "timeline"},
 		"metrics":    {"baseline", "report"},
 		"flywheel":   {"status", "nudge"},
+		"gate":       {"pending", "review"},
 		"constraint": {"activate", "retire", "review", "list"},
-		"pool":       {"list", "ingest"},
+		"pool":       {"list", "ingest", "verify", "migrate-chain"},
 		"store":      {"rebuild", "search"},
+		"taxonomy":   {"show"},
 	}
 	for parent, expectedSubs := range parentExpectations {
 		for _, sub := range expectedSubs {
@@ -286,12 +303,12 @@ func TestCobraExpectedCmdsMatchRegistration(t *testing.T) {
 		"anti-patterns", "autodev", "badge", "batch-feedback", "completion", "config",
 		"constraint", "context", "codex", "contradict", "curate", "dedup",
 		"defrag", "demo", "doctor", "extract", "feedback", "feedback-loop",
-		"findings", "flywheel", "forge", "gate", "goals", "handoff", "hooks",
+		"findings", "flywheel", "forge", "gate", "goals", "graph", "handoff", "hooks",
 		"index", "init", "inject", "lookup", "maturity",
-		"memory", "metrics", "migrate", "mind", "mine", "notebook", "plans",
+		"memory", "memrl", "metrics", "migrate", "mind", "mine", "notebook", "plans",
 		"pool", "quick-start", "ratchet", "rpi",
 		"search", "seed", "session", "session-outcome", "status",
-		"store", "task-feedback", "task-status", "task-sync", "temper",
+		"store", "task-feedback", "task-status", "task-sync", "taxonomy", "temper",
 		"trace", "version", "vibe-check", "worktree",
 	}
 
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/spf13/cobra"
@@ -13,6 +14,7 @@ import (
 
 // ContradictionPair represents two learnings that may contradict each other.
 type ContradictionPair struct {
+	ID         string  `json:"id"`
 	FileA      string  `json:"file_a"`
 	FileB      string  `json:"file_b"`
 	Similarity float64 `json:"similarity"`
@@ -27,6 +29,7 @@ type ContradictResult struct {
 	PairsChecked   int                 `json:"pairs_checked"`
 	Contradictions int                 `json:"contradictions"`
 	Pairs          []ContradictionPair `json:"pairs,omitempty"`
+	Recorded       int                 `json:"recorded"`
 }
 
 var contradictCmd = &cobra.Command{
@@ -41,9 +44,14 @@ Pairs with high topic overlap but opposing sentiment indicators (e.g., "always"
 
 This is a heuristic tool — false positives are expected. Use it as a review aid.
 
+Each pair gets a stable ID and is recorded in .agents/contradictions.jsonl.
+Until a pair is resolved with ao contradict resolve, ao inject never serves
+both of its learnings in the same packet.
+
 Examples:
   ao contradict
-  ao contradict --json`,
+  ao contradict --json
+  ao contradict resolve <pair-id> --keep A --note "B is outdated"`,
 	RunE: runContradict,
 }
 
@@ -169,6 +177,7 @@ func runContradict(_ *cobra.Command, _ []string) error {
 
 			result.Contradictions++
 			result.Pairs = append(result.Pairs, ContradictionPair{
+				ID:         contradictionPairID(relA, relB),
 				FileA:      relA,
 				FileB:      relB,
 				Similarity: sim,
@@ -179,6 +188,17 @@ func runContradict(_ *cobra.Command, _ []string) error {
 		}
 	}
 
+	if !GetDryRun() && result.Contradictions > 0 {
+		records, err := loadContradictions(cwd)
+		if err != nil {
+			return err
+		}
+		records, result.Recorded = mergeContradictions(records, result.Pairs, time.Now().UTC())
+		if err := saveContradictions(cwd, records); err != nil {
+			return err
+		}
+	}
+
 	// Output
 	if GetOutput() == "json" {
 		enc := json.NewEncoder(os.Stdout)
@@ -195,12 +215,16 @@ func runContradict(_ *cobra.Command, _ []string) error {
 	if result.Contradictions > 0 {
 		fmt.Println("\nPotential Contradictions:")
 		for i, p := range result.Pairs {
-			fmt.Printf("\n  %d. Similarity: %.1f%% — %s\n", i+1, p.Similarity*100, p.Reason)
+			fmt.Printf("\n  %d. [%s] Similarity: %.1f%% — %s\n", i+1, p.ID, p.Similarity*100, p.Reason)
 			fmt.Printf("     A: %s\n", p.FileA)
 			fmt.Printf("        %s\n", p.SnippetA)
 			fmt.Printf("     B: %s\n", p.FileB)
 			fmt.Printf("        %s\n", p.SnippetB)
 		}
+		if result.Recorded > 0 {
+			fmt.Printf("\nRecorded %d new contradiction(s) in .agents/%s\n", result.Recorded, contradictionStoreFile)
+		}
+		fmt.Println("Resolve with: ao contradict resolve <pair-id> --keep A|B|both --note ...")
 	} else {
 		fmt.Println("\nNo potential contradictions found.")
 	}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Contradiction statuses.
+const (
+	contradictionUnresolved = "unresolved"
+	contradictionResolved   = "resolved"
+)
+
+// contradictionStoreFile is the persisted contradiction log under .agents/.
+const contradictionStoreFile = "contradictions.jsonl"
+
+// contradictionEventsFile is the append-only log of resolutions under
+// .agents/ao/. It is kept apart from the ratchet chain, whose entries are
+// RPI workflow steps that ratchet next and status walk.
+const contradictionEventsFile = "contradiction-resolutions.jsonl"
+
+// contradictionEvent is one resolution appended to the events log.
+type contradictionEvent struct {
+	PairID     string    `json:"pair_id"`
+	Keep       string    `json:"keep"`
+	Kept       []string  `json:"kept"`
+	Superseded string    `json:"superseded,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+var (
+	contradictKeep string
+	contradictNote string
+)
+
+// contradictionRecord is one persisted contradiction and its resolution.
+type contradictionRecord struct {
+	ContradictionPair
+	Status     string    `json:"status"`
+	DetectedAt time.Time `json:"detected_at"`
+	ResolvedAt time.Time `json:"resolved_at,omitzero"`
+	Keep       string    `json:"keep,omitempty"` // A, B or both
+	Note       string    `json:"note,omitempty"`
+}
+
+var contradictResolveCmd = &cobra.Command{
+	Use:   "resolve <pair-id>",
+	Short: "Resolve a recorded contradiction",
+	Long: `Resolve a contradiction recorded by ao contradict.
+
+--keep A or --keep B marks the other learning as superseded (superseded_by in
+its frontmatter, or on the first record of a .jsonl file), so inject and
+search stop serving it. --keep both records that the two learnings apply in
+different contexts and leaves both files untouched.
+
+Every resolution is appended to .agents/ao/contradiction-resolutions.jsonl.
+
+Examples:
+  ao contradict resolve pair-3f2a9c1d0b --keep A --note "B predates the v2 API"
+  ao contradict resolve pair-3f2a9c1d0b --keep both --note "A is for CI, B for local runs"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContradictResolve,
+}
+
+func init() {
+	contradictCmd.AddCommand(contradictResolveCmd)
+	contradictResolveCmd.Flags().StringVar(&contradictKeep, "keep", "", "Which side to keep: A, B or both (required)")
+	contradictResolveCmd.Flags().StringVar(&contradictNote, "note", "", "Why the contradiction was resolved this way")
+	_ = contradictResolveCmd.MarkFlagRequired("keep")
+}
+
+// contradictionPairID derives a stable ID from the two file paths, so the
+// same pair keeps its ID across scans regardless of order.
+func contradictionPairID(fileA, fileB string) string {
+	a, b := filepath.ToSlash(fileA), filepath.ToSlash(fileB)
+	if b < a {
+		a, b = b, a
+	}
+	h := sha256.Sum256([]byte(a + "\x00" + b))
+	return "pair-" + hex.EncodeToString(h[:])[:10]
+}
+
+func contradictionStorePath(cwd string) string {
+	return filepath.Join(cwd, ".agents", contradictionStoreFile)
+}
+
+// loadContradictions reads the contradiction log. A missing file is empty.
+func loadContradictions(cwd string) ([]contradictionRecord, error) {
+	f, err := os.Open(contradictionStorePath(cwd))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open contradictions: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // read-only file
+
+	var records []contradictionRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var r contradictionRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			VerbosePrintf("Skipping malformed contradiction record: %v\n", err)
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// saveContradictions rewrites the contradiction log atomically.
+func saveContradictions(cwd string, records []contradictionRecord) error {
+	var b strings.Builder
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("marshal contradiction %s: %w", r.ID, err)
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	path := contradictionStorePath(cwd)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create .agents: %w", err)
+	}
+	return atomicWriteFile(path, []byte(b.String()), 0o600)
+}
+
+// mergeContradictions adds newly detected pairs to the log as unresolved and
+// refreshes the scan details of pairs already recorded. Resolutions are kept.
+func mergeContradictions(records []contradictionRecord, pairs []ContradictionPair, now time.Time) ([]contradictionRecord, int) {
+	byID := make(map[string]int, len(records))
+	for i, r := range records {
+		byID[r.ID] = i
+	}
+	added := 0
+	for _, p := range pairs {
+		if i, ok := byID[p.ID]; ok {
+			records[i].ContradictionPair = p
+			continue
+		}
+		byID[p.ID] = len(records)
+		records = append(records, contradictionRecord{ContradictionPair: p, Status: contradictionUnresolved, DetectedAt: now})
+		added++
+	}
+	return records, added
+}
+
+func runContradictResolve(_ *cobra.Command, args []string) error {
+	keep := strings.ToUpper(contradictKeep)
+	if keep == "BOTH" {
+		keep = "both"
+	}
+	if keep != "A" && keep != "B" && keep != "both" {
+		return fmt.Errorf("--keep must be A, B or both, got %q", contradictKeep)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	records, err := loadContradictions(cwd)
+	if err != nil {
+		return err
+	}
+	idx := -1
+	for i, r := range records {
+		if r.ID == args[0] {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("contradiction not found: %s (run ao contradict to record pairs)", args[0])
+	}
+	rec := &records[idx]
+	if rec.Status == contradictionResolved {
+		return fmt.Errorf("contradiction %s already resolved (keep %s)", rec.ID, rec.Keep)
+	}
+
+	winner, loser := rec.FileA, rec.FileB
+	if keep == "B" {
+		winner, loser = rec.FileB, rec.FileA
+	}
+
+	if GetDryRun() {
+		if keep == "both" {
+			fmt.Printf("[dry-run] Would resolve %s keeping both learnings\n", rec.ID)
+		} else {
+			fmt.Printf("[dry-run] Would mark %s superseded by %s\n", loser, winner)
+		}
+		return nil
+	}
+
+	if keep != "both" {
+		if err := markSuperseded(filepath.Join(cwd, loser), learningStem(winner)); err != nil {
+			return fmt.Errorf("mark %s superseded: %w", loser, err)
+		}
+	}
+
+	rec.Status = contradictionResolved
+	rec.ResolvedAt = time.Now().UTC()
+	rec.Keep = keep
+	rec.Note = contradictNote
+	if err := saveContradictions(cwd, records); err != nil {
+		return err
+	}
+	if err := recordContradictionResolution(cwd, *rec, winner, loser); err != nil {
+		return err
+	}
+
+	if keep == "both" {
+		fmt.Printf("Resolved %s: kept both learnings\n", rec.ID)
+	} else {
+		fmt.Printf("Resolved %s: kept %s, superseded %s\n", rec.ID, winner, loser)
+	}
+	return nil
+}
+
+// recordContradictionResolution appends the resolution to the events log.
+func recordContradictionResolution(cwd string, rec contradictionRecord, winner, loser string) error {
+	event := contradictionEvent{
+		PairID:     rec.ID,
+		Keep:       rec.Keep,
+		Kept:       []string{winner},
+		Superseded: loser,
+		Note:       rec.Note,
+		ResolvedAt: rec.ResolvedAt,
+	}
+	if rec.Keep == "both" {
+		event.Kept = []string{rec.FileA, rec.FileB}
+		event.Superseded = ""
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal resolution: %w", err)
+	}
+
+	path := filepath.Join(cwd, ".agents", "ao", contradictionEventsFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create .agents/ao: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open resolution log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close() //nolint:errcheck // write already failed
+		return fmt.Errorf("record resolution: %w", err)
+	}
+	return f.Close()
+}
+
+// markSuperseded sets superseded_by on a learning file.
+func markSuperseded(path, supersededBy string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var updated string
+	if strings.HasSuffix(path, ".jsonl") {
+		first, rest, hasRest := strings.Cut(string(content), "\n")
+		var data map[string]any
+		if err := json.Unmarshal([]byte(first), &data); err != nil {
+			return fmt.Errorf("parse first record: %w", err)
+		}
+		data["superseded_by"] = supersededBy
+		line, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		updated = string(line)
+		if hasRest {
+			updated += "\n" + rest
+		}
+	} else {
+		updated = setFrontmatterField(string(content), "superseded_by", supersededBy)
+	}
+	return atomicWriteFile(path, []byte(updated), info.Mode().Perm())
+}
+
+// setFrontmatterField sets a scalar YAML frontmatter field, replacing an
+// existing value or adding a frontmatter block when there is none.
+func setFrontmatterField(text, key, value string) string {
+	field := key + ": " + value
+	lines := strings.Split(text, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "---\n" + field + "\n---\n" + text
+	}
+	for i := 1; i < len(lines); i++ {
+		switch {
+		case strings.TrimSpace(lines[i]) == "---":
+			return strings.Join(append(lines[:i:i], append([]string{field}, lines[i:]...)...), "\n")
+		case strings.HasPrefix(lines[i], key+":"):
+			lines[i] = field
+			return strings.Join(lines, "\n")
+		}
+	}
+	return "---\n" + field + "\n---\n" + text
+}
+
+// learningStem returns the file name without extension, the ID used for
+// superseded_by references.
+func learningStem(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeContradictionFixture writes two learnings that ao contradict flags
+// and returns their cwd-relative paths.
+func writeContradictionFixture(t *testing.T, root string) (string, string) {
+	t.Helper()
+	dir := filepath.Join(root, ".agents", "learnings")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"learn-mutex-yes.md": "---\ntitle: Mutex Usage\n---\n# Always Use Mutex for Shared State\n\nAlways use mutex locks when accessing shared state in concurrent goroutines. Mutex ensures data consistency and prevents race conditions in concurrent code.",
+		"learn-mutex-no.md":  "---\ntitle: Mutex Avoidance\n---\n# Never Use Mutex for Shared State\n\nNever use mutex locks when accessing shared state in concurrent goroutines. Channels are the idiomatic approach and avoid deadlock risks in concurrent code.",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(".agents", "learnings", "learn-mutex-no.md"), filepath.Join(".agents", "learnings", "learn-mutex-yes.md")
+}
+
+// scanContradictions runs ao contradict and returns the recorded log.
+func scanContradictions(t *testing.T, cwd string) []contradictionRecord {
+	t.Helper()
+	if _, err := captureStdout(t, func() error { return runContradict(nil, nil) }); err != nil {
+		t.Fatalf("runContradict: %v", err)
+	}
+	records, err := loadContradictions(cwd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func resolveContradiction(t *testing.T, id, keep, note string) error {
+	t.Helper()
+	origKeep, origNote := contradictKeep, contradictNote
+	t.Cleanup(func() { contradictKeep, contradictNote = origKeep, origNote })
+	contradictKeep, contradictNote = keep, note
+	_, err := captureStdout(t, func() error { return runContradictResolve(nil, []string{id}) })
+	return err
+}
+
+func TestContradictionPairID(t *testing.T) {
+	a := contradictionPairID(".agents/learnings/a.md", ".agents/learnings/b.md")
+	b := contradictionPairID(".agents/learnings/b.md", ".agents/learnings/a.md")
+	if a != b {
+		t.Errorf("pair ID depends on order: %s vs %s", a, b)
+	}
+	if !strings.HasPrefix(a, "pair-") || len(a) != len("pair-")+10 {
+		t.Errorf("unexpected pair ID format: %s", a)
+	}
+	if a == contradictionPairID(".agents/learnings/a.md", ".agents/learnings/c.md") {
+		t.Error("different pairs must get different IDs")
+	}
+}
+
+func TestRunContradict_PersistsPairs(t *testing.T) {
+	cwd := chdirTemp(t)
+	fileA, fileB := writeContradictionFixture(t, cwd)
+
+	records := scanContradictions(t, cwd)
+	if len(records) != 1 {
+		t.Fatalf("recorded %d contradictions, want 1", len(records))
+	}
+	r := records[0]
+	if r.Status != contradictionUnresolved || r.DetectedAt.IsZero() {
+		t.Errorf("new record = %+v, want unresolved with detected_at", r)
+	}
+	if r.ID != contradictionPairID(fileA, fileB) {
+		t.Errorf("ID = %s, want %s", r.ID, contradictionPairID(fileA, fileB))
+	}
+
+	if again := scanContradictions(t, cwd); len(again) != 1 || again[0].DetectedAt != r.DetectedAt {
+		t.Errorf("rescan should keep the existing record, got %+v", again)
+	}
+}
+
+func TestRunContradictResolve_KeepA(t *testing.T) {
+	cwd := chdirTemp(t)
+	writeContradictionFixture(t, cwd)
+	rec := scanContradictions(t, cwd)[0]
+
+	if err := resolveContradiction(t, rec.ID, "a", "channels are preferred here"); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cwd, rec.FileB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "superseded_by: " + learningStem(rec.FileA); !strings.Contains(string(data), want) {
+		t.Errorf("loser should carry %q, got:\n%s", want, data)
+	}
+	if l, err := parseLearningFile(filepath.Join(cwd, rec.FileB)); err != nil || !l.Superseded {
+		t.Errorf("inject should treat the loser as superseded (err=%v)", err)
+	}
+
+	records, err := loadContradictions(cwd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := records[0]; got.Status != contradictionResolved || got.Keep != "A" || got.Note != "channels are preferred here" {
+		t.Errorf("resolved record = %+v", got)
+	}
+
+	events, err := os.ReadFile(filepath.Join(cwd, ".agents", "ao", contradictionEventsFile))
+	if err != nil {
+		t.Fatalf("read resolution log: %v", err)
+	}
+	if !strings.Contains(string(events), `"pair_id":"`+rec.ID+`"`) || !strings.Contains(string(events), `"superseded":"`+filepath.ToSlash(rec.FileB)+`"`) {
+		t.Errorf("resolution log missing event:\n%s", events)
+	}
+	if _, err := os.Stat(filepath.Join(cwd, ".agents", "ao", "chain.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("resolution must not touch the ratchet chain (stat err = %v)", err)
+	}
+
+	if err := resolveContradiction(t, rec.ID, "B", ""); err == nil {
+		t.Error("resolving twice should fail")
+	}
+}
+
+func TestRunContradictResolve_KeepBoth(t *testing.T) {
+	cwd := chdirTemp(t)
+	writeContradictionFixture(t, cwd)
+	rec := scanContradictions(t, cwd)[0]
+
+	if err := resolveContradiction(t, rec.ID, "both", "different contexts"); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	for _, f := range []string{rec.FileA, rec.FileB} {
+		data, err := os.ReadFile(filepath.Join(cwd, f))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "superseded_by") {
+			t.Errorf("--keep both must not supersede %s", f)
+		}
+	}
+}
+
+func TestRunContradictResolve_Errors(t *testing.T) {
+	cwd := chdirTemp(t)
+	writeContradictionFixture(t, cwd)
+	rec := scanContradictions(t, cwd)[0]
+
+	if err := resolveContradiction(t, rec.ID, "C", ""); err == nil {
+		t.Error("expected error for invalid --keep")
+	}
+	if err := resolveContradiction(t, "pair-missing", "A", ""); err == nil {
+		t.Error("expected error for unknown pair")
+	}
+}
+
+func TestSetFrontmatterField(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"no frontmatter", "body", "---\nk: v\n---\nbody"},
+		{"adds field", "---\ntitle: T\n---\nbody", "---\ntitle: T\nk: v\n---\nbody"},
+		{"replaces field", "---\nk: old\ntitle: T\n---\nbody", "---\nk: v\ntitle: T\n---\nbody"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setFrontmatterField(tt.in, "k", "v"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContradictionGuard(t *testing.T) {
+	cwd := chdirTemp(t)
+	writeContradictionFixture(t, cwd)
+	rec := scanContradictions(t, cwd)[0]
+
+	guard := loadContradictionGuard(cwd)
+	if guard == nil {
+		t.Fatal("expected a guard for an unresolved pair")
+	}
+	learnings := []learning{
+		{ID: "b", Source: filepath.Join(cwd, rec.FileB)},
+		{ID: "a", Source: filepath.Join(cwd, rec.FileA)},
+		{ID: "other", Source: filepath.Join(cwd, ".agents", "learnings", "other.md")},
+	}
+	kept := guard.filterLearnings(learnings)
+	if len(kept) != 2 || kept[0].ID != "b" || kept[1].ID != "other" {
+		t.Errorf("kept = %+v, want the better-ranked side and the unrelated learning", kept)
+	}
+	if ps := guard.filterPatterns([]pattern{{Name: "a", FilePath: rec.FileA}}); len(ps) != 0 {
+		t.Errorf("pattern contradicting a served learning should be dropped, got %+v", ps)
+	}
+
+	if err := resolveContradiction(t, rec.ID, "both", ""); err != nil {
+		t.Fatal(err)
+	}
+	if loadContradictionGuard(cwd) != nil {
+		t.Error("resolved pairs should not produce a guard")
+	}
+}
+
+func TestGatherLearnings_GuardFiltersBeforeLimit(t *testing.T) {
+	cwd := chdirTemp(t)
+	fileA, fileB := writeContradictionFixture(t, cwd)
+	scanContradictions(t, cwd)
+
+	// Both sides of the pair outrank every filler, so a packet cut before
+	// the guard would lose one of its ten slots.
+	for _, f := range []string{fileA, fileB} {
+		path := filepath.Join(cwd, f)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := string(data)
+		for _, kv := range [][2]string{{"maturity", "established"}, {"utility", "0.9"}, {"source_bead", "ag-pair"}} {
+			text = setFrontmatterField(text, kv[0], kv[1])
+		}
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range MaxLearningsToInject {
+		body := fmt.Sprintf("---\nmaturity: provisional\nutility: 0.4\nsource_bead: ag-%d\n---\n# Filler %d\n\nSomething less useful.\n", i, i)
+		if err := os.WriteFile(filepath.Join(cwd, ".agents", "learnings", fmt.Sprintf("filler-%02d.md", i)), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	origNoCite := injectNoCite
+	injectNoCite = true
+	t.Cleanup(func() { injectNoCite = origNoCite })
+
+	learnings := gatherLearnings(cwd, "", "session-guard", "", 0, loadContradictionGuard(cwd), nil)
+	if len(learnings) != MaxLearningsToInject {
+		t.Errorf("packet has %d learnings, want a full %d after dropping the contradicting side", len(learnings), MaxLearningsToInject)
+	}
+}
@@ -5,15 +5,23 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/boshu2/agentops/cli/internal/search"
 )
 
-var dedupMerge bool
+var (
+	dedupMerge     bool
+	dedupNear      bool
+	dedupThreshold float64
+)
 
 // DedupGroup represents a set of duplicate learnings.
 type DedupGroup struct {
@@ -42,19 +50,29 @@ formatting), and groups by SHA256 hash. Groups with more than one member are
 duplicates. The patterns directory is optional — if it does not exist, only
 learnings are scanned.
 
+With --near, files are instead clustered by MinHash similarity over word
+shingles of the same body text, so paraphrased learnings are caught too.
+Locality-sensitive hashing keeps the comparison near linear in file count.
+--threshold sets the minimum estimated Jaccard similarity (default 0.8).
+
 With --merge, automatically resolves each duplicate group by keeping the file
 with the highest utility (from YAML frontmatter or JSON) and archiving the
 rest to .agents/archive/dedup/. Files without a utility field default to 0.5.
+The kept file records the archived IDs in its supersedes field.
 
 Examples:
   ao dedup
   ao dedup --json
-  ao dedup --merge`,
+  ao dedup --merge
+  ao dedup --near --threshold 0.7
+  ao dedup --near --merge`,
 	RunE: runDedup,
 }
 
 func init() {
 	dedupCmd.Flags().BoolVar(&dedupMerge, "merge", false, "Auto-resolve duplicates: keep highest utility, archive the rest")
+	dedupCmd.Flags().BoolVar(&dedupNear, "near", false, "Cluster near-duplicates by MinHash similarity instead of exact hash")
+	dedupCmd.Flags().Float64Var(&dedupThreshold, "threshold", search.DefaultNearThreshold, "Minimum similarity for --near clusters (0-1)")
 	dedupCmd.GroupID = "core"
 	rootCmd.AddCommand(dedupCmd)
 }
@@ -142,6 +160,7 @@ func mergeDedupGroups(hashToFiles map[string][]string, cwd string, dryRun bool)
 		kept, archived := pickHighestUtility(group)
 		keptRel, _ := filepath.Rel(cwd, kept)
 		fmt.Printf("  Keep: %s (utility %.2f)\n", keptRel, readUtilityFromFile(kept))
+		var moved []string
 		for _, a := range archived {
 			aRel, _ := filepath.Rel(cwd, a)
 			dst := filepath.Join(archiveDir, filepath.Base(a))
@@ -149,8 +168,12 @@ func mergeDedupGroups(hashToFiles map[string][]string, cwd string, dryRun bool)
 				fmt.Fprintf(os.Stderr, "Error archiving %s: %v\n", aRel, mvErr)
 				continue
 			}
+			moved = append(moved, a)
 			fmt.Printf("  Archived: %s -> .agents/archive/dedup/%s\n", aRel, filepath.Base(a))
 		}
+		if err := recordSupersedes(kept, moved); err != nil {
+			fmt.Fprintf(os.Stderr, "Error recording supersedes on %s: %v\n", keptRel, err)
+		}
 	}
 	return nil
 }
@@ -182,6 +205,7 @@ func buildDedupResult(hashToFiles map[string][]string, totalFiles int, cwd strin
 			})
 		}
 	}
+	slices.SortFunc(result.Groups, func(a, b DedupGroup) int { return strings.Compare(a.Files[0], b.Files[0]) })
 
 	return result
 }
@@ -205,7 +229,16 @@ func runDedup(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	hashToFiles := groupByContentHash(files)
+	if err := validateNearThreshold("threshold", dedupThreshold); err != nil {
+		return err
+	}
+
+	var hashToFiles map[string][]string
+	if dedupNear {
+		hashToFiles = groupByNearContent(files, dedupThreshold)
+	} else {
+		hashToFiles = groupByContentHash(files)
+	}
 	result := buildDedupResult(hashToFiles, len(files), cwd)
 
 	// --merge: resolve duplicate groups by keeping highest utility
@@ -385,3 +418,12 @@ func hashNormalizedContent(body string) string {
 	h := sha256.Sum256([]byte(s))
 	return hex.EncodeToString(h[:])
 }
+
+// validateNearThreshold rejects a near-duplicate similarity threshold
+// outside (0, 1], including NaN, for the flag named flag.
+func validateNearThreshold(flag string, threshold float64) error {
+	if math.IsNaN(threshold) || threshold <= 0 || threshold > 1 {
+		return fmt.Errorf("--%s must be in (0, 1], got %g", flag, threshold)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/boshu2/agentops/cli/internal/search"
+)
+
+// groupByNearContent clusters files whose bodies are near-duplicates by
+// MinHash similarity. It returns the same shape as groupByContentHash:
+// every file with a body appears in exactly one group, keyed by the content
+// hash of the group's first file, so singletons count as unique content.
+func groupByNearContent(files []string, threshold float64) map[string][]string {
+	bodies := make(map[string]string, len(files))
+	for _, f := range files {
+		if body := extractLearningBody(f); body != "" {
+			bodies[f] = body
+		}
+	}
+
+	groups := make(map[string][]string)
+	clustered := make(map[string]bool)
+	for _, c := range search.NearDuplicates(bodies, threshold) {
+		groups[hashNormalizedContent(bodies[c.Keys[0]])] = c.Keys
+		for _, f := range c.Keys {
+			clustered[f] = true
+		}
+	}
+	for f, body := range bodies {
+		if !clustered[f] {
+			hash := hashNormalizedContent(body)
+			groups[hash] = append(groups[hash], f)
+		}
+	}
+	return groups
+}
+
+// recordSupersedes adds the IDs of archived duplicates to the survivor's
+// supersedes list so the merge stays traceable (ao graph follows it).
+// Markdown files get a frontmatter list; JSONL files get a field on the
+// first record.
+func recordSupersedes(path string, archived []string) error {
+	if len(archived) == 0 {
+		return nil
+	}
+	ids := make([]string, len(archived))
+	for i, a := range archived {
+		ids[i] = strings.TrimSuffix(filepath.Base(a), filepath.Ext(a))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var updated string
+	if strings.HasSuffix(path, ".jsonl") {
+		updated, err = addJSONLSupersedes(string(content), ids)
+		if err != nil {
+			return fmt.Errorf("update %s: %w", path, err)
+		}
+	} else {
+		updated = addFrontmatterSupersedes(string(content), ids)
+	}
+	return atomicWriteFile(path, []byte(updated), info.Mode().Perm())
+}
+
+// addFrontmatterSupersedes merges ids into the supersedes field, written as
+// an inline YAML list. A block-style list is folded into the inline form.
+func addFrontmatterSupersedes(text string, ids []string) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "---\nsupersedes: " + formatSupersedes(ids) + "\n---\n" + text
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return "---\nsupersedes: " + formatSupersedes(ids) + "\n---\n" + text
+	}
+
+	var existing []string
+	fm := make([]string, 0, end)
+	for i := 1; i < end; i++ {
+		if !strings.HasPrefix(lines[i], "supersedes:") {
+			fm = append(fm, lines[i])
+			continue
+		}
+		value := strings.TrimSpace(strings.TrimPrefix(lines[i], "supersedes:"))
+		for _, v := range strings.Split(strings.Trim(value, "[]"), ",") {
+			if v = strings.Trim(strings.TrimSpace(v), `"'`); v != "" {
+				existing = append(existing, v)
+			}
+		}
+		for i+1 < end && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "- ") {
+			i++
+			existing = append(existing, strings.Trim(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), "- ")), `"'`))
+		}
+	}
+	fm = append(fm, "supersedes: "+formatSupersedes(mergeIDs(existing, ids)))
+	return strings.Join(slices.Concat([]string{"---"}, fm, lines[end:]), "\n")
+}
+
+// addJSONLSupersedes merges ids into the first record's supersedes field.
+func addJSONLSupersedes(text string, ids []string) (string, error) {
+	first, rest, _ := strings.Cut(text, "\n")
+	var data map[string]any
+	if err := json.Unmarshal([]byte(first), &data); err != nil {
+		return "", err
+	}
+	var existing []string
+	if list, ok := data["supersedes"].([]any); ok {
+		for _, v := range list {
+			if s, ok := v.(string); ok {
+				existing = append(existing, s)
+			}
+		}
+	}
+	data["supersedes"] = mergeIDs(existing, ids)
+	line, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	if !strings.Contains(text, "\n") {
+		return string(line), nil
+	}
+	return string(line) + "\n" + rest, nil
+}
+
+func mergeIDs(existing, ids []string) []string {
+	merged := slices.Clone(existing)
+	for _, id := range ids {
+		if !slices.Contains(merged, id) {
+			merged = append(merged, id)
+		}
+	}
+	return merged
+}
+
+func formatSupersedes(ids []string) string {
+	return "[" + strings.Join(ids, ", ") + "]"
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	nearRetryBody   = "When a remote call fails with a transient error, retry it with exponential backoff and jitter, cap the number of attempts, and log the final failure so the operator can see which dependency was unhealthy during the incident window."
+	nearRetryBodyV2 = "When a remote call fails with a transient error, retry it with exponential backoff plus jitter, cap the number of attempts, and log the final failure so the operator can see which dependency was unhealthy during the incident window."
+	nearMutexBody   = "Guard shared maps with a mutex or use sync.Map; concurrent writes to a plain map crash the runtime with a fatal error that recover cannot catch."
+)
+
+// writeNearFixture writes two paraphrased learnings and one unrelated one.
+func writeNearFixture(t *testing.T, root string) (high, low, other string) {
+	t.Helper()
+	dir := filepath.Join(root, ".agents", "learnings")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	high = filepath.Join(dir, "retry-a.md")
+	low = filepath.Join(dir, "retry-b.md")
+	other = filepath.Join(dir, "mutex.md")
+	for path, content := range map[string]string{
+		high:  "---\ntitle: Retry\nutility: 0.9\n---\n" + nearRetryBody,
+		low:   "---\ntitle: Retry again\nutility: 0.2\n---\n" + nearRetryBodyV2,
+		other: "---\ntitle: Mutex\n---\n" + nearMutexBody,
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return high, low, other
+}
+
+func TestGroupByNearContent(t *testing.T) {
+	high, low, other := writeNearFixture(t, t.TempDir())
+	files := []string{high, low, other}
+
+	if exact := groupByContentHash(files); len(exact) != 3 {
+		t.Fatalf("exact hashing should not group paraphrases, got %d groups", len(exact))
+	}
+
+	groups := groupByNearContent(files, 0.7)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2: %v", len(groups), groups)
+	}
+	for _, g := range groups {
+		if len(g) == 2 && (g[0] != high || g[1] != low) {
+			t.Errorf("near cluster = %v, want [%s %s]", g, high, low)
+		}
+	}
+}
+
+func TestAddFrontmatterSupersedes(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{
+			name: "no frontmatter",
+			in:   "body\n",
+			want: "---\nsupersedes: [a]\n---\nbody\n",
+		},
+		{
+			name: "adds field",
+			in:   "---\ntitle: T\n---\nbody\n",
+			want: "---\ntitle: T\nsupersedes: [a]\n---\nbody\n",
+		},
+		{
+			name: "merges inline list",
+			in:   "---\nsupersedes: [x, a]\ntitle: T\n---\nbody\n",
+			want: "---\ntitle: T\nsupersedes: [x, a]\n---\nbody\n",
+		},
+		{
+			name: "folds block list",
+			in:   "---\nsupersedes:\n  - x\ntitle: T\n---\nbody\n",
+			want: "---\ntitle: T\nsupersedes: [x, a]\n---\nbody\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addFrontmatterSupersedes(tt.in, []string{"a"}); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddJSONLSupersedes(t *testing.T) {
+	got, err := addJSONLSupersedes(`{"id":"k","supersedes":["x"]}`+"\n"+`{"id":"k2"}`, []string{"a", "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, rest, _ := strings.Cut(got, "\n")
+	var data map[string]any
+	if err := json.Unmarshal([]byte(first), &data); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := data["supersedes"].([]any); len(list) != 2 || list[0] != "x" || list[1] != "a" {
+		t.Errorf("supersedes = %v, want [x a]", data["supersedes"])
+	}
+	if rest != `{"id":"k2"}` {
+		t.Errorf("later records changed: %q", rest)
+	}
+}
+
+func TestRunDedup_NearMergeRecordsSupersedes(t *testing.T) {
+	high, low, other := writeNearFixture(t, chdirTemp(t))
+
+	origMerge, origNear, origThreshold, origDryRun := dedupMerge, dedupNear, dedupThreshold, dryRun
+	t.Cleanup(func() { dedupMerge, dedupNear, dedupThreshold, dryRun = origMerge, origNear, origThreshold, origDryRun })
+	dedupMerge, dedupNear, dedupThreshold, dryRun = true, true, 0.7, false
+
+	if _, err := captureStdout(t, func() error { return runDedup(dedupCmd, nil) }); err != nil {
+		t.Fatalf("runDedup: %v", err)
+	}
+
+	if _, err := os.Stat(low); !os.IsNotExist(err) {
+		t.Error("lower-utility paraphrase should be archived")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("unrelated learning should be kept: %v", err)
+	}
+	data, err := os.ReadFile(high)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "supersedes: [retry-b]") {
+		t.Errorf("survivor should record supersedes, got:\n%s", data)
+	}
+	if extractLearningBody(high) != nearRetryBody {
+		t.Error("survivor body must be unchanged")
+	}
+}
+
+func TestRunDedup_RejectsBadThreshold(t *testing.T) {
+	writeNearFixture(t, chdirTemp(t))
+
+	origThreshold := dedupThreshold
+	t.Cleanup(func() { dedupThreshold = origThreshold })
+	dedupThreshold = 1.5
+
+	if err := runDedup(dedupCmd, nil); err == nil {
+		t.Error("expected error for threshold > 1")
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
@@ -882,3 +883,16 @@ func TestBuildDedupResult_RelativePathFallback(t *testing.T) {
 		t.Errorf("files = %d", len(result.Groups[0].Files))
 	}
 }
+
+func TestValidateNearThreshold(t *testing.T) {
+	for _, v := range []float64{0.01, 0.8, 1} {
+		if err := validateNearThreshold("threshold", v); err != nil {
+			t.Errorf("%g: unexpected error %v", v, err)
+		}
+	}
+	for _, v := range []float64{math.NaN(), math.Inf(1), -0.1, 0, 1.01} {
+		if err := validateNearThreshold("threshold", v); err == nil {
+			t.Errorf("%g: expected an error", v)
+		}
+	}
+}
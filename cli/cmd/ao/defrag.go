@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/boshu2/agentops/cli/internal/search"
 )
 
 var (
@@ -21,6 +23,7 @@ var (
 	defragStaleDays        int
 	defragOutputDir        string
 	defragQuiet            bool
+	defragNearThreshold    float64
 )
 
 var defragCmd = &cobra.Command{
@@ -29,12 +32,14 @@ var defragCmd = &cobra.Command{
 	Long: `Defrag performs mechanical cleanup of the knowledge base:
 
   --prune             Find orphaned learnings (no references, >N days old)
-  --dedup             Flag learnings with >80% content similarity
+  --dedup             Flag learnings with >80% content similarity, and report
+                      near-duplicate clusters as ao dedup --near finds them
   --oscillation-sweep Read evolve cycle history; flag goals alternating >=3 cycles
 
 By default, defrag applies prune/dedup changes unless you pass the global
 --dry-run flag. Use ao --dry-run defrag ... to inspect the report without
-deleting orphaned or duplicate learnings.
+deleting orphaned or duplicate learnings. Near-duplicate clusters are only
+reported; resolve them with ao dedup --near --merge.
 
 Output: .agents/defrag/YYYY-MM-DD.json with full delta report.
 
@@ -53,6 +58,8 @@ func init() {
 		"Flag learnings with >80% content similarity")
 	defragCmd.Flags().BoolVar(&defragOscillationSweep, "oscillation-sweep", false,
 		"Flag evolve goals alternating improved/fail >=3 consecutive cycles")
+	defragCmd.Flags().Float64Var(&defragNearThreshold, "near-threshold", search.DefaultNearThreshold,
+		"Minimum MinHash similarity for near-duplicate clusters (0-1)")
 	defragCmd.Flags().IntVar(&defragStaleDays, "stale-days", 30,
 		"Days after which an unreferenced learning is considered stale")
 	defragCmd.Flags().StringVar(&defragOutputDir, "output-dir", ".agents/defrag",
@@ -79,9 +86,10 @@ type PruneResult struct {
 
 // DefragDedupResult holds near-duplicate detection results for defrag.
 type DefragDedupResult struct {
-	Checked        int         `json:"checked"`
-	DuplicatePairs [][2]string `json:"duplicate_pairs,omitempty"`
-	Deleted        []string    `json:"deleted,omitempty"`
+	Checked        int          `json:"checked"`
+	DuplicatePairs [][2]string  `json:"duplicate_pairs,omitempty"`
+	Deleted        []string     `json:"deleted,omitempty"`
+	NearClusters   []DedupGroup `json:"near_clusters,omitempty"`
 }
 
 // OscillationResult holds oscillating-goal sweep results.
@@ -102,6 +110,10 @@ func runDefrag(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("get working directory: %w", err)
 	}
 
+	if err := validateNearThreshold("near-threshold", defragNearThreshold); err != nil {
+		return err
+	}
+
 	isDryRun := GetDryRun()
 
 	// Default to all operations when no mode flag is specified.
@@ -183,6 +195,14 @@ func executeDedup(cwd string, isDryRun bool) (*DefragDedupResult, error) {
 		}
 		result.DuplicatePairs = nil // pairs resolved
 	}
+
+	// Clusters are computed after deletion so they only name files that
+	// still exist.
+	files, err := collectDedupFiles(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("dedup: %w", err)
+	}
+	result.NearClusters = buildDedupResult(groupByNearContent(files, defragNearThreshold), len(files), cwd).Groups
 	return result, nil
 }
 
@@ -501,8 +521,8 @@ func printDefragSummary(r *DefragReport) {
 		}
 	}
 	if r.Dedup != nil {
-		fmt.Printf("  Dedup: %d checked, %d duplicate pairs\n",
-			r.Dedup.Checked, len(r.Dedup.DuplicatePairs))
+		fmt.Printf("  Dedup: %d checked, %d duplicate pairs, %d near-duplicate clusters\n",
+			r.Dedup.Checked, len(r.Dedup.DuplicatePairs), len(r.Dedup.NearClusters))
 	}
 	if r.Oscillation != nil {
 		fmt.Printf("  Oscillation: %d oscillating goals\n",
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -1004,3 +1005,40 @@ func TestSweepOscillatingGoals_SkipsMalformedLines(t *testing.T) {
 	}
 }
 
+
+func TestExecuteDedup_ReportsNearClusters(t *testing.T) {
+	tmp := t.TempDir()
+	high, low, _ := writeNearFixture(t, tmp)
+
+	origThreshold := defragNearThreshold
+	t.Cleanup(func() { defragNearThreshold = origThreshold })
+	defragNearThreshold = 0.7
+
+	result, err := executeDedup(tmp, true)
+	if err != nil {
+		t.Fatalf("executeDedup: %v", err)
+	}
+	if len(result.NearClusters) != 1 {
+		t.Fatalf("NearClusters = %+v, want 1 cluster", result.NearClusters)
+	}
+	want := []string{
+		filepath.Join(".agents", "learnings", filepath.Base(high)),
+		filepath.Join(".agents", "learnings", filepath.Base(low)),
+	}
+	if got := result.NearClusters[0].Files; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("cluster files = %v, want %v", got, want)
+	}
+}
+
+func TestRunDefrag_RejectsInvalidNearThreshold(t *testing.T) {
+	t.Chdir(t.TempDir())
+	origThreshold := defragNearThreshold
+	t.Cleanup(func() { defragNearThreshold = origThreshold })
+
+	for _, v := range []float64{math.NaN(), -0.5, 0, 1.5} {
+		defragNearThreshold = v
+		if err := runDefrag(nil, nil); err == nil || !strings.Contains(err.Error(), "--near-threshold must be in (0, 1]") {
+			t.Errorf("--near-threshold %g: err = %v", v, err)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/boshu2/agentops/cli/internal/pool"
 	"github.com/boshu2/agentops/cli/internal/storage"
 )
 
@@ -63,6 +64,7 @@ func gatherDoctorChecks() []doctorCheck {
 		checkKnowledgeBase(),
 		checkKnowledgeFreshness(),
 		checkSearchIndex(),
+		checkPoolChain(),
 		checkFlywheelHealth(),
 		checkSkills(),
 		checkCodexSync(),
@@ -454,6 +456,38 @@ func checkSearchIndex() doctorCheck {
 	}
 }
 
+// checkPoolChain verifies the hash links of the pool chain.
+func checkPoolChain() doctorCheck {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return doctorCheck{Name: "Pool Chain", Status: "warn", Detail: "cannot determine working directory", Required: false}
+	}
+
+	v, err := pool.NewPool(cwd).VerifyChain()
+	switch {
+	case err != nil:
+		return doctorCheck{Name: "Pool Chain", Status: "warn", Detail: fmt.Sprintf("cannot read chain: %v", err), Required: false}
+	case !v.Intact():
+		first := v.Problems[0]
+		return doctorCheck{
+			Name:     "Pool Chain",
+			Status:   "fail",
+			Detail:   fmt.Sprintf("%d integrity problem(s), first at line %d: %s \u2014 run 'ao pool verify'", len(v.Problems), first.Line, first.Message),
+			Required: false,
+		}
+	case v.Legacy > 0:
+		return doctorCheck{
+			Name:     "Pool Chain",
+			Status:   "warn",
+			Detail:   fmt.Sprintf("%d legacy event(s) without hashes \u2014 run 'ao pool migrate-chain'", v.Legacy),
+			Required: false,
+		}
+	case v.Events == 0:
+		return doctorCheck{Name: "Pool Chain", Status: "pass", Detail: "No pool events yet", Required: false}
+	}
+	return doctorCheck{Name: "Pool Chain", Status: "pass", Detail: fmt.Sprintf("%d events hash-linked", v.Events), Required: false}
+}
+
 // countFileLines counts non-empty lines in a file.
 func countFileLines(path string) int {
 	f, err := os.Open(path)
@@ -147,13 +147,13 @@ func runFeedback(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	oldUtility, newUtility, err := updateLearningUtility(learningPath, feedbackReward, feedbackAlpha)
+	feedbackType := classifyFeedbackType(feedbackHelpful, feedbackHarmful)
+	oldUtility, newUtility, err := updateLearningUtilityRecorded(cwd, learningPath, feedbackReward, feedbackAlpha,
+		utilityOrigin{Source: "feedback", SessionID: resolveSessionID(""), CitationType: feedbackType})
 	if err != nil {
 		return fmt.Errorf("update utility: %w", err)
 	}
 
-	feedbackType := classifyFeedbackType(feedbackHelpful, feedbackHarmful)
-
 	if GetOutput() == "json" {
 		return printFeedbackJSON(learningID, learningPath, feedbackType, oldUtility, newUtility, feedbackReward, feedbackAlpha)
 	}
@@ -199,7 +199,11 @@ func processUniqueCitations(cwd, sessionID, transcriptPath string, citations []t
 			}
 		}
 
-		oldUtility, newUtility, err := updateLearningUtility(learningPath, reward, alpha)
+		oldUtility, newUtility, err := updateLearningUtilityRecorded(cwd, learningPath, reward, alpha, utilityOrigin{
+			Source:       "feedback-loop",
+			SessionID:    canonicalSessionID(sessionID),
+			CitationType: canonicalCitationType(citation.CitationType),
+		})
 		if err != nil {
 			VerbosePrintf("Warning: failed to update %s: %v\n", learningPath, err)
 			failedCount++
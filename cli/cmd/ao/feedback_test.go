@@ -785,6 +785,8 @@ func TestRunFeedback_DryRun(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestRunFeedback_ActualUpdate(t *testing.T) {
+	t.Setenv("CLAUDE_SESSION_ID", "")
+	t.Setenv("CODEX_THREAD_ID", "3F2504E0-4F89-11D3-9A0C-0305E82C3301")
 	tmp := t.TempDir()
 	prevWD, err := os.Getwd()
 	if err != nil {
@@ -841,6 +843,16 @@ func TestRunFeedback_ActualUpdate(t *testing.T) {
 	if data["utility"] == nil {
 		t.Error("expected utility field after update")
 	}
+
+	// The history records the same canonical session ID as injection and citations.
+	events, err := loadUtilityHistory(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "session-uuid-3f2504e0-4f89-11d3-9a0c-0305e82c3301"
+	if len(events) != 1 || events[0].SessionID != want {
+		t.Errorf("history = %+v, want one event with session %q", events, want)
+	}
 }
 
 // ---------------------------------------------------------------------------
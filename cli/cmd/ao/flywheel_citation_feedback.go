@@ -1,6 +1,7 @@
 package main
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -96,7 +97,11 @@ func processCitationFeedback(cwd string) (int, int, int) {
 		rewardCount := getLearningRewardCount(path)
 		alpha := annealedAlpha(types.DefaultAlpha, rewardCount)
 
-		oldUtility, newUtility, err := updateLearningUtility(path, reward, alpha)
+		oldUtility, newUtility, err := updateLearningUtilityRecorded(cwd, path, reward, alpha, utilityOrigin{
+			Source:       "flywheel",
+			SessionID:    cmp.Or(c.SessionID, sessionID),
+			CitationType: citationType,
+		})
 		if err != nil {
 			currentUtility := parseUtilityFromFile(path)
 			feedbackEvents = append(feedbackEvents, FeedbackEvent{
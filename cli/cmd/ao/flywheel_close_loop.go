@@ -14,6 +14,7 @@ import (
 
 	"github.com/boshu2/agentops/cli/internal/pool"
 	"github.com/boshu2/agentops/cli/internal/ratchet"
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
 	"github.com/boshu2/agentops/cli/internal/types"
 )
 
@@ -190,6 +191,10 @@ func ingestPendingFilesToPool(cwd string, files []string) (poolIngestResult, err
 	if len(files) == 0 {
 		return res, nil
 	}
+	tax, err := taxonomy.Load(cwd)
+	if err != nil {
+		return res, err
+	}
 
 	for _, f := range files {
 		data, rerr := os.ReadFile(f)
@@ -201,7 +206,7 @@ func ingestPendingFilesToPool(cwd string, files []string) (poolIngestResult, err
 		blocks := parseLearningBlocks(string(data))
 		res.CandidatesFound += len(blocks)
 		for _, b := range blocks {
-			cand, scoring, ok := buildCandidateFromLearningBlock(b, f, fileDate, sessionHint)
+			cand, scoring, ok := buildCandidateFromLearningBlock(b, f, fileDate, sessionHint, tax)
 			if !ok {
 				res.SkippedMalformed++
 				continue
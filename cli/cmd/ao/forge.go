@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/boshu2/agentops/cli/internal/parser"
 	"github.com/boshu2/agentops/cli/internal/search"
 	"github.com/boshu2/agentops/cli/internal/storage"
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
 	"github.com/boshu2/agentops/cli/internal/types"
 )
 
@@ -25,6 +27,8 @@ var (
 	forgeLastSession bool
 	forgeQuiet       bool
 	forgeQueue       bool
+	forgeDialect     string
+	forgeRestart     bool
 	forgeMdQuiet     bool
 	forgeMdQueue     bool
 )
@@ -56,7 +60,7 @@ var forgeCmd = &cobra.Command{
 	Long: `The forge command extracts knowledge candidates from various sources.
 
 Currently supported forges:
-  transcript    Extract from Claude Code JSONL transcripts
+  transcript    Extract from agent session transcripts (Claude, Codex, OpenCode, Cursor)
   markdown      Extract from markdown files (.md)
 
 Example:
@@ -66,8 +70,19 @@ Example:
 
 var forgeTranscriptCmd = &cobra.Command{
 	Use:   "transcript <path-or-glob>",
-	Short: "Extract knowledge from Claude Code transcripts",
-	Long: `Parse Claude Code JSONL transcript files and extract knowledge candidates.
+	Short: "Extract knowledge from agent session transcripts",
+	Long: `Parse agent session transcripts and extract knowledge candidates.
+
+The transcript format is detected from the first lines of each file:
+Claude Code and Codex session JSONL, OpenCode session exports
+(opencode export) and Cursor chat exports are built in. Use --dialect to
+skip detection.
+
+Forge streams each transcript and checkpoints its byte offset and line in
+.agents/ao/forge-cursors.jsonl. A rerun resumes where the last one stopped,
+so an interrupted forge of a very large session keeps its progress and a
+transcript that is still growing is forged incrementally. Files with no new
+lines are skipped; --restart re-reads from the beginning.
 
 The transcript forge identifies:
   - Decisions: Architectural choices with rationale
@@ -80,6 +95,8 @@ Examples:
   ao forge transcript session.jsonl
   ao forge transcript ~/.claude/projects/**/*.jsonl
   ao forge transcript /path/to/*.jsonl --output candidates.json
+  ao forge transcript opencode-session.json       # Auto-detected OpenCode export
+  ao forge transcript cursor-chat.json --dialect cursor
   ao forge transcript --last-session              # Process most recent transcript
   ao forge transcript --last-session --quiet      # Silent mode for hooks`,
 	Args: func(cmd *cobra.Command, args []string) error {
@@ -119,6 +136,8 @@ func init() {
 	forgeTranscriptCmd.Flags().BoolVar(&forgeLastSession, "last-session", false, "Process only the most recent transcript")
 	forgeTranscriptCmd.Flags().BoolVar(&forgeQuiet, "quiet", false, "Suppress all output (for hooks)")
 	forgeTranscriptCmd.Flags().BoolVar(&forgeQueue, "queue", false, "Queue session for learning extraction at next session start")
+	forgeTranscriptCmd.Flags().BoolVar(&forgeRestart, "restart", false, "Ignore saved forge cursors and re-read transcripts from the start")
+	forgeTranscriptCmd.Flags().StringVar(&forgeDialect, "dialect", "", "Transcript dialect ("+strings.Join(parser.DialectNames(), ", ")+"); detected when empty")
 
 	// Markdown flags
 	forgeMarkdownCmd.Flags().BoolVar(&forgeMdQuiet, "quiet", false, "Suppress all output (for hooks)")
@@ -214,6 +233,22 @@ func initForgeStorage() (cwd, baseDir string, fs *storage.FileStorage, err error
 	return cwd, baseDir, fs, nil
 }
 
+// loadForgeExtractor returns an extractor using the repo's
+// .agents/extraction-rules.yaml, or the default rules when there is none.
+func loadForgeExtractor(cwd string) (*parser.Extractor, error) {
+	return loadExtractionRules(cwd, filepath.Join(cwd, parser.ExtractionRulesFile))
+}
+
+// loadExtractionRules loads the rules file at path, checking rule types
+// against the taxonomy of the repo at cwd.
+func loadExtractionRules(cwd, path string) (*parser.Extractor, error) {
+	tax, err := taxonomy.Load(cwd)
+	if err != nil {
+		return nil, err
+	}
+	return parser.LoadExtractor(path, tax)
+}
+
 func forgeWarnf(quiet bool, format string, args ...any) {
 	if quiet {
 		return
@@ -268,6 +303,14 @@ func printForgeSummary(w io.Writer, totals forgeTotals, baseDir, noun string) {
 func runForgeTranscript(cmd *cobra.Command, args []string) error {
 	w := cmd.OutOrStdout()
 
+	var dialect parser.TranscriptDialect
+	if forgeDialect != "" {
+		var err error
+		if dialect, err = parser.LookupDialect(forgeDialect); err != nil {
+			return err
+		}
+	}
+
 	files, err := resolveTranscriptFiles(args, forgeQuiet)
 	if err != nil {
 		return err
@@ -292,23 +335,56 @@ func runForgeTranscript(cmd *cobra.Command, args []string) error {
 
 	p := parser.NewParser()
 	p.MaxContentLength = 0
+	p.Dialect = dialect
 
-	extractor := parser.NewExtractor()
+	extractor, err := loadForgeExtractor(cwd)
+	if err != nil {
+		return err
+	}
 
 	totals := forgeTotals{}
 
+	cursors, err := loadForgeCursors(baseDir)
+	if err != nil {
+		return err
+	}
 	for _, filePath := range files {
-		session, err := processTranscript(filePath, p, extractor, forgeQuiet, w)
+		key, err := filepath.Abs(filePath)
+		if err != nil {
+			key = filePath
+		}
+		saveCursor := func(c *forgeCursor) {
+			c.Path = key
+			cursors[key] = c
+			if err := saveForgeCursors(baseDir, cursors); err != nil {
+				forgeWarnf(forgeQuiet, "Warning: failed to save forge cursor: %v\n", err)
+			}
+		}
+		var cursor *forgeCursor
+		if !forgeRestart {
+			cursor = cursors[key]
+		}
+
+		session, next, err := streamTranscript(filePath, p, extractor, cursor, forgeQuiet, w, saveCursor)
 		if err != nil {
-			if errors.Is(err, errTranscriptHasNoChatMessages) {
+			switch {
+			case errors.Is(err, errTranscriptHasNoChatMessages):
 				VerbosePrintf("  - skipped %s (no chat messages)\n", filepath.Base(filePath))
-				continue
+			case errors.Is(err, errTranscriptUpToDate):
+				VerbosePrintf("  - skipped %s (no new lines since last forge)\n", filepath.Base(filePath))
+			default:
+				forgeWarnf(forgeQuiet, "Warning: failed to process %s: %v\n", filePath, err)
 			}
-			forgeWarnf(forgeQuiet, "Warning: failed to process %s: %v\n", filePath, err)
 			continue
 		}
 
-		forgeTranscriptFile(fs, session, filePath, baseDir, cwd, &totals)
+		if sessionPath := forgeTranscriptFile(fs, session, cursor, filePath, baseDir, cwd, &totals); sessionPath != "" {
+			next.SessionPath = sessionPath
+			next.Summary = session.Summary
+			next.WrittenDecisions = len(next.Decisions)
+			next.WrittenKnowledge = len(next.Knowledge)
+			saveCursor(next)
+		}
 	}
 
 	if !forgeQuiet {
@@ -318,19 +394,27 @@ func runForgeTranscript(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func forgeTranscriptFile(fs *storage.FileStorage, session *storage.Session, filePath, baseDir, cwd string, totals *forgeTotals) {
+// forgeTranscriptFile writes a forged session and its index, provenance and
+// pending learnings. prev is the cursor the run resumed from, if any: only
+// the decisions and knowledge found since its last completed write are
+// queued and written as pending learnings. It returns the session path, or "" if the write failed.
+func forgeTranscriptFile(fs *storage.FileStorage, session *storage.Session, prev *forgeCursor, filePath, baseDir, cwd string, totals *forgeTotals) string {
 	sessionPath, err := fs.WriteSession(session)
 	if err != nil {
 		forgeWarnf(forgeQuiet, "Warning: failed to write session for %s: %v\n", filePath, err)
-		return
+		return ""
 	}
 
 	if err := writeSessionIndex(fs, session, sessionPath); err != nil {
 		forgeWarnf(forgeQuiet, "Warning: failed to index session: %v\n", err)
 	}
 
-	if err := writeSessionProvenance(fs, session.ID, sessionPath, filePath, "transcript", true); err != nil {
-		forgeWarnf(forgeQuiet, "Warning: failed to write provenance: %v\n", err)
+	if recorded, err := sessionProvenanceRecorded(fs, sessionPath, filePath); err != nil {
+		forgeWarnf(forgeQuiet, "Warning: failed to read provenance: %v\n", err)
+	} else if !recorded {
+		if err := writeSessionProvenance(fs, session.ID, sessionPath, filePath, "transcript", true); err != nil {
+			forgeWarnf(forgeQuiet, "Warning: failed to write provenance: %v\n", err)
+		}
 	}
 
 	updateSearchIndexForFile(baseDir, sessionPath, forgeQuiet)
@@ -340,65 +424,67 @@ func forgeTranscriptFile(fs *storage.FileStorage, session *storage.Session, file
 		VerbosePrintf("  ✓ %s → %s\n", filepath.Base(filePath), filepath.Base(sessionPath))
 	}
 
-	if forgeQueue {
-		if err := queueForExtraction(session, sessionPath, filePath, cwd); err != nil {
+	delta := forgeDelta(session, prev)
+	if forgeQueue && (prev == nil || len(delta.Decisions)+len(delta.Knowledge) > 0) {
+		if err := queueForExtraction(delta, sessionPath, filePath, cwd); err != nil {
 			forgeWarnf(forgeQuiet, "Warning: failed to queue for extraction: %v\n", err)
 		}
 	}
 
 	// Auto-write pending learnings for close-loop ingestion (bridges forge→pool)
-	if n, err := writePendingLearnings(session, cwd); err != nil {
+	if n, err := writePendingLearnings(delta, cwd); err != nil {
 		forgeWarnf(forgeQuiet, "Warning: failed to write pending learnings: %v\n", err)
 	} else if n > 0 && !forgeQuiet {
 		VerbosePrintf("  → %d pending learning(s) written\n", n)
 	}
+	return sessionPath
 }
 
-// processTranscript parses a transcript and extracts session data.
-func processTranscript(filePath string, p *parser.Parser, extractor *parser.Extractor, quiet bool, w io.Writer) (session *storage.Session, err error) {
-	f, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("open file: %w", err)
-	}
-	defer func() {
-		if cerr := f.Close(); cerr != nil && err == nil {
-			err = cerr
-		}
-	}()
-
-	info, err := f.Stat()
-	if err != nil {
-		return nil, fmt.Errorf("stat file: %w", err)
-	}
-	fileSize := info.Size()
-	totalLines := countLines(filePath)
-
-	if _, err := f.Seek(0, 0); err != nil {
-		return nil, fmt.Errorf("seek file: %w", err)
-	}
+// forgeDelta returns a copy of session holding only the decisions and
+// knowledge prev had not already written. With no prev it is session.
+func forgeDelta(session *storage.Session, prev *forgeCursor) *storage.Session {
+	if prev == nil {
+		return session
+	}
+	decisions, knowledge := prev.written()
+	delta := *session
+	delta.Decisions = newForgeItems(session.Decisions, decisions)
+	delta.Knowledge = newForgeItems(session.Knowledge, knowledge)
+	return &delta
+}
 
-	msgCh, errCh := p.ParseChannel(f)
-	session = initSession(filePath)
-	state := &transcriptState{
-		seenFiles:  make(map[string]bool),
-		seenIssues: make(map[string]bool),
+// newForgeItems returns the items not present in seen, compared trimmed.
+func newForgeItems(items, seen []string) []string {
+	known := make(map[string]bool, len(seen))
+	for _, s := range seen {
+		known[strings.TrimSpace(s)] = true
 	}
-
-	consumeTranscriptMessages(msgCh, session, extractor, state, quiet, w, totalLines)
-
-	if err := drainParseErrors(errCh); err != nil {
-		return nil, err
+	var fresh []string
+	for _, it := range items {
+		if !known[strings.TrimSpace(it)] {
+			fresh = append(fresh, it)
+		}
 	}
+	return fresh
+}
 
-	if session.Date.IsZero() {
-		session.Date = info.ModTime().UTC()
-	}
-	finalizeTranscriptSession(session, state, fileSize)
-	if state.chatMessages == 0 {
-		return nil, errTranscriptHasNoChatMessages
+// sessionProvenanceRecorded reports whether the provenance graph already
+// links sessionPath to sourcePath, so re-forging a transcript does not append
+// the same record again.
+func sessionProvenanceRecorded(fs *storage.FileStorage, sessionPath, sourcePath string) (bool, error) {
+	records, err := fs.QueryProvenance(sessionPath)
+	if err != nil {
+		return false, err
 	}
+	return slices.ContainsFunc(records, func(r storage.ProvenanceRecord) bool {
+		return r.SourcePath == sourcePath
+	}), nil
+}
 
-	return session, nil
+// processTranscript forges a whole transcript and extracts session data.
+func processTranscript(filePath string, p *parser.Parser, extractor *parser.Extractor, quiet bool, w io.Writer) (*storage.Session, error) {
+	session, _, err := streamTranscript(filePath, p, extractor, nil, quiet, w, nil)
+	return session, err
 }
 
 func consumeTranscriptMessages(msgCh <-chan types.TranscriptMessage, session *storage.Session, extractor *parser.Extractor, state *transcriptState, quiet bool, w io.Writer, totalLines int) {
@@ -408,12 +494,7 @@ func consumeTranscriptMessages(msgCh <-chan types.TranscriptMessage, session *st
 	for msg := range msgCh {
 		lineCount++
 		reportProgress(quiet, w, lineCount, totalLines, &lastProgress)
-		updateSessionMeta(session, msg)
-		if isConversationMessage(msg) {
-			state.chatMessages++
-		}
-		extractMessageKnowledge(msg, extractor, state)
-		extractMessageRefs(msg, session, state)
+		consumeTranscriptMessage(msg, session, extractor, state)
 	}
 
 	if !quiet {
@@ -421,6 +502,16 @@ func consumeTranscriptMessages(msgCh <-chan types.TranscriptMessage, session *st
 	}
 }
 
+// consumeTranscriptMessage folds one parsed message into the session.
+func consumeTranscriptMessage(msg types.TranscriptMessage, session *storage.Session, extractor *parser.Extractor, state *transcriptState) {
+	updateSessionMeta(session, msg)
+	if isConversationMessage(msg) {
+		state.chatMessages++
+	}
+	extractMessageKnowledge(msg, extractor, state)
+	extractMessageRefs(msg, session, state)
+}
+
 func reportProgress(quiet bool, w io.Writer, lineCount, totalLines int, lastProgress *int) {
 	if quiet || lineCount-*lastProgress < 1000 {
 		return
@@ -525,8 +616,9 @@ func extractMessageKnowledge(msg types.TranscriptMessage, extractor *parser.Extr
 		switch result.Type {
 		case types.KnowledgeTypeDecision:
 			state.decisions = append(state.decisions, text)
-		case types.KnowledgeTypeSolution, types.KnowledgeTypeLearning,
-			types.KnowledgeTypeFailure, types.KnowledgeTypeReference:
+		default:
+			// Solutions, learnings, failures, references and any custom
+			// types from extraction-rules.yaml.
 			state.knowledge = append(state.knowledge, text)
 		}
 	}
@@ -775,7 +867,10 @@ func runForgeMarkdown(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	extractor := parser.NewExtractor()
+	extractor, err := loadForgeExtractor(cwd)
+	if err != nil {
+		return err
+	}
 
 	totals := forgeTotals{}
 
@@ -928,7 +1023,7 @@ func splitMarkdownSections(content string) []string {
 // entry for the given file path, and saves it back. If no index exists yet
 // this is a no-op -- the user can create one with `ao store rebuild`.
 func updateSearchIndexForFile(baseDir, filePath string, quiet bool) {
-	idxPath := filepath.Join(baseDir, "index.jsonl")
+	idxPath := filepath.Join(baseDir, search.DefaultIndexFile)
 	if _, err := os.Stat(idxPath); os.IsNotExist(err) {
 		return // no index yet -- nothing to update
 	}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/boshu2/agentops/cli/internal/parser"
+	"github.com/boshu2/agentops/cli/internal/storage"
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// forgeCursorsFile is the per-transcript checkpoint log under .agents/ao/.
+const forgeCursorsFile = "forge-cursors.jsonl"
+
+// forgeCheckpointBytes is how much transcript is forged between checkpoints.
+var forgeCheckpointBytes int64 = 8 << 20
+
+// forgeHeadBytes is the transcript prefix hashed to notice a file that was
+// replaced rather than appended to.
+const forgeHeadBytes int64 = 4096
+
+var errTranscriptUpToDate = errors.New("transcript already forged up to its end")
+
+// forgeCursor records how far a transcript has been forged and the
+// extraction state up to that point, so a rerun continues where the last
+// one stopped instead of starting over.
+type forgeCursor struct {
+	Path    string `json:"path"`
+	Dialect string `json:"dialect"`
+	parser.Position
+	HeadHash string `json:"head_hash"`
+
+	// SessionPath and Summary are set once the session has been written;
+	// later runs keep the summary so the session file name stays stable.
+	SessionPath string `json:"session_path,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+
+	// WrittenDecisions and WrittenKnowledge count the leading Decisions and
+	// Knowledge already queued and written as pending learnings. Checkpoints
+	// save extraction state before anything is written, so the next run
+	// writes everything past these counts.
+	WrittenDecisions int `json:"written_decisions,omitempty"`
+	WrittenKnowledge int `json:"written_knowledge,omitempty"`
+
+	SessionID    string         `json:"session_id,omitempty"`
+	Date         time.Time      `json:"date,omitzero"`
+	Decisions    []string       `json:"decisions,omitempty"`
+	Knowledge    []string       `json:"knowledge,omitempty"`
+	FilesChanged []string       `json:"files_changed,omitempty"`
+	Issues       []string       `json:"issues,omitempty"`
+	ToolCalls    map[string]int `json:"tool_calls,omitempty"`
+	ChatMessages int            `json:"chat_messages,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func forgeCursorsPath(baseDir string) string {
+	return filepath.Join(baseDir, forgeCursorsFile)
+}
+
+// loadForgeCursors reads the cursor log keyed by absolute transcript path.
+// A missing file is empty; the last record for a path wins.
+func loadForgeCursors(baseDir string) (map[string]*forgeCursor, error) {
+	cursors := make(map[string]*forgeCursor)
+	f, err := os.Open(forgeCursorsPath(baseDir))
+	if os.IsNotExist(err) {
+		return cursors, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open forge cursors: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // read-only file
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var c forgeCursor
+		if err := json.Unmarshal([]byte(line), &c); err != nil || c.Path == "" {
+			VerbosePrintf("Warning: skipping malformed forge cursor: %v\n", err)
+			continue
+		}
+		cursors[c.Path] = &c
+	}
+	return cursors, scanner.Err()
+}
+
+// saveForgeCursors rewrites the cursor log atomically, sorted by path.
+func saveForgeCursors(baseDir string, cursors map[string]*forgeCursor) error {
+	var b strings.Builder
+	for _, path := range slices.Sorted(maps.Keys(cursors)) {
+		line, err := json.Marshal(cursors[path])
+		if err != nil {
+			return fmt.Errorf("marshal forge cursor %s: %w", path, err)
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	if err := os.MkdirAll(baseDir, 0o750); err != nil {
+		return fmt.Errorf("create %s: %w", baseDir, err)
+	}
+	return atomicWriteFile(forgeCursorsPath(baseDir), []byte(b.String()), 0o600)
+}
+
+// transcriptHeadHash hashes the first forgeHeadBytes of f, or its first
+// forged bytes when fewer have been forged.
+func transcriptHeadHash(f io.ReaderAt, forged int64) (string, error) {
+	buf := make([]byte, forgeHeadBytes)
+	if forged < forgeHeadBytes {
+		buf = buf[:forged]
+	}
+	read, err := f.ReadAt(buf, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	sum := sha256.Sum256(buf[:read])
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// resumeFrom checks that cursor still describes f: the file must be at least
+// as long as the forged prefix and start with the same bytes. It returns nil
+// when the transcript has to be forged from the beginning.
+func resumeFrom(cursor *forgeCursor, f *os.File, size int64) *forgeCursor {
+	if cursor == nil || cursor.Offset <= 0 {
+		return nil
+	}
+	if d, err := parser.LookupDialect(cursor.Dialect); err == nil && d.Document() && size != cursor.Offset {
+		// An export has no inner resume points; any change means a re-read.
+		return nil
+	}
+	if size < cursor.Offset {
+		VerbosePrintf("  %s shrank since it was last forged; starting over\n", filepath.Base(cursor.Path))
+		return nil
+	}
+	hash, err := transcriptHeadHash(f, cursor.Offset)
+	if err != nil || hash != cursor.HeadHash {
+		VerbosePrintf("  %s was rewritten since it was last forged; starting over\n", filepath.Base(cursor.Path))
+		return nil
+	}
+	return cursor
+}
+
+// restoreSession rebuilds the session and extraction state saved in cursor.
+func (c *forgeCursor) restoreSession(filePath string) (*storage.Session, *transcriptState) {
+	session := initSession(filePath)
+	session.ID = cmp.Or(session.ID, c.SessionID)
+	session.Date = c.Date
+	maps.Copy(session.ToolCalls, c.ToolCalls)
+
+	state := &transcriptState{
+		decisions:    slices.Clone(c.Decisions),
+		knowledge:    slices.Clone(c.Knowledge),
+		filesChanged: slices.Clone(c.FilesChanged),
+		issues:       slices.Clone(c.Issues),
+		seenFiles:    make(map[string]bool, len(c.FilesChanged)),
+		seenIssues:   make(map[string]bool, len(c.Issues)),
+		chatMessages: c.ChatMessages,
+	}
+	for _, f := range c.FilesChanged {
+		state.seenFiles[f] = true
+	}
+	for _, id := range c.Issues {
+		state.seenIssues[id] = true
+	}
+	return session, state
+}
+
+// written returns the decisions and knowledge already written from c.
+func (c *forgeCursor) written() (decisions, knowledge []string) {
+	return c.Decisions[:min(c.WrittenDecisions, len(c.Decisions))],
+		c.Knowledge[:min(c.WrittenKnowledge, len(c.Knowledge))]
+}
+
+// snapshotCursor captures the session and extraction state at pos.
+func snapshotCursor(prev *forgeCursor, path, dialect, headHash string, pos parser.Position, session *storage.Session, state *transcriptState) *forgeCursor {
+	c := &forgeCursor{
+		Path:         path,
+		Dialect:      dialect,
+		Position:     pos,
+		HeadHash:     headHash,
+		SessionID:    session.ID,
+		Date:         session.Date,
+		Decisions:    slices.Clone(state.decisions),
+		Knowledge:    slices.Clone(state.knowledge),
+		FilesChanged: slices.Clone(state.filesChanged),
+		Issues:       slices.Clone(state.issues),
+		ToolCalls:    maps.Clone(session.ToolCalls),
+		ChatMessages: state.chatMessages,
+		UpdatedAt:    time.Now().UTC(),
+	}
+	if prev != nil {
+		c.SessionPath = prev.SessionPath
+		c.Summary = prev.Summary
+		c.WrittenDecisions = prev.WrittenDecisions
+		c.WrittenKnowledge = prev.WrittenKnowledge
+	}
+	return c
+}
+
+// reportStreamProgress reports progress every 1000 lines as a share of the
+// file's bytes, which the stream position already carries, so neither a
+// fresh run nor a resume has to count the transcript's lines first.
+func reportStreamProgress(quiet bool, w io.Writer, pos parser.Position, fileSize int64, lastLine *int) {
+	if quiet || pos.Line-*lastLine < 1000 || fileSize <= 0 {
+		return
+	}
+	fmt.Fprintf(w, "\r[forge] Processing... line %d (%d%%)  ", pos.Line, pos.Offset*100/fileSize)
+	*lastLine = pos.Line
+}
+
+// streamTranscript forges filePath from cursor (or from the start when
+// cursor is nil), passing each message through the extractor as it is
+// parsed. checkpoint, when non-nil, is called every forgeCheckpointBytes
+// with a cursor a later run can resume from. The returned cursor covers
+// everything forged; it is nil only on error.
+func streamTranscript(filePath string, p *parser.Parser, extractor *parser.Extractor, cursor *forgeCursor, quiet bool, w io.Writer, checkpoint func(*forgeCursor)) (session *storage.Session, next *forgeCursor, err error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open file: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, fmt.Errorf("stat file: %w", err)
+	}
+	fileSize := info.Size()
+
+	cursor = resumeFrom(cursor, f, fileSize)
+	var (
+		state *transcriptState
+		start parser.Position
+	)
+	if cursor != nil {
+		if cursor.Offset == fileSize {
+			return nil, nil, errTranscriptUpToDate
+		}
+		session, state = cursor.restoreSession(filePath)
+		start = cursor.Position
+		if _, err := f.Seek(start.Offset, io.SeekStart); err != nil {
+			return nil, nil, fmt.Errorf("seek file: %w", err)
+		}
+	} else {
+		session = initSession(filePath)
+		state = &transcriptState{
+			seenFiles:  make(map[string]bool),
+			seenIssues: make(map[string]bool),
+		}
+	}
+
+	if p.Dialect == nil {
+		// Pin the dialect: a resume starts mid-file where there is nothing
+		// to sniff, so it reuses the dialect detected on the first run.
+		var d parser.TranscriptDialect
+		if cursor != nil {
+			d, _ = parser.LookupDialect(cursor.Dialect)
+		}
+		if d == nil {
+			head := make([]byte, 64*1024)
+			n, _ := f.ReadAt(head, 0)
+			d = parser.DetectDialect(head[:n])
+		}
+		pinned := *p
+		pinned.Dialect = d
+		p = &pinned
+	}
+	dialect := p.Dialect.Name()
+	headHash := func(pos parser.Position) string {
+		hash, _ := transcriptHeadHash(f, pos.Offset)
+		return hash
+	}
+
+	lastProgress := start.Line
+	lastCheckpoint := start.Offset
+	end, _, err := p.Stream(f, start, func(msg types.TranscriptMessage, pos parser.Position) error {
+		reportStreamProgress(quiet, w, pos, fileSize, &lastProgress)
+		consumeTranscriptMessage(msg, session, extractor, state)
+		if checkpoint != nil && pos.Offset-lastCheckpoint >= forgeCheckpointBytes {
+			checkpoint(snapshotCursor(cursor, filePath, dialect, headHash(pos), pos, session, state))
+			lastCheckpoint = pos.Offset
+		}
+		return nil
+	})
+	if !quiet {
+		fmt.Fprintf(w, "\r%s\r", "                                                    ")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if cursor != nil && end.Offset == cursor.Offset {
+		// Only a partial trailing record arrived since the last run.
+		return nil, nil, errTranscriptUpToDate
+	}
+
+	if session.Date.IsZero() {
+		session.Date = info.ModTime().UTC()
+	}
+	finalizeTranscriptSession(session, state, fileSize)
+	if state.chatMessages == 0 {
+		return nil, nil, errTranscriptHasNoChatMessages
+	}
+	if cursor != nil && cursor.Summary != "" {
+		session.Summary = cursor.Summary
+	}
+	return session, snapshotCursor(cursor, filePath, dialect, headHash(end), end, session, state), nil
+}
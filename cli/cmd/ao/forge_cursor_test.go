@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/parser"
+	"github.com/boshu2/agentops/cli/internal/storage"
+)
+
+func forgeLine(role, content string) string {
+	return `{"type":"` + role + `","sessionId":"ses_cursor1","timestamp":"2026-04-01T10:00:00Z","message":{"role":"` + role + `","content":"` + content + `"}}` + "\n"
+}
+
+var forgeCursorTranscript = forgeLine("user", "How should retries work?") +
+	forgeLine("assistant", "We decided to use exponential backoff because the API rate limits bursts.") +
+	forgeLine("user", "And the cache?") +
+	forgeLine("assistant", "The fix is to invalidate the cache on write because readers saw stale entries.")
+
+// runForgeTranscriptQuiet runs ao forge transcript on path with --quiet.
+func runForgeTranscriptQuiet(t *testing.T, restart bool, path string) {
+	t.Helper()
+	origQuiet, origRestart := forgeQuiet, forgeRestart
+	forgeQuiet, forgeRestart = true, restart
+	t.Cleanup(func() { forgeQuiet, forgeRestart = origQuiet, origRestart })
+	if err := runForgeTranscript(forgeTranscriptCmd, []string{path}); err != nil {
+		t.Fatalf("forge transcript: %v", err)
+	}
+}
+
+func readForgeCursor(t *testing.T, dir, path string) *forgeCursor {
+	t.Helper()
+	cursors, err := loadForgeCursors(filepath.Join(dir, storage.DefaultBaseDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cursors[path]
+}
+
+// forgeOutputCounts returns how many pending learnings and provenance
+// records forge has written under dir.
+func forgeOutputCounts(t *testing.T, dir string) (pending, provenance int) {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(dir, ".agents", "knowledge", "pending"))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, storage.DefaultBaseDir, storage.ProvenanceDir, storage.ProvenanceFile))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return len(entries), bytes.Count(data, []byte("\n"))
+}
+
+func TestForgeTranscript_ResumesAppendedTranscript(t *testing.T) {
+	dir := chdirTemp(t)
+	path := filepath.Join(dir, "ses_cursor1.jsonl")
+	half := strings.Index(forgeCursorTranscript, forgeLine("user", "And the cache?"))
+	if err := os.WriteFile(path, []byte(forgeCursorTranscript[:half]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runForgeTranscriptQuiet(t, false, path)
+	first := readForgeCursor(t, dir, path)
+	if first == nil || first.Offset != int64(half) || first.Line != 2 || first.SessionPath == "" {
+		t.Fatalf("cursor after first run = %+v, want offset %d line 2 with a session path", first, half)
+	}
+	firstPending, _ := forgeOutputCounts(t, dir)
+	if firstPending == 0 {
+		t.Fatal("first run should write pending learnings")
+	}
+
+	// Nothing new: the rerun is a no-op.
+	runForgeTranscriptQuiet(t, false, path)
+	if again := readForgeCursor(t, dir, path); !again.UpdatedAt.Equal(first.UpdatedAt) {
+		t.Errorf("unchanged transcript was re-forged")
+	}
+
+	// The session keeps growing; only the new lines are read, and the
+	// session carries knowledge from both runs.
+	if err := os.WriteFile(path, []byte(forgeCursorTranscript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runForgeTranscriptQuiet(t, false, path)
+	second := readForgeCursor(t, dir, path)
+	if second.Offset != int64(len(forgeCursorTranscript)) || second.Line != 4 {
+		t.Fatalf("cursor after resume = %+v", second)
+	}
+	if second.SessionPath != first.SessionPath {
+		t.Errorf("session path changed on resume: %s -> %s", first.SessionPath, second.SessionPath)
+	}
+	if len(second.Decisions) != 1 || len(second.Knowledge) == 0 || second.ChatMessages != 4 {
+		t.Errorf("resumed state lost earlier extraction: %+v", second)
+	}
+
+	data, err := os.ReadFile(strings.TrimSuffix(second.SessionPath, filepath.Ext(second.SessionPath)) + ".jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "exponential backoff") || !strings.Contains(string(data), "invalidate the cache") {
+		t.Errorf("session should hold knowledge from both runs:\n%s", data)
+	}
+
+	// Only the new learnings were written; the session's provenance was not
+	// recorded a second time.
+	pending, provenance := forgeOutputCounts(t, dir)
+	if want := firstPending + len(second.Knowledge) + len(second.Decisions) - len(first.Knowledge) - len(first.Decisions); pending != want {
+		t.Errorf("pending learnings = %d, want %d", pending, want)
+	}
+	if provenance != 1 {
+		t.Errorf("provenance records = %d, want 1", provenance)
+	}
+	if second.WrittenKnowledge != len(second.Knowledge) || second.WrittenDecisions != len(second.Decisions) {
+		t.Errorf("cursor should mark everything written: %+v", second)
+	}
+}
+
+func TestForgeTranscript_RewrittenTranscriptStartsOver(t *testing.T) {
+	dir := chdirTemp(t)
+	path := filepath.Join(dir, "ses_cursor1.jsonl")
+	if err := os.WriteFile(path, []byte(forgeCursorTranscript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runForgeTranscriptQuiet(t, false, path)
+
+	rewritten := forgeLine("user", "A different session entirely") + forgeLine("assistant", "Sure.")
+	if err := os.WriteFile(path, []byte(rewritten+forgeCursorTranscript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runForgeTranscriptQuiet(t, false, path)
+	if c := readForgeCursor(t, dir, path); c.ChatMessages != 6 {
+		t.Errorf("rewritten file should be re-forged from the start, chat messages = %d", c.ChatMessages)
+	}
+}
+
+func TestForgeTranscript_RestartIgnoresCursor(t *testing.T) {
+	dir := chdirTemp(t)
+	path := filepath.Join(dir, "ses_cursor1.jsonl")
+	if err := os.WriteFile(path, []byte(forgeCursorTranscript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runForgeTranscriptQuiet(t, false, path)
+	first := readForgeCursor(t, dir, path)
+	firstPending, _ := forgeOutputCounts(t, dir)
+
+	runForgeTranscriptQuiet(t, true, path)
+	if again := readForgeCursor(t, dir, path); again.UpdatedAt.Equal(first.UpdatedAt) || again.ChatMessages != 4 {
+		t.Errorf("--restart should re-forge the whole transcript: %+v", again)
+	}
+	if pending, provenance := forgeOutputCounts(t, dir); pending != firstPending || provenance != 1 {
+		t.Errorf("re-forging rewrote outputs: %d pending (want %d), %d provenance (want 1)", pending, firstPending, provenance)
+	}
+}
+
+func TestStreamTranscript_CheckpointResumeMatchesFullRun(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ses_cursor1.jsonl")
+	if err := os.WriteFile(path, []byte(forgeCursorTranscript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	orig := forgeCheckpointBytes
+	forgeCheckpointBytes = 1
+	t.Cleanup(func() { forgeCheckpointBytes = orig })
+
+	p := parser.NewParser()
+	p.MaxContentLength = 0
+	extractor := parser.NewExtractor()
+	var checkpoints []*forgeCursor
+	var buf bytes.Buffer
+	full, _, err := streamTranscript(path, p, extractor, nil, true, &buf, func(c *forgeCursor) {
+		checkpoints = append(checkpoints, c)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoints) != 4 {
+		t.Fatalf("got %d checkpoints, want one per message", len(checkpoints))
+	}
+
+	// Simulate a crash after the second message and resume from there.
+	crashed := checkpoints[1]
+	crashed.Path = path
+	resumed, next, err := streamTranscript(path, p, extractor, crashed, true, &buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Offset != int64(len(forgeCursorTranscript)) {
+		t.Errorf("resumed cursor offset = %d", next.Offset)
+	}
+	if strings.Join(resumed.Decisions, "|") != strings.Join(full.Decisions, "|") ||
+		strings.Join(resumed.Knowledge, "|") != strings.Join(full.Knowledge, "|") ||
+		resumed.ID != full.ID || !resumed.Date.Equal(full.Date) {
+		t.Errorf("resumed session differs from a full run:\nfull:    %+v\nresumed: %+v", full, resumed)
+	}
+}
@@ -1718,6 +1718,37 @@ func TestForge_ProcessTranscript(t *testing.T) {
 		}
 	})
 
+	t.Run("opencode export is forgeable", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "export.json")
+		content := `{
+  "info": {"id": "ses_oc42", "time": {"created": 1767261600000}},
+  "messages": [
+    {"info": {"sessionID": "ses_oc42", "role": "user", "time": {"created": 1767261600000}},
+     "parts": [{"type": "text", "text": "Why is the cache stale?"}]},
+    {"info": {"sessionID": "ses_oc42", "role": "assistant", "time": {"created": 1767261605000}},
+     "parts": [{"type": "text", "text": "The fix is to invalidate on write because readers hold old entries."},
+               {"type": "tool", "tool": "edit", "state": {"status": "completed", "input": {"filePath": "cache.go"}}}]}
+  ]
+}
+`
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		session, err := processTranscript(path, p, extractor, true, &buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if session.ID != "ses_oc42" {
+			t.Fatalf("session ID = %q, want ses_oc42", session.ID)
+		}
+		if session.Date.Year() != 2026 {
+			t.Fatalf("session date = %v, want the export's message time", session.Date)
+		}
+	})
+
 	t.Run("oversized Claude transcript line parses successfully", func(t *testing.T) {
 		dir := t.TempDir()
 		path := filepath.Join(dir, "ses_large.jsonl")
@@ -1877,7 +1908,7 @@ func TestForge_ForgeTranscriptFile(t *testing.T) {
 	}
 
 	totals := forgeTotals{}
-	forgeTranscriptFile(fs, session, "/in/session.jsonl", baseDir, dir, &totals)
+	forgeTranscriptFile(fs, session, nil, "/in/session.jsonl", baseDir, dir, &totals)
 
 	if totals.sessions != 1 {
 		t.Errorf("totals.sessions = %d, want 1", totals.sessions)
@@ -2035,7 +2066,7 @@ func TestForge_ForgeTranscriptFileWithQueue(t *testing.T) {
 	}
 
 	totals := forgeTotals{}
-	forgeTranscriptFile(fs, session, "/in/session.jsonl", baseDir, dir, &totals)
+	forgeTranscriptFile(fs, session, nil, "/in/session.jsonl", baseDir, dir, &totals)
 
 	// Verify pending.jsonl was created
 	pendingPath := filepath.Join(dir, storage.DefaultBaseDir, "pending.jsonl")
@@ -1,23 +1,37 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/boshu2/agentops/cli/internal/storage"
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
 )
 
 // writePendingLearnings writes forge-extracted knowledge as markdown files
 // to .agents/knowledge/pending/ for pool ingestion by close-loop.
 // This bridges the gap between forge output and pool ingest input.
+//
+// Each file is named after a hash of its normalized text, so forging the
+// same session again (a resumed or incremental run) finds the learnings it
+// already wrote and skips them. It returns the number of new files.
 func writePendingLearnings(session *storage.Session, baseDir string) (int, error) {
 	if session == nil {
 		return 0, nil
 	}
 
+	tax, err := taxonomy.Load(baseDir)
+	if err != nil {
+		return 0, err
+	}
+
 	// Collect all items: knowledge + decisions
 	type item struct {
 		text     string
@@ -29,7 +43,7 @@ func writePendingLearnings(session *storage.Session, baseDir string) (int, error
 		if k == "" {
 			continue
 		}
-		items = append(items, item{text: k, category: inferCategory(k)})
+		items = append(items, item{text: k, category: pendingCategory(k, tax)})
 	}
 	for _, d := range session.Decisions {
 		d = strings.TrimSpace(d)
@@ -54,10 +68,10 @@ func writePendingLearnings(session *storage.Session, baseDir string) (int, error
 		dateStr = time.Now().Format("2006-01-02")
 	}
 
-	// Guard against empty session ID — generate a short hash from content
+	// Guard against empty session ID — the content hash keeps names unique
 	sessionShort := session.ID
 	if sessionShort == "" {
-		sessionShort = fmt.Sprintf("anon%d", time.Now().UnixNano()%100000)
+		sessionShort = "anon"
 	}
 	if len(sessionShort) > 7 {
 		sessionShort = sessionShort[:7]
@@ -66,10 +80,15 @@ func writePendingLearnings(session *storage.Session, baseDir string) (int, error
 	sessionShort = sanitizePathComponent(sessionShort)
 
 	written := 0
-	for i, it := range items {
+	for _, it := range items {
+		id := fmt.Sprintf("%s-%s-%s", dateStr, sessionShort, pendingContentHash(it.text))
+		filename := id + ".md"
+		path := filepath.Join(pendingDir, filename)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+
 		title := pendingTitle(it.text)
-		id := fmt.Sprintf("%s-%s-%d", dateStr, sessionShort, i+1)
-		filename := fmt.Sprintf("%s-%s-%d.md", dateStr, sessionShort, i+1)
 
 		safeText := escapeFrontmatterText(it.text)
 		safeSessionID := sanitizePathComponent(session.ID)
@@ -93,7 +112,6 @@ source: %s
 - **Session**: %s
 `, dateStr, it.category, safeSessionID, title, id, it.category, safeText, safeSessionID)
 
-		path := filepath.Join(pendingDir, filename)
 		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
 			return written, fmt.Errorf("write %s: %w", filename, err)
 		}
@@ -103,6 +121,27 @@ source: %s
 	return written, nil
 }
 
+// pendingContentHash is a short hash of text with case and whitespace
+// normalized, used as the stable part of a pending learning's ID.
+func pendingContentHash(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:4])
+}
+
+// pendingCategory labels knowledge with a type the repo taxonomy adds when
+// the text carries that label ("Gotcha: ..."), so ao pool ingest scores it
+// with that type's base score. Otherwise it falls back to inferCategory.
+func pendingCategory(text string, tax *taxonomy.Taxonomy) string {
+	lower := strings.ToLower(text)
+	for _, kt := range slices.Sorted(maps.Keys(tax.KnowledgeTypes)) {
+		if _, custom := tax.CustomType(string(kt)); custom && strings.Contains(lower, string(kt)+":") {
+			return string(kt)
+		}
+	}
+	return inferCategory(text)
+}
+
 // inferCategory guesses the knowledge type from content.
 func inferCategory(text string) string {
 	lower := strings.ToLower(text)
@@ -87,6 +87,32 @@ func TestWritePendingLearnings_IncludesDecisions(t *testing.T) {
 	}
 }
 
+func TestWritePendingLearnings_StableIDsSkipExisting(t *testing.T) {
+	dir := t.TempDir()
+	session := &storage.Session{
+		ID:        "stable-ids-abc",
+		Date:      time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC),
+		Knowledge: []string{"Retry with exponential backoff"},
+	}
+	if n, err := writePendingLearnings(session, dir); err != nil || n != 1 {
+		t.Fatalf("first write = %d, %v", n, err)
+	}
+
+	// A later forge of the same session sees a new item ahead of the old one.
+	session.Knowledge = []string{"Invalidate the cache on write", "  retry with  Exponential backoff"}
+	n, err := writePendingLearnings(session, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("second write = %d, want only the new learning", n)
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, ".agents", "knowledge", "pending"))
+	if len(entries) != 2 {
+		t.Errorf("pending files = %d, want 2", len(entries))
+	}
+}
+
 func TestWritePendingLearnings_EmptySession(t *testing.T) {
 	dir := t.TempDir()
 	session := &storage.Session{
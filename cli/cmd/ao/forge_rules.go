@@ -0,0 +1,207 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/boshu2/agentops/cli/internal/parser"
+)
+
+var (
+	forgeRulesPath    string
+	forgeRulesDialect string
+)
+
+var forgeRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Inspect extraction rules",
+	Long: `Inspect the rules forge uses to pull knowledge out of messages.
+
+Rules live in .agents/extraction-rules.yaml and extend the built-in
+decision, solution, learning, failure and reference rules. Each rule names a
+knowledge type, the roles it applies to (user, assistant, tool), keywords
+and/or regexes, and a base score. The type must be a built-in knowledge type
+or one defined in .agents/taxonomy.yaml (gotcha below needs an entry there):
+
+  rules:
+    - name: gotcha
+      type: gotcha
+      roles: [assistant]
+      keywords: ["watch out", "gotcha"]
+      patterns: ['(?i)turns out .+ (?:silently|quietly)']
+      score: 0.7
+    - name: reference
+      disabled: true
+
+A rule named after a built-in one replaces it; disabled: true removes it.
+Set replace: true at the top level to drop the built-in rules entirely.
+
+Examples:
+  ao forge rules test ~/.claude/projects/myproj/session.jsonl
+  ao forge rules test session.jsonl --rules /tmp/draft-rules.yaml -o json`,
+}
+
+var forgeRulesTestCmd = &cobra.Command{
+	Use:   "test <transcript>",
+	Short: "Show which extraction rules fire on a transcript",
+	Long: `Run the extraction rules over a transcript without forging it.
+
+Every rule match is listed with the message it fired on, so a new rule can
+be tuned against a real session before it changes what forge extracts.
+Forge itself keeps only the best match per type for each message; this
+listing shows all of them. Rules that never fired are reported at the end.
+
+Examples:
+  ao forge rules test session.jsonl
+  ao forge rules test session.jsonl --rules draft-rules.yaml
+  ao forge rules test export.json --dialect opencode -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runForgeRulesTest,
+}
+
+func init() {
+	forgeCmd.AddCommand(forgeRulesCmd)
+	forgeRulesCmd.AddCommand(forgeRulesTestCmd)
+
+	forgeRulesTestCmd.Flags().StringVar(&forgeRulesPath, "rules", "", "Rules file (default: "+parser.ExtractionRulesFile+")")
+	forgeRulesTestCmd.Flags().StringVar(&forgeRulesDialect, "dialect", "", "Transcript dialect ("+strings.Join(parser.DialectNames(), ", ")+"); detected when empty")
+}
+
+// ruleHit is one rule firing on one message.
+type ruleHit struct {
+	Message int     `json:"message"`
+	Role    string  `json:"role"`
+	Rule    string  `json:"rule"`
+	Type    string  `json:"type"`
+	Score   float64 `json:"score"`
+	Match   string  `json:"match"`
+	Snippet string  `json:"snippet"`
+}
+
+// ruleTestReport is the result of ao forge rules test.
+type ruleTestReport struct {
+	Transcript string         `json:"transcript"`
+	Dialect    string         `json:"dialect"`
+	Rules      string         `json:"rules"`
+	Messages   int            `json:"messages"`
+	Hits       []ruleHit      `json:"hits"`
+	Counts     map[string]int `json:"counts"`
+	Unfired    []string       `json:"unfired,omitempty"`
+}
+
+func runForgeRulesTest(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	rulesPath := forgeRulesPath
+	if rulesPath == "" {
+		rulesPath = filepath.Join(cwd, parser.ExtractionRulesFile)
+	} else if _, err := os.Stat(rulesPath); err != nil {
+		// An explicit file must exist; only the default falls back silently.
+		return fmt.Errorf("rules file: %w", err)
+	}
+	extractor, err := loadExtractionRules(cwd, rulesPath)
+	if err != nil {
+		return err
+	}
+
+	p := parser.NewParser()
+	p.MaxContentLength = 0
+	if forgeRulesDialect != "" {
+		if p.Dialect, err = parser.LookupDialect(forgeRulesDialect); err != nil {
+			return err
+		}
+	}
+	result, err := p.ParseFile(args[0])
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", args[0], err)
+	}
+
+	report := testExtractionRules(extractor, result)
+	report.Transcript = args[0]
+	report.Rules = rulesPath
+
+	w := cmd.OutOrStdout()
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return printRuleTestReport(w, report)
+}
+
+// testExtractionRules runs every rule over every message in result.
+func testExtractionRules(extractor *parser.Extractor, result *parser.ParseResult) *ruleTestReport {
+	report := &ruleTestReport{
+		Dialect:  result.Dialect,
+		Messages: len(result.Messages),
+		Hits:     []ruleHit{},
+		Counts:   make(map[string]int, len(extractor.Patterns)),
+	}
+	for i, msg := range result.Messages {
+		if msg.Content == "" {
+			continue
+		}
+		for _, r := range extractor.ExtractAll(msg) {
+			report.Hits = append(report.Hits, ruleHit{
+				Message: i + 1,
+				Role:    parser.MessageRole(msg),
+				Rule:    r.Rule,
+				Type:    string(r.Type),
+				Score:   r.Score,
+				Match:   cmp.Or(r.MatchedPattern, r.MatchedKeyword),
+				Snippet: extractSnippet(msg.Content, r.StartIndex, 80),
+			})
+			report.Counts[r.Rule]++
+		}
+	}
+	for _, pattern := range extractor.Patterns {
+		if name := pattern.RuleName(); report.Counts[name] == 0 {
+			report.Unfired = append(report.Unfired, name)
+		}
+	}
+	return report
+}
+
+func printRuleTestReport(w io.Writer, report *ruleTestReport) error {
+	fmt.Fprintf(w, "Transcript: %s (%s, %d messages)\n", report.Transcript, report.Dialect, report.Messages)
+	fmt.Fprintf(w, "Rules:      %s\n\n", report.Rules)
+
+	if len(report.Hits) == 0 {
+		fmt.Fprintln(w, "No rules fired.")
+	} else {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		//nolint:errcheck // CLI tabwriter output
+		fmt.Fprintln(tw, "MSG\tROLE\tRULE\tTYPE\tSCORE\tMATCH\tSNIPPET")
+		for _, h := range report.Hits {
+			//nolint:errcheck // CLI tabwriter output
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%.2f\t%s\t%s\n",
+				h.Message, h.Role, h.Rule, h.Type, h.Score,
+				truncateString(h.Match, 30), strings.Join(strings.Fields(h.Snippet), " "))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Fired:")
+	for _, name := range slices.Sorted(maps.Keys(report.Counts)) {
+		fmt.Fprintf(w, "  %-16s %d\n", name, report.Counts[name])
+	}
+	if len(report.Unfired) > 0 {
+		fmt.Fprintf(w, "Never fired: %s\n", strings.Join(report.Unfired, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/parser"
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
+)
+
+const gotchaRules = `rules:
+  - name: gotcha
+    type: gotcha
+    roles: [assistant]
+    keywords: ["watch out"]
+    score: 0.7
+  - name: reference
+    disabled: true
+`
+
+func writeGotchaFixture(t *testing.T, dir string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".agents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, parser.ExtractionRulesFile), []byte(gotchaRules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, taxonomy.TaxonomyFile), []byte("knowledge_types:\n  gotcha: {base_score: 0.4}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	transcript := forgeLine("user", "Watch out for the flaky test, please.") +
+		forgeLine("assistant", "Watch out: the migration locks the table. We decided to use batches because of that.")
+	path := filepath.Join(dir, "ses_rules.jsonl")
+	if err := os.WriteFile(path, []byte(transcript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTestExtractionRules(t *testing.T) {
+	dir := t.TempDir()
+	path := writeGotchaFixture(t, dir)
+	extractor, err := loadExtractionRules(dir, filepath.Join(dir, parser.ExtractionRulesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := parser.NewParser().ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := testExtractionRules(extractor, result)
+	var fired []string
+	for _, h := range report.Hits {
+		fired = append(fired, h.Role+":"+h.Rule)
+	}
+	// The user message says "watch out" too, but the rule is assistant-only.
+	// The decision rule fires on both its keyword and its regex.
+	if got := strings.Join(fired, ","); got != "assistant:decision,assistant:decision,assistant:gotcha" {
+		t.Errorf("hits = %s", got)
+	}
+	if strings.Join(report.Unfired, ",") != "solution,learning,failure" {
+		t.Errorf("unfired = %v", report.Unfired)
+	}
+
+	var buf bytes.Buffer
+	report.Transcript = path
+	if err := printRuleTestReport(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"MSG", "gotcha", "watch out", "Never fired: solution, learning, failure"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestForgeRulesTest_JSON(t *testing.T) {
+	dir := chdirTemp(t)
+	path := writeGotchaFixture(t, dir)
+
+	out, err := executeCommand("forge", "rules", "test", path, "-o", "json")
+	if err != nil {
+		t.Fatalf("forge rules test: %v\n%s", err, out)
+	}
+	var report ruleTestReport
+	if err := json.Unmarshal([]byte(out[strings.Index(out, "{"):]), &report); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	if report.Dialect != "claude" || report.Messages != 2 || report.Counts["gotcha"] != 1 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestForgeTranscript_CustomRuleTypeIsKnowledge(t *testing.T) {
+	dir := chdirTemp(t)
+	path := writeGotchaFixture(t, dir)
+	runForgeTranscriptQuiet(t, false, path)
+
+	c := readForgeCursor(t, dir, path)
+	if c == nil || len(c.Decisions) != 1 || len(c.Knowledge) != 1 || !strings.Contains(c.Knowledge[0], "migration locks") {
+		t.Errorf("custom gotcha should be forged as knowledge: %+v", c)
+	}
+}
+
+func TestForgeRulesTest_RuleTypeMustBeInTaxonomy(t *testing.T) {
+	dir := chdirTemp(t)
+	path := writeGotchaFixture(t, dir)
+	if err := os.Remove(filepath.Join(dir, taxonomy.TaxonomyFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := executeCommand("forge", "rules", "test", path)
+	if err == nil || !strings.Contains(err.Error(), `rule "gotcha": unknown knowledge type "gotcha"`) {
+		t.Errorf("err = %v, want the rule and type named\n%s", err, out)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/boshu2/agentops/cli/internal/pool"
+	"github.com/boshu2/agentops/cli/internal/types"
 )
 
 var (
@@ -18,15 +19,29 @@ var (
 	gateReason    string
 	gateOlderThan string
 	gateTier      string
+	gateMine      bool
 )
 
 var gateCmd = &cobra.Command{
 	Use:   "gate",
 	Short: "Human review gates",
-	Long: `Manage human review gates for bronze-tier candidates.
+	Long: `Manage human review gates for candidates that need them.
 
-Bronze-tier candidates (score 0.50-0.69) require human review
-before promotion. The gate command provides the review interface.
+Candidates scored into a tier whose taxonomy sets human_gate (bronze,
+score 0.50-0.69, by default; see .agents/taxonomy.yaml) require human
+review before promotion. The gate command provides the review interface.
+
+A .agents/gate-policy.yaml can require several distinct approvals per
+tier and restrict who reviews each knowledge type:
+
+  quorum:
+    gold: 2
+  reviewers:
+    decision: [alice, bob, carol]
+
+Promotion is blocked until a tier's quorum is met. Rejections short of
+the quorum are recorded as dissent. Reviewer lists may name any knowledge
+type defined in .agents/taxonomy.yaml.
 
 Examples:
   ao gate pending
@@ -37,13 +52,17 @@ Examples:
 var gatePendingCmd = &cobra.Command{
 	Use:   "pending",
 	Short: "List candidates pending review",
-	Long: `List bronze-tier candidates awaiting human review.
+	Long: `List candidates awaiting the human gate of their tier (bronze by default).
 
 Shows age/urgency with oldest items first.
 Highlights items approaching 24h auto-promote threshold.
+Candidates of tiers with a gate policy quorum are listed until the
+quorum is met. --mine keeps only candidates you may review and have not
+reviewed yet.
 
 Examples:
   ao gate pending
+  ao gate pending --mine
   ao gate pending --json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if GetDryRun() {
@@ -62,11 +81,29 @@ Examples:
 		if err != nil {
 			return fmt.Errorf("list pending: %w", err)
 		}
+		if gateMine {
+			policy, err := p.GatePolicy()
+			if err != nil {
+				return err
+			}
+			entries = filterAssignedEntries(entries, policy, GetCurrentUser())
+		}
 
 		return outputGatePending(entries)
 	},
 }
 
+// filterAssignedEntries keeps the entries waiting on reviewer.
+func filterAssignedEntries(entries []pool.PoolEntry, policy *pool.GatePolicy, reviewer string) []pool.PoolEntry {
+	var mine []pool.PoolEntry
+	for _, e := range entries {
+		if policy.AssignedTo(reviewer, e) {
+			mine = append(mine, e)
+		}
+	}
+	return mine
+}
+
 func outputGatePending(entries []pool.PoolEntry) error {
 	switch GetOutput() {
 	case "json":
@@ -108,17 +145,18 @@ func outputGatePendingTable(entries []pool.PoolEntry) error {
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	//nolint:errcheck // CLI tabwriter output to stdout, errors unlikely and non-recoverable
-	fmt.Fprintln(w, "ID\tTIER\tAGE\tUTILITY\tURGENCY")
+	fmt.Fprintln(w, "ID\tTIER\tAGE\tUTILITY\tAPPROVALS\tURGENCY")
 	//nolint:errcheck // CLI tabwriter output to stdout
-	fmt.Fprintln(w, "--\t----\t---\t-------\t-------")
+	fmt.Fprintln(w, "--\t----\t---\t-------\t---------\t-------")
 
 	for _, e := range entries {
 		//nolint:errcheck // CLI tabwriter output to stdout
-		fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\t%s\n",
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\t%s\t%s\n",
 			truncateID(e.Candidate.ID, 16),
 			e.Candidate.Tier,
 			e.AgeString,
 			e.Candidate.Utility,
+			reviewProgress(e.HumanReview, e.Quorum),
 			entryUrgency(e),
 		)
 	}
@@ -131,6 +169,15 @@ func outputGatePendingTable(entries []pool.PoolEntry) error {
 	return nil
 }
 
+// reviewProgress renders approvals against quorum, noting any dissent.
+func reviewProgress(hr *types.HumanReview, quorum int) string {
+	progress := fmt.Sprintf("%d/%d", hr.Approvals(), max(1, quorum))
+	if d := hr.Dissents(); d > 0 {
+		progress += fmt.Sprintf(" (%d dissent)", d)
+	}
+	return progress
+}
+
 // printAutoPromoteWarning prints a warning if any entries are approaching auto-promote.
 func printAutoPromoteWarning(entries []pool.PoolEntry) {
 	fmt.Println()
@@ -148,9 +195,11 @@ func printAutoPromoteWarning(entries []pool.PoolEntry) {
 var gateApproveCmd = &cobra.Command{
 	Use:   "approve <candidate-id>",
 	Short: "Approve candidate for promotion",
-	Long: `Approve a bronze-tier candidate for promotion.
+	Long: `Approve a gated candidate for promotion.
 
-Records reviewer identity and triggers promotion flow.
+Records reviewer identity and triggers promotion flow. When the gate
+policy sets a quorum for the candidate's tier, each approval must come
+from a different reviewer and promotion waits for the quorum.
 
 Examples:
   ao gate approve cand-abc123
@@ -188,6 +237,22 @@ Examples:
 			fmt.Printf("Note: %s\n", gateNote)
 		}
 
+		entry, err := p.Get(candidateID)
+		if err != nil {
+			return err
+		}
+		policy, err := p.GatePolicy()
+		if err != nil {
+			return err
+		}
+		quorum := max(1, policy.EntryQuorum(entry))
+		fmt.Printf("Approvals: %s\n", reviewProgress(entry.HumanReview, quorum))
+		if approvals := entry.HumanReview.Approvals(); approvals < quorum {
+			fmt.Println()
+			fmt.Printf("Quorum not met: %d more approval(s) needed before promotion\n", quorum-approvals)
+			return nil
+		}
+
 		// Suggest next step
 		fmt.Println()
 		fmt.Printf("To promote: ao pool promote %s\n", candidateID)
@@ -201,7 +266,10 @@ var gateRejectCmd = &cobra.Command{
 	Short: "Reject candidate",
 	Long: `Reject a candidate with a required reason.
 
-Records in audit trail for future analysis.
+Records in audit trail for future analysis. When the gate policy sets
+a quorum for the candidate's tier, the candidate is rejected only once
+that many reviewers reject it; until then the rejection is recorded as
+dissent.
 
 Examples:
   ao gate reject cand-abc123 --reason="Lacks specificity"
@@ -233,7 +301,16 @@ Examples:
 			return fmt.Errorf("reject candidate: %w", err)
 		}
 
-		fmt.Printf("Rejected: %s\n", candidateID)
+		entry, err := p.Get(candidateID)
+		if err != nil {
+			return err
+		}
+		if entry.Status == types.PoolStatusRejected {
+			fmt.Printf("Rejected: %s\n", candidateID)
+		} else {
+			fmt.Printf("Dissent recorded: %s (%d dissenting review(s), quorum not reached)\n",
+				candidateID, entry.HumanReview.Dissents())
+		}
 		fmt.Printf("Reviewer: %s\n", reviewer)
 		fmt.Printf("Reason: %s\n", gateReason)
 
@@ -315,6 +392,7 @@ func init() {
 	gateCmd.AddCommand(gateBulkApproveCmd)
 
 	// Add flags
+	gatePendingCmd.Flags().BoolVar(&gateMine, "mine", false, "Only show candidates assigned to you that you have not reviewed")
 	gateApproveCmd.Flags().StringVar(&gateNote, "note", "", "Optional approval note")
 	gateRejectCmd.Flags().StringVar(&gateReason, "reason", "", "Required rejection reason")
 	_ = gateRejectCmd.MarkFlagRequired("reason") //nolint:errcheck
@@ -0,0 +1,515 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/boshu2/agentops/cli/internal/parser"
+	"github.com/boshu2/agentops/cli/internal/pool"
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+var gateReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review pending candidates in a full-screen terminal UI",
+	Long: `Review pending candidates one at a time in a full-screen terminal UI.
+
+Lists the same candidates as ao gate pending with their rubric scores,
+content and an excerpt of the source transcript. Decisions go through the
+same pool operations as ao gate approve and ao gate reject, so they are
+recorded in the pool chain and respect the gate policy quorum. Changing a
+candidate's tier needs the same reviewer assignment, and a candidate moved
+down keeps the quorum of the highest tier it held.
+
+Keys:
+  a        approve
+  r        reject (prompts for a reason)
+  s        skip to the next candidate
+  t        change tier (g/s/b)
+  j / k    next / previous candidate
+  q        quit
+
+Examples:
+  ao gate review`,
+	Args: cobra.NoArgs,
+	RunE: runGateReview,
+}
+
+func init() {
+	gateCmd.AddCommand(gateReviewCmd)
+}
+
+// ANSI sequences for the full-screen view.
+const (
+	ansiAltScreenOn  = "\x1b[?1049h"
+	ansiAltScreenOff = "\x1b[?1049l"
+	ansiClearScreen  = "\x1b[H\x1b[2J"
+	ansiReverse      = "\x1b[7m"
+	ansiReset        = "\x1b[0m"
+)
+
+// Review keys. Ctrl-C arrives as a byte in raw mode.
+const (
+	keyCtrlC     = 3
+	keyBackspace = 8
+	keyEnter     = '\r'
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// excerptRadius is how many transcript messages around the source message
+// the excerpt shows.
+const excerptRadius = 2
+
+func runGateReview(cmd *cobra.Command, args []string) error {
+	if GetDryRun() {
+		fmt.Println("[dry-run] Would open the interactive review queue")
+		return nil
+	}
+	if !isTerminal() || !stdinIsTerminal() {
+		return errors.New("ao gate review needs an interactive terminal; use ao gate pending, approve and reject instead")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	r := newGateReviewer(pool.NewPool(cwd), GetCurrentUser())
+	if err := r.reload(); err != nil {
+		return err
+	}
+	if len(r.entries) == 0 {
+		fmt.Println("No pending reviews")
+		return nil
+	}
+	r.height, r.width = terminalSize()
+
+	if err := runGateReviewTerminal(r); err != nil {
+		return err
+	}
+	fmt.Println(r.summary())
+	return nil
+}
+
+// runGateReviewTerminal runs r on the alternate screen in raw mode. The
+// terminal is restored on return, including when the session panics.
+func runGateReviewTerminal(r *gateReviewer) error {
+	restore, err := enterRawMode()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		fmt.Print(ansiAltScreenOff)
+		restore()
+	}()
+	fmt.Print(ansiAltScreenOn)
+	return r.run(bufio.NewReader(os.Stdin), os.Stdout)
+}
+
+// gateReviewer is the state of an interactive review session. It reads
+// keys from any reader and renders to any writer, so it runs the same
+// against a raw terminal and in tests.
+type gateReviewer struct {
+	pool     *pool.Pool
+	reviewer string
+	entries  []pool.PoolEntry
+	cursor   int
+	skipped  map[string]bool
+	excerpts map[string][]string
+	status   string
+
+	width, height int
+
+	approved, rejected, retiered int
+}
+
+func newGateReviewer(p *pool.Pool, reviewer string) *gateReviewer {
+	return &gateReviewer{
+		pool:     p,
+		reviewer: reviewer,
+		skipped:  make(map[string]bool),
+		excerpts: make(map[string][]string),
+		width:    80,
+		height:   24,
+	}
+}
+
+// reload refreshes the queue, keeping the cursor on the same candidate
+// when it is still pending.
+func (r *gateReviewer) reload() error {
+	currentID := ""
+	if e := r.current(); e != nil {
+		currentID = e.Candidate.ID
+	}
+	entries, err := r.pool.ListPendingReview()
+	if err != nil {
+		return fmt.Errorf("list pending: %w", err)
+	}
+	r.entries = entries
+	for i, e := range entries {
+		if e.Candidate.ID == currentID {
+			r.cursor = i
+			return nil
+		}
+	}
+	r.cursor = min(r.cursor, max(0, len(entries)-1))
+	return nil
+}
+
+// current returns the candidate under the cursor, or nil when the queue is empty.
+func (r *gateReviewer) current() *pool.PoolEntry {
+	if r.cursor < 0 || r.cursor >= len(r.entries) {
+		return nil
+	}
+	return &r.entries[r.cursor]
+}
+
+// run renders and handles keys until the user quits, input ends or the
+// queue is empty.
+func (r *gateReviewer) run(in *bufio.Reader, out io.Writer) error {
+	for {
+		r.render(out)
+		if len(r.entries) == 0 {
+			return nil
+		}
+		key, err := in.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		quit, err := r.handleKey(key, in, out)
+		if err != nil {
+			return err
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// handleKey applies one key press. Pool errors become the status line
+// rather than ending the session; only I/O errors are returned.
+func (r *gateReviewer) handleKey(key byte, in *bufio.Reader, out io.Writer) (quit bool, err error) {
+	entry := r.current()
+	if entry == nil {
+		return true, nil
+	}
+	id := entry.Candidate.ID
+
+	switch key {
+	case 'q', keyCtrlC:
+		return true, nil
+	case 'j', 'n':
+		r.move(1)
+	case 'k', 'p':
+		r.move(-1)
+	case 's':
+		r.skipped[id] = true
+		r.status = "Skipped " + id
+		r.move(1)
+	case 'a':
+		if err := r.pool.Approve(id, "", r.reviewer); err != nil {
+			r.status = "Approve failed: " + err.Error()
+			return false, nil
+		}
+		r.approved++
+		r.status = "Approved " + id
+		return false, r.reload()
+	case 'r':
+		r.render(out)
+		reason, ok, err := readLine(in, out, "Reject reason (Enter to confirm, Esc to cancel): ")
+		if err != nil {
+			return false, err
+		}
+		if !ok || strings.TrimSpace(reason) == "" {
+			r.status = "Reject cancelled"
+			return false, nil
+		}
+		if err := r.pool.Reject(id, strings.TrimSpace(reason), r.reviewer); err != nil {
+			r.status = "Reject failed: " + err.Error()
+			return false, nil
+		}
+		r.rejected++
+		r.status = "Rejected " + id
+		return false, r.reload()
+	case 't':
+		r.status = "New tier: [g]old [s]ilver [b]ronze, any other key cancels"
+		r.render(out)
+		choice, err := in.ReadByte()
+		if err != nil {
+			return false, err
+		}
+		tier, ok := map[byte]types.Tier{'g': types.TierGold, 's': types.TierSilver, 'b': types.TierBronze}[choice]
+		if !ok {
+			r.status = "Tier change cancelled"
+			return false, nil
+		}
+		if err := r.pool.Retier(id, tier, r.reviewer); err != nil {
+			r.status = "Tier change failed: " + err.Error()
+			return false, nil
+		}
+		r.retiered++
+		r.status = fmt.Sprintf("Moved %s to %s", id, tier)
+		return false, r.reload()
+	default:
+		r.status = fmt.Sprintf("Unknown key %q", key)
+	}
+	return false, nil
+}
+
+// move shifts the cursor, wrapping around the queue.
+func (r *gateReviewer) move(delta int) {
+	if n := len(r.entries); n > 0 {
+		r.cursor = ((r.cursor+delta)%n + n) % n
+	}
+}
+
+// summary is printed after the session ends.
+func (r *gateReviewer) summary() string {
+	return fmt.Sprintf("Reviewed: %d approved, %d rejected, %d retiered, %d skipped",
+		r.approved, r.rejected, r.retiered, len(r.skipped))
+}
+
+// render draws the full screen. Lines end in \r\n because the terminal is
+// in raw mode.
+func (r *gateReviewer) render(out io.Writer) {
+	lines := r.screenLines()
+	if len(lines) > r.height {
+		lines = lines[:r.height]
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, ansiReverse) {
+			lines[i] = truncateText(line, r.width)
+		}
+	}
+	fmt.Fprint(out, ansiClearScreen+strings.Join(lines, "\r\n")) //nolint:errcheck // terminal output
+}
+
+// screenLines lays out the queue, the current candidate and the key help.
+func (r *gateReviewer) screenLines() []string {
+	rule := strings.Repeat("─", max(0, r.width))
+	lines := []string{
+		fmt.Sprintf("ao gate review — %d pending — reviewer %s", len(r.entries), r.reviewer),
+		rule,
+	}
+	if len(r.entries) == 0 {
+		return append(lines, "All caught up: no pending reviews.", "", r.summary())
+	}
+
+	// Queue window around the cursor, at most a third of the screen.
+	rows := max(3, r.height/3)
+	start := max(0, min(r.cursor-rows/2, len(r.entries)-rows))
+	for i := start; i < min(len(r.entries), start+rows); i++ {
+		e := r.entries[i]
+		mark := " "
+		if r.skipped[e.Candidate.ID] {
+			mark = "~"
+		}
+		row := fmt.Sprintf("%s %-18s %-7s %-10s %.2f  %-14s %s",
+			mark,
+			truncateID(e.Candidate.ID, 18),
+			e.Candidate.Tier,
+			e.Candidate.Type,
+			e.ScoringResult.RawScore,
+			reviewProgress(e.HumanReview, e.Quorum),
+			e.AgeString)
+		if i == r.cursor {
+			row = ansiReverse + truncateText(row, r.width) + ansiReset
+		}
+		lines = append(lines, row)
+	}
+	lines = append(lines, rule)
+
+	e := r.entries[r.cursor]
+	rubric := e.ScoringResult.Rubric
+	lines = append(lines,
+		fmt.Sprintf("%s  (%s %s, %s, %s)", e.Candidate.ID, e.Candidate.Tier, e.Candidate.Type, e.Status, e.AgeString),
+		fmt.Sprintf("Score %.2f  specificity %.2f  actionability %.2f  novelty %.2f  context %.2f  confidence %.2f",
+			e.ScoringResult.RawScore, rubric.Specificity, rubric.Actionability, rubric.Novelty, rubric.Context, rubric.Confidence),
+	)
+	if hr := e.HumanReview; hr != nil {
+		for _, rv := range hr.Reviews {
+			verdict := "approved"
+			if !rv.Approved {
+				verdict = "dissent"
+			}
+			lines = append(lines, fmt.Sprintf("  %s by %s: %s", verdict, rv.Reviewer, displayOrDash(rv.Notes)))
+		}
+	}
+	lines = append(lines, "")
+
+	// Body and excerpt share what is left above the two footer lines.
+	excerpt := r.excerpt(e)
+	budget := max(2, r.height-len(lines)-3)
+	excerptRows := 0
+	if len(excerpt) > 0 {
+		excerptRows = min(len(excerpt)+1, budget/2)
+	}
+	body := wrapLines(e.Candidate.Content, r.width)
+	lines = append(lines, body[:min(len(body), budget-excerptRows)]...)
+	if excerptRows > 0 {
+		lines = append(lines, fmt.Sprintf("Source: %s #%d", filepath.Base(e.Candidate.Source.TranscriptPath), e.Candidate.Source.MessageIndex))
+		lines = append(lines, excerpt[:excerptRows-1]...)
+	}
+
+	// Pin the footer to the bottom of the screen.
+	for len(lines) < r.height-2 {
+		lines = append(lines, "")
+	}
+	return append(lines,
+		displayOrDash(r.status),
+		"[a]pprove  [r]eject  [s]kip  [t]ier  [j/k] next/prev  [q]uit",
+	)
+}
+
+// excerpt returns the cached transcript excerpt of e.
+func (r *gateReviewer) excerpt(e pool.PoolEntry) []string {
+	id := e.Candidate.ID
+	if lines, ok := r.excerpts[id]; ok {
+		return lines
+	}
+	lines := transcriptExcerpt(e.Candidate.Source, excerptRadius)
+	r.excerpts[id] = lines
+	return lines
+}
+
+// transcriptExcerpt renders the messages within radius of the candidate's
+// source message, one line each. Missing or unparseable transcripts give
+// no excerpt.
+func transcriptExcerpt(src types.Source, radius int) []string {
+	if src.TranscriptPath == "" || filepath.Ext(src.TranscriptPath) != ".jsonl" {
+		return nil
+	}
+	result, err := parser.NewParser().ParseFile(src.TranscriptPath)
+	if err != nil {
+		return nil
+	}
+	var lines []string
+	for _, msg := range result.Messages {
+		if msg.MessageIndex < src.MessageIndex-radius || msg.MessageIndex > src.MessageIndex+radius {
+			continue
+		}
+		marker := " "
+		if msg.MessageIndex == src.MessageIndex {
+			marker = ">"
+		}
+		content := strings.Join(strings.Fields(msg.Content), " ")
+		lines = append(lines, fmt.Sprintf("%s %s: %s", marker, displayOrDash(msg.Role), displayOrDash(content)))
+	}
+	return lines
+}
+
+// wrapLines splits s into lines of at most width runes, breaking at spaces.
+func wrapLines(s string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			switch {
+			case line == "":
+				line = word
+			case len([]rune(line))+1+len([]rune(word)) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// readLine reads an edited line of input in raw mode, echoing it after
+// prompt. ok is false when the user cancels with Esc or Ctrl-C.
+func readLine(in *bufio.Reader, out io.Writer, prompt string) (line string, ok bool, err error) {
+	var buf []rune
+	redraw := func() {
+		fmt.Fprintf(out, "\r\x1b[2K%s%s", prompt, string(buf)) //nolint:errcheck // terminal output
+	}
+	redraw()
+	for {
+		ch, _, err := in.ReadRune()
+		if err != nil {
+			return "", false, err
+		}
+		switch ch {
+		case keyEnter, '\n':
+			return string(buf), true, nil
+		case keyEscape, keyCtrlC:
+			return "", false, nil
+		case keyBackspace, keyDelete:
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+			}
+		default:
+			if ch >= ' ' && len(buf) < pool.MaxReasonLength {
+				buf = append(buf, ch)
+			}
+		}
+		redraw()
+	}
+}
+
+// stdinIsTerminal reports whether stdin is connected to a terminal.
+func stdinIsTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// enterRawMode switches the terminal on stdin to raw, unechoed input with
+// stty and returns a function that restores the previous settings.
+func enterRawMode() (restore func(), err error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("save terminal state: %w", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("enter raw mode: %w", err)
+	}
+	return func() {
+		_, _ = stty(strings.TrimSpace(saved)) //nolint:errcheck // best-effort restore
+	}, nil
+}
+
+// terminalSize returns the rows and columns of the terminal on stdin,
+// falling back to 24x80.
+func terminalSize() (rows, cols int) {
+	out, err := stty("size")
+	if err != nil {
+		return 24, 80
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 24, 80
+	}
+	rows, errRows := strconv.Atoi(fields[0])
+	cols, errCols := strconv.Atoi(fields[1])
+	if errRows != nil || errCols != nil || rows <= 0 || cols <= 0 {
+		return 24, 80
+	}
+	return rows, cols
+}
+
+// stty runs stty against the terminal on stdin.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/pool"
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+func newReviewPool(t *testing.T, ids ...string) *pool.Pool {
+	t.Helper()
+	p := pool.NewPool(t.TempDir())
+	for _, id := range ids {
+		candidate := types.Candidate{
+			ID:      id,
+			Tier:    types.TierBronze,
+			Type:    types.KnowledgeTypeLearning,
+			Content: "Content of " + id,
+		}
+		scoring := types.Scoring{
+			RawScore:     0.6,
+			Rubric:       types.RubricScores{Specificity: 0.7, Actionability: 0.5},
+			GateRequired: true,
+		}
+		if err := p.Add(candidate, scoring); err != nil {
+			t.Fatalf("Add %s: %v", id, err)
+		}
+	}
+	return p
+}
+
+func runReviewKeys(t *testing.T, r *gateReviewer, keys string) string {
+	t.Helper()
+	if err := r.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	var out bytes.Buffer
+	if err := r.run(bufio.NewReader(strings.NewReader(keys)), &out); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	return out.String()
+}
+
+func chainOperations(t *testing.T, p *pool.Pool) []string {
+	t.Helper()
+	events, err := p.GetChain()
+	if err != nil {
+		t.Fatalf("GetChain: %v", err)
+	}
+	var ops []string
+	for _, e := range events {
+		if e.Operation != "add" {
+			ops = append(ops, e.Operation+":"+e.CandidateID+":"+e.Reviewer)
+		}
+	}
+	return ops
+}
+
+func TestGateReview_ApproveRejectRecordChain(t *testing.T) {
+	p := newReviewPool(t, "review-a", "review-b")
+	r := newGateReviewer(p, "alice")
+
+	// Approve the first candidate, reject the next with a typed reason.
+	runReviewKeys(t, r, "a"+"r"+"dup\r")
+
+	got := strings.Join(chainOperations(t, p), ",")
+	if got != "approve:review-a:alice,reject:review-b:alice" && got != "approve:review-b:alice,reject:review-a:alice" {
+		t.Errorf("chain operations = %s", got)
+	}
+	if r.approved != 1 || r.rejected != 1 {
+		t.Errorf("approved/rejected = %d/%d, want 1/1", r.approved, r.rejected)
+	}
+	if len(r.entries) != 0 {
+		t.Errorf("queue should be empty, has %d", len(r.entries))
+	}
+
+	rejected, err := p.List(pool.ListOptions{Status: types.PoolStatusRejected})
+	if err != nil || len(rejected) != 1 || rejected[0].HumanReview.Notes != "dup" {
+		t.Errorf("rejected entries = %+v, err = %v", rejected, err)
+	}
+}
+
+func TestGateReview_RejectCancelled(t *testing.T) {
+	p := newReviewPool(t, "review-cancel")
+	r := newGateReviewer(p, "alice")
+
+	runReviewKeys(t, r, "r"+"no\x1b"+"q")
+
+	if ops := chainOperations(t, p); len(ops) != 0 {
+		t.Errorf("cancelled reject wrote chain events: %v", ops)
+	}
+	if r.status != "Reject cancelled" {
+		t.Errorf("status = %q", r.status)
+	}
+}
+
+func TestGateReview_SkipAndRetier(t *testing.T) {
+	p := newReviewPool(t, "review-skip", "review-tier")
+	r := newGateReviewer(p, "alice")
+
+	// Skip whatever is first, then move the second to silver, which takes
+	// it out of the bronze queue.
+	runReviewKeys(t, r, "s"+"ts"+"q")
+
+	if len(r.skipped) != 1 || r.retiered != 1 {
+		t.Fatalf("skipped/retiered = %d/%d, want 1/1", len(r.skipped), r.retiered)
+	}
+	if len(r.entries) != 1 || !r.skipped[r.entries[0].Candidate.ID] {
+		t.Errorf("queue should hold only the skipped candidate: %+v", r.entries)
+	}
+	ops := chainOperations(t, p)
+	if len(ops) != 1 || !strings.HasPrefix(ops[0], "retier:") {
+		t.Errorf("chain operations = %v, want one retier", ops)
+	}
+}
+
+func TestGateReview_RenderShowsRubricAndExcerpt(t *testing.T) {
+	dir := t.TempDir()
+	transcript := filepath.Join(dir, "session.jsonl")
+	lines := []string{
+		`{"type":"user","sessionId":"s1","timestamp":"2026-01-01T00:00:00Z","message":{"role":"user","content":"why does the build fail"}}`,
+		`{"type":"assistant","sessionId":"s1","timestamp":"2026-01-01T00:00:01Z","message":{"role":"assistant","content":"the cache key ignores go.sum"}}`,
+	}
+	if err := os.WriteFile(transcript, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := pool.NewPool(dir)
+	candidate := types.Candidate{
+		ID:      "render-1",
+		Tier:    types.TierBronze,
+		Type:    types.KnowledgeTypeLearning,
+		Content: "Include go.sum in the cache key",
+		Source:  types.Source{TranscriptPath: transcript, MessageIndex: 2},
+	}
+	scoring := types.Scoring{RawScore: 0.61, Rubric: types.RubricScores{Specificity: 0.8, Novelty: 0.4}, GateRequired: true}
+	if err := p.Add(candidate, scoring); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newGateReviewer(p, "alice")
+	r.width, r.height = 120, 30
+	out := runReviewKeys(t, r, "q")
+
+	for _, want := range []string{"render-1", "specificity 0.80", "novelty 0.40", "Include go.sum in the cache key", "the cache key ignores go.sum", "[a]pprove"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGateReview_readLineEditing(t *testing.T) {
+	in := bufio.NewReader(strings.NewReader("abx\x7fc\r"))
+	line, ok, err := readLine(in, &bytes.Buffer{}, "> ")
+	if err != nil || !ok || line != "abc" {
+		t.Errorf("readLine = %q, %v, %v; want abc, true, nil", line, ok, err)
+	}
+}
+
+func TestGateReview_wrapLines(t *testing.T) {
+	got := wrapLines("one two three four\nfive", 9)
+	want := []string{"one two", "three", "four", "five"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("wrapLines = %q, want %q", got, want)
+	}
+}
@@ -725,3 +725,57 @@ func TestGate_printAutoPromoteWarning(t *testing.T) {
 		}
 	})
 }
+
+func TestGate_filterAssignedEntries(t *testing.T) {
+	policy := &pool.GatePolicy{
+		Reviewers: map[types.KnowledgeType][]string{
+			types.KnowledgeTypeDecision: {"alice", "bob"},
+		},
+	}
+	entry := func(id string, kt types.KnowledgeType, reviewedBy ...string) pool.PoolEntry {
+		e := pool.PoolEntry{PoolEntry: types.PoolEntry{
+			Candidate: types.Candidate{ID: id, Type: kt},
+		}}
+		if len(reviewedBy) > 0 {
+			e.HumanReview = &types.HumanReview{}
+			for _, r := range reviewedBy {
+				e.HumanReview.Reviews = append(e.HumanReview.Reviews, types.Review{Reviewer: r, Approved: true})
+			}
+		}
+		return e
+	}
+	entries := []pool.PoolEntry{
+		entry("decision-open", types.KnowledgeTypeDecision),
+		entry("decision-done", types.KnowledgeTypeDecision, "alice"),
+		entry("learning-open", types.KnowledgeTypeLearning),
+	}
+
+	var ids []string
+	for _, e := range filterAssignedEntries(entries, policy, "alice") {
+		ids = append(ids, e.Candidate.ID)
+	}
+	if strings.Join(ids, ",") != "decision-open,learning-open" {
+		t.Errorf("alice: got %v", ids)
+	}
+
+	ids = nil
+	for _, e := range filterAssignedEntries(entries, policy, "mallory") {
+		ids = append(ids, e.Candidate.ID)
+	}
+	if strings.Join(ids, ",") != "learning-open" {
+		t.Errorf("mallory: got %v", ids)
+	}
+}
+
+func TestGate_reviewProgress(t *testing.T) {
+	hr := &types.HumanReview{Reviews: []types.Review{
+		{Reviewer: "alice", Approved: true},
+		{Reviewer: "carol", Approved: false},
+	}}
+	if got := reviewProgress(hr, 2); got != "1/2 (1 dissent)" {
+		t.Errorf("reviewProgress = %q", got)
+	}
+	if got := reviewProgress(nil, 0); got != "0/1" {
+		t.Errorf("reviewProgress(nil) = %q", got)
+	}
+}
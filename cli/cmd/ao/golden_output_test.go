@@ -162,7 +162,7 @@ func TestGoldenRatchetNextJSON_NoSteps(t *testing.T) {
 		Entries: []ratchet.ChainEntry{},
 	}
 
-	result := computeNextStep(chain)
+	result := computeNextStep(chain, ratchet.DefaultWorkflow())
 
 	origOutput := output
 	output = "json"
@@ -195,7 +195,7 @@ func TestGoldenRatchetNextJSON_ResearchLocked(t *testing.T) {
 		},
 	}
 
-	result := computeNextStep(chain)
+	result := computeNextStep(chain, ratchet.DefaultWorkflow())
 
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
@@ -224,7 +224,7 @@ func TestGoldenRatchetNextJSON_AllComplete(t *testing.T) {
 		},
 	}
 
-	result := computeNextStep(chain)
+	result := computeNextStep(chain, ratchet.DefaultWorkflow())
 
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
@@ -346,7 +346,7 @@ func TestGoldenRatchetNextJSON_PlanLocked(t *testing.T) {
 		},
 	}
 
-	result := computeNextStep(chain)
+	result := computeNextStep(chain, ratchet.DefaultWorkflow())
 
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
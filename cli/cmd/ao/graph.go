@@ -0,0 +1,370 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/boshu2/agentops/cli/internal/graph"
+	"github.com/boshu2/agentops/cli/internal/mind"
+	"github.com/boshu2/agentops/cli/internal/provenance"
+	"github.com/boshu2/agentops/cli/internal/storage"
+)
+
+var (
+	graphQueryDepth      int
+	graphQueryPathTo     string
+	graphQueryDependents bool
+	graphQueryFormat     string
+)
+
+// sessionRefFields are learning frontmatter keys that name the session a
+// learning was extracted from.
+var sessionRefFields = []string{"source_session", "session_id", "source"}
+
+// derivationFields are mind edge kinds meaning "this artifact was derived
+// from the target".
+var derivationFields = map[string]bool{
+	"source":          true,
+	"sources":         true,
+	"source_artifact": true,
+	"derived_from":    true,
+	"based_on":        true,
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Query the unified knowledge graph",
+	Long: `Query sessions, learnings, findings and constraints as one typed graph.
+
+The graph is built on each run from:
+  - the session index and provenance records (.agents/ao/)
+  - .agents/ markdown artifacts and their wikilinks (as used by ao mind)
+  - finding source_artifact fields
+  - the constraint index (.agents/constraints/index.json)
+
+Edge kinds:
+  produced       session → learning
+  compiled_into  learning → finding
+  enforced_by    finding → constraint
+  derives        any other source → derived artifact
+  supersedes     replacement → replaced artifact
+  links          wikilink between artifacts
+  references     any other frontmatter reference`,
+}
+
+var graphQueryCmd = &cobra.Command{
+	Use:   "query [node]",
+	Short: "Show a neighborhood, shortest path or dependents",
+	Long: `Query the knowledge graph around a node.
+
+A node may be named by id, vault path, session id or short session id.
+With no node, the whole graph is printed.
+
+Modes:
+  (default)     nodes within --depth hops of the node, in either direction
+  --path-to X   a shortest path from the node to X, ignoring direction
+  --dependents  everything derived from the node: learnings produced by a
+                session, findings compiled from a learning, constraints
+                enforcing a finding, and so on`,
+	Example: `  ao graph query learning-retry-backoff --depth 2
+  ao graph query .agents/learnings/2026-01-02-retry.md --dependents --format mermaid
+  ao graph query abc1234 --path-to constraint-no-sleep --format dot | dot -Tsvg > path.svg`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGraphQuery,
+}
+
+func init() {
+	graphCmd.GroupID = "knowledge"
+	rootCmd.AddCommand(graphCmd)
+	graphCmd.AddCommand(graphQueryCmd)
+
+	graphQueryCmd.Flags().IntVar(&graphQueryDepth, "depth", 1, "Neighborhood radius in hops")
+	graphQueryCmd.Flags().StringVar(&graphQueryPathTo, "path-to", "", "Show a shortest path from the node to this node")
+	graphQueryCmd.Flags().BoolVar(&graphQueryDependents, "dependents", false, "Show everything derived from the node")
+	graphQueryCmd.Flags().StringVar(&graphQueryFormat, "format", graph.FormatJSON, "Output format: "+strings.Join(graph.Formats, ", "))
+}
+
+func runGraphQuery(cmd *cobra.Command, args []string) error {
+	if graphQueryPathTo != "" && graphQueryDependents {
+		return fmt.Errorf("--path-to and --dependents are mutually exclusive")
+	}
+	if len(args) == 0 && (graphQueryPathTo != "" || graphQueryDependents) {
+		return fmt.Errorf("--path-to and --dependents require a node")
+	}
+	if graphQueryDepth < 0 {
+		return fmt.Errorf("--depth must be >= 0")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	g, err := loadKnowledgeGraph(cwd)
+	if err != nil {
+		return err
+	}
+
+	result, err := queryKnowledgeGraph(g, args)
+	if err != nil {
+		return err
+	}
+	return graph.Render(os.Stdout, result, graphQueryFormat)
+}
+
+// queryKnowledgeGraph applies the query flags to g.
+func queryKnowledgeGraph(g *graph.Graph, args []string) (*graph.Graph, error) {
+	if len(args) == 0 {
+		return g, nil
+	}
+	id, err := resolveGraphNode(g, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case graphQueryPathTo != "":
+		to, err := resolveGraphNode(g, graphQueryPathTo)
+		if err != nil {
+			return nil, err
+		}
+		path, err := g.ShortestPath(id, to)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return g.Neighborhood(id, 0), nil
+		}
+		return g.PathGraph(path), nil
+	case graphQueryDependents:
+		return g.Dependents(id), nil
+	default:
+		return g.Neighborhood(id, graphQueryDepth), nil
+	}
+}
+
+func resolveGraphNode(g *graph.Graph, ref string) (string, error) {
+	ref = strings.TrimPrefix(filepath.ToSlash(ref), "./")
+	if id, ok := g.Resolve(ref); ok {
+		return id, nil
+	}
+	if id, ok := g.Resolve(strings.TrimSuffix(ref, ".md") + ".md"); ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("node not found: %s", ref)
+}
+
+// loadKnowledgeGraph assembles the typed graph for the project at cwd.
+// Missing sources are skipped, so a fresh repo yields an empty graph.
+func loadKnowledgeGraph(cwd string) (*graph.Graph, error) {
+	g := graph.New()
+	if err := addSessionNodes(g, cwd); err != nil {
+		return nil, err
+	}
+	if err := addArtifactNodes(g, cwd); err != nil {
+		return nil, err
+	}
+	if err := addConstraintNodes(g, cwd); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// addSessionNodes adds sessions from the session index and provenance
+// records, with an edge from each transcript a session was forged from.
+func addSessionNodes(g *graph.Graph, cwd string) error {
+	baseDir := filepath.Join(cwd, storage.DefaultBaseDir)
+	sessions, err := storage.NewFileStorage(storage.WithBaseDir(baseDir)).ListSessions()
+	if err != nil {
+		VerbosePrintf("Session index unavailable: %v\n", err)
+	}
+	for _, s := range sessions {
+		addSessionNode(g, s.SessionID, vaultPath(cwd, s.SessionPath), s.Summary)
+	}
+
+	prov, err := provenance.NewGraph(filepath.Join(baseDir, storage.ProvenanceDir, storage.ProvenanceFile))
+	if err != nil {
+		return fmt.Errorf("load provenance: %w", err)
+	}
+	for _, r := range prov.Records {
+		if r.ArtifactType != graph.TypeSession {
+			continue
+		}
+		path := vaultPath(cwd, r.ArtifactPath)
+		id, ok := g.Resolve(path)
+		if !ok {
+			id = r.SessionID
+			if id == "" {
+				id = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			}
+			addSessionNode(g, id, path, "")
+		}
+		if r.SourcePath == "" {
+			continue
+		}
+		source := vaultPath(cwd, r.SourcePath)
+		sourceID, ok := g.Resolve(source)
+		if !ok {
+			sourceID = source
+			g.AddNode(graph.Node{ID: source, Type: cmp.Or(r.SourceType, "transcript"), Label: filepath.Base(source), Path: source})
+		}
+		if err := g.AddEdge(graph.Edge{From: sourceID, To: id, Kind: graph.KindDerives}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSessionNode adds a session, resolvable by its id, path and the short
+// id used in artifact file names.
+func addSessionNode(g *graph.Graph, id, path, label string) {
+	g.AddNode(graph.Node{ID: id, Type: graph.TypeSession, Label: label, Path: path})
+	if len(id) > 7 {
+		g.Alias(id[:7], id)
+	}
+}
+
+// addArtifactNodes adds .agents/ markdown artifacts and their mind edges,
+// plus produced edges from the sessions learnings name in frontmatter.
+func addArtifactNodes(g *graph.Graph, cwd string) error {
+	if _, err := os.Stat(filepath.Join(cwd, mind.AgentsDir)); os.IsNotExist(err) {
+		return nil
+	}
+	artifacts, err := mind.Scan(cwd)
+	if err != nil {
+		return err
+	}
+	mg := mind.BuildGraph(artifacts)
+
+	// Artifact ids are not guaranteed unique; fall back to the path.
+	idByPath := make(map[string]string, len(mg.Nodes))
+	for _, n := range mg.Nodes {
+		id := n.ID
+		if existing, ok := g.Node(id); ok && existing.Path != n.Path {
+			id = n.Path
+		}
+		idByPath[n.Path] = id
+		g.AddNode(graph.Node{ID: id, Type: n.Type, Label: n.Title, Path: n.Path})
+		g.Alias(strings.TrimSuffix(n.Path, ".md"), id)
+	}
+
+	for _, e := range mg.Edges {
+		if err := g.AddEdge(knowledgeEdge(g, idByPath[e.From], idByPath[e.To], e.Kind)); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range artifacts {
+		from := idByPath[a.Path]
+		fm := a.Frontmatter()
+		switch n, _ := g.Node(from); n.Type {
+		case graph.TypeLearning:
+			for _, key := range sessionRefFields {
+				session := fm.Get(key)
+				if session == "" || strings.Contains(session, "[[") {
+					continue
+				}
+				id, ok := g.Resolve(session)
+				if !ok && key == "source" {
+					continue // free-form source, not a session id
+				}
+				if !ok {
+					id = session
+					addSessionNode(g, id, "", "")
+				}
+				if s, _ := g.Node(id); s.Type == graph.TypeSession {
+					if err := g.AddEdge(graph.Edge{From: id, To: from, Kind: graph.KindProduced}); err != nil {
+						return err
+					}
+				}
+			}
+		case graph.TypeFinding:
+			// source_artifact is a plain path; wikilink values are
+			// already covered by the mind edges.
+			source := fm.Get("source_artifact")
+			if source == "" || strings.Contains(source, "[[") {
+				continue
+			}
+			if id, ok := g.Resolve(strings.TrimPrefix(source, "./")); ok {
+				if err := g.AddEdge(knowledgeEdge(g, from, id, "source_artifact")); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addConstraintNodes adds compiled constraints with an edge from the
+// finding each one enforces.
+func addConstraintNodes(g *graph.Graph, cwd string) error {
+	if _, err := os.Stat(filepath.Join(cwd, constraintIndexPath())); os.IsNotExist(err) {
+		return nil
+	}
+	idx, err := loadConstraintIndex()
+	if err != nil {
+		return err
+	}
+	for _, c := range idx.Constraints {
+		g.AddNode(graph.Node{ID: c.ID, Type: graph.TypeConstraint, Label: c.Title, Path: c.File})
+		finding, ok := g.Resolve(c.FindingID)
+		if !ok && c.SourceArtifact != "" {
+			finding, ok = g.Resolve(c.SourceArtifact)
+		}
+		if !ok {
+			VerbosePrintf("Constraint %s: source finding not found\n", c.ID)
+			continue
+		}
+		if err := g.AddEdge(knowledgeEdge(g, c.ID, finding, "source")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// knowledgeEdge maps a reference from one artifact to another onto a typed
+// edge. Derivation references are flipped to point downstream.
+func knowledgeEdge(g *graph.Graph, from, to, kind string) graph.Edge {
+	switch {
+	case kind == mind.EdgeLink || kind == mind.EdgeRelated:
+		return graph.Edge{From: from, To: to, Kind: graph.KindLinks}
+	case kind == "supersedes":
+		return graph.Edge{From: from, To: to, Kind: graph.KindSupersedes}
+	case kind == "superseded_by":
+		return graph.Edge{From: to, To: from, Kind: graph.KindSupersedes}
+	case derivationFields[kind]:
+		up, _ := g.Node(to)
+		down, _ := g.Node(from)
+		return graph.Edge{From: to, To: from, Kind: derivationKind(up.Type, down.Type)}
+	default:
+		return graph.Edge{From: from, To: to, Kind: graph.KindReferences}
+	}
+}
+
+// derivationKind names a derivation edge after the node types it joins.
+func derivationKind(upType, downType string) string {
+	switch {
+	case upType == graph.TypeSession && downType == graph.TypeLearning:
+		return graph.KindProduced
+	case upType == graph.TypeLearning && downType == graph.TypeFinding:
+		return graph.KindCompiledInto
+	case upType == graph.TypeFinding && downType == graph.TypeConstraint:
+		return graph.KindEnforcedBy
+	default:
+		return graph.KindDerives
+	}
+}
+
+// vaultPath makes path slash-separated and relative to cwd when inside it.
+func vaultPath(cwd, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/graph"
+)
+
+// writeGraphFixture lays out a session-derived learning compiled into a
+// finding and a constraint, plus a newer learning that supersedes it.
+func writeGraphFixture(t *testing.T, root string) {
+	t.Helper()
+	files := map[string]string{
+		".agents/learnings/retry.md":    "---\nid: learning-retry\nsource_session: sess-1234567890\n---\n# Retry with backoff\n",
+		".agents/learnings/retry-v2.md": "---\nid: learning-retry-v2\nsupersedes: learning-retry\n---\n# Retry v2\n\nSee [[retry]].\n",
+		".agents/findings/f-retry.md":   "---\nid: f-retry\nsource_artifact: .agents/learnings/retry.md\n---\n# Retry finding\n",
+		".agents/constraints/index.json": `{"schema_version": 1, "constraints": [
+  {"id": "c-retry", "finding_id": "f-retry", "title": "No bare sleeps", "source": "finding", "status": "active", "compiled_at": "2026-01-01", "file": "hooks/c-retry.sh"}
+]}`,
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func resetGraphQueryFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		graphQueryDepth = 1
+		graphQueryPathTo = ""
+		graphQueryDependents = false
+		graphQueryFormat = graph.FormatJSON
+	})
+}
+
+func TestLoadKnowledgeGraph(t *testing.T) {
+	root := chdirTemp(t)
+	writeGraphFixture(t, root)
+
+	g, err := loadKnowledgeGraph(root)
+	if err != nil {
+		t.Fatalf("loadKnowledgeGraph: %v", err)
+	}
+	want := []graph.Edge{
+		{From: "f-retry", To: "c-retry", Kind: graph.KindEnforcedBy},
+		{From: "learning-retry", To: "f-retry", Kind: graph.KindCompiledInto},
+		{From: "learning-retry-v2", To: "learning-retry", Kind: graph.KindLinks},
+		{From: "learning-retry-v2", To: "learning-retry", Kind: graph.KindSupersedes},
+		{From: "sess-1234567890", To: "learning-retry", Kind: graph.KindProduced},
+	}
+	if got := g.Edges(); !slices.Equal(got, want) {
+		t.Errorf("edges = %v\nwant %v", got, want)
+	}
+	if id, ok := g.Resolve("sess-12"); !ok || id != "sess-1234567890" {
+		t.Errorf("short session id should resolve, got %q, %v", id, ok)
+	}
+}
+
+func TestLoadKnowledgeGraph_EmptyRepo(t *testing.T) {
+	g, err := loadKnowledgeGraph(chdirTemp(t))
+	if err != nil {
+		t.Fatalf("loadKnowledgeGraph: %v", err)
+	}
+	if len(g.Nodes()) != 0 {
+		t.Errorf("expected empty graph, got %v", g.Nodes())
+	}
+}
+
+func TestGraphQuery(t *testing.T) {
+	writeGraphFixture(t, chdirTemp(t))
+
+	t.Run("dependents of a learning", func(t *testing.T) {
+		resetGraphQueryFlags(t)
+		graphQueryDependents = true
+
+		out, err := captureStdout(t, func() error {
+			return runGraphQuery(graphQueryCmd, []string{".agents/learnings/retry.md"})
+		})
+		if err != nil {
+			t.Fatalf("runGraphQuery: %v", err)
+		}
+		var doc struct {
+			Nodes []graph.Node `json:"nodes"`
+		}
+		if err := json.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		var ids []string
+		for _, n := range doc.Nodes {
+			ids = append(ids, n.ID)
+		}
+		if want := []string{"c-retry", "f-retry", "learning-retry"}; !slices.Equal(ids, want) {
+			t.Errorf("dependents = %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("shortest path as mermaid", func(t *testing.T) {
+		resetGraphQueryFlags(t)
+		graphQueryPathTo = "c-retry"
+		graphQueryFormat = graph.FormatMermaid
+
+		out, err := captureStdout(t, func() error {
+			return runGraphQuery(graphQueryCmd, []string{"sess-1234567890"})
+		})
+		if err != nil {
+			t.Fatalf("runGraphQuery: %v", err)
+		}
+		for _, want := range []string{"flowchart LR", "|produced|", "|compiled_into|", "|enforced_by|"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("missing %q in:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		resetGraphQueryFlags(t)
+		if err := runGraphQuery(graphQueryCmd, []string{"nope"}); err == nil {
+			t.Error("expected error for unknown node")
+		}
+	})
+}
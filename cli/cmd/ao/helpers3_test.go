@@ -214,7 +214,7 @@ func TestHelper3_isContainedPath(t *testing.T) {
 
 // ---------------------------------------------------------------------------
 // 2. store.go — accumulateEntryStats, artifactTypeFromPath, extractTitle,
-//    extractKeywords, computeSearchScore, createSearchSnippet,
+//    extractKeywords, applyUtilityBoost, createSearchSnippet,
 //    parseBracketedList, splitCSV, parseMemRLMetadata,
 //    appendCategoryKeywords, extractCategoryAndTags
 // ---------------------------------------------------------------------------
@@ -334,31 +334,14 @@ This is about a pattern: we found a solution: to fix: errors.
 	}
 }
 
-func TestHelper3_computeSearchScore(t *testing.T) {
-	entry := IndexEntry{
-		Title:    "Mutex Pattern",
-		Content:  "This is about mutex locking in Go",
-		Keywords: []string{"mutex", "concurrency"},
-		Utility:  0.8,
+func TestHelper3_applyUtilityBoost(t *testing.T) {
+	boosted := applyUtilityBoost(2.0, 0.9)
+	plain := applyUtilityBoost(2.0, 0)
+	if plain != 2.0 {
+		t.Errorf("expected unchanged score without utility, got %f", plain)
 	}
-
-	// Single term matching title, content, and keyword
-	score := computeSearchScore(entry, []string{"mutex"})
-	if score <= 0 {
-		t.Errorf("expected positive score for matching query, got %f", score)
-	}
-
-	// Non-matching
-	noScore := computeSearchScore(entry, []string{"database"})
-	if noScore != 0 {
-		t.Errorf("expected zero score for non-matching query, got %f", noScore)
-	}
-
-	// Title match should score higher than content-only match
-	titleMatch := computeSearchScore(entry, []string{"mutex"})
-	contentOnly := computeSearchScore(entry, []string{"locking"})
-	if titleMatch <= contentOnly {
-		t.Errorf("title match score (%f) should be > content-only score (%f)", titleMatch, contentOnly)
+	if boosted >= plain {
+		t.Errorf("utility below 1 should dampen the score: %f >= %f", boosted, plain)
 	}
 }
 
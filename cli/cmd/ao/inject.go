@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"slices"
@@ -89,8 +90,10 @@ type learning struct {
 	AgeWeeks       float64 `json:"age_weeks,omitempty"`
 	Utility        float64 `json:"utility,omitempty"`         // MemRL utility value
 	CompositeScore float64 `json:"composite_score,omitempty"` // Two-Phase ranking score
+	Relevance      float64 `json:"relevance,omitempty"`       // Hybrid BM25+vector query match (--hybrid)
 	Maturity       string  `json:"maturity,omitempty"`        // CASS maturity level
 	SessionType    string  `json:"session_type,omitempty"`    // career, research, debug, implement, brainstorm
+	Selection      string  `json:"selection,omitempty"`       // explore or exploit (--explore only)
 	Superseded     bool    `json:"-"`                         // Internal flag - not serialized
 	Global         bool    `json:"-"`                         // Internal flag: from global dir
 }
@@ -150,6 +153,11 @@ Searches:
 Uses file-based search with Two-Phase retrieval (freshness + utility scoring).
 CASS integration adds maturity weighting and confidence decay.
 
+With --explore, up to --explore-budget learning slots are filled by a bandit
+(Thompson sampling or UCB) over each learning's utility and reward count, so
+new learnings get injected and can earn feedback. The bandit is seeded from
+the session ID, and each citation records whether it was explore or exploit.
+
 Examples:
   ao inject                     # Inject general knowledge
   ao inject "authentication"    # Inject knowledge about auth
@@ -157,6 +165,8 @@ Examples:
   ao inject --format json       # JSON output
   ao inject --no-cite           # Skip citation recording
   ao inject --apply-decay       # Apply confidence decay before ranking
+  ao inject --hybrid "auth"     # Rank by BM25 + local vector similarity
+  ao inject --explore thompson  # Spend 2 slots exploring rarely-rewarded learnings
   ao inject --bead ag-7abc      # Work-scoped injection for bead
   ao inject --predecessor /path/to/handoff.md  # Include predecessor context
   ao inject --for=research "authentication"    # Filtered by skill's context contract
@@ -184,6 +194,9 @@ func init() {
 	injectCmd.Flags().StringVar(&injectForSkill, "for", "", "Skill name — assembles context per skill's context declaration")
 	injectCmd.Flags().StringVar(&injectSessionType, "session-type", "", "Session type for scoring boost (career, research, debug, implement, brainstorm)")
 	injectCmd.Flags().BoolVar(&injectProfile, "profile", false, "Include .agents/profile.md identity artifact in output")
+	injectCmd.Flags().BoolVar(&hybridRetrieval, "hybrid", false, hybridFlagUsage)
+	injectCmd.Flags().StringVar(&injectExplore, "explore", exploreOff, "Exploration strategy for learning selection: off, thompson, ucb")
+	injectCmd.Flags().IntVar(&injectExploreBudget, "explore-budget", defaultExploreBudget, "Maximum learnings per packet chosen by exploration")
 }
 
 func runInject(cmd *cobra.Command, args []string) error {
@@ -206,7 +219,11 @@ func runInject(cmd *cobra.Command, args []string) error {
 	beadCtx := resolveInjectBeadContext(cwd)
 
 	sessionID := resolveSessionID(injectSessionID)
-	knowledge := gatherKnowledge(cwd, query, sessionID, cfg)
+	explorer, err := newLearningExplorer(injectExplore, injectExploreBudget, sessionID)
+	if err != nil {
+		return err
+	}
+	knowledge := gatherKnowledge(cwd, query, sessionID, cfg, explorer)
 	knowledge.BeadID = injectBead
 
 	// Dedup: skip learnings whose title already appears in MEMORY.md
@@ -378,7 +395,8 @@ func printInjectDryRun(query string) {
 }
 
 // gatherKnowledge collects all knowledge sources and records citations.
-func gatherKnowledge(cwd, query, sessionID string, cfg *config.Config) *injectedKnowledge {
+// A nil explorer ranks learnings purely by composite score.
+func gatherKnowledge(cwd, query, sessionID string, cfg *config.Config, explorer *learningExplorer) *injectedKnowledge {
 	knowledge := &injectedKnowledge{
 		Timestamp: time.Now(),
 		Query:     query,
@@ -393,8 +411,9 @@ func gatherKnowledge(cwd, query, sessionID string, cfg *config.Config) *injected
 		globalWeight = cfg.Paths.GlobalWeight
 	}
 
-	knowledge.Learnings = gatherLearnings(cwd, query, sessionID, globalLearningsDir, globalWeight)
-	knowledge.Patterns = gatherPatterns(cwd, query, sessionID, globalPatternsDir, globalWeight)
+	guard := loadContradictionGuard(cwd)
+	knowledge.Learnings = gatherLearnings(cwd, query, sessionID, globalLearningsDir, globalWeight, guard, explorer)
+	knowledge.Patterns = gatherPatterns(cwd, query, sessionID, globalPatternsDir, globalWeight, guard)
 
 	// Non-verbose quality gate summary (stderr — does not pollute stdout inject output)
 	if len(knowledge.Learnings) > 0 || len(knowledge.Patterns) > 0 {
@@ -407,12 +426,28 @@ func gatherKnowledge(cwd, query, sessionID string, cfg *config.Config) *injected
 	return knowledge
 }
 
-// gatherLearnings collects learnings and records their citations.
-func gatherLearnings(cwd, query, sessionID, globalDir string, globalWeight float64) []learning {
-	learnings, err := collectLearnings(cwd, query, MaxLearningsToInject, globalDir, globalWeight)
+// gatherLearnings collects learnings, drops the losing side of unresolved
+// contradictions, and records citations for the rest. With an explorer, the
+// whole ranking is collected so exploration can reach below the cut.
+func gatherLearnings(cwd, query, sessionID, globalDir string, globalWeight float64, guard *contradictionGuard, explorer *learningExplorer) []learning {
+	// The guard runs before the cut, so dropped contradictions leave room
+	// for the next-ranked learnings instead of shortening the packet.
+	limit := MaxLearningsToInject
+	if explorer != nil || guard != nil {
+		limit = math.MaxInt
+	}
+	learnings, err := collectLearnings(cwd, query, limit, globalDir, globalWeight)
 	if err != nil {
 		VerbosePrintf("Warning: failed to collect learnings: %v\n", err)
 	}
+	learnings = guard.filterLearnings(learnings)
+	if explorer != nil {
+		learnings = explorer.selectLearnings(learnings, MaxLearningsToInject)
+		VerbosePrintf("Exploration (%s): %d of %d learnings explored\n",
+			explorer.strategy, countSelection(learnings, selectionExplore), len(learnings))
+	} else if len(learnings) > MaxLearningsToInject {
+		learnings = learnings[:MaxLearningsToInject]
+	}
 
 	// Record citations for retrieved learnings (Phase 0: Critical for MemRL feedback loop)
 	if !injectNoCite && len(learnings) > 0 {
@@ -426,12 +461,21 @@ func gatherLearnings(cwd, query, sessionID, globalDir string, globalWeight float
 	return learnings
 }
 
-// gatherPatterns collects patterns and records their citations.
-func gatherPatterns(cwd, query, sessionID, globalDir string, globalWeight float64) []pattern {
-	patterns, err := collectPatterns(cwd, query, MaxPatternsToInject, globalDir, globalWeight)
+// gatherPatterns collects patterns, drops any that contradict knowledge
+// already in the packet, and records citations for the rest.
+func gatherPatterns(cwd, query, sessionID, globalDir string, globalWeight float64, guard *contradictionGuard) []pattern {
+	limit := MaxPatternsToInject
+	if guard != nil {
+		limit = math.MaxInt
+	}
+	patterns, err := collectPatterns(cwd, query, limit, globalDir, globalWeight)
 	if err != nil {
 		VerbosePrintf("Warning: failed to collect patterns: %v\n", err)
 	}
+	patterns = guard.filterPatterns(patterns)
+	if len(patterns) > MaxPatternsToInject {
+		patterns = patterns[:MaxPatternsToInject]
+	}
 
 	// Record citations for retrieved patterns (closes σ gap: patterns were retrieved but never cited)
 	if !injectNoCite && len(patterns) > 0 {
@@ -815,6 +859,7 @@ func recordCitations(baseDir string, learnings []learning, sessionID, query stri
 			CitedAt:      time.Now(),
 			CitationType: "retrieved", // Will be upgraded to "applied" if session succeeds
 			Query:        query,
+			Selection:    l.Selection,
 		}
 
 		if err := ratchet.RecordCitation(baseDir, event); err != nil {
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"path/filepath"
+)
+
+// contradictionGuard keeps both sides of an unresolved contradiction out of
+// a single inject packet. Candidates are admitted in rank order, so the
+// better-ranked side of each pair wins. A nil guard admits everything.
+type contradictionGuard struct {
+	cwd          string
+	counterparts map[string][]string
+	served       map[string]bool
+}
+
+// loadContradictionGuard builds a guard from the unresolved pairs in
+// .agents/contradictions.jsonl. It returns nil when there are none.
+func loadContradictionGuard(cwd string) *contradictionGuard {
+	records, err := loadContradictions(cwd)
+	if err != nil {
+		VerbosePrintf("Warning: failed to load contradictions: %v\n", err)
+		return nil
+	}
+	g := &contradictionGuard{cwd: cwd, counterparts: make(map[string][]string), served: make(map[string]bool)}
+	for _, r := range records {
+		if r.Status != contradictionUnresolved {
+			continue
+		}
+		a, b := filepath.ToSlash(r.FileA), filepath.ToSlash(r.FileB)
+		g.counterparts[a] = append(g.counterparts[a], b)
+		g.counterparts[b] = append(g.counterparts[b], a)
+	}
+	if len(g.counterparts) == 0 {
+		return nil
+	}
+	return g
+}
+
+// admit reports whether path may join the packet and, if so, marks it
+// served. It rejects path when a contradicting file was already served.
+func (g *contradictionGuard) admit(path string) bool {
+	if g == nil || path == "" {
+		return true
+	}
+	key := g.key(path)
+	for _, other := range g.counterparts[key] {
+		if g.served[other] {
+			VerbosePrintf("Skipping %s: unresolved contradiction with %s\n", key, other)
+			return false
+		}
+	}
+	g.served[key] = true
+	return true
+}
+
+// key maps a path to the cwd-relative, slash-separated form used in the
+// contradiction log.
+func (g *contradictionGuard) key(path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(g.cwd, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// filterLearnings drops learnings that contradict a better-ranked one.
+func (g *contradictionGuard) filterLearnings(learnings []learning) []learning {
+	if g == nil {
+		return learnings
+	}
+	kept := learnings[:0]
+	for _, l := range learnings {
+		if g.admit(l.Source) {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
+
+// filterPatterns drops patterns that contradict anything already served.
+func (g *contradictionGuard) filterPatterns(patterns []pattern) []pattern {
+	if g == nil {
+		return patterns
+	}
+	kept := patterns[:0]
+	for _, p := range patterns {
+		if g.admit(p.FilePath) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// Exploration strategies for ao inject --explore.
+const (
+	exploreOff      = "off"
+	exploreThompson = "thompson"
+	exploreUCB      = "ucb"
+
+	// defaultExploreBudget is how many packet slots exploration may take.
+	defaultExploreBudget = 2
+
+	// Selection tags recorded on injected learnings and their citations.
+	selectionExploit = "exploit"
+	selectionExplore = "explore"
+)
+
+var (
+	injectExplore       string
+	injectExploreBudget int
+)
+
+// learningExplorer fills part of an inject packet with learnings chosen by a
+// bandit over their feedback history instead of their composite rank. New
+// learnings start at the default utility and rarely outrank established ones,
+// so without exploration they are never injected, never cited and never
+// rewarded by ao feedback-loop.
+type learningExplorer struct {
+	strategy    string
+	budget      int
+	rng         *rand.Rand
+	rewardCount func(path string) int
+}
+
+// newLearningExplorer returns nil when exploration is off. The random source
+// is seeded from the session ID so the same session always gets the same
+// packet.
+func newLearningExplorer(strategy string, budget int, sessionID string) (*learningExplorer, error) {
+	switch strategy {
+	case "", exploreOff:
+		return nil, nil
+	case exploreThompson, exploreUCB:
+	default:
+		return nil, fmt.Errorf("unknown --explore strategy %q (want %s, %s or %s)", strategy, exploreThompson, exploreUCB, exploreOff)
+	}
+	if budget < 0 {
+		return nil, fmt.Errorf("--explore-budget must be >= 0, got %d", budget)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(sessionID)) //nolint:errcheck // hash writes never fail
+	seed := h.Sum64()
+	return &learningExplorer{
+		strategy:    strategy,
+		budget:      budget,
+		rng:         rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+		rewardCount: getLearningRewardCount,
+	}, nil
+}
+
+// selectLearnings picks up to limit learnings from ranked, which must be
+// sorted by composite score. The top limit-budget learnings are exploited;
+// the remaining slots go to the learnings further down the ranking that the
+// bandit scores highest. Every returned learning carries its selection tag.
+func (e *learningExplorer) selectLearnings(ranked []learning, limit int) []learning {
+	budget := min(e.budget, limit)
+	if len(ranked) <= limit || budget == 0 {
+		selected := ranked[:min(len(ranked), limit)]
+		for i := range selected {
+			selected[i].Selection = selectionExploit
+		}
+		return selected
+	}
+
+	exploitN := limit - budget
+	selected := make([]learning, 0, limit)
+	for _, l := range ranked[:exploitN] {
+		l.Selection = selectionExploit
+		selected = append(selected, l)
+	}
+
+	pool := ranked[exploitN:]
+	scores := e.banditScores(pool)
+	order := make([]int, len(pool))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(scores[b], scores[a])
+	})
+	for _, i := range order[:budget] {
+		l := pool[i]
+		l.Selection = selectionExplore
+		selected = append(selected, l)
+	}
+	return selected
+}
+
+// countSelection counts learnings carrying the given selection tag.
+func countSelection(learnings []learning, selection string) int {
+	n := 0
+	for _, l := range learnings {
+		if l.Selection == selection {
+			n++
+		}
+	}
+	return n
+}
+
+// banditScores scores each candidate from its utility and how often it has
+// been rewarded: the fewer rewards, the wider the uncertainty around the
+// utility and the more likely it is to be explored.
+func (e *learningExplorer) banditScores(pool []learning) []float64 {
+	counts := make([]int, len(pool))
+	total := 0
+	for i, l := range pool {
+		counts[i] = e.rewardCount(l.Source)
+		total += counts[i]
+	}
+
+	scores := make([]float64, len(pool))
+	for i, l := range pool {
+		u := math.Max(0, math.Min(1, l.Utility))
+		n := float64(counts[i])
+		switch e.strategy {
+		case exploreThompson:
+			scores[i] = sampleBeta(e.rng, 1+u*n, 1+(1-u)*n)
+		case exploreUCB:
+			// UCB1, counting one pseudo-observation per candidate so
+			// unrewarded learnings get a large but finite bonus.
+			scores[i] = u + math.Sqrt(2*math.Log(float64(total+len(pool)))/(n+1))
+		}
+	}
+	return scores
+}
+
+// sampleBeta draws from Beta(a, b) as X/(X+Y) with X ~ Gamma(a), Y ~ Gamma(b).
+func sampleBeta(rng *rand.Rand, a, b float64) float64 {
+	x := sampleGamma(rng, a)
+	y := sampleGamma(rng, b)
+	return x / (x + y)
+}
+
+// sampleGamma draws from Gamma(shape, 1) for shape >= 1 using
+// Marsaglia and Tsang's method.
+func sampleGamma(rng *rand.Rand, shape float64) float64 {
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/ratchet"
+)
+
+// rankedLearnings returns n learnings in rank order; the first rewarded have
+// each been rewarded 50 times and the rest never.
+func rankedLearnings(n, rewarded int) ([]learning, func(string) int) {
+	learnings := make([]learning, n)
+	counts := make(map[string]int, n)
+	for i := range learnings {
+		learnings[i] = learning{ID: fmt.Sprintf("L%02d", i), Source: fmt.Sprintf("L%02d.md", i), Utility: 0.5}
+		if i < rewarded {
+			counts[learnings[i].Source] = 50
+		}
+	}
+	return learnings, func(path string) int { return counts[path] }
+}
+
+func selectionSummary(learnings []learning) string {
+	parts := make([]string, len(learnings))
+	for i, l := range learnings {
+		parts[i] = l.ID + ":" + l.Selection
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestLearningExplorer_UCBExploresUnrewarded(t *testing.T) {
+	ranked, counts := rankedLearnings(12, 10)
+	e, err := newLearningExplorer(exploreUCB, 2, "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.rewardCount = counts
+
+	selected := e.selectLearnings(ranked, 4)
+	want := "L00:exploit,L01:exploit,L10:explore,L11:explore"
+	if got := selectionSummary(selected); got != want {
+		t.Errorf("selected = %s, want %s", got, want)
+	}
+}
+
+func TestLearningExplorer_ThompsonIsSeededBySession(t *testing.T) {
+	pick := func(sessionID string) string {
+		ranked, counts := rankedLearnings(30, 5)
+		e, err := newLearningExplorer(exploreThompson, 3, sessionID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.rewardCount = counts
+		selected := e.selectLearnings(ranked, 5)
+		if countSelection(selected, selectionExplore) != 3 || countSelection(selected, selectionExploit) != 2 {
+			t.Fatalf("selected = %s, want 2 exploit + 3 explore", selectionSummary(selected))
+		}
+		return selectionSummary(selected)
+	}
+	if a, b := pick("session-a"), pick("session-a"); a != b {
+		t.Errorf("same session gave different packets:\n%s\n%s", a, b)
+	}
+}
+
+func TestLearningExplorer_SmallPoolIsAllExploit(t *testing.T) {
+	ranked, counts := rankedLearnings(3, 0)
+	e, err := newLearningExplorer(exploreThompson, 2, "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.rewardCount = counts
+	if got := selectionSummary(e.selectLearnings(ranked, 10)); got != "L00:exploit,L01:exploit,L02:exploit" {
+		t.Errorf("selected = %s", got)
+	}
+}
+
+func TestNewLearningExplorer(t *testing.T) {
+	if e, err := newLearningExplorer(exploreOff, 2, "s"); e != nil || err != nil {
+		t.Errorf("off: explorer=%v err=%v, want nil, nil", e, err)
+	}
+	if _, err := newLearningExplorer("greedy", 2, "s"); err == nil || !strings.Contains(err.Error(), "unknown --explore strategy") {
+		t.Errorf("err = %v, want unknown strategy", err)
+	}
+	if _, err := newLearningExplorer(exploreUCB, -1, "s"); err == nil {
+		t.Error("expected error for negative budget")
+	}
+}
+
+func TestSampleBeta_InUnitInterval(t *testing.T) {
+	e, _ := newLearningExplorer(exploreThompson, 1, "beta")
+	var sum float64
+	const n = 2000
+	for range n {
+		x := sampleBeta(e.rng, 9, 3)
+		if x < 0 || x > 1 {
+			t.Fatalf("sample %v outside [0, 1]", x)
+		}
+		sum += x
+	}
+	if mean := sum / n; mean < 0.7 || mean > 0.8 {
+		t.Errorf("Beta(9, 3) sample mean = %.3f, want about 0.75", mean)
+	}
+}
+
+func TestGatherLearnings_ExplorationTagsCitations(t *testing.T) {
+	dir := t.TempDir()
+	learningsDir := filepath.Join(dir, ".agents", "learnings")
+	if err := os.MkdirAll(learningsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for i := range MaxLearningsToInject + 3 {
+		body := fmt.Sprintf("---\nmaturity: provisional\nutility: 0.6\nsource_bead: ag-%d\n---\n# Learning %d\n\nSomething worth knowing.\n", i, i)
+		if err := os.WriteFile(filepath.Join(learningsDir, fmt.Sprintf("l%02d.md", i)), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e, err := newLearningExplorer(exploreUCB, 2, "session-explore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	learnings := gatherLearnings(dir, "", "session-explore", "", 0, nil, e)
+	if len(learnings) != MaxLearningsToInject || countSelection(learnings, selectionExplore) != 2 {
+		t.Fatalf("got %d learnings, %d explored", len(learnings), countSelection(learnings, selectionExplore))
+	}
+
+	citations, err := ratchet.LoadCitations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := map[string]int{}
+	for _, c := range citations {
+		tags[c.Selection]++
+	}
+	if tags[selectionExplore] != 2 || tags[selectionExploit] != MaxLearningsToInject-2 {
+		t.Errorf("citation selection tags = %v", tags)
+	}
+}
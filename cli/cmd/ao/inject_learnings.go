@@ -43,6 +43,10 @@ func collectLearnings(cwd, query string, limit int, globalDir string, globalWeig
 
 	now := time.Now()
 	queryLower := strings.ToLower(query)
+	hybrid := hybridRetrieval && query != ""
+	if hybrid {
+		queryLower = "" // relevance comes from hybrid ranking, not substring match
+	}
 	learnings := make([]learning, 0, len(files))
 
 	for _, file := range files {
@@ -83,6 +87,9 @@ func collectLearnings(cwd, query string, limit int, globalDir string, globalWeig
 	}
 
 	rankLearnings(learnings)
+	if hybrid {
+		learnings = rerankLearningsHybrid(cwd, query, learnings)
+	}
 
 	// Apply global weight penalty post-scoring
 	if globalWeight > 0 && globalWeight < 1.0 {
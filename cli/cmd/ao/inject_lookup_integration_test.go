@@ -56,7 +56,7 @@ Use specialized, independent judges for validation.
 
 	// Phase 1: Test index-only output
 	t.Run("phase1_index_output", func(t *testing.T) {
-		knowledge := gatherKnowledge(tmpDir, "", "test-session", nil)
+		knowledge := gatherKnowledge(tmpDir, "", "test-session", nil, nil)
 		indexOutput := renderKnowledgeIndex(knowledge)
 
 		// Should contain table headers
@@ -122,7 +122,7 @@ Use specialized, independent judges for validation.
 			os.WriteFile(filepath.Join(learningsDir, "extra-"+string(rune('a'+i))+".md"), []byte(content), 0644)
 		}
 
-		knowledge := gatherKnowledge(tmpDir, "", "test-session-2", nil)
+		knowledge := gatherKnowledge(tmpDir, "", "test-session-2", nil, nil)
 		indexOutput := renderKnowledgeIndex(knowledge)
 		fullOutput := formatKnowledgeMarkdown(knowledge)
 
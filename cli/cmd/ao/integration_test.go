@@ -156,7 +156,7 @@ func TestIntegration_RatchetNextAfterRecording(t *testing.T) {
 				Entries: tt.entries,
 			}
 
-			result := computeNextStep(chain)
+			result := computeNextStep(chain, ratchet.DefaultWorkflow())
 
 			if result.Next != tt.wantNext {
 				t.Errorf("Next = %q, want %q", result.Next, tt.wantNext)
@@ -234,7 +234,7 @@ func TestIntegration_RatchetNextProgressiveRecording(t *testing.T) {
 			t.Fatalf("reload chain after %s: %v", s.step, err)
 		}
 
-		result := computeNextStep(loaded)
+		result := computeNextStep(loaded, ratchet.DefaultWorkflow())
 		if result.Next != s.wantNext {
 			t.Errorf("after %s: Next = %q, want %q", s.step, result.Next, s.wantNext)
 		}
@@ -259,7 +259,7 @@ func TestIntegration_RatchetNextProgressiveRecording(t *testing.T) {
 		t.Fatalf("reload chain after post-mortem: %v", err)
 	}
 
-	result := computeNextStep(loaded)
+	result := computeNextStep(loaded, ratchet.DefaultWorkflow())
 	if !result.Complete {
 		t.Error("expected chain to be complete after post-mortem")
 	}
@@ -43,7 +43,8 @@ Examples:
   ao lookup learn-2026-02-22-cross-lang
   ao lookup --query "authentication" --limit 5
   ao lookup --bead ag-mrr
-  ao lookup --query "anti-patterns" --json`,
+  ao lookup --query "anti-patterns" --json
+  ao lookup --query "auth" --hybrid   # BM25 + local vector similarity`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runLookup,
 }
@@ -58,6 +59,7 @@ func init() {
 	lookupCmd.Flags().BoolVar(&lookupNoCite, "no-cite", false, "Skip citation recording")
 	lookupCmd.Flags().StringVar(&lookupCiteType, "cite", "retrieved", "Citation type to record for returned artifacts: retrieved, reference, applied")
 	lookupCmd.Flags().StringVar(&lookupSessionID, "session", "", "Session ID for citation tracking")
+	lookupCmd.Flags().BoolVar(&hybridRetrieval, "hybrid", false, hybridFlagUsage)
 }
 
 func runLookup(cmd *cobra.Command, args []string) error {
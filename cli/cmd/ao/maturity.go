@@ -236,11 +236,13 @@ func runMaturityRecalibrate(learningsDir string) error {
 		return nil
 	}
 
+	// learningsDir is <repo>/.agents/learnings; the history lives under <repo>.
+	baseDir := filepath.Dir(filepath.Dir(learningsDir))
 	recalibrated := 0
 	for _, file := range files {
 		// updateLearningUtility with alpha=1.0 and reward=InitialUtility
 		// yields: new = (1-1.0)*old + 1.0*0.5 = 0.5
-		_, _, err := updateLearningUtility(file, types.InitialUtility, 1.0)
+		_, _, err := updateLearningUtilityRecorded(baseDir, file, types.InitialUtility, 1.0, utilityOrigin{Source: "recalibrate"})
 		if err != nil {
 			VerbosePrintf("Warning: could not recalibrate %s: %v\n", filepath.Base(file), err)
 			continue
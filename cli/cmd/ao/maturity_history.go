@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var maturityHistorySparkline bool
+
+var maturityHistoryCmd = &cobra.Command{
+	Use:   "history <learning-id>",
+	Short: "Show the utility history of a learning",
+	Long: `Show every recorded utility update of a learning.
+
+Each update made by ao feedback, ao feedback-loop, the flywheel citation
+loop, task sync or --recalibrate is appended to .agents/ao/utility-history.jsonl
+with the old and new utility, the reward, the alpha used, and the session and
+citation type that drove it.
+
+Examples:
+  ao maturity history L001
+  ao maturity history L001 --sparkline
+  ao maturity history L001 -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMaturityHistory,
+}
+
+func init() {
+	maturityCmd.AddCommand(maturityHistoryCmd)
+	maturityHistoryCmd.Flags().BoolVar(&maturityHistorySparkline, "sparkline", false, "Print a one-line sparkline instead of the table")
+}
+
+// maturityHistoryReport is the JSON form of ao maturity history.
+type maturityHistoryReport struct {
+	LearningID string                `json:"learning_id"`
+	Path       string                `json:"path,omitempty"`
+	Events     []UtilityHistoryEvent `json:"events"`
+}
+
+func runMaturityHistory(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	learningID := args[0]
+	// A deleted learning keeps its history; match it by ID instead.
+	learningPath, _ := findLearningFile(cwd, learningID) //nolint:errcheck // fall back to ID match
+
+	all, err := loadUtilityHistory(cwd)
+	if err != nil {
+		return err
+	}
+	report := maturityHistoryReport{
+		LearningID: learningID,
+		Path:       learningPath,
+		Events:     utilityHistoryFor(cwd, all, learningID, learningPath),
+	}
+	if report.Events == nil {
+		report.Events = []UtilityHistoryEvent{}
+	}
+
+	w := cmd.OutOrStdout()
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	if len(report.Events) == 0 {
+		fmt.Fprintf(w, "No utility history for %s\n", learningID)
+		return nil
+	}
+	if maturityHistorySparkline {
+		first, last := report.Events[0], report.Events[len(report.Events)-1]
+		fmt.Fprintf(w, "%s  %s  %.3f → %.3f (%d updates)\n",
+			learningID, utilitySparkline(report.Events), first.OldUtility, last.NewUtility, len(report.Events))
+		return nil
+	}
+	return printUtilityHistoryTable(w, report.Events)
+}
+
+// printUtilityHistoryTable prints one row per utility update.
+func printUtilityHistoryTable(w io.Writer, events []UtilityHistoryEvent) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "WHEN\tSOURCE\tSESSION\tCITATION\tREWARD\tALPHA\tUTILITY") //nolint:errcheck // CLI tabwriter output
+	for _, e := range events {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.2f\t%.3f\t%.3f → %.3f\n", //nolint:errcheck // CLI tabwriter output
+			e.RecordedAt.Local().Format("2006-01-02 15:04"),
+			e.Source,
+			displayOrDash(truncateID(e.SessionID, 20)),
+			displayOrDash(e.CitationType),
+			e.Reward, e.Alpha, e.OldUtility, e.NewUtility)
+	}
+	return tw.Flush()
+}
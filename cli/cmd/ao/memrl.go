@@ -0,0 +1,411 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+var (
+	memrlReplayContract string
+	memrlReplayMode     string
+	memrlReplayRun      string
+)
+
+var memrlCmd = &cobra.Command{
+	Use:   "memrl",
+	Short: "Inspect MemRL gate retry policies",
+	Long: `Inspect MemRL gate retry policies and their recorded decisions.
+
+The MemRL policy decides whether a failed RPI gate is retried or escalated.
+With MEMRL_MODE=observe or enforce, every gate retry decision is recorded as
+a gate.policy.decision event in .agents/rpi/runs/<run-id>/events.jsonl. The
+memrl subcommands read that history.
+
+Examples:
+  ao memrl replay --contract new-policy.yaml`,
+}
+
+var memrlReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Re-evaluate recorded gate decisions under another policy contract",
+	Long: `Replay recorded gate retry decisions against an alternative policy contract.
+
+Each failed gate attempt recorded by ao rpi is re-evaluated with
+--contract (YAML or JSON, same fields as the built-in contract) in --mode.
+The report compares the replayed action with what the orchestrator did:
+
+  changed          decisions where the new contract picks a different action
+  saved retries    retries the new contract would have skipped on gates that
+                   escalated anyway
+  lost recoveries  gates that passed after a retry the new contract would
+                   have escalated instead
+  extra retries    escalations the new contract would have retried (outcome
+                   unknown, counted as one retry each)
+
+Rule coverage lists how often each rule of the contract fired, so a contract
+can be promoted from observe to enforce on evidence.
+
+Decisions come from gate.policy.decision events. Runs recorded before those
+events existed are read from the memrl lines in .agents/ledger/rpi-events.jsonl.
+
+Examples:
+  ao memrl replay --contract new-policy.yaml
+  ao memrl replay --contract new-policy.yaml --mode observe --run 3f2a9c1b
+  ao memrl replay --contract new-policy.yaml -o json`,
+	RunE: runMemRLReplay,
+}
+
+func init() {
+	memrlCmd.GroupID = "workflow"
+	rootCmd.AddCommand(memrlCmd)
+	memrlCmd.AddCommand(memrlReplayCmd)
+
+	memrlReplayCmd.Flags().StringVar(&memrlReplayContract, "contract", "", "Policy contract to replay (YAML or JSON)")
+	memrlReplayCmd.Flags().StringVar(&memrlReplayMode, "mode", string(types.MemRLModeEnforce), "Mode to evaluate the contract in (off, observe, enforce)")
+	memrlReplayCmd.Flags().StringVar(&memrlReplayRun, "run", "", "Only replay decisions from this run ID")
+	_ = memrlReplayCmd.MarkFlagRequired("contract")
+}
+
+// gatePolicyAttempt is one recorded gate retry decision.
+type gatePolicyAttempt struct {
+	RunID     string `json:"run_id"`
+	Source    string `json:"source"`
+	Timestamp string `json:"timestamp,omitempty"`
+	gatePolicyRecord
+}
+
+// memrlReplayDecision is one recorded decision next to its replay.
+type memrlReplayDecision struct {
+	RunID            string                   `json:"run_id"`
+	Phase            string                   `json:"phase"`
+	Attempt          int                      `json:"attempt"`
+	FailureClass     types.MemRLFailureClass  `json:"failure_class"`
+	AttemptBucket    types.MemRLAttemptBucket `json:"attempt_bucket"`
+	RecordedRule     string                   `json:"recorded_rule"`
+	RecordedContract string                   `json:"recorded_contract,omitempty"`
+	RecordedAction   types.MemRLAction        `json:"recorded_action"`
+	SelectedAction   types.MemRLAction        `json:"selected_action"`
+	ReplayRule       string                   `json:"replay_rule"`
+	ReplayAction     types.MemRLAction        `json:"replay_action"`
+	Impact           string                   `json:"impact,omitempty"`
+}
+
+// memrlReplayReport is the result of ao memrl replay.
+type memrlReplayReport struct {
+	Contract       string                `json:"contract"`
+	ContractHash   string                `json:"contract_hash"`
+	Mode           types.MemRLMode       `json:"memrl_mode"`
+	Runs           int                   `json:"runs"`
+	Gates          int                   `json:"gates"`
+	Decisions      int                   `json:"decisions"`
+	Changed        int                   `json:"changed"`
+	PolicyChanged  int                   `json:"policy_changed"`
+	SavedRetries   int                   `json:"saved_retries"`
+	LostRecoveries int                   `json:"lost_recoveries"`
+	ExtraRetries   int                   `json:"extra_retries"`
+	RecordedUnder  map[string]int        `json:"recorded_under,omitempty"`
+	RuleHits       map[string]int        `json:"rule_hits"`
+	UnhitRules     []string              `json:"unhit_rules,omitempty"`
+	RuleCoverage   float64               `json:"rule_coverage"`
+	Changes        []memrlReplayDecision `json:"changes"`
+}
+
+func runMemRLReplay(cmd *cobra.Command, _ []string) error {
+	mode := types.MemRLMode(strings.ToLower(strings.TrimSpace(memrlReplayMode)))
+	if types.ParseMemRLMode(string(mode)) != mode {
+		return fmt.Errorf("invalid --mode %q (want off, observe or enforce)", memrlReplayMode)
+	}
+	contract, err := loadMemRLContract(memrlReplayContract)
+	if err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	attempts, err := loadGatePolicyHistory(cwd)
+	if err != nil {
+		return err
+	}
+	if memrlReplayRun != "" {
+		attempts = slices.DeleteFunc(attempts, func(a gatePolicyAttempt) bool { return a.RunID != memrlReplayRun })
+	}
+
+	report := replayMemRLPolicy(contract, mode, attempts)
+	report.Contract = memrlReplayContract
+
+	w := cmd.OutOrStdout()
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return printMemRLReplayReport(w, report)
+}
+
+// loadGatePolicyHistory collects recorded gate retry decisions from every
+// run's C2 events, falling back to the ledger's memrl log lines for runs
+// that have no decision events.
+func loadGatePolicyHistory(root string) ([]gatePolicyAttempt, error) {
+	var attempts []gatePolicyAttempt
+	fromEvents := make(map[string]bool)
+
+	entries, err := os.ReadDir(filepath.Join(root, ".agents", "rpi", "runs"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read runs: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		events, err := loadRPIC2Events(root, entry.Name())
+		if err != nil {
+			VerbosePrintf("Warning: skipping run %s: %v\n", entry.Name(), err)
+			continue
+		}
+		for _, ev := range events {
+			if ev.Type != gatePolicyDecisionEvent {
+				continue
+			}
+			var rec gatePolicyRecord
+			if err := json.Unmarshal(ev.Details, &rec); err != nil {
+				VerbosePrintf("Warning: skipping malformed %s event %s: %v\n", ev.Type, ev.EventID, err)
+				continue
+			}
+			attempts = append(attempts, gatePolicyAttempt{RunID: ev.RunID, Source: "events", Timestamp: ev.Timestamp, gatePolicyRecord: rec})
+			fromEvents[ev.RunID] = true
+		}
+	}
+
+	records, err := LoadRPILedgerRecords(root)
+	if err != nil {
+		return nil, err
+	}
+	ledgerAttempts := make(map[string]int)
+	for _, record := range records {
+		if fromEvents[record.RunID] {
+			continue
+		}
+		var details struct {
+			Details string `json:"details"`
+		}
+		if json.Unmarshal(record.Details, &details) != nil {
+			continue
+		}
+		rec, ok := parseMemRLLogLine(details.Details)
+		if !ok {
+			continue
+		}
+		// The log line carries the attempt bucket but not the attempt;
+		// one line is written per failed attempt, so count them.
+		key := record.RunID + "\x00" + record.Phase
+		ledgerAttempts[key]++
+		rec.PhaseName = record.Phase
+		rec.Attempt = ledgerAttempts[key]
+		attempts = append(attempts, gatePolicyAttempt{RunID: record.RunID, Source: "ledger", Timestamp: record.TS, gatePolicyRecord: rec})
+	}
+	return attempts, nil
+}
+
+// parseMemRLLogLine parses a "memrl policy key=value ..." line written by
+// logGateRetryMemRL.
+func parseMemRLLogLine(line string) (gatePolicyRecord, bool) {
+	rest, ok := strings.CutPrefix(line, "memrl policy ")
+	if !ok {
+		return gatePolicyRecord{}, false
+	}
+	var rec gatePolicyRecord
+	for field := range strings.FieldsSeq(rest) {
+		key, value, _ := strings.Cut(field, "=")
+		switch key {
+		case "mode":
+			rec.Mode = types.MemRLMode(value)
+		case "failure_class":
+			rec.FailureClass = types.MemRLFailureClass(value)
+		case "attempt_bucket":
+			rec.AttemptBucket = types.MemRLAttemptBucket(value)
+		case "policy_action":
+			rec.Action = types.MemRLAction(value)
+		case "selected_action":
+			rec.SelectedAction = types.MemRLAction(value)
+		case "rule":
+			rec.RuleID = value
+		case "contract":
+			rec.ContractHash = value
+		}
+	}
+	rec.MetadataPresent = rec.FailureClass != "" && rec.AttemptBucket != ""
+	return rec, rec.SelectedAction != ""
+}
+
+// replayMemRLPolicy re-evaluates attempts under contract in mode.
+//
+// Attempts are grouped into gates (one run and phase). A gate whose last
+// recorded action was retry is taken to have passed after that retry. Only
+// the first decision in a gate that the replay changes is priced: after it,
+// the gate would have gone differently and the recorded history no longer
+// applies.
+func replayMemRLPolicy(contract types.MemRLPolicyContract, mode types.MemRLMode, attempts []gatePolicyAttempt) *memrlReplayReport {
+	report := &memrlReplayReport{
+		ContractHash:  types.MemRLPolicyContractHash(contract),
+		Mode:          mode,
+		Decisions:     len(attempts),
+		RecordedUnder: make(map[string]int),
+		RuleHits:      make(map[string]int),
+		Changes:       []memrlReplayDecision{},
+	}
+
+	type gateKey struct{ run, phase string }
+	gates := make(map[gateKey][]gatePolicyAttempt)
+	var order []gateKey
+	runs := make(map[string]bool)
+	for _, a := range attempts {
+		k := gateKey{a.RunID, a.PhaseName}
+		if _, ok := gates[k]; !ok {
+			order = append(order, k)
+		}
+		gates[k] = append(gates[k], a)
+		runs[a.RunID] = true
+		if a.ContractHash != "" {
+			report.RecordedUnder[a.ContractHash]++
+		}
+	}
+	report.Runs = len(runs)
+	report.Gates = len(order)
+
+	for _, k := range order {
+		gate := gates[k]
+		slices.SortStableFunc(gate, func(a, b gatePolicyAttempt) int { return cmp.Compare(a.Attempt, b.Attempt) })
+		escalated := gate[len(gate)-1].SelectedAction == types.MemRLActionEscalate
+
+		diverged := false
+		for i, a := range gate {
+			replay := types.EvaluateMemRLPolicy(contract, types.MemRLPolicyInput{
+				Mode:            mode,
+				FailureClass:    a.FailureClass,
+				AttemptBucket:   a.AttemptBucket,
+				Attempt:         a.Attempt,
+				MaxAttempts:     a.MaxRetries,
+				MetadataPresent: a.MetadataPresent,
+			})
+			report.RuleHits[replay.RuleID]++
+			if replay.Action != a.Action {
+				report.PolicyChanged++
+			}
+			if replay.Action == a.SelectedAction {
+				continue
+			}
+			report.Changed++
+			d := memrlReplayDecision{
+				RunID:            a.RunID,
+				Phase:            a.PhaseName,
+				Attempt:          a.Attempt,
+				FailureClass:     a.FailureClass,
+				AttemptBucket:    a.AttemptBucket,
+				RecordedRule:     a.RuleID,
+				RecordedContract: a.ContractHash,
+				RecordedAction:   a.Action,
+				SelectedAction:   a.SelectedAction,
+				ReplayRule:       replay.RuleID,
+				ReplayAction:     replay.Action,
+			}
+			if !diverged {
+				diverged = true
+				d.Impact = priceReplayChange(report, gate[i:], replay.Action, escalated)
+			}
+			report.Changes = append(report.Changes, d)
+		}
+	}
+
+	var inMode int
+	for _, rule := range contract.Rules {
+		if rule.Mode != mode {
+			continue
+		}
+		inMode++
+		if report.RuleHits[rule.RuleID] == 0 {
+			report.UnhitRules = append(report.UnhitRules, rule.RuleID)
+		}
+	}
+	if inMode > 0 {
+		report.RuleCoverage = float64(inMode-len(report.UnhitRules)) / float64(inMode)
+	}
+	return report
+}
+
+// priceReplayChange adds the estimated effect of replacing the action taken
+// at rest[0] with replayed to report and describes it.
+func priceReplayChange(report *memrlReplayReport, rest []gatePolicyAttempt, replayed types.MemRLAction, escalated bool) string {
+	if replayed == types.MemRLActionRetry {
+		report.ExtraRetries++
+		return "extra retry"
+	}
+	if !escalated {
+		report.LostRecoveries++
+		return "loses recovery"
+	}
+	saved := 0
+	for _, a := range rest {
+		if a.SelectedAction == types.MemRLActionRetry {
+			saved++
+		}
+	}
+	report.SavedRetries += saved
+	return fmt.Sprintf("saves %d retries", saved)
+}
+
+func printMemRLReplayReport(w io.Writer, report *memrlReplayReport) error {
+	fmt.Fprintf(w, "MemRL replay: %s (mode=%s, hash %s)\n", report.Contract, report.Mode, report.ContractHash)
+	fmt.Fprintf(w, "  %d decisions across %d gates in %d runs\n", report.Decisions, report.Gates, report.Runs)
+	for _, hash := range slices.Sorted(maps.Keys(report.RecordedUnder)) {
+		fmt.Fprintf(w, "  %d recorded under contract %s\n", report.RecordedUnder[hash], hash)
+	}
+	fmt.Fprintln(w)
+	if report.Decisions == 0 {
+		fmt.Fprintln(w, "No recorded gate decisions. Run ao rpi with MEMRL_MODE=observe to record them.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Changed decisions: %d (vs recorded policy: %d)\n", report.Changed, report.PolicyChanged)
+	fmt.Fprintf(w, "Saved retries:     %d\n", report.SavedRetries)
+	fmt.Fprintf(w, "Lost recoveries:   %d\n", report.LostRecoveries)
+	fmt.Fprintf(w, "Extra retries:     %d\n\n", report.ExtraRetries)
+
+	if len(report.Changes) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		//nolint:errcheck // CLI tabwriter output
+		fmt.Fprintln(tw, "RUN\tPHASE\tATTEMPT\tCLASS\tTOOK\tREPLAY\tRULE\tIMPACT")
+		for _, d := range report.Changes {
+			//nolint:errcheck // CLI tabwriter output
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+				truncateID(d.RunID, 12), d.Phase, d.Attempt, d.FailureClass,
+				d.SelectedAction, d.ReplayAction, d.ReplayRule, d.Impact)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "Rule coverage: %.0f%% of %s rules fired\n", report.RuleCoverage*100, report.Mode)
+	names := slices.SortedFunc(maps.Keys(report.RuleHits), func(a, b string) int {
+		return cmp.Or(cmp.Compare(report.RuleHits[b], report.RuleHits[a]), cmp.Compare(a, b))
+	})
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-40s %d\n", name, report.RuleHits[name])
+	}
+	return nil
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// memrlPolicyRelPath is the per-repo MemRL policy contract.
+const memrlPolicyRelPath = ".agents/memrl/policy.yaml"
+
+// builtinMemRLPolicy names the contract compiled into the binary wherever a
+// contract path is accepted.
+const builtinMemRLPolicy = "builtin"
+
+var memrlPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Show, validate and diff MemRL policy contracts",
+	Long: `Manage the MemRL policy contract used by ao rpi gate retries.
+
+A repo can override the built-in contract with .agents/memrl/policy.yaml.
+The file has the same fields as the built-in contract (see ao memrl policy
+show) and is validated when loaded: unknown fields, unknown failure classes,
+duplicate rule ids and incomplete rollback triggers are rejected.
+schema_version must not be newer than this build understands.
+
+Every recorded policy decision carries the contract_hash of the contract
+that produced it, so the ledger can be audited against the exact policy.
+
+Wherever a contract path is taken, "builtin" names the compiled-in contract.
+
+Examples:
+  ao memrl policy show > .agents/memrl/policy.yaml
+  ao memrl policy validate
+  ao memrl policy diff`,
+}
+
+var memrlPolicyShowCmd = &cobra.Command{
+	Use:   "show [path]",
+	Short: "Print the effective policy contract",
+	Long: `Print a policy contract as YAML, or as JSON with -o json.
+
+Without a path, shows the contract ao rpi would use here: .agents/memrl/policy.yaml
+if it exists, else the built-in contract.
+
+Examples:
+  ao memrl policy show
+  ao memrl policy show builtin -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMemRLPolicyShow,
+}
+
+var memrlPolicyValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Validate a policy contract",
+	Long: `Validate a policy contract against the contract schema.
+
+Without a path, validates .agents/memrl/policy.yaml.
+
+Examples:
+  ao memrl policy validate
+  ao memrl policy validate candidate.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMemRLPolicyValidate,
+}
+
+var memrlPolicyDiffCmd = &cobra.Command{
+	Use:   "diff [old] [new]",
+	Short: "Compare two policy contracts",
+	Long: `Compare two policy contracts rule by rule.
+
+With no arguments, compares the built-in contract with the effective one.
+With one argument, compares the effective contract with that file.
+
+Examples:
+  ao memrl policy diff
+  ao memrl policy diff candidate.yaml
+  ao memrl policy diff builtin candidate.yaml -o json`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runMemRLPolicyDiff,
+}
+
+func init() {
+	memrlCmd.AddCommand(memrlPolicyCmd)
+	memrlPolicyCmd.AddCommand(memrlPolicyShowCmd, memrlPolicyValidateCmd, memrlPolicyDiffCmd)
+}
+
+// loadMemRLContract reads a policy contract from YAML or JSON and validates
+// it. Field names are those of the contract schema.
+func loadMemRLContract(path string) (types.MemRLPolicyContract, error) {
+	var contract types.MemRLPolicyContract
+	if path == builtinMemRLPolicy {
+		return types.DefaultMemRLPolicyContract(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return contract, fmt.Errorf("read contract: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&contract); err != nil && !errors.Is(err, io.EOF) {
+		return contract, fmt.Errorf("parse contract %s: %w", path, err)
+	}
+	if err := types.ValidateMemRLPolicyContract(contract); err != nil {
+		return contract, fmt.Errorf("invalid contract %s: %w", path, err)
+	}
+	return contract, nil
+}
+
+// resolveMemRLContract returns the repo's policy contract and where it came
+// from, or the built-in contract when the repo has none.
+func resolveMemRLContract(root string) (types.MemRLPolicyContract, string, error) {
+	path := filepath.Join(root, memrlPolicyRelPath)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return types.DefaultMemRLPolicyContract(), builtinMemRLPolicy, nil
+	}
+	contract, err := loadMemRLContract(path)
+	return contract, path, err
+}
+
+// gateMemRLContract returns the contract gate retries are evaluated under.
+// A broken repo policy must not wedge a run, so it falls back to the
+// built-in contract with a warning.
+func gateMemRLContract(cwd string) types.MemRLPolicyContract {
+	contract, _, err := resolveMemRLContract(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using the built-in MemRL policy\n", err)
+		return types.DefaultMemRLPolicyContract()
+	}
+	return contract
+}
+
+// contractArg resolves an optional contract argument: empty means the
+// effective contract for the working directory.
+func contractArg(path string) (types.MemRLPolicyContract, string, error) {
+	if path != "" {
+		contract, err := loadMemRLContract(path)
+		return contract, path, err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return types.MemRLPolicyContract{}, "", fmt.Errorf("get working directory: %w", err)
+	}
+	return resolveMemRLContract(cwd)
+}
+
+func runMemRLPolicyShow(cmd *cobra.Command, args []string) error {
+	contract, source, err := contractArg(optionalArg(args, 0))
+	if err != nil {
+		return err
+	}
+	hash := types.MemRLPolicyContractHash(contract)
+	w := cmd.OutOrStdout()
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"source": source, "contract_hash": hash, "contract": contract})
+	}
+	data, err := yaml.Marshal(contract)
+	if err != nil {
+		return fmt.Errorf("marshal contract: %w", err)
+	}
+	fmt.Fprintf(w, "# source: %s\n# contract_hash: %s\n", source, hash)
+	_, err = w.Write(data)
+	return err
+}
+
+// memrlPolicyValidation is the result of ao memrl policy validate.
+type memrlPolicyValidation struct {
+	Path             string `json:"path"`
+	Valid            bool   `json:"valid"`
+	Error            string `json:"error,omitempty"`
+	SchemaVersion    int    `json:"schema_version,omitempty"`
+	ContractHash     string `json:"contract_hash,omitempty"`
+	Rules            int    `json:"rules,omitempty"`
+	RollbackTriggers int    `json:"rollback_triggers,omitempty"`
+}
+
+func runMemRLPolicyValidate(cmd *cobra.Command, args []string) error {
+	path := optionalArg(args, 0)
+	if path == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("get working directory: %w", err)
+		}
+		path = filepath.Join(cwd, memrlPolicyRelPath)
+	}
+
+	result := memrlPolicyValidation{Path: path}
+	contract, loadErr := loadMemRLContract(path)
+	if loadErr != nil {
+		result.Error = loadErr.Error()
+	} else {
+		result.Valid = true
+		result.SchemaVersion = contract.SchemaVersion
+		result.ContractHash = types.MemRLPolicyContractHash(contract)
+		result.Rules = len(contract.Rules)
+		result.RollbackTriggers = len(contract.RollbackMatrix)
+	}
+
+	w := cmd.OutOrStdout()
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+		return loadErr
+	}
+	if loadErr != nil {
+		return loadErr
+	}
+	fmt.Fprintf(w, "✓ %s: valid (schema v%d, %d rules, %d rollback triggers, hash %s)\n",
+		path, result.SchemaVersion, result.Rules, result.RollbackTriggers, result.ContractHash)
+	return nil
+}
+
+// memrlFieldChange is one field that differs between two contracts.
+type memrlFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// memrlEntryChange is a rule or rollback trigger present in both contracts
+// whose fields differ.
+type memrlEntryChange struct {
+	ID      string             `json:"id"`
+	Changes []memrlFieldChange `json:"changes"`
+}
+
+// memrlPolicyDiff is the result of ao memrl policy diff.
+type memrlPolicyDiff struct {
+	Old             string             `json:"old"`
+	New             string             `json:"new"`
+	OldHash         string             `json:"old_hash"`
+	NewHash         string             `json:"new_hash"`
+	Fields          []memrlFieldChange `json:"fields,omitempty"`
+	RulesAdded      []string           `json:"rules_added,omitempty"`
+	RulesRemoved    []string           `json:"rules_removed,omitempty"`
+	RulesChanged    []memrlEntryChange `json:"rules_changed,omitempty"`
+	TriggersAdded   []string           `json:"triggers_added,omitempty"`
+	TriggersRemoved []string           `json:"triggers_removed,omitempty"`
+	TriggersChanged []memrlEntryChange `json:"triggers_changed,omitempty"`
+}
+
+// Empty reports whether the two contracts are identical.
+func (d *memrlPolicyDiff) Empty() bool {
+	return d.OldHash == d.NewHash
+}
+
+func runMemRLPolicyDiff(cmd *cobra.Command, args []string) error {
+	oldPath, newPath := builtinMemRLPolicy, ""
+	switch len(args) {
+	case 1:
+		oldPath, newPath = "", args[0]
+	case 2:
+		oldPath, newPath = args[0], args[1]
+	}
+	oldContract, oldSource, err := contractArg(oldPath)
+	if err != nil {
+		return err
+	}
+	newContract, newSource, err := contractArg(newPath)
+	if err != nil {
+		return err
+	}
+
+	diff := diffMemRLContracts(oldContract, newContract)
+	diff.Old, diff.New = oldSource, newSource
+
+	w := cmd.OutOrStdout()
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
+	}
+	printMemRLPolicyDiff(w, diff)
+	return nil
+}
+
+// diffMemRLContracts compares two contracts field by field, matching rules
+// by rule_id and rollback triggers by trigger_id.
+func diffMemRLContracts(oldC, newC types.MemRLPolicyContract) *memrlPolicyDiff {
+	diff := &memrlPolicyDiff{
+		OldHash: types.MemRLPolicyContractHash(oldC),
+		NewHash: types.MemRLPolicyContractHash(newC),
+		Fields: fieldChanges(
+			[]string{"schema_version", "default_mode", "unknown_failure_class_action", "missing_metadata_action", "tie_break_rules"},
+			[]string{strconv.Itoa(oldC.SchemaVersion), string(oldC.DefaultMode), string(oldC.UnknownFailureClassAction), string(oldC.MissingMetadataAction), strings.Join(oldC.TieBreakRules, "; ")},
+			[]string{strconv.Itoa(newC.SchemaVersion), string(newC.DefaultMode), string(newC.UnknownFailureClassAction), string(newC.MissingMetadataAction), strings.Join(newC.TieBreakRules, "; ")},
+		),
+	}
+
+	ruleFields := []string{"memrl_mode", "failure_class", "attempt_bucket", "action", "priority"}
+	ruleValues := func(r types.MemRLPolicyRule) []string {
+		return []string{string(r.Mode), string(r.FailureClass), string(r.AttemptBucket), string(r.Action), strconv.Itoa(r.Priority)}
+	}
+	diff.RulesAdded, diff.RulesRemoved, diff.RulesChanged = diffEntries(oldC.Rules, newC.Rules,
+		func(r types.MemRLPolicyRule) string { return r.RuleID },
+		func(a, b types.MemRLPolicyRule) []memrlFieldChange {
+			return fieldChanges(ruleFields, ruleValues(a), ruleValues(b))
+		})
+
+	triggerFields := []string{"metric", "metric_source_command", "lookback_window", "min_sample_size", "threshold", "operator_action", "verification_command"}
+	triggerValues := func(t types.MemRLRollbackTrigger) []string {
+		return []string{t.Metric, t.MetricSourceCommand, t.LookbackWindow, strconv.Itoa(t.MinSampleSize), t.Threshold, t.OperatorAction, t.VerificationCommand}
+	}
+	diff.TriggersAdded, diff.TriggersRemoved, diff.TriggersChanged = diffEntries(oldC.RollbackMatrix, newC.RollbackMatrix,
+		func(t types.MemRLRollbackTrigger) string { return t.TriggerID },
+		func(a, b types.MemRLRollbackTrigger) []memrlFieldChange {
+			return fieldChanges(triggerFields, triggerValues(a), triggerValues(b))
+		})
+	return diff
+}
+
+// diffEntries matches entries by id and reports added, removed and changed
+// ids, each in the order they appear.
+func diffEntries[T any](oldEntries, newEntries []T, id func(T) string, compare func(a, b T) []memrlFieldChange) (added, removed []string, changed []memrlEntryChange) {
+	oldByID := make(map[string]T, len(oldEntries))
+	for _, e := range oldEntries {
+		oldByID[id(e)] = e
+	}
+	newIDs := make(map[string]bool, len(newEntries))
+	for _, e := range newEntries {
+		newIDs[id(e)] = true
+		prev, ok := oldByID[id(e)]
+		if !ok {
+			added = append(added, id(e))
+			continue
+		}
+		if changes := compare(prev, e); len(changes) > 0 {
+			changed = append(changed, memrlEntryChange{ID: id(e), Changes: changes})
+		}
+	}
+	for _, e := range oldEntries {
+		if !newIDs[id(e)] {
+			removed = append(removed, id(e))
+		}
+	}
+	return added, removed, changed
+}
+
+func fieldChanges(fields, oldValues, newValues []string) []memrlFieldChange {
+	var changes []memrlFieldChange
+	for i, field := range fields {
+		if oldValues[i] != newValues[i] {
+			changes = append(changes, memrlFieldChange{Field: field, Old: oldValues[i], New: newValues[i]})
+		}
+	}
+	return changes
+}
+
+func printMemRLPolicyDiff(w io.Writer, diff *memrlPolicyDiff) {
+	fmt.Fprintf(w, "--- %s (%s)\n+++ %s (%s)\n", diff.Old, diff.OldHash, diff.New, diff.NewHash)
+	if diff.Empty() {
+		fmt.Fprintln(w, "No differences.")
+		return
+	}
+	for _, c := range diff.Fields {
+		fmt.Fprintf(w, "~ %s: %s -> %s\n", c.Field, c.Old, c.New)
+	}
+	printEntryDiff(w, "rule", diff.RulesAdded, diff.RulesRemoved, diff.RulesChanged)
+	printEntryDiff(w, "rollback trigger", diff.TriggersAdded, diff.TriggersRemoved, diff.TriggersChanged)
+}
+
+func printEntryDiff(w io.Writer, kind string, added, removed []string, changed []memrlEntryChange) {
+	for _, id := range added {
+		fmt.Fprintf(w, "+ %s %s\n", kind, id)
+	}
+	for _, id := range removed {
+		fmt.Fprintf(w, "- %s %s\n", kind, id)
+	}
+	for _, c := range changed {
+		parts := make([]string, len(c.Changes))
+		for i, fc := range c.Changes {
+			parts[i] = fmt.Sprintf("%s %s -> %s", fc.Field, fc.Old, fc.New)
+		}
+		fmt.Fprintf(w, "~ %s %s: %s\n", kind, c.ID, strings.Join(parts, ", "))
+	}
+}
+
+// optionalArg returns args[i], or "" when it was not given.
+func optionalArg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+func writeRepoPolicy(t *testing.T, dir string, contract types.MemRLPolicyContract) string {
+	t.Helper()
+	path := filepath.Join(dir, memrlPolicyRelPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveMemRLContract(t *testing.T) {
+	dir := t.TempDir()
+	builtin := types.DefaultMemRLPolicyContract()
+
+	_, source, err := resolveMemRLContract(dir)
+	if err != nil || source != builtinMemRLPolicy {
+		t.Fatalf("without a repo policy: source=%q err=%v, want builtin", source, err)
+	}
+
+	custom := types.DefaultMemRLPolicyContract()
+	custom.DefaultMode = types.MemRLModeObserve
+	path := writeRepoPolicy(t, dir, custom)
+	got, source, err := resolveMemRLContract(dir)
+	if err != nil || source != path || got.DefaultMode != types.MemRLModeObserve {
+		t.Fatalf("with a repo policy: source=%q mode=%q err=%v", source, got.DefaultMode, err)
+	}
+
+	// A broken repo policy is an error for the policy commands, but gate
+	// retries fall back to the built-in contract.
+	if err := os.WriteFile(path, []byte("schema_version: 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := resolveMemRLContract(dir); err == nil || !strings.Contains(err.Error(), "unsupported schema_version") {
+		t.Errorf("err = %v, want unsupported schema_version", err)
+	}
+	if fallback := gateMemRLContract(dir); types.MemRLPolicyContractHash(fallback) != types.MemRLPolicyContractHash(builtin) {
+		t.Error("broken repo policy should fall back to the built-in contract")
+	}
+}
+
+func TestGateRetryDecisionCarriesContractHash(t *testing.T) {
+	t.Setenv(types.MemRLModeEnvVar, string(types.MemRLModeEnforce))
+	custom := types.DefaultMemRLPolicyContract()
+	for i, rule := range custom.Rules {
+		if rule.RuleID == "enforce.vibe_fail.initial" {
+			custom.Rules[i].Action = types.MemRLActionEscalate
+		}
+	}
+
+	state := &phasedState{RunID: "run-1", Opts: defaultPhasedEngineOptions()}
+	action, decision := resolveGateRetryAction(custom, state, 3, &gateFailError{Phase: 3, Verdict: "FAIL"}, 1)
+	if action != types.MemRLActionEscalate || decision.RuleID != "enforce.vibe_fail.initial" {
+		t.Fatalf("action=%s rule=%s, want the repo contract's escalate", action, decision.RuleID)
+	}
+	if decision.ContractHash != types.MemRLPolicyContractHash(custom) {
+		t.Errorf("decision contract hash = %q, want %q", decision.ContractHash, types.MemRLPolicyContractHash(custom))
+	}
+
+	// The hash survives the ledger log line.
+	root := t.TempDir()
+	logPath := filepath.Join(root, ".agents", "rpi", "phased-orchestration.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	logGateRetryMemRL(logPath, state.RunID, "validation", decision, action)
+	attempts, err := loadGatePolicyHistory(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 1 || attempts[0].ContractHash != decision.ContractHash {
+		t.Errorf("ledger attempts = %+v, want one stamped with %s", attempts, decision.ContractHash)
+	}
+}
+
+func TestMemRLPolicyShowRoundTrips(t *testing.T) {
+	dir := chdirTemp(t)
+	out, err := executeCommand("memrl", "policy", "show")
+	if err != nil {
+		t.Fatalf("show: %v\n%s", err, out)
+	}
+	builtinHash := types.MemRLPolicyContractHash(types.DefaultMemRLPolicyContract())
+	if !strings.Contains(out, "# source: builtin") || !strings.Contains(out, "contract_hash: "+builtinHash) {
+		t.Errorf("show header missing source or hash:\n%s", out)
+	}
+
+	// The shown YAML is a valid repo policy with the same hash.
+	path := filepath.Join(dir, memrlPolicyRelPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(out[strings.Index(out, "# source"):]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = executeCommand("memrl", "policy", "validate")
+	if err != nil {
+		t.Fatalf("validate: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "valid") || !strings.Contains(out, builtinHash) {
+		t.Errorf("validate output = %s", out)
+	}
+}
+
+func TestMemRLPolicyValidate_RejectsBadPolicy(t *testing.T) {
+	dir := chdirTemp(t)
+	contract := types.DefaultMemRLPolicyContract()
+	contract.Rules = append(contract.Rules, contract.Rules[0])
+	writeRepoPolicy(t, dir, contract)
+
+	out, err := executeCommand("memrl", "policy", "validate")
+	if err == nil || !strings.Contains(err.Error(), "duplicate id") {
+		t.Errorf("err = %v, want duplicate id\n%s", err, out)
+	}
+}
+
+func TestDiffMemRLContracts(t *testing.T) {
+	oldC := types.DefaultMemRLPolicyContract()
+	newC := types.DefaultMemRLPolicyContract()
+	newC.DefaultMode = types.MemRLModeObserve
+	newC.Rules = newC.Rules[1:]
+	newC.Rules[0].Action = types.MemRLActionEscalate
+	newC.Rules[0].Priority = 150
+	newC.Rules = append(newC.Rules, types.MemRLPolicyRule{
+		RuleID: "enforce.vibe_fail.any", Mode: types.MemRLModeEnforce,
+		FailureClass: types.MemRLFailureClassVibeFail, AttemptBucket: types.MemRLAttemptBucketAny,
+		Action: types.MemRLActionEscalate, Priority: 50,
+	})
+	newC.RollbackMatrix[0].Threshold = "escalation_rate > 0.50"
+
+	diff := diffMemRLContracts(oldC, newC)
+	if diff.Empty() {
+		t.Fatal("diff should not be empty")
+	}
+	if len(diff.Fields) != 1 || diff.Fields[0].Field != "default_mode" {
+		t.Errorf("fields = %+v", diff.Fields)
+	}
+	if strings.Join(diff.RulesAdded, ",") != "enforce.vibe_fail.any" || strings.Join(diff.RulesRemoved, ",") != oldC.Rules[0].RuleID {
+		t.Errorf("added=%v removed=%v", diff.RulesAdded, diff.RulesRemoved)
+	}
+	if len(diff.RulesChanged) != 1 || diff.RulesChanged[0].ID != oldC.Rules[1].RuleID || len(diff.RulesChanged[0].Changes) != 2 {
+		t.Errorf("changed = %+v", diff.RulesChanged)
+	}
+	if len(diff.TriggersChanged) != 1 || diff.TriggersChanged[0].Changes[0].Field != "threshold" {
+		t.Errorf("triggers changed = %+v", diff.TriggersChanged)
+	}
+
+	if same := diffMemRLContracts(oldC, types.DefaultMemRLPolicyContract()); !same.Empty() || len(same.RulesChanged) != 0 {
+		t.Errorf("identical contracts should not differ: %+v", same)
+	}
+}
+
+func TestMemRLPolicyDiff_Command(t *testing.T) {
+	dir := chdirTemp(t)
+	custom := types.DefaultMemRLPolicyContract()
+	custom.MissingMetadataAction = types.MemRLActionRetry
+	writeRepoPolicy(t, dir, custom)
+
+	out, err := executeCommand("memrl", "policy", "diff")
+	if err != nil {
+		t.Fatalf("diff: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "--- builtin") || !strings.Contains(out, "~ missing_metadata_action: escalate -> retry") {
+		t.Errorf("diff output:\n%s", out)
+	}
+}
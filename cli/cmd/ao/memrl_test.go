@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// recordGateAttempts writes one gate.policy.decision event per action, as
+// handleGateRetry would under observe mode.
+func recordGateAttempts(t *testing.T, root, runID string, phaseNum int, verdict string, selected ...types.MemRLAction) {
+	t.Helper()
+	state := &phasedState{RunID: runID, Opts: defaultPhasedEngineOptions()}
+	gateErr := &gateFailError{Phase: phaseNum, Verdict: verdict}
+	for i, action := range selected {
+		attempt := i + 1
+		decision := types.EvaluateDefaultMemRLPolicy(types.MemRLPolicyInput{
+			Mode:         types.MemRLModeObserve,
+			FailureClass: classifyGateFailureClass(phaseNum, gateErr),
+			Attempt:      attempt,
+			MaxAttempts:  state.Opts.MaxRetries,
+		})
+		recordGatePolicyDecision(root, state, phaseNum, attempt, gateErr, decision, action)
+	}
+}
+
+// writeEscalateEarlyContract writes the default contract with vibe failures
+// escalated on the first attempt in enforce mode.
+func writeEscalateEarlyContract(t *testing.T, dir string) string {
+	t.Helper()
+	contract := types.DefaultMemRLPolicyContract()
+	for i, rule := range contract.Rules {
+		if rule.RuleID == "enforce.vibe_fail.initial" {
+			contract.Rules[i].Action = types.MemRLActionEscalate
+		}
+	}
+	data, err := json.Marshal(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "escalate-early.yaml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReplayMemRLPolicy(t *testing.T) {
+	root := t.TempDir()
+	retry, escalate := types.MemRLActionRetry, types.MemRLActionEscalate
+	// Vibe failed three times and escalated; a second run's vibe passed
+	// after one retry; a pre-mortem passed after one retry.
+	recordGateAttempts(t, root, "run-escalated", 3, "FAIL", retry, retry, escalate)
+	recordGateAttempts(t, root, "run-recovered", 3, "FAIL", retry)
+	recordGateAttempts(t, root, "run-recovered", 1, "FAIL", retry)
+
+	// An older run that only left the ledger's log line: a stall the
+	// orchestrator escalated straight away.
+	if _, err := appendRPILedgerEvent(root, rpiLedgerEvent{
+		RunID:  "run-legacy",
+		Phase:  "implementation",
+		Action: "memrl",
+		Details: map[string]any{"details": "memrl policy mode=observe failure_class=phase_stall attempt_bucket=initial " +
+			"policy_action=retry selected_action=escalate rule=observe.phase_stall.initial"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	attempts, err := loadGatePolicyHistory(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 6 {
+		t.Fatalf("loaded %d attempts, want 6", len(attempts))
+	}
+
+	contract, err := loadMemRLContract(writeEscalateEarlyContract(t, root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := replayMemRLPolicy(contract, types.MemRLModeEnforce, attempts)
+
+	if report.Runs != 3 || report.Gates != 4 || report.Decisions != 6 {
+		t.Errorf("runs/gates/decisions = %d/%d/%d, want 3/4/6", report.Runs, report.Gates, report.Decisions)
+	}
+	if report.Changed != 3 {
+		t.Errorf("changed = %d, want 3", report.Changed)
+	}
+	if report.SavedRetries != 2 || report.LostRecoveries != 1 || report.ExtraRetries != 1 {
+		t.Errorf("saved/lost/extra = %d/%d/%d, want 2/1/1", report.SavedRetries, report.LostRecoveries, report.ExtraRetries)
+	}
+	if report.RuleHits["enforce.vibe_fail.initial"] != 2 {
+		t.Errorf("rule hits = %v", report.RuleHits)
+	}
+	// Legacy ledger lines predate contract hashes.
+	if builtin := types.MemRLPolicyContractHash(types.DefaultMemRLPolicyContract()); report.RecordedUnder[builtin] != 5 || len(report.RecordedUnder) != 1 {
+		t.Errorf("recorded under = %v, want 5 decisions under the built-in contract", report.RecordedUnder)
+	}
+	if report.RuleCoverage <= 0 || report.RuleCoverage >= 1 {
+		t.Errorf("rule coverage = %v, want a partial fraction", report.RuleCoverage)
+	}
+
+	var impacts []string
+	for _, c := range report.Changes {
+		impacts = append(impacts, c.RunID+":"+c.Impact)
+	}
+	want := "run-escalated:saves 2 retries,run-recovered:loses recovery,run-legacy:extra retry"
+	if got := strings.Join(impacts, ","); got != want {
+		t.Errorf("impacts = %s, want %s", got, want)
+	}
+}
+
+func TestReplayMemRLPolicy_DefaultContractInObserveMatchesRecord(t *testing.T) {
+	root := t.TempDir()
+	recordGateAttempts(t, root, "run-1", 3, "FAIL", types.MemRLActionRetry, types.MemRLActionRetry, types.MemRLActionEscalate)
+	attempts, err := loadGatePolicyHistory(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := replayMemRLPolicy(types.DefaultMemRLPolicyContract(), types.MemRLModeObserve, attempts)
+	if report.PolicyChanged != 0 || report.Changed != 0 {
+		t.Errorf("replaying the recorded contract should change nothing: %+v", report)
+	}
+}
+
+func TestLoadMemRLContract_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct{ name, body, wantErr string }{
+		{"unknown field", "schema_version: 1\nbogus: true\n", "field bogus not found"},
+		{"invalid", "schema_version: 0\n", "invalid contract"},
+		{"not yaml", "rules: [\n", "parse contract"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-")+".yaml")
+			if err := os.WriteFile(path, []byte(tt.body), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadMemRLContract(path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMemRLReplay_JSONOutput(t *testing.T) {
+	dir := chdirTemp(t)
+	recordGateAttempts(t, dir, "run-1", 3, "FAIL", types.MemRLActionRetry)
+	contract := writeEscalateEarlyContract(t, dir)
+
+	out, err := executeCommand("memrl", "replay", "--contract", contract, "-o", "json")
+	if err != nil {
+		t.Fatalf("memrl replay: %v\n%s", err, out)
+	}
+	var report memrlReplayReport
+	if err := json.Unmarshal([]byte(out[strings.Index(out, "{"):]), &report); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	if report.Decisions != 1 || report.LostRecoveries != 1 || report.Mode != types.MemRLModeEnforce {
+		t.Errorf("report = %+v", report)
+	}
+}
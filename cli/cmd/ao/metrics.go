@@ -549,6 +549,28 @@ func printMetricsTable(m *types.FlywheelMetrics) {
 	}
 	printMetricsLoopClosure(m)
 	printMetricsUtility(m)
+	printMetricsUtilityMovers(m)
+}
+
+// printMetricsUtilityMovers prints the period's biggest utility gains and losses.
+func printMetricsUtilityMovers(m *types.FlywheelMetrics) {
+	if m.UtilityMovers == nil {
+		return
+	}
+	printMovers := func(title string, movers []types.UtilityMover) {
+		if len(movers) == 0 {
+			return
+		}
+		fmt.Printf("  %s:\n", title)
+		for _, mv := range movers {
+			fmt.Printf("    %-32s %.3f → %.3f (%+.3f, %d updates)\n",
+				truncateText(mv.LearningID, 32), mv.From, mv.To, mv.Delta, mv.Updates)
+		}
+	}
+	fmt.Println()
+	fmt.Println("UTILITY MOVERS:")
+	printMovers("Most improved", m.UtilityMovers.MostImproved)
+	printMovers("Most degraded", m.UtilityMovers.MostDegraded)
 }
 
 // countNewArtifactsInDir counts artifacts created after a time in a specific directory.
@@ -22,6 +22,7 @@ func runMetricsReport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("compute metrics: %w", err)
 	}
 	populateGoldenSignals(cwd, metricsDays, metrics)
+	populateUtilityMovers(cwd, metricsDays, metrics)
 
 	switch GetOutput() {
 	case "json":
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/boshu2/agentops/cli/internal/mind"
+)
+
+var (
+	mindMinSimilarity float64
+	mindMaxLinks      int
 )
 
 var mindCmd = &cobra.Command{
@@ -13,77 +22,215 @@ var mindCmd = &cobra.Command{
 	Short: "Knowledge graph operations",
 	Long: `Scan, normalize, link, and index .agents/ markdown into an Obsidian knowledge graph.
 
-Wraps the mind Python tool (python3 -m mind). Subcommands:
-  scan       Show what needs normalization
-  normalize  Add/fix YAML frontmatter
+Subcommands:
+  scan       Show what needs normalization or linking
+  normalize  Add/fix YAML frontmatter (id, type, date, tags)
   link       Insert wikilinks between related artifacts
-  index      Rebuild the graph index
+  index      Rebuild the graph index (.agents/ao/graph.json)
   all        Run full pipeline (normalize → link → index)
-  graph      Show graph statistics
+  graph      Show graph statistics as JSON
+
+Related artifacts are found by TF-IDF similarity over title, tags and body.
+Links go in a "## Related" block that later runs replace in place; links
+written by hand are left alone.
 
-By default, ao mind applies changes (--write). Use --dry-run to preview.`,
+By default, ao mind applies changes. Use --dry-run to print a unified diff
+of every file that would change.`,
+	Example: `  ao mind scan
+  ao mind all --dry-run
+  ao mind link --min-similarity 0.3 --max-links 3
+  ao mind graph`,
 }
 
 func init() {
 	mindCmd.GroupID = "knowledge"
 	rootCmd.AddCommand(mindCmd)
 
-	// Register subcommands
-	for _, sub := range []struct {
-		use, short string
-	}{
-		{"scan", "Show what needs normalization"},
-		{"normalize", "Add/fix YAML frontmatter on .agents/ markdown"},
-		{"link", "Insert wikilinks between related artifacts"},
-		{"index", "Rebuild the graph index"},
-		{"all", "Run full pipeline (normalize → link → index)"},
-		{"graph", "Show graph statistics"},
-	} {
-		subcmd := sub // capture loop variable
-		mindCmd.AddCommand(&cobra.Command{
-			Use:   subcmd.use,
-			Short: subcmd.short,
-			RunE:  mindRunFunc(subcmd.use),
-		})
-	}
+	mindCmd.AddCommand(
+		&cobra.Command{Use: "scan", Short: "Show what needs normalization or linking", Args: cobra.NoArgs, RunE: runMindScan},
+		&cobra.Command{Use: "normalize", Short: "Add/fix YAML frontmatter on .agents/ markdown", Args: cobra.NoArgs, RunE: runMindNormalize},
+		&cobra.Command{Use: "link", Short: "Insert wikilinks between related artifacts", Args: cobra.NoArgs, RunE: runMindLink},
+		&cobra.Command{Use: "index", Short: "Rebuild the graph index", Args: cobra.NoArgs, RunE: runMindIndex},
+		&cobra.Command{Use: "all", Short: "Run full pipeline (normalize → link → index)", Args: cobra.NoArgs, RunE: runMindAll},
+		&cobra.Command{Use: "graph", Short: "Show graph statistics", Args: cobra.NoArgs, RunE: runMindGraph},
+	)
+
+	defaults := mind.DefaultLinkOptions()
+	mindCmd.PersistentFlags().Float64Var(&mindMinSimilarity, "min-similarity", defaults.MinSimilarity, "Minimum TF-IDF similarity for a related link (link, all)")
+	mindCmd.PersistentFlags().IntVar(&mindMaxLinks, "max-links", defaults.MaxLinks, "Maximum related links per artifact (link, all)")
 }
 
-// mindRunFunc returns a RunE handler that shells out to python3 -m mind <subcommand>.
-func mindRunFunc(subcommand string) func(cmd *cobra.Command, args []string) error {
-	return func(cmd *cobra.Command, args []string) error {
-		pythonPath, err := exec.LookPath("python3")
-		if err != nil {
-			return fmt.Errorf("python3 not found: install Python 3 to use ao mind")
-		}
+// mindScanEntry is one artifact with pending pipeline work.
+type mindScanEntry struct {
+	Path    string   `json:"path"`
+	Reasons []string `json:"reasons"`
+}
 
-		mindArgs := []string{"-m", "mind", subcommand}
+// mindScanReport is the ao mind scan result.
+type mindScanReport struct {
+	Artifacts int             `json:"artifacts"`
+	Normalize []mindScanEntry `json:"normalize"`
+	Link      []mindScanEntry `json:"link"`
+}
 
-		// ao defaults to execute; mind defaults to dry-run.
-		// So: ao mind all → mind all --write
-		//     ao mind all --dry-run → mind all (no --write)
-		if !GetDryRun() {
-			mindArgs = append(mindArgs, "--write")
+func runMindScan(cmd *cobra.Command, args []string) error {
+	_, artifacts, err := scanMindArtifacts()
+	if err != nil {
+		return err
+	}
+
+	report := mindScanReport{Artifacts: len(artifacts), Normalize: []mindScanEntry{}, Link: []mindScanEntry{}}
+	for _, a := range artifacts {
+		if reasons := mind.Check(a); len(reasons) > 0 {
+			report.Normalize = append(report.Normalize, mindScanEntry{Path: a.Path, Reasons: reasons})
 		}
+	}
+	for _, c := range mind.LinkAll(artifacts, mindLinkOptions()) {
+		report.Link = append(report.Link, mindScanEntry{Path: c.Path, Reasons: c.Reasons})
+	}
 
-		// Detect vault path from working directory
-		cwd, err := os.Getwd()
-		if err == nil {
-			mindArgs = append(mindArgs, "--vault", cwd)
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	fmt.Printf("Scanned %d artifacts: %d need normalization, %d need links\n",
+		report.Artifacts, len(report.Normalize), len(report.Link))
+	for _, section := range []struct {
+		title   string
+		entries []mindScanEntry
+	}{{"Normalize", report.Normalize}, {"Link", report.Link}} {
+		if len(section.entries) == 0 {
+			continue
 		}
+		fmt.Printf("\n%s:\n", section.title)
+		for _, e := range section.entries {
+			fmt.Printf("  %s: %s\n", e.Path, strings.Join(e.Reasons, ", "))
+		}
+	}
+	return nil
+}
 
-		if GetVerbose() {
-			VerbosePrintf("Running: %s %v\n", pythonPath, mindArgs)
+func runMindNormalize(cmd *cobra.Command, args []string) error {
+	cwd, artifacts, err := scanMindArtifacts()
+	if err != nil {
+		return err
+	}
+	return applyMindChanges(cwd, "normalize", len(artifacts), mind.NormalizeAll(artifacts))
+}
+
+func runMindLink(cmd *cobra.Command, args []string) error {
+	cwd, artifacts, err := scanMindArtifacts()
+	if err != nil {
+		return err
+	}
+	return applyMindChanges(cwd, "link", len(artifacts), mind.LinkAll(artifacts, mindLinkOptions()))
+}
+
+func runMindIndex(cmd *cobra.Command, args []string) error {
+	cwd, artifacts, err := scanMindArtifacts()
+	if err != nil {
+		return err
+	}
+	return writeMindGraph(cwd, mind.BuildGraph(artifacts))
+}
+
+// runMindAll chains the pipeline in memory so that, in dry-run mode, the
+// diff shows each file's combined normalize + link result.
+func runMindAll(cmd *cobra.Command, args []string) error {
+	cwd, artifacts, err := scanMindArtifacts()
+	if err != nil {
+		return err
+	}
+
+	normalized := mind.NormalizeAll(artifacts)
+	mind.Apply(artifacts, normalized)
+	linked := mind.LinkAll(artifacts, mindLinkOptions())
+	mind.Apply(artifacts, linked)
+
+	if err := applyMindChanges(cwd, "all", len(artifacts), mind.Merge(normalized, linked)); err != nil {
+		return err
+	}
+	return writeMindGraph(cwd, mind.BuildGraph(artifacts))
+}
+
+// runMindGraph prints statistics for the saved graph index, or for a graph
+// built on the fly when no current index exists.
+func runMindGraph(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	g, err := mind.LoadGraph(mind.GraphIndexPath(cwd))
+	if err != nil {
+		VerbosePrintf("Graph index unavailable (%v), building from .agents/\n", err)
+		artifacts, scanErr := mind.Scan(cwd)
+		if scanErr != nil {
+			return scanErr
 		}
+		g = mind.BuildGraph(artifacts)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g.Stats())
+}
+
+// scanMindArtifacts loads the .agents/ markdown of the working directory.
+func scanMindArtifacts() (string, []mind.Artifact, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", nil, fmt.Errorf("get working directory: %w", err)
+	}
+	artifacts, err := mind.Scan(cwd)
+	if err != nil {
+		return "", nil, err
+	}
+	return cwd, artifacts, nil
+}
 
-		execCmd := exec.Command(pythonPath, mindArgs...)
-		execCmd.Stdout = os.Stdout
-		execCmd.Stderr = os.Stderr
-		execCmd.Stdin = os.Stdin
+func mindLinkOptions() mind.LinkOptions {
+	return mind.LinkOptions{MinSimilarity: mindMinSimilarity, MaxLinks: mindMaxLinks}
+}
 
-		if err := execCmd.Run(); err != nil {
-			return fmt.Errorf("mind %s failed: %w", subcommand, err)
+// applyMindChanges prints changes as a unified diff in dry-run mode and
+// writes them otherwise.
+func applyMindChanges(cwd, step string, total int, changes []mind.Change) error {
+	if GetDryRun() {
+		for _, c := range changes {
+			fmt.Print(c.Diff())
 		}
+		fmt.Fprintf(os.Stderr, "[dry-run] mind %s: %d of %d artifacts would change\n", step, len(changes), total)
+		return nil
+	}
 
+	for _, c := range changes {
+		path := filepath.Join(cwd, filepath.FromSlash(c.Path))
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", c.Path, err)
+		}
+		if err := atomicWriteFile(path, []byte(c.New), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("write %s: %w", c.Path, err)
+		}
+		VerbosePrintf("  %s: %s\n", c.Path, strings.Join(c.Reasons, ", "))
+	}
+	fmt.Printf("mind %s: updated %d of %d artifacts\n", step, len(changes), total)
+	return nil
+}
+
+// writeMindGraph saves the graph index, or reports it in dry-run mode.
+func writeMindGraph(cwd string, g *mind.Graph) error {
+	path := mind.GraphIndexPath(cwd)
+	if GetDryRun() {
+		fmt.Fprintf(os.Stderr, "[dry-run] Would write %s (%d nodes, %d edges)\n", path, len(g.Nodes), len(g.Edges))
 		return nil
 	}
+	if err := mind.SaveGraph(g, path); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s (%d nodes, %d edges)\n", path, len(g.Nodes), len(g.Edges))
+	return nil
 }
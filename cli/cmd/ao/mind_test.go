@@ -1,174 +1,103 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
-)
-
-func writeFakePython3(t *testing.T) string {
-	t.Helper()
 
-	binDir := t.TempDir()
-	pythonPath := filepath.Join(binDir, "python3")
-	script := `#!/bin/sh
-set -eu
-if [ -n "${MIND_TEST_ARGS_FILE:-}" ]; then
-  : > "$MIND_TEST_ARGS_FILE"
-  for arg in "$@"; do
-    printf '%s\n' "$arg" >> "$MIND_TEST_ARGS_FILE"
-  done
-fi
-exit "${MIND_TEST_EXIT_CODE:-0}"
-`
-	if err := os.WriteFile(pythonPath, []byte(script), 0755); err != nil {
-		t.Fatalf("write fake python3: %v", err)
-	}
-	return binDir
-}
+	"github.com/boshu2/agentops/cli/internal/mind"
+)
 
-func readArgsFile(t *testing.T, path string) []string {
+func writeMindFixture(t *testing.T, root string) string {
 	t.Helper()
-
-	data, err := os.ReadFile(path)
-	if err != nil {
-		t.Fatalf("read args file: %v", err)
-	}
-	trimmed := strings.TrimSpace(string(data))
-	if trimmed == "" {
-		return nil
+	dir := filepath.Join(root, ".agents", "learnings")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
 	}
-	return strings.Split(trimmed, "\n")
-}
-
-func hasArg(args []string, want string) bool {
-	for _, arg := range args {
-		if arg == want {
-			return true
-		}
+	path := filepath.Join(dir, "2026-01-02-retry.md")
+	if err := os.WriteFile(path, []byte("# Retry\n\nUse exponential backoff.\n"), 0644); err != nil {
+		t.Fatal(err)
 	}
-	return false
+	return path
 }
 
-func TestMindRunFunc(t *testing.T) {
-	t.Run("dry-run inversion", func(t *testing.T) {
-		tests := []struct {
-			name      string
-			dryRun    bool
-			wantWrite bool
-		}{
-			{name: "dry-run false adds --write", dryRun: false, wantWrite: true},
-			{name: "dry-run true omits --write", dryRun: true, wantWrite: false},
-		}
-
-		for _, tc := range tests {
-			t.Run(tc.name, func(t *testing.T) {
-				origDryRun := dryRun
-				t.Cleanup(func() { dryRun = origDryRun })
-
-				_ = chdirTemp(t)
-				argsFile := filepath.Join(t.TempDir(), "mind-args.txt")
-				binDir := writeFakePython3(t)
-
-				t.Setenv("MIND_TEST_ARGS_FILE", argsFile)
-				t.Setenv("MIND_TEST_EXIT_CODE", "0")
-				t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
-
-				dryRun = tc.dryRun
-				if err := mindRunFunc("all")(mindCmd, nil); err != nil {
-					t.Fatalf("mindRunFunc returned error: %v", err)
-				}
-
-				args := readArgsFile(t, argsFile)
-				gotWrite := hasArg(args, "--write")
-				if gotWrite != tc.wantWrite {
-					t.Fatalf("args=%v, has --write=%v, want %v", args, gotWrite, tc.wantWrite)
-				}
-			})
-		}
-	})
-
-	t.Run("builds --vault from cwd", func(t *testing.T) {
+func TestMindNormalize(t *testing.T) {
+	t.Run("dry-run prints a diff without writing", func(t *testing.T) {
 		origDryRun := dryRun
 		t.Cleanup(func() { dryRun = origDryRun })
 
-		cwd := chdirTemp(t)
-		argsFile := filepath.Join(t.TempDir(), "mind-args.txt")
-		binDir := writeFakePython3(t)
-
-		t.Setenv("MIND_TEST_ARGS_FILE", argsFile)
-		t.Setenv("MIND_TEST_EXIT_CODE", "0")
-		t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
-
+		path := writeMindFixture(t, chdirTemp(t))
 		dryRun = true
-		if err := mindRunFunc("graph")(mindCmd, nil); err != nil {
-			t.Fatalf("mindRunFunc returned error: %v", err)
-		}
 
-		args := readArgsFile(t, argsFile)
-		vaultIdx := -1
-		for i, arg := range args {
-			if arg == "--vault" {
-				vaultIdx = i
-				break
-			}
-		}
-		if vaultIdx == -1 {
-			t.Fatalf("expected --vault in args, got %v", args)
-		}
-		if vaultIdx+1 >= len(args) {
-			t.Fatalf("expected vault path after --vault, got %v", args)
-		}
-		gotVault := args[vaultIdx+1]
-		gotInfo, err := os.Stat(gotVault)
+		out, err := captureStdout(t, func() error { return runMindNormalize(mindCmd, nil) })
 		if err != nil {
-			t.Fatalf("stat vault path %q: %v", gotVault, err)
+			t.Fatalf("runMindNormalize: %v", err)
+		}
+		if !strings.Contains(out, "+++ b/.agents/learnings/2026-01-02-retry.md") || !strings.Contains(out, "+id: learning-2026-01-02-retry") {
+			t.Errorf("expected unified diff, got:\n%s", out)
 		}
-		cwdInfo, err := os.Stat(cwd)
+		data, err := os.ReadFile(path)
 		if err != nil {
-			t.Fatalf("stat cwd %q: %v", cwd, err)
+			t.Fatal(err)
 		}
-		if !os.SameFile(gotInfo, cwdInfo) {
-			t.Fatalf("vault path = %q, want path to cwd %q", gotVault, cwd)
+		if strings.HasPrefix(string(data), "---") {
+			t.Error("dry-run must not modify files")
 		}
 	})
 
-	t.Run("returns error when python3 is missing", func(t *testing.T) {
+	t.Run("writes frontmatter", func(t *testing.T) {
 		origDryRun := dryRun
 		t.Cleanup(func() { dryRun = origDryRun })
 
+		path := writeMindFixture(t, chdirTemp(t))
 		dryRun = false
-		t.Setenv("PATH", t.TempDir())
 
-		err := mindRunFunc("scan")(mindCmd, nil)
-		if err == nil {
-			t.Fatal("expected error when python3 is not on PATH")
+		if _, err := captureStdout(t, func() error { return runMindNormalize(mindCmd, nil) }); err != nil {
+			t.Fatalf("runMindNormalize: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
 		}
-		if !strings.Contains(err.Error(), "python3 not found") {
-			t.Fatalf("expected python3-not-found error, got: %v", err)
+		if !strings.HasPrefix(string(data), "---\nid: learning-2026-01-02-retry\ntype: learning\ndate: 2026-01-02\n---\n") {
+			t.Errorf("unexpected content:\n%s", data)
 		}
 	})
+}
 
-	t.Run("wraps command failures", func(t *testing.T) {
-		origDryRun := dryRun
-		t.Cleanup(func() { dryRun = origDryRun })
+func TestMindAllAndGraph(t *testing.T) {
+	origDryRun := dryRun
+	t.Cleanup(func() { dryRun = origDryRun })
 
-		_ = chdirTemp(t)
-		argsFile := filepath.Join(t.TempDir(), "mind-args.txt")
-		binDir := writeFakePython3(t)
+	root := chdirTemp(t)
+	writeMindFixture(t, root)
+	dryRun = false
 
-		t.Setenv("MIND_TEST_ARGS_FILE", argsFile)
-		t.Setenv("MIND_TEST_EXIT_CODE", "7")
-		t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	if _, err := captureStdout(t, func() error { return runMindAll(mindCmd, nil) }); err != nil {
+		t.Fatalf("runMindAll: %v", err)
+	}
+	if _, err := os.Stat(mind.GraphIndexPath(root)); err != nil {
+		t.Fatalf("expected graph index: %v", err)
+	}
 
-		dryRun = false
-		err := mindRunFunc("normalize")(mindCmd, nil)
-		if err == nil {
-			t.Fatal("expected error from failing python3 wrapper")
-		}
-		if !strings.Contains(err.Error(), "mind normalize failed") {
-			t.Fatalf("expected wrapped error, got: %v", err)
-		}
-	})
+	out, err := captureStdout(t, func() error { return runMindGraph(mindCmd, nil) })
+	if err != nil {
+		t.Fatalf("runMindGraph: %v", err)
+	}
+	var stats mind.GraphStats
+	if err := json.Unmarshal([]byte(out), &stats); err != nil {
+		t.Fatalf("graph output is not JSON: %v\n%s", err, out)
+	}
+	if stats.Nodes != 1 || stats.NodesByType["learning"] != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestMindScan_MissingAgentsDir(t *testing.T) {
+	chdirTemp(t)
+	if err := runMindScan(mindCmd, nil); err == nil {
+		t.Fatal("expected error without .agents/")
+	}
 }
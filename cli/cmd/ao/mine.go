@@ -367,6 +367,54 @@ func mineGitLog(cwd string, window time.Duration) (*GitFindings, error) {
 	return findings, nil
 }
 
+// mineGitCoChange counts, for every pair of files, how many commits within
+// the window changed both. The result is symmetric: pairs[a][b] == pairs[b][a].
+// Commits touching more than maxFiles files (mass renames, formatting sweeps)
+// are ignored since they say nothing about coupling.
+func mineGitCoChange(cwd string, window time.Duration, maxFiles int) (map[string]map[string]int, error) {
+	sinceArg := fmt.Sprintf("--since=%d seconds ago", int64(window.Seconds()))
+	cmd := exec.Command("git", "log", sinceArg, "--name-only", "--pretty=format:%x00%H")
+	cmd.Dir = cwd
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git log: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	pairs := make(map[string]map[string]int)
+	for _, commit := range strings.Split(string(out), "\x00") {
+		lines := strings.Split(strings.TrimSpace(commit), "\n")
+		if len(lines) < 3 {
+			continue // hash plus fewer than two files
+		}
+		files := lines[1:]
+		if maxFiles > 0 && len(files) > maxFiles {
+			continue
+		}
+		for i, a := range files {
+			a = strings.TrimSpace(a)
+			for _, b := range files[i+1:] {
+				b = strings.TrimSpace(b)
+				if a == "" || b == "" || a == b {
+					continue
+				}
+				if pairs[a] == nil {
+					pairs[a] = make(map[string]int)
+				}
+				if pairs[b] == nil {
+					pairs[b] = make(map[string]int)
+				}
+				pairs[a][b]++
+				pairs[b][a]++
+			}
+		}
+	}
+	return pairs, nil
+}
+
 // mineAgentsDir scans .agents/research/ for files not referenced in learnings.
 func mineAgentsDir(cwd string) (*AgentsFindings, error) {
 	researchDir := filepath.Join(cwd, ".agents", "research")
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestMineWorkItemID_HotspotUsesFileFunc(t *testing.T) {
@@ -565,3 +567,42 @@ func TestMineAgentsDir_EmptyResearchDir(t *testing.T) {
 		t.Errorf("TotalResearch = %d, want 0", findings.TotalResearch)
 	}
 }
+
+func TestMineGitCoChange(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %v", args, out, err)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@test.com")
+	git("config", "user.name", "Test")
+	for i, files := range [][]string{{"a.go", "b.go"}, {"a.go", "b.go", "c.go"}, {"c.go"}} {
+		for _, f := range files {
+			if err := os.WriteFile(filepath.Join(dir, f), []byte(strings.Repeat("x", i+1)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		git("add", ".")
+		git("commit", "-m", "change")
+	}
+
+	pairs, err := mineGitCoChange(dir, 24*time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pairs["a.go"]["b.go"] != 2 || pairs["b.go"]["a.go"] != 2 || pairs["a.go"]["c.go"] != 1 {
+		t.Errorf("pairs = %v", pairs)
+	}
+
+	pairs, err = mineGitCoChange(dir, 24*time.Hour, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pairs["a.go"]["b.go"] != 1 || pairs["a.go"]["c.go"] != 0 {
+		t.Errorf("commits over maxFiles should be skipped: %v", pairs)
+	}
+}
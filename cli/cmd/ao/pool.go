@@ -3,7 +3,11 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -11,12 +15,14 @@ import (
 
 	"github.com/boshu2/agentops/cli/internal/formatter"
 	"github.com/boshu2/agentops/cli/internal/pool"
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
 	"github.com/boshu2/agentops/cli/internal/types"
 )
 
 var (
 	poolTier      string
 	poolStatus    string
+	poolType      string
 	poolLimit     int
 	poolOffset    int
 	poolReason    string
@@ -47,11 +53,17 @@ Examples:
 var poolListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List candidates in pools",
-	Long: `List knowledge candidates filtered by tier and/or status.
+	Long: `List knowledge candidates filtered by tier, knowledge type and/or status.
+
+--tier and --type accept the tiers and knowledge types of the repo's
+taxonomy, including custom types from .agents/taxonomy.yaml. When the
+taxonomy changes the tier thresholds, entries that would now land in a
+different tier are listed after the table.
 
 Examples:
   ao pool list
   ao pool list --tier=gold
+  ao pool list --type=decision
   ao pool list --status=pending
   ao pool list --tier=bronze --status=staged`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -60,6 +72,9 @@ Examples:
 			if poolTier != "" {
 				fmt.Printf(" with tier=%s", poolTier)
 			}
+			if poolType != "" {
+				fmt.Printf(" with type=%s", poolType)
+			}
 			if poolStatus != "" {
 				fmt.Printf(" with status=%s", poolStatus)
 			}
@@ -72,16 +87,22 @@ Examples:
 			return fmt.Errorf("get working directory: %w", err)
 		}
 
+		tax, err := taxonomy.Load(cwd)
+		if err != nil {
+			return err
+		}
+		if err := validatePoolListFilters(tax, poolTier, poolType); err != nil {
+			return err
+		}
+
 		p := pool.NewPool(cwd)
 
 		opts := pool.ListOptions{
+			Tier:   types.Tier(poolTier),
+			Type:   types.KnowledgeType(poolType),
 			Limit:  poolLimit,
 			Offset: poolOffset,
 		}
-
-		if poolTier != "" {
-			opts.Tier = types.Tier(poolTier)
-		}
 		if poolStatus != "" {
 			opts.Status = types.PoolStatus(poolStatus)
 		}
@@ -91,10 +112,82 @@ Examples:
 			return fmt.Errorf("list pool: %w", err)
 		}
 
-		return outputPoolList(result.Entries, poolOffset, poolLimit, result.Total)
+		if err := outputPoolList(result.Entries, poolOffset, poolLimit, result.Total); err != nil {
+			return err
+		}
+		if GetOutput() == "table" {
+			printTaxonomyDrift(os.Stdout, result.Entries, tax)
+		}
+		return nil
 	},
 }
 
+// validatePoolListFilters rejects a --tier or --type the repo taxonomy does
+// not define, so a typo is an error rather than an empty list.
+func validatePoolListFilters(tax *taxonomy.Taxonomy, tier, kt string) error {
+	if tier != "" {
+		if _, ok := tax.Tiers[types.Tier(tier)]; !ok {
+			return fmt.Errorf("unknown tier %q (valid: %s)", tier, joinSortedKeys(tax.Tiers))
+		}
+	}
+	if kt != "" {
+		if _, ok := tax.KnowledgeTypes[types.KnowledgeType(kt)]; !ok {
+			return fmt.Errorf("unknown knowledge type %q (valid: %s)", kt, joinSortedKeys(tax.KnowledgeTypes))
+		}
+	}
+	return nil
+}
+
+// joinSortedKeys lists the keys of m in sorted order, comma separated.
+func joinSortedKeys[K ~string, V any](m map[K]V) string {
+	keys := slices.Sorted(maps.Keys(m))
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = string(k)
+	}
+	return strings.Join(names, ", ")
+}
+
+// taxonomyDrift is a listed entry the repo taxonomy would tier differently
+// from the tier its score was assigned.
+type taxonomyDrift struct {
+	ID         string
+	ScoredTier types.Tier
+	NowTier    types.Tier
+}
+
+// findTaxonomyDrift compares each entry's scored tier with the tier tax
+// assigns to the same raw score. Only a repo taxonomy can drift.
+func findTaxonomyDrift(entries []pool.PoolEntry, tax *taxonomy.Taxonomy) []taxonomyDrift {
+	if tax.Source == "" {
+		return nil
+	}
+	var drift []taxonomyDrift
+	for _, e := range entries {
+		scored := e.ScoringResult.TierAssignment
+		if scored == "" {
+			continue
+		}
+		if now := tax.AssignTier(e.ScoringResult.RawScore); now != scored {
+			drift = append(drift, taxonomyDrift{ID: e.Candidate.ID, ScoredTier: scored, NowTier: now})
+		}
+	}
+	return drift
+}
+
+// printTaxonomyDrift notes entries scored under different tier thresholds
+// than the repo taxonomy now defines.
+func printTaxonomyDrift(w io.Writer, entries []pool.PoolEntry, tax *taxonomy.Taxonomy) {
+	drift := findTaxonomyDrift(entries, tax)
+	if len(drift) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%d entry(ies) would be tiered differently under %s:\n", len(drift), taxonomy.TaxonomyFile)
+	for _, d := range drift {
+		fmt.Fprintf(w, "  %s: %s → %s\n", d.ID, d.ScoredTier, d.NowTier)
+	}
+}
+
 func outputPoolList(entries []pool.PoolEntry, offset, limit, total int) error {
 	switch GetOutput() {
 	case "json":
@@ -112,7 +205,7 @@ func outputPoolList(entries []pool.PoolEntry, offset, limit, total int) error {
 			return nil
 		}
 
-		tbl := formatter.NewTable(os.Stdout, "ID", "TIER", "STATUS", "AGE", "UTILITY", "CONFIDENCE")
+		tbl := formatter.NewTable(os.Stdout, "ID", "TIER", "TYPE", "STATUS", "AGE", "UTILITY", "CONFIDENCE")
 		if !poolWide {
 			tbl.SetMaxWidth(0, 12)
 		}
@@ -121,6 +214,7 @@ func outputPoolList(entries []pool.PoolEntry, offset, limit, total int) error {
 			tbl.AddRow(
 				e.Candidate.ID,
 				string(e.Candidate.Tier),
+				displayOrDash(string(e.Candidate.Type)),
 				string(e.Status),
 				e.AgeString,
 				fmt.Sprintf("%.2f", e.Candidate.Utility),
@@ -497,6 +591,7 @@ func init() {
 
 	// Add flags to list command
 	poolListCmd.Flags().StringVar(&poolTier, "tier", "", "Filter by tier (gold, silver, bronze)")
+	poolListCmd.Flags().StringVar(&poolType, "type", "", "Filter by knowledge type (any type in the repo taxonomy)")
 	poolListCmd.Flags().StringVar(&poolStatus, "status", "", "Filter by status (pending, staged, promoted, rejected)")
 	poolListCmd.Flags().IntVar(&poolLimit, "limit", 50, "Maximum results to return (default 50, 0 for unlimited)")
 	poolListCmd.Flags().IntVar(&poolOffset, "offset", 0, "Skip first N results (for pagination)")
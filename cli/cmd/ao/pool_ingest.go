@@ -59,10 +59,10 @@ func init() {
 
 // ingestFileBlocks processes all learning blocks from one file, updating res.
 // Returns true if any block had an add error (not skipped or malformed).
-func ingestFileBlocks(p *pool.Pool, blocks []learningBlock, f string, fileDate time.Time, sessionHint string, res *poolIngestResult) bool {
+func ingestFileBlocks(p *pool.Pool, tax *taxonomy.Taxonomy, blocks []learningBlock, f string, fileDate time.Time, sessionHint string, res *poolIngestResult) bool {
 	hadError := false
 	for _, b := range blocks {
-		cand, scoring, ok := buildCandidateFromLearningBlock(b, f, fileDate, sessionHint)
+		cand, scoring, ok := buildCandidateFromLearningBlock(b, f, fileDate, sessionHint, tax)
 		if !ok {
 			res.SkippedMalformed++
 			continue
@@ -110,6 +110,10 @@ func runPoolIngest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("get working directory: %w", err)
 	}
 	p := pool.NewPool(cwd)
+	tax, err := taxonomy.Load(cwd)
+	if err != nil {
+		return err
+	}
 
 	files, err := resolveIngestFiles(cwd, poolIngestDir, args)
 	if err != nil {
@@ -135,7 +139,7 @@ func runPoolIngest(cmd *cobra.Command, args []string) error {
 		blocks := parseLearningBlocks(string(data))
 		res.CandidatesFound += len(blocks)
 
-		hadError := ingestFileBlocks(p, blocks, f, fileDate, sessionHint, &res)
+		hadError := ingestFileBlocks(p, tax, blocks, f, fileDate, sessionHint, &res)
 		if !hadError && !GetDryRun() {
 			processedFiles = append(processedFiles, f)
 		}
@@ -426,7 +430,7 @@ func extractSessionHint(md, path string) string {
 	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
 }
 
-func buildCandidateFromLearningBlock(b learningBlock, srcPath string, fileDate time.Time, sessionHint string) (types.Candidate, types.Scoring, bool) {
+func buildCandidateFromLearningBlock(b learningBlock, srcPath string, fileDate time.Time, sessionHint string, tax *taxonomy.Taxonomy) (types.Candidate, types.Scoring, bool) {
 	if strings.TrimSpace(b.Title) == "" || strings.TrimSpace(b.Body) == "" {
 		return types.Candidate{}, types.Scoring{}, false
 	}
@@ -446,11 +450,15 @@ func buildCandidateFromLearningBlock(b learningBlock, srcPath string, fileDate t
 		id = slugify(id[:90] + "-" + hex.EncodeToString(h[:4]))
 	}
 
-	candType := inferKnowledgeType(b)
+	// A category naming a type the repo taxonomy adds is taken as-is.
+	candType, ok := tax.CustomType(strings.ToLower(strings.TrimSpace(b.Category)))
+	if !ok {
+		candType = inferKnowledgeType(b)
+	}
 	confDim := confidenceToScore(b.Confidence)
 	rubric := computeRubricScores(b.Body, confDim)
-	weighted := rubricWeightedSum(rubric, taxonomy.DefaultRubricWeights)
-	raw := (taxonomy.GetBaseScore(candType) + weighted) / 2.0
+	weighted := rubricWeightedSum(rubric, tax.Weights)
+	raw := (tax.BaseScore(candType) + weighted) / 2.0
 
 	// Pending learnings already reflect some human/LLM filtering (they were written intentionally),
 	// so bias score upwards based on the declared confidence to reduce false "bronze" assignments.
@@ -468,8 +476,8 @@ func buildCandidateFromLearningBlock(b learningBlock, srcPath string, fileDate t
 		raw = 0.0
 	}
 
-	tier := taxonomy.AssignTier(raw, taxonomy.DefaultTierConfigs)
-	gateRequired := taxonomy.RequiresHumanGate(tier, taxonomy.DefaultTierConfigs)
+	tier := tax.AssignTier(raw)
+	gateRequired := tax.RequiresHumanGate(tier)
 
 	cand := types.Candidate{
 		ID:          id,
@@ -488,7 +496,7 @@ func buildCandidateFromLearningBlock(b learningBlock, srcPath string, fileDate t
 		ExpiryStatus: types.ExpiryStatusActive,
 		Utility:      types.InitialUtility,
 		Maturity:     types.MaturityProvisional,
-		Confidence:   taxonomy.GetConfidence(tier, taxonomy.DefaultTierConfigs),
+		Confidence:   tax.Confidence(tier),
 		LastDecayAt:  fileDate,
 		DecayCount:   0,
 		HelpfulCount: 0,
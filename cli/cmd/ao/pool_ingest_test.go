@@ -508,7 +508,7 @@ func TestPoolIngestCoverage_BuildCandidateFromLearningBlock(t *testing.T) {
 			Confidence: "high",
 			Body:       "## What We Learned\n\nRun go test before commit.\n",
 		}
-		cand, scoring, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz")
+		cand, scoring, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz", taxonomy.Default())
 		if !ok {
 			t.Fatal("expected ok=true")
 		}
@@ -537,7 +537,7 @@ func TestPoolIngestCoverage_BuildCandidateFromLearningBlock(t *testing.T) {
 
 	t.Run("empty title returns not ok", func(t *testing.T) {
 		b := learningBlock{Title: "", Body: "some body"}
-		_, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz")
+		_, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz", taxonomy.Default())
 		if ok {
 			t.Error("expected ok=false for empty title")
 		}
@@ -545,7 +545,7 @@ func TestPoolIngestCoverage_BuildCandidateFromLearningBlock(t *testing.T) {
 
 	t.Run("empty body returns not ok", func(t *testing.T) {
 		b := learningBlock{Title: "Title", Body: ""}
-		_, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz")
+		_, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz", taxonomy.Default())
 		if ok {
 			t.Error("expected ok=false for empty body")
 		}
@@ -553,7 +553,7 @@ func TestPoolIngestCoverage_BuildCandidateFromLearningBlock(t *testing.T) {
 
 	t.Run("whitespace-only title returns not ok", func(t *testing.T) {
 		b := learningBlock{Title: "   ", Body: "some body"}
-		_, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz")
+		_, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz", taxonomy.Default())
 		if ok {
 			t.Error("expected ok=false for whitespace-only title")
 		}
@@ -565,7 +565,7 @@ func TestPoolIngestCoverage_BuildCandidateFromLearningBlock(t *testing.T) {
 			ID:    "L-stub",
 			Body:  "No significant learnings from this session.",
 		}
-		_, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz")
+		_, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz", taxonomy.Default())
 		if ok {
 			t.Error("expected ok=false for stub 'no significant learnings' body")
 		}
@@ -579,7 +579,7 @@ func TestPoolIngestCoverage_BuildCandidateFromLearningBlock(t *testing.T) {
 			Confidence: "high",
 			Body:       "We decided to always use conventional commits for consistency.",
 		}
-		cand, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz")
+		cand, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz", taxonomy.Default())
 		if !ok {
 			t.Fatal("expected ok=true")
 		}
@@ -591,8 +591,8 @@ func TestPoolIngestCoverage_BuildCandidateFromLearningBlock(t *testing.T) {
 	t.Run("high confidence boosts raw score", func(t *testing.T) {
 		bHigh := learningBlock{Title: "Test", ID: "L1", Confidence: "high", Body: "Some body content here."}
 		bLow := learningBlock{Title: "Test", ID: "L2", Confidence: "low", Body: "Some body content here."}
-		candHigh, _, _ := buildCandidateFromLearningBlock(bHigh, "/test/file.md", fileDate, "ag-xyz")
-		candLow, _, _ := buildCandidateFromLearningBlock(bLow, "/test/file.md", fileDate, "ag-xyz")
+		candHigh, _, _ := buildCandidateFromLearningBlock(bHigh, "/test/file.md", fileDate, "ag-xyz", taxonomy.Default())
+		candLow, _, _ := buildCandidateFromLearningBlock(bLow, "/test/file.md", fileDate, "ag-xyz", taxonomy.Default())
 		if candHigh.RawScore <= candLow.RawScore {
 			t.Errorf("high confidence score %v should be > low confidence score %v", candHigh.RawScore, candLow.RawScore)
 		}
@@ -601,7 +601,7 @@ func TestPoolIngestCoverage_BuildCandidateFromLearningBlock(t *testing.T) {
 	t.Run("long ID gets truncated with hash", func(t *testing.T) {
 		longID := strings.Repeat("x", 200)
 		b := learningBlock{Title: "Test", ID: longID, Confidence: "medium", Body: "Some learning body."}
-		cand, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz")
+		cand, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz", taxonomy.Default())
 		if !ok {
 			t.Fatal("expected ok=true")
 		}
@@ -612,7 +612,7 @@ func TestPoolIngestCoverage_BuildCandidateFromLearningBlock(t *testing.T) {
 
 	t.Run("metadata populated", func(t *testing.T) {
 		b := learningBlock{Title: "Test", ID: "L1", Category: "process", Confidence: "high", Body: "Body text."}
-		cand, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz")
+		cand, _, ok := buildCandidateFromLearningBlock(b, "/test/file.md", fileDate, "ag-xyz", taxonomy.Default())
 		if !ok {
 			t.Fatal("expected ok=true")
 		}
@@ -842,7 +842,7 @@ func TestPoolIngest_buildCandidateFromLearningBlock(t *testing.T) {
 		}
 		fileDate := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
 
-		cand, scoring, ok := buildCandidateFromLearningBlock(b, "/path/to/2026-01-15-learning.md", fileDate, "ag-test")
+		cand, scoring, ok := buildCandidateFromLearningBlock(b, "/path/to/2026-01-15-learning.md", fileDate, "ag-test", taxonomy.Default())
 		if !ok {
 			t.Fatal("expected ok=true for valid block")
 		}
@@ -868,7 +868,7 @@ func TestPoolIngest_buildCandidateFromLearningBlock(t *testing.T) {
 
 	t.Run("empty title returns not ok", func(t *testing.T) {
 		b := learningBlock{Title: "", Body: "some body"}
-		_, _, ok := buildCandidateFromLearningBlock(b, "/path.md", time.Now(), "ag-test")
+		_, _, ok := buildCandidateFromLearningBlock(b, "/path.md", time.Now(), "ag-test", taxonomy.Default())
 		if ok {
 			t.Error("expected ok=false for empty title")
 		}
@@ -876,7 +876,7 @@ func TestPoolIngest_buildCandidateFromLearningBlock(t *testing.T) {
 
 	t.Run("empty body returns not ok", func(t *testing.T) {
 		b := learningBlock{Title: "Has Title", Body: ""}
-		_, _, ok := buildCandidateFromLearningBlock(b, "/path.md", time.Now(), "ag-test")
+		_, _, ok := buildCandidateFromLearningBlock(b, "/path.md", time.Now(), "ag-test", taxonomy.Default())
 		if ok {
 			t.Error("expected ok=false for empty body")
 		}
@@ -888,7 +888,7 @@ func TestPoolIngest_buildCandidateFromLearningBlock(t *testing.T) {
 			ID:    strings.Repeat("a", 200),
 			Body:  "Body content for long ID test.",
 		}
-		cand, _, ok := buildCandidateFromLearningBlock(b, "/path/to/very-long-filename-that-is-quite-lengthy.md", time.Now(), "ag-test-session-with-long-name")
+		cand, _, ok := buildCandidateFromLearningBlock(b, "/path/to/very-long-filename-that-is-quite-lengthy.md", time.Now(), "ag-test-session-with-long-name", taxonomy.Default())
 		if !ok {
 			t.Fatal("expected ok=true")
 		}
@@ -900,8 +900,8 @@ func TestPoolIngest_buildCandidateFromLearningBlock(t *testing.T) {
 	t.Run("high confidence boosts score", func(t *testing.T) {
 		bHigh := learningBlock{Title: "T", ID: "id", Confidence: "high", Body: "Body content here."}
 		bLow := learningBlock{Title: "T", ID: "id", Confidence: "low", Body: "Body content here."}
-		candH, _, _ := buildCandidateFromLearningBlock(bHigh, "/p.md", time.Now(), "s")
-		candL, _, _ := buildCandidateFromLearningBlock(bLow, "/p.md", time.Now(), "s")
+		candH, _, _ := buildCandidateFromLearningBlock(bHigh, "/p.md", time.Now(), "s", taxonomy.Default())
+		candL, _, _ := buildCandidateFromLearningBlock(bLow, "/p.md", time.Now(), "s", taxonomy.Default())
 		if candH.RawScore <= candL.RawScore {
 			t.Errorf("high confidence score (%f) should be > low confidence score (%f)", candH.RawScore, candL.RawScore)
 		}
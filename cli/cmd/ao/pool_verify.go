@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/boshu2/agentops/cli/internal/pool"
+)
+
+var poolVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the pool chain has not been tampered with",
+	Long: `Verify the hash links of the pool chain (.agents/pool/chain.jsonl).
+
+Every pool operation is recorded with a hash of its content and the hash of
+the event before it. An edited event fails its own hash; a deleted or
+reordered event breaks the next event's prev_hash.
+
+Events recorded before the chain was hash-linked are reported as legacy;
+run ao pool migrate-chain to back-fill their hashes.
+
+Exits non-zero when the chain is broken.
+
+Examples:
+  ao pool verify
+  ao pool verify --json`,
+	Args: cobra.NoArgs,
+	RunE: runPoolVerify,
+}
+
+var poolMigrateChainCmd = &cobra.Command{
+	Use:   "migrate-chain",
+	Short: "Back-fill hashes for legacy pool chain events",
+	Long: `Back-fill hashes for pool chain events recorded before hash linking.
+
+Legacy events gain prev_hash and hash fields and the whole chain is relinked
+from its first event.
+
+Refuses to run if the already hash-linked events fail verification, so a
+tampered chain cannot be laundered into a valid one.
+
+Examples:
+  ao pool migrate-chain
+  ao pool migrate-chain --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runPoolMigrateChain,
+}
+
+func init() {
+	poolCmd.AddCommand(poolVerifyCmd)
+	poolCmd.AddCommand(poolMigrateChainCmd)
+}
+
+func runPoolVerify(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	v, err := pool.NewPool(cwd).VerifyChain()
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	} else {
+		printChainVerification(w, v)
+	}
+	if !v.Intact() {
+		return fmt.Errorf("pool chain broken: %d problem(s)", len(v.Problems))
+	}
+	return nil
+}
+
+// printChainVerification prints the verification verdict and any problems.
+func printChainVerification(w io.Writer, v *pool.ChainVerification) {
+	if v.Events == 0 {
+		fmt.Fprintln(w, "Pool chain is empty")
+		return
+	}
+	if v.Intact() {
+		fmt.Fprintf(w, "✓ Pool chain intact: %d events, head %s\n", v.Events, truncateID(v.Head, 12))
+	} else {
+		fmt.Fprintf(w, "✗ Pool chain broken: %d problem(s) in %d events\n", len(v.Problems), v.Events)
+		for _, p := range v.Problems {
+			fmt.Fprintf(w, "  line %d", p.Line)
+			if p.CandidateID != "" {
+				fmt.Fprintf(w, " (%s)", p.CandidateID)
+			}
+			fmt.Fprintf(w, ": %s\n", p.Message)
+		}
+	}
+	if v.Legacy > 0 {
+		fmt.Fprintf(w, "  %d legacy event(s) without hashes — run 'ao pool migrate-chain'\n", v.Legacy)
+	}
+}
+
+func runPoolMigrateChain(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	p := pool.NewPool(cwd)
+
+	w := cmd.OutOrStdout()
+	if GetDryRun() {
+		v, err := p.VerifyChain()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "[dry-run] Would back-fill hashes for %d legacy event(s) of %d\n", v.Legacy, v.Events)
+		return nil
+	}
+
+	migrated, err := p.MigrateChain()
+	if err != nil {
+		return fmt.Errorf("migrate chain: %w", err)
+	}
+	if migrated == 0 {
+		fmt.Fprintln(w, "Pool chain already hash-linked; nothing to migrate")
+		return nil
+	}
+	fmt.Fprintf(w, "Back-filled hashes for %d legacy event(s)\n", migrated)
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boshu2/agentops/cli/internal/pool"
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// seedPoolChain adds candidates through the pool so their events are chained.
+func seedPoolChain(t *testing.T, dir string, ids ...string) string {
+	t.Helper()
+	p := pool.NewPool(dir)
+	for _, id := range ids {
+		candidate := types.Candidate{ID: id, Content: "content for " + id, Tier: types.TierSilver, ExtractedAt: time.Now()}
+		if err := p.Add(candidate, types.Scoring{RawScore: 0.7, TierAssignment: types.TierSilver}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(p.PoolPath, pool.ChainFile)
+}
+
+func TestPoolVerify_Command(t *testing.T) {
+	dir := chdirTemp(t)
+	chainPath := seedPoolChain(t, dir, "cand-1", "cand-2")
+
+	out, err := executeCommand("pool", "verify")
+	if err != nil || !strings.Contains(out, "Pool chain intact: 2 events") {
+		t.Fatalf("verify: %v\n%s", err, out)
+	}
+	if check := checkPoolChain(); check.Status != "pass" {
+		t.Errorf("doctor check = %+v", check)
+	}
+
+	data, err := os.ReadFile(chainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(data), `"candidate_id":"cand-1"`, `"candidate_id":"cand-9"`, 1)
+	if err := os.WriteFile(chainPath, []byte(tampered), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = executeCommand("pool", "verify", "-o", "json")
+	if err == nil || !strings.Contains(err.Error(), "pool chain broken") {
+		t.Fatalf("err = %v, want pool chain broken", err)
+	}
+	var v pool.ChainVerification
+	// The command's error follows the report in the output.
+	if err := json.NewDecoder(strings.NewReader(out[strings.Index(out, "{"):])).Decode(&v); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	if len(v.Problems) != 1 || v.Problems[0].Line != 1 {
+		t.Errorf("problems = %+v", v.Problems)
+	}
+	if check := checkPoolChain(); check.Status != "fail" || !strings.Contains(check.Detail, "ao pool verify") {
+		t.Errorf("doctor check = %+v", check)
+	}
+}
+
+func TestPoolMigrateChain_Command(t *testing.T) {
+	dir := chdirTemp(t)
+	chainPath := filepath.Join(dir, pool.PoolDir, pool.ChainFile)
+	if err := os.MkdirAll(filepath.Dir(chainPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `{"timestamp":"2026-01-02T03:04:05Z","operation":"add","candidate_id":"legacy-1"}` + "\n"
+	if err := os.WriteFile(chainPath, []byte(legacy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if check := checkPoolChain(); check.Status != "warn" || !strings.Contains(check.Detail, "migrate-chain") {
+		t.Errorf("doctor check before migration = %+v", check)
+	}
+
+	out, err := executeCommand("pool", "migrate-chain")
+	if err != nil || !strings.Contains(out, "Back-filled hashes for 1 legacy event") {
+		t.Fatalf("migrate-chain: %v\n%s", err, out)
+	}
+	out, err = executeCommand("pool", "verify")
+	if err != nil || !strings.Contains(out, "intact") || strings.Contains(out, "legacy") {
+		t.Errorf("verify after migration: %v\n%s", err, out)
+	}
+}
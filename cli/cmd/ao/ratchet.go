@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"github.com/boshu2/agentops/cli/internal/ratchet"
@@ -18,6 +21,7 @@ Inspection:
   status (s)    Show current ratchet chain state
   check (c)     Check if a step's gate is met
   next (n)      Show next pending RPI step
+  timeline (tl) Show full chain history (text, json, mermaid, dot)
   spec          Get current spec path
   validate      Validate step requirements
 
@@ -34,7 +38,25 @@ Management:
   migrate            Migrate legacy chain format
   migrate-artifacts  Add schema_version to artifacts
 
-The ratchet chain is stored in .agents/ao/chain.jsonl`,
+The ratchet chain is stored in .agents/ao/chain.jsonl
+
+The steps default to the RPI workflow (research, pre-mortem, plan,
+implement, crank, vibe, post-mortem). Define your own steps, their order,
+dependencies, gates and aliases in .agents/ratchet/workflow.yaml:
+
+  steps:
+    - name: research
+    - name: plan
+      aliases: [formulate]
+    - name: implement
+      needs: [plan]
+    - name: security-review
+      needs: [implement]
+      gate: {artifacts: ["security/*.md"]}
+      skill: /security-review
+    - name: post-mortem
+
+Built-in step names keep their built-in gates and aliases unless overridden.`,
 }
 
 // Ratchet command flags (shared across subcommands)
@@ -67,11 +89,12 @@ type ratchetStepInfo struct {
 
 // ratchetStatusOutput holds the full status output structure.
 type ratchetStatusOutput struct {
-	ChainID string            `json:"chain_id"`
-	Started string            `json:"started"`
-	EpicID  string            `json:"epic_id,omitempty"`
-	Steps   []ratchetStepInfo `json:"steps"`
-	Path    string            `json:"path"`
+	ChainID  string            `json:"chain_id"`
+	Started  string            `json:"started"`
+	EpicID   string            `json:"epic_id,omitempty"`
+	Steps    []ratchetStepInfo `json:"steps"`
+	Path     string            `json:"path"`
+	Workflow string            `json:"workflow,omitempty"`
 }
 
 func init() {
@@ -85,6 +108,19 @@ func init() {
 	rootCmd.AddCommand(ratchetCmd)
 }
 
+// parseWorkflowStep resolves a step name or alias against the workflow.
+func parseWorkflowStep(wf *ratchet.Workflow, name string) (ratchet.Step, error) {
+	step := wf.ParseStep(name)
+	if step == "" {
+		names := make([]string, 0, len(wf.Steps))
+		for _, s := range wf.StepNames() {
+			names = append(names, string(s))
+		}
+		return "", fmt.Errorf("unknown step: %s (workflow steps: %s)", name, strings.Join(names, ", "))
+	}
+	return step, nil
+}
+
 func statusIcon(status ratchet.StepStatus) string {
 	switch status {
 	case ratchet.StatusLocked:
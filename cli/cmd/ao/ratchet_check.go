@@ -23,6 +23,9 @@ Returns exit code 0 if gate passes, 1 if not.
 Steps: research, pre-mortem, plan, implement, crank, vibe, post-mortem
 Aliases: premortem, postmortem, autopilot, validate, review
 
+With .agents/ratchet/workflow.yaml the workflow's steps and aliases apply,
+and a step also fails its gate until the steps it needs are recorded.
+
 Examples:
   ao ratchet check research
   ao ratchet check plan
@@ -35,23 +38,31 @@ Examples:
 
 // runRatchetCheck validates a step gate.
 func runRatchetCheck(cmd *cobra.Command, args []string) error {
-	stepName := args[0]
-	step := ratchet.ParseStep(stepName)
-	if step == "" {
-		return fmt.Errorf("unknown step: %s", stepName)
-	}
-
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("get working directory: %w", err)
 	}
 
+	wf, err := ratchet.LoadWorkflow(cwd)
+	if err != nil {
+		return fmt.Errorf("load workflow: %w", err)
+	}
+	step, err := parseWorkflowStep(wf, args[0])
+	if err != nil {
+		return err
+	}
+
+	chain, err := ratchet.LoadChain(cwd)
+	if err != nil {
+		return fmt.Errorf("load chain: %w", err)
+	}
+
 	checker, err := ratchet.NewGateChecker(cwd)
 	if err != nil {
 		return fmt.Errorf("create gate checker: %w", err)
 	}
 
-	result, err := checker.Check(step)
+	result, err := checker.CheckWorkflowStep(wf, chain, step)
 	if err != nil {
 		return fmt.Errorf("check gate: %w", err)
 	}
@@ -21,17 +21,6 @@ type NextResult struct {
 	Complete     bool   `json:"complete" yaml:"complete"`
 }
 
-// stepSkillMap maps ratchet steps to their corresponding skill commands.
-var stepSkillMap = map[string]string{
-	"research":    "/research",
-	"pre-mortem":  "/pre-mortem",
-	"plan":        "/plan",
-	"implement":   "/implement or /crank",
-	"crank":       "/implement or /crank",
-	"vibe":        "/vibe",
-	"post-mortem": "/post-mortem",
-}
-
 func init() {
 	ratchetNextCmd := &cobra.Command{
 		Use:     "next",
@@ -41,7 +30,8 @@ func init() {
 		Long: `Show the next pending step in the RPI workflow.
 
 Returns structured output indicating what to do next based on the current
-ratchet chain state. Returns "complete" if all steps are locked.
+ratchet chain state and workflow. Optional workflow steps are never
+suggested. Returns "complete" once the final step is locked.
 
 Examples:
   ao ratchet next
@@ -70,29 +60,34 @@ func runRatchetNext(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no chain found for epic %s", ratchetEpicID)
 	}
 
-	result := computeNextStep(chain)
+	wf, err := ratchet.LoadWorkflow(cwd)
+	if err != nil {
+		return fmt.Errorf("load workflow: %w", err)
+	}
+
+	result := computeNextStep(chain, wf)
 	return outputNextResult(&result)
 }
 
-// computeNextStep analyzes the chain and determines the next step.
-func computeNextStep(chain *ratchet.Chain) NextResult {
-	allSteps := ratchet.AllSteps()
-
+// computeNextStep analyzes the chain and determines the next workflow step.
+func computeNextStep(chain *ratchet.Chain, wf *ratchet.Workflow) NextResult {
 	lastStep, lastEntry := findLastLockedEntry(chain)
 	if lastEntry == nil {
 		reason := "no steps completed yet"
 		if len(chain.Entries) > 0 {
 			reason = "no steps locked yet"
 		}
-		return newStartResult(allSteps[0], reason)
+		return newStartResult(wf, wf.Steps[0].Name, reason)
 	}
 
-	if lastStep == ratchet.StepPostMortem {
+	if lastStep == wf.Last() {
 		return newCompleteResult(lastStep, lastEntry)
 	}
 
-	nextStep := resolveNextStep(allSteps, lastStep)
-	return newPendingResult(nextStep, lastStep, lastEntry)
+	// Optional steps are passed over, so after implement (or crank, which
+	// stands in for it) the default workflow moves on to vibe.
+	nextStep := wf.Successor(lastStep)
+	return newPendingResult(wf, nextStep, lastStep, lastEntry)
 }
 
 // findLastLockedEntry returns the most recent locked or skipped entry.
@@ -106,31 +101,18 @@ func findLastLockedEntry(chain *ratchet.Chain) (ratchet.Step, *ratchet.ChainEntr
 	return "", nil
 }
 
-// resolveNextStep determines the successor step, handling implement/crank aliasing.
-func resolveNextStep(allSteps []ratchet.Step, lastStep ratchet.Step) ratchet.Step {
-	// Either implement or crank satisfies the build position; next is vibe.
-	if lastStep == ratchet.StepImplement || lastStep == ratchet.StepCrank {
-		return ratchet.StepVibe
-	}
-	// After plan, suggest implement (skip crank).
-	if lastStep == ratchet.StepPlan {
-		return ratchet.StepImplement
-	}
-	// Default: advance to the successor in AllSteps.
-	for i, step := range allSteps {
-		if step == lastStep && i < len(allSteps)-1 {
-			return allSteps[i+1]
-		}
-	}
-	return ""
+// stepSkill returns the skill suggested for a workflow step.
+func stepSkill(wf *ratchet.Workflow, step ratchet.Step) string {
+	ws, _ := wf.Step(step)
+	return ws.Skill
 }
 
 // newStartResult builds a NextResult for the first step.
-func newStartResult(first ratchet.Step, reason string) NextResult {
+func newStartResult(wf *ratchet.Workflow, first ratchet.Step, reason string) NextResult {
 	return NextResult{
 		Next:   string(first),
 		Reason: reason,
-		Skill:  stepSkillMap[string(first)],
+		Skill:  stepSkill(wf, first),
 	}
 }
 
@@ -145,7 +127,7 @@ func newCompleteResult(lastStep ratchet.Step, lastEntry *ratchet.ChainEntry) Nex
 }
 
 // newPendingResult builds a NextResult for the next pending step.
-func newPendingResult(nextStep, lastStep ratchet.Step, lastEntry *ratchet.ChainEntry) NextResult {
+func newPendingResult(wf *ratchet.Workflow, nextStep, lastStep ratchet.Step, lastEntry *ratchet.ChainEntry) NextResult {
 	reason := "unexpected state"
 	if nextStep != "" {
 		reason = fmt.Sprintf("%s locked", lastStep)
@@ -155,7 +137,7 @@ func newPendingResult(nextStep, lastStep ratchet.Step, lastEntry *ratchet.ChainE
 		Reason:       reason,
 		LastStep:     string(lastStep),
 		LastArtifact: lastEntry.Output,
-		Skill:        stepSkillMap[string(nextStep)],
+		Skill:        stepSkill(wf, nextStep),
 	}
 }
 
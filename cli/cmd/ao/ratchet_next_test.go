@@ -306,7 +306,7 @@ func TestComputeNextStep(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := computeNextStep(tt.chain)
+			result := computeNextStep(tt.chain, ratchet.DefaultWorkflow())
 
 			if result.Complete != tt.wantDone {
 				t.Errorf("Complete = %v, want %v", result.Complete, tt.wantDone)
@@ -38,17 +38,20 @@ Examples:
 
 // runRatchetRecord records step completion.
 func runRatchetRecord(cmd *cobra.Command, args []string) error {
-	stepName := args[0]
-	step := ratchet.ParseStep(stepName)
-	if step == "" {
-		return fmt.Errorf("unknown step: %s", stepName)
-	}
-
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("get working directory: %w", err)
 	}
 
+	wf, err := ratchet.LoadWorkflow(cwd)
+	if err != nil {
+		return fmt.Errorf("load workflow: %w", err)
+	}
+	step, err := parseWorkflowStep(wf, args[0])
+	if err != nil {
+		return err
+	}
+
 	if GetDryRun() {
 		fmt.Printf("Would record step: %s\n", step)
 		fmt.Printf("  Input: %s\n", ratchetInput)
@@ -32,17 +32,20 @@ Examples:
 
 // runRatchetSkip records an intentional skip.
 func runRatchetSkip(cmd *cobra.Command, args []string) error {
-	stepName := args[0]
-	step := ratchet.ParseStep(stepName)
-	if step == "" {
-		return fmt.Errorf("unknown step: %s", stepName)
-	}
-
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("get working directory: %w", err)
 	}
 
+	wf, err := ratchet.LoadWorkflow(cwd)
+	if err != nil {
+		return fmt.Errorf("load workflow: %w", err)
+	}
+	step, err := parseWorkflowStep(wf, args[0])
+	if err != nil {
+		return err
+	}
+
 	if GetDryRun() {
 		fmt.Printf("Would skip step: %s\n", step)
 		fmt.Printf("  Reason: %s\n", ratchetReason)
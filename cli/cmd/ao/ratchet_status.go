@@ -21,7 +21,8 @@ func init() {
 		Short:   "Show ratchet chain state",
 		Long: `Display the current state of the ratchet chain.
 
-Shows all steps and their status (pending, in_progress, locked, skipped).
+Shows all workflow steps and their status (pending, in_progress, locked,
+skipped), following .agents/ratchet/workflow.yaml when it exists.
 
 Examples:
   ao ratchet status
@@ -46,22 +47,25 @@ func runRatchetStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("load chain: %w", err)
 	}
 
-	// Get status for all steps
-	allStatus := chain.GetAllStatus()
+	wf, err := ratchet.LoadWorkflow(cwd)
+	if err != nil {
+		return fmt.Errorf("load workflow: %w", err)
+	}
 
 	// Build output structure
 	output := ratchetStatusOutput{
-		ChainID: chain.ID,
-		Started: chain.Started.Format(time.RFC3339),
-		EpicID:  chain.EpicID,
-		Path:    chain.Path(),
-		Steps:   make([]ratchetStepInfo, 0),
+		ChainID:  chain.ID,
+		Started:  chain.Started.Format(time.RFC3339),
+		EpicID:   chain.EpicID,
+		Path:     chain.Path(),
+		Workflow: wf.Source,
+		Steps:    make([]ratchetStepInfo, 0),
 	}
 
-	for _, step := range ratchet.AllSteps() {
+	for _, step := range wf.StepNames() {
 		info := ratchetStepInfo{
 			Step:   step,
-			Status: allStatus[step],
+			Status: chain.GetStatus(step),
 		}
 
 		// Get details from latest entry
@@ -123,6 +127,9 @@ func outputRatchetStatus(w io.Writer, data *ratchetStatusOutput) error {
 		}
 
 		fmt.Fprintf(w, "\nPath: %s\n", data.Path)
+		if data.Workflow != "" {
+			fmt.Fprintf(w, "Workflow: %s\n", data.Workflow)
+		}
 		return nil
 	}
 }
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/boshu2/agentops/cli/internal/ratchet"
+)
+
+// ratchetTimelineFormatText is the default human-readable timeline format.
+const ratchetTimelineFormatText = "text"
+
+var ratchetTimelineFormat string
+
+func init() {
+	timelineSubCmd := &cobra.Command{
+		Use:     "timeline",
+		Aliases: []string{"tl"},
+		GroupID: "inspection",
+		Short:   "Show the full ratchet chain history",
+		Long: `Show the full history of the ratchet chain.
+
+Unlike status, which shows only the latest entry per step, the timeline lists
+every entry in order, grouped by cycle: skips with their reasons, steps
+re-opened after they were locked, and the time taken to reach each step.
+The longest wait is called out so post-mortems can see where a cycle stalled.
+
+Formats:
+  text      human-readable timeline (default)
+  json      structured timeline for dashboards
+  mermaid   Mermaid gantt chart
+  dot       Graphviz digraph
+
+Examples:
+  ao ratchet timeline
+  ao ratchet timeline --cycle 2
+  ao ratchet timeline --format mermaid > timeline.mmd
+  ao ratchet timeline --format dot | dot -Tsvg > timeline.svg`,
+		RunE: runRatchetTimeline,
+	}
+	timelineSubCmd.Flags().StringVar(&ratchetTimelineFormat, "format", ratchetTimelineFormatText,
+		"Output format: "+ratchetTimelineFormatText+", "+strings.Join(ratchet.TimelineFormats, ", "))
+	timelineSubCmd.Flags().IntVar(&ratchetCycle, "cycle", 0, "Only show this RPI cycle")
+	ratchetCmd.AddCommand(timelineSubCmd)
+}
+
+// runRatchetTimeline renders the chain history.
+func runRatchetTimeline(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	chain, err := ratchet.LoadChain(cwd)
+	if err != nil {
+		return fmt.Errorf("load chain: %w", err)
+	}
+
+	timeline := ratchet.BuildTimeline(chain)
+	if ratchetCycle > 0 {
+		timeline = timeline.Cycle(ratchetCycle)
+		if timeline == nil {
+			return fmt.Errorf("chain has no cycle %d", ratchetCycle)
+		}
+	}
+
+	format := ratchetTimelineFormat
+	if format == ratchetTimelineFormatText && GetOutput() == "json" {
+		format = ratchet.TimelineFormatJSON
+	}
+
+	w := cmd.OutOrStdout()
+	if format == ratchetTimelineFormatText {
+		return printRatchetTimeline(w, timeline)
+	}
+	return ratchet.RenderTimeline(w, timeline, format)
+}
+
+// printRatchetTimeline writes the text timeline.
+func printRatchetTimeline(w io.Writer, t *ratchet.Timeline) error {
+	fmt.Fprintf(w, "Ratchet Timeline: %s\n", t.ChainID) //nolint:errcheck // CLI output
+	if t.EpicID != "" {
+		fmt.Fprintf(w, "Epic: %s\n", t.EpicID) //nolint:errcheck // CLI output
+	}
+	if len(t.Cycles) == 0 {
+		fmt.Fprintln(w, "\nNo entries recorded yet.") //nolint:errcheck // CLI output
+		return nil
+	}
+
+	for _, c := range t.Cycles {
+		fmt.Fprintf(w, "\nCycle %d", c.Cycle) //nolint:errcheck // CLI output
+		if c.ParentEpic != "" {
+			fmt.Fprintf(w, " (parent %s)", c.ParentEpic) //nolint:errcheck // CLI output
+		}
+		fmt.Fprintln(w) //nolint:errcheck // CLI output
+		for _, e := range c.Events {
+			icon := statusIcon(e.Status)
+			if e.Reopened {
+				icon = "↺"
+			}
+			detail := e.Output
+			if e.Status == ratchet.StatusSkipped {
+				detail = "reason: " + e.Reason
+			}
+			fmt.Fprintf(w, "  %s  %-15s %s %-11s %7s  %s\n", //nolint:errcheck // CLI output
+				e.End.Local().Format("2006-01-02 15:04"), e.Step, icon, timelineStatus(e), "+"+formatDuration(e.Duration()), truncate(detail, 40))
+		}
+	}
+
+	fmt.Fprintf(w, "\nTotal: %s\n", formatDuration(t.Ended.Sub(t.Started))) //nolint:errcheck // CLI output
+	if e, cycle, ok := t.LongestWait(); ok && e.Duration() > 0 {
+		fmt.Fprintf(w, "Longest wait: %s before %s (cycle %d)\n", formatDuration(e.Duration()), e.Step, cycle) //nolint:errcheck // CLI output
+	}
+	return nil
+}
+
+// timelineStatus is the event status, noting re-opened steps.
+func timelineStatus(e ratchet.TimelineEvent) string {
+	if e.Reopened {
+		return "reopened"
+	}
+	return string(e.Status)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRatchetTimeline(t *testing.T) {
+	dir := chdirTemp(t)
+	if err := os.MkdirAll(filepath.Join(dir, ".agents"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := executeCommand("ratchet", "timeline")
+	if err != nil {
+		t.Fatalf("timeline on empty chain: %v", err)
+	}
+	if !strings.Contains(out, "No entries recorded yet") {
+		t.Errorf("empty timeline output:\n%s", out)
+	}
+
+	if _, err := executeCommand("ratchet", "record", "research", "--output", "r.md"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executeCommand("ratchet", "skip", "pre-mortem", "--reason", "bug fix"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = executeCommand("ratchet", "timeline")
+	if err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	for _, want := range []string{"Cycle 1", "research", "reason: bug fix", "Total:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("timeline missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = executeCommand("ratchet", "timeline", "--format", "mermaid")
+	if err != nil {
+		t.Fatalf("timeline mermaid: %v", err)
+	}
+	if !strings.HasPrefix(out, "gantt\n") {
+		t.Errorf("mermaid output:\n%s", out)
+	}
+
+	if _, err := executeCommand("ratchet", "timeline", "--cycle", "4"); err == nil {
+		t.Error("missing cycle should error")
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/ratchet"
+)
+
+const testSecurityWorkflow = `steps:
+  - name: research
+  - name: plan
+  - name: implement
+    needs: [plan]
+  - name: security-review
+    aliases: [secrev]
+    needs: [implement]
+    gate: {artifacts: ["security/*.md"]}
+    skill: /security-review
+  - name: post-mortem
+`
+
+func setupRatchetWorkflow(t *testing.T) string {
+	t.Helper()
+	dir := chdirTemp(t)
+	path := filepath.Join(dir, ".agents", ratchet.WorkflowFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(testSecurityWorkflow), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRatchetWorkflow_RecordAndNext(t *testing.T) {
+	setupRatchetWorkflow(t)
+
+	for _, step := range []string{"research", "plan", "implement"} {
+		if _, err := executeCommand("ratchet", "record", step, "--output", step+".md"); err != nil {
+			t.Fatalf("record %s: %v", step, err)
+		}
+	}
+
+	out, err := executeCommand("ratchet", "next")
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	if !strings.Contains(out, "Next step: security-review") || !strings.Contains(out, "/security-review") {
+		t.Errorf("next should suggest security-review:\n%s", out)
+	}
+
+	if _, err := executeCommand("ratchet", "skip", "secrev", "--reason", "docs only"); err != nil {
+		t.Fatalf("skip by alias: %v", err)
+	}
+	out, err = executeCommand("ratchet", "next")
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	if !strings.Contains(out, "Next step: post-mortem") {
+		t.Errorf("next after skip should be post-mortem:\n%s", out)
+	}
+}
+
+func TestRatchetWorkflow_UnknownStepListsWorkflow(t *testing.T) {
+	setupRatchetWorkflow(t)
+
+	_, err := executeCommand("ratchet", "record", "crank", "--output", "x")
+	if err == nil {
+		t.Fatal("crank is not part of the custom workflow")
+	}
+	if !strings.Contains(err.Error(), "unknown step: crank") || !strings.Contains(err.Error(), "security-review") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestRatchetWorkflow_CheckEnforcesNeeds(t *testing.T) {
+	setupRatchetWorkflow(t)
+
+	_, err := executeCommand("ratchet", "check", "security-review")
+	if err == nil || !strings.Contains(err.Error(), "Requires implement") {
+		t.Errorf("check error = %v, want unmet need", err)
+	}
+}
+
+func TestRatchetWorkflow_Status(t *testing.T) {
+	setupRatchetWorkflow(t)
+
+	out, err := executeCommand("ratchet", "status")
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !strings.Contains(out, "security-review") || strings.Contains(out, "crank") {
+		t.Errorf("status should list the custom steps:\n%s", out)
+	}
+	if !strings.Contains(out, "Workflow: ") {
+		t.Errorf("status should name the workflow file:\n%s", out)
+	}
+}
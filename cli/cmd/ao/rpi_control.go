@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Control command kinds honored by the phased engine. They are appended to
+// the run's commands.jsonl and acknowledged with command.<kind>.ack (or
+// command.<kind>.rejected) C2 events when the engine acts on them.
+const (
+	rpiControlPause     = "pause"
+	rpiControlResume    = "resume"
+	rpiControlSkipPhase = "skip-phase"
+	rpiControlRetryGate = "retry-gate"
+	rpiControlAbort     = "abort"
+	rpiControlSetBudget = "set-budget"
+)
+
+// rpiControlKinds lists the control command kinds in help order.
+var rpiControlKinds = []string{
+	rpiControlPause, rpiControlResume, rpiControlSkipPhase,
+	rpiControlRetryGate, rpiControlAbort, rpiControlSetBudget,
+}
+
+// rpiControlTarget is the command target for the phased engine itself.
+const rpiControlTarget = "orchestrator"
+
+var (
+	rpiControlPhase  int
+	rpiControlReason string
+)
+
+func init() {
+	controlCmd := &cobra.Command{
+		Use:   "control <run-id> <kind> [value]",
+		Short: "Send a control command to a running phased RPI run",
+		Long: `Send a control command to a running phased RPI run.
+
+The command is appended to the run's commands.jsonl. The orchestrator polls
+it at safe points (before each phase and between gate retries) and records a
+command.<kind>.ack or command.<kind>.rejected event when it acts.
+
+Kinds:
+  pause       stop at the next safe point and persist state until resumed
+  resume      continue a paused run
+  skip-phase  skip a phase that has not started (--phase, default: next phase)
+  retry-gate  grant one more retry when a gate would escalate (--phase optional)
+  abort       stop the run at the next safe point (reason required)
+  set-budget  override phase budgets (<phase>:<seconds>, comma-separated)
+
+When a gate would escalate, the run waits up to 2 minutes for a retry-gate
+command before it fails; pause and abort are honored while it waits. A
+retry-gate sent after that window is rejected because the run has ended.
+
+Examples:
+  ao rpi control 760fc86f0c0f pause
+  ao rpi control 760fc86f0c0f resume
+  ao rpi control 760fc86f0c0f skip-phase --phase 3
+  ao rpi control 760fc86f0c0f retry-gate
+  ao rpi control 760fc86f0c0f abort "wrong goal"
+  ao rpi control 760fc86f0c0f set-budget implementation:3600,validation:900`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: runRPIControl,
+	}
+	controlCmd.Flags().IntVar(&rpiControlPhase, "phase", 0, "Phase the command applies to (1-3; skip-phase and retry-gate)")
+	controlCmd.Flags().StringVar(&rpiControlReason, "reason", "", "Reason recorded with the command (required for abort)")
+	rpiCmd.AddCommand(controlCmd)
+}
+
+// rpiControlRequest is a control command before it is queued, as accepted by
+// ao rpi control and the POST /control/<kind> endpoint of ao rpi serve.
+type rpiControlRequest struct {
+	RunID  string `json:"run_id"`
+	Kind   string `json:"kind"`
+	Phase  int    `json:"phase,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Budget string `json:"budget,omitempty"`
+}
+
+func runRPIControl(cmd *cobra.Command, args []string) error {
+	req := rpiControlRequest{
+		RunID:  strings.TrimSpace(args[0]),
+		Kind:   strings.ToLower(strings.TrimSpace(args[1])),
+		Phase:  rpiControlPhase,
+		Reason: strings.TrimSpace(rpiControlReason),
+	}
+	if len(args) == 3 {
+		value := strings.TrimSpace(args[2])
+		switch req.Kind {
+		case rpiControlSetBudget:
+			req.Budget = value
+		case rpiControlAbort:
+			if req.Reason == "" {
+				req.Reason = value
+			}
+		default:
+			return fmt.Errorf("%s takes no value argument", req.Kind)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	state, root, err := locateRunMetadata(cwd, req.RunID)
+	if err != nil {
+		return fmt.Errorf("locate run %s: %w", req.RunID, err)
+	}
+	if state.TerminalStatus != "" {
+		return fmt.Errorf("run %s is %s; control commands only apply to active runs", req.RunID, state.TerminalStatus)
+	}
+
+	record, err := queueRPIControlCommand(root, req, "cli")
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(record)
+	}
+	fmt.Fprintf(w, "Queued %s for run %s (command %s)\n", record.Kind, record.RunID, record.CommandID) //nolint:errcheck // CLI output
+	return nil
+}
+
+// validateRPIControlRequest checks a control request before it is queued.
+// The engine re-validates commands it reads, since commands.jsonl may be
+// written by other tools.
+func validateRPIControlRequest(req rpiControlRequest) error {
+	if strings.TrimSpace(req.RunID) == "" {
+		return fmt.Errorf("run_id is required")
+	}
+	if !slices.Contains(rpiControlKinds, req.Kind) {
+		return fmt.Errorf("unknown control kind %q (valid: %s)", req.Kind, strings.Join(rpiControlKinds, "|"))
+	}
+	if req.Phase < 0 || req.Phase > len(phases) {
+		return fmt.Errorf("phase must be between 1 and %d (got %d)", len(phases), req.Phase)
+	}
+	if req.Phase != 0 && req.Kind != rpiControlSkipPhase && req.Kind != rpiControlRetryGate {
+		return fmt.Errorf("%s does not take a phase", req.Kind)
+	}
+	switch req.Kind {
+	case rpiControlAbort:
+		if strings.TrimSpace(req.Reason) == "" {
+			return fmt.Errorf("abort requires a reason")
+		}
+	case rpiControlSetBudget:
+		if strings.TrimSpace(req.Budget) == "" {
+			return fmt.Errorf("set-budget requires a budget spec (<phase>:<seconds>, comma-separated)")
+		}
+		if _, err := parsePhaseBudgetSpec(req.Budget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queueRPIControlCommand validates req and appends it to the run's command
+// log under root. source records who sent it (cli, serve).
+func queueRPIControlCommand(root string, req rpiControlRequest, source string) (RPIC2Command, error) {
+	req.Kind = strings.ToLower(strings.TrimSpace(req.Kind))
+	if err := validateRPIControlRequest(req); err != nil {
+		return RPIC2Command{}, err
+	}
+	message := strings.TrimSpace(req.Reason)
+	if req.Kind == rpiControlSetBudget {
+		message = strings.TrimSpace(req.Budget)
+	}
+	return appendRPIC2Command(root, rpiC2CommandInput{
+		RunID:    req.RunID,
+		Phase:    req.Phase,
+		Kind:     req.Kind,
+		Targets:  []string{rpiControlTarget},
+		Message:  message,
+		Metadata: map[string]any{"source": source},
+	})
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateRPIControlRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     rpiControlRequest
+		wantErr string
+	}{
+		{"pause", rpiControlRequest{RunID: "r1", Kind: "pause"}, ""},
+		{"skip with phase", rpiControlRequest{RunID: "r1", Kind: "skip-phase", Phase: 3}, ""},
+		{"retry-gate any phase", rpiControlRequest{RunID: "r1", Kind: "retry-gate"}, ""},
+		{"set-budget", rpiControlRequest{RunID: "r1", Kind: "set-budget", Budget: "implementation:600"}, ""},
+		{"missing run", rpiControlRequest{Kind: "pause"}, "run_id is required"},
+		{"unknown kind", rpiControlRequest{RunID: "r1", Kind: "reprioritize"}, "unknown control kind"},
+		{"phase out of range", rpiControlRequest{RunID: "r1", Kind: "skip-phase", Phase: 4}, "phase must be between"},
+		{"phase on pause", rpiControlRequest{RunID: "r1", Kind: "pause", Phase: 2}, "does not take a phase"},
+		{"abort without reason", rpiControlRequest{RunID: "r1", Kind: "abort"}, "abort requires a reason"},
+		{"set-budget without spec", rpiControlRequest{RunID: "r1", Kind: "set-budget"}, "requires a budget spec"},
+		{"set-budget bad spec", rpiControlRequest{RunID: "r1", Kind: "set-budget", Budget: "nope"}, "invalid budget"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRPIControlRequest(tt.req)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueueRPIControlCommand(t *testing.T) {
+	root := t.TempDir()
+	if _, err := queueRPIControlCommand(root, rpiControlRequest{RunID: "run-ctl", Kind: "Set-Budget", Budget: "validation:300"}, "cli"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queueRPIControlCommand(root, rpiControlRequest{RunID: "run-ctl", Kind: "abort", Reason: "wrong goal"}, "serve"); err != nil {
+		t.Fatal(err)
+	}
+	commands, err := loadRPIC2Commands(root, "run-ctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commands) != 2 {
+		t.Fatalf("commands = %d, want 2", len(commands))
+	}
+	if commands[0].Kind != "set-budget" || commands[0].Message != "validation:300" {
+		t.Errorf("set-budget command = %+v", commands[0])
+	}
+	if commands[1].Kind != "abort" || commands[1].Message != "wrong goal" || commands[1].Targets[0] != rpiControlTarget {
+		t.Errorf("abort command = %+v", commands[1])
+	}
+	if _, err := queueRPIControlCommand(root, rpiControlRequest{RunID: "run-ctl", Kind: "abort"}, "cli"); err == nil {
+		t.Error("abort without reason should not be queued")
+	}
+}
+
+func TestServeRPIControl(t *testing.T) {
+	root := t.TempDir()
+	runID := "rpi-control-serve"
+	writeRegistryRun(t, root, registryRunSpec{runID: runID, phase: 2, schema: 1, goal: "g", hbAge: time.Minute})
+	mux := buildServeMux(&serveMuxRoot{path: root}, runID)
+
+	postHost := func(host, path, contentType, origin, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Host = host
+		req.Header.Set("Content-Type", contentType)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+	post := func(path, contentType, origin, body string) *httptest.ResponseRecorder {
+		return postHost("localhost:7799", path, contentType, origin, body)
+	}
+
+	rr := post("/control/skip-phase", "application/json", "http://localhost:7799", `{"phase": 3}`)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body = %s", rr.Code, rr.Body.String())
+	}
+	var record RPIC2Command
+	if err := json.Unmarshal(rr.Body.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.RunID != runID || record.Kind != "skip-phase" || record.Phase != 3 {
+		t.Errorf("record = %+v", record)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Methods"); got != "POST, OPTIONS" {
+		t.Errorf("Allow-Methods = %q", got)
+	}
+
+	for name, tc := range map[string]struct {
+		rr   *httptest.ResponseRecorder
+		want int
+	}{
+		"foreign origin":  {post("/control/pause", "application/json", "https://evil.example", `{}`), http.StatusForbidden},
+		"lookalike":       {post("/control/pause", "application/json", "http://localhost.attacker.example", `{}`), http.StatusForbidden},
+		"rebound host":    {postHost("attacker.example:7799", "/control/pause", "application/json", "", `{}`), http.StatusForbidden},
+		"ipv6 loopback":   {postHost("[::1]:7799", "/control/pause", "application/json", "http://[::1]:7799", `{"run_id": "rpi-missing"}`), http.StatusNotFound},
+		"form post":       {post("/control/pause", "text/plain", "", `{}`), http.StatusUnsupportedMediaType},
+		"invalid request": {post("/control/abort", "application/json", "", `{}`), http.StatusBadRequest},
+		"unknown run":     {post("/control/pause", "application/json", "", `{"run_id": "rpi-missing"}`), http.StatusNotFound},
+	} {
+		if tc.rr.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d (%s)", name, tc.rr.Code, tc.want, tc.rr.Body.String())
+		}
+	}
+
+	getRR := httptest.NewRecorder()
+	mux.ServeHTTP(getRR, httptest.NewRequest(http.MethodGet, "/control/pause", nil))
+	if getRR.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want 405", getRR.Code)
+	}
+
+	commands, err := loadRPIC2Commands(root, runID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commands) != 1 {
+		t.Errorf("only the valid request should be queued, got %d commands", len(commands))
+	}
+}
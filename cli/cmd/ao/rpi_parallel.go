@@ -3,11 +3,11 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -21,13 +21,20 @@ var (
 	parallelRuntimeCmd   string
 	parallelPhaseTimeout time.Duration
 	parallelTmux         bool
+	parallelMaxConc      int
+	parallelConflictPol  string
+	parallelNoResolve    bool
+	parallelResolveTO    time.Duration
 )
 
 // parallelEpic describes one epic to run in parallel.
 type parallelEpic struct {
-	Name       string `json:"name" yaml:"name"`
-	Goal       string `json:"goal" yaml:"goal"`
-	MergeOrder int    `json:"merge_order" yaml:"merge_order"`
+	Name       string   `json:"name" yaml:"name"`
+	Goal       string   `json:"goal" yaml:"goal"`
+	MergeOrder int      `json:"merge_order" yaml:"merge_order"`
+	DependsOn  []string `json:"depends_on,omitempty" yaml:"depends_on,omitempty"` // epics that must merge before this one starts
+	Plan       string   `json:"plan,omitempty" yaml:"plan,omitempty"`             // plan file mined for conflict prediction
+	Bead       string   `json:"bead,omitempty" yaml:"bead,omitempty"`             // bead whose description is mined for conflict prediction
 }
 
 // parallelResult captures the outcome of one epic's execution.
@@ -40,6 +47,11 @@ type parallelResult struct {
 	Duration  time.Duration
 	CommitSHA string
 	LogFile   string
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Skipped    bool // not run because a prerequisite did not succeed
+	Merged     bool // merged early so dependents could start
 }
 
 // parallelManifestFile is the top-level manifest structure.
@@ -67,6 +79,23 @@ Each epic gets:
 After all epics complete, worktrees are merged back to the base branch
 in the specified order. A validation gate runs after all merges.
 
+Epics in a manifest may declare depends_on. An epic starts only after its
+prerequisites succeeded and were merged, so its worktree includes their
+changes; an epic downstream of a failure is skipped. --max-concurrency caps
+how many epics run at once. The final report lists the DAG, the critical
+path and per-epic wall-clock (also written to .agents/rpi/parallel/report.json
+and printed with -o json, which sends all progress output to stderr so the
+report is the only thing on stdout).
+
+Before spawning, a planning pass predicts which files and packages each epic
+will touch, from its goal, plan file and bead description plus git co-change
+history, and warns about overlapping epics (--conflict-policy warn) or makes
+the later of each high-risk pair wait for the earlier one (serialize). When a
+merge still conflicts, a focused resolve-conflict phase runs in the epic's
+worktree and the merge is retried (disable with --no-resolve). Scheduling
+waits on that phase, so it has its own shorter --resolve-timeout, and a
+resolution that leaves conflict markers in any file is rolled back.
+
 Input: either a manifest file (--manifest) or inline goals as arguments.
 
 Examples:
@@ -79,11 +108,16 @@ Examples:
   # Skip auto-merge (leave worktrees for manual review)
   ao rpi parallel --no-merge "goal 1" "goal 2"
 
+  # Dependency-aware manifest, at most two epics at a time
+  ao rpi parallel --manifest epics.json --max-concurrency 2
+
 Manifest format (JSON):
   {
     "epics": [
       {"name": "evolve", "goal": "Add evolve watchdog...", "merge_order": 1},
-      {"name": "cli-obs", "goal": "Add CLI dashboard...", "merge_order": 2}
+      {"name": "cli-obs", "goal": "Add CLI dashboard...", "merge_order": 2},
+      {"name": "docs", "goal": "Document both...", "depends_on": ["evolve", "cli-obs"]},
+      {"name": "auth", "goal": "Add login", "plan": ".agents/plans/auth.md", "bead": "ag-123"}
     ]
   }`,
 		Args: cobra.ArbitraryArgs,
@@ -97,6 +131,10 @@ Manifest format (JSON):
 	parallelCmd.Flags().StringVar(&parallelRuntimeCmd, "runtime-cmd", "", "Runtime command for phased sessions (default: claude)")
 	parallelCmd.Flags().DurationVar(&parallelPhaseTimeout, "phase-timeout", 90*time.Minute, "Timeout per epic (kills subprocess if exceeded)")
 	parallelCmd.Flags().BoolVar(&parallelTmux, "tmux", false, "Spawn epics in tmux windows for interactive visibility")
+	parallelCmd.Flags().IntVar(&parallelMaxConc, "max-concurrency", 0, "Maximum epics running at once (0 = unlimited)")
+	parallelCmd.Flags().StringVar(&parallelConflictPol, "conflict-policy", parallelConflictWarn, "How to handle predicted merge conflicts: warn|serialize|off")
+	parallelCmd.Flags().BoolVar(&parallelNoResolve, "no-resolve", false, "Leave merge conflicts for manual review instead of running a resolve-conflict phase")
+	parallelCmd.Flags().DurationVar(&parallelResolveTO, "resolve-timeout", 20*time.Minute, "Timeout per resolve-conflict phase (scheduling waits on it)")
 
 	rpiCmd.AddCommand(parallelCmd)
 }
@@ -107,11 +145,20 @@ func runRPIParallel(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	reportOut, restoreStdout := redirectParallelProgress()
+	defer restoreStdout()
+
+	conflicts := planParallelConflicts(os.Stdout, baseCwd, epics, parallelConflictPol)
+	hasDeps := parallelHasDependencies(epics)
+
 	if GetDryRun() {
 		fmt.Printf("DRY RUN: would run %d epics in parallel worktrees\n", len(epics))
 		for i, e := range epics {
 			fmt.Printf("  [%d] %s: %s\n", i+1, e.Name, truncateGoal(e.Goal, 80))
 		}
+		if hasDeps {
+			printParallelDAG(os.Stdout, epics)
+		}
 		return nil
 	}
 
@@ -121,10 +168,24 @@ func runRPIParallel(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
-	worktrees, err := createParallelWorktrees(baseCwd, epics, runtimeCmd)
+	// Epics without prerequisites branch from the current HEAD up front;
+	// dependents get their worktree once their prerequisites are merged.
+	worktrees := make([]worktreeInfo, len(epics))
+	var roots []parallelEpic
+	var rootIdx []int
+	for i, e := range epics {
+		if len(e.DependsOn) == 0 {
+			roots = append(roots, e)
+			rootIdx = append(rootIdx, i)
+		}
+	}
+	rootWorktrees, err := createParallelWorktrees(baseCwd, roots, runtimeCmd)
 	if err != nil {
 		return err
 	}
+	for j, i := range rootIdx {
+		worktrees[i] = rootWorktrees[j]
+	}
 
 	logDir := filepath.Join(baseCwd, ".agents", "rpi", "parallel")
 	_ = os.MkdirAll(logDir, 0o750)
@@ -135,13 +196,24 @@ func runRPIParallel(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	results := spawnParallelEpics(epics, worktrees, runtimeCmd, logDir, tmuxSession, tmuxCmd, parallelPhaseTimeout)
+	runStart := time.Now()
+	var resolve parallelConflictResolver
+	if !parallelNoResolve {
+		resolve = newParallelConflictResolver(baseCwd, runtimeCmd, logDir, parallelResolveTO)
+	}
+	results := spawnParallelEpics(baseCwd, epics, worktrees, runtimeCmd, logDir, tmuxSession, tmuxCmd, parallelPhaseTimeout, resolve)
 
 	allSuccess := reportParallelResults(results, epics, parallelTmux, tmuxSession, tmuxCmd)
+	if err := emitParallelReport(reportOut, epics, results, conflicts, logDir, runStart); err != nil {
+		return err
+	}
 
 	if parallelNoMerge {
 		fmt.Println("--no-merge: worktrees left in place for manual review:")
 		for i, wt := range worktrees {
+			if wt.path == "" {
+				continue
+			}
 			fmt.Printf("  %s: %s (branch: %s)\n", epics[i].Name, wt.path, wt.branch)
 		}
 		return nil
@@ -151,7 +223,7 @@ func runRPIParallel(cmd *cobra.Command, args []string) error {
 		fmt.Println("Some epics failed. Merging only successful ones.")
 	}
 
-	mergedCount, err := mergeParallelWorktrees(epics, results, worktrees)
+	mergedCount, err := mergeParallelWorktrees(epics, results, worktrees, resolve)
 	if err != nil {
 		return err
 	}
@@ -166,9 +238,43 @@ func runRPIParallel(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// redirectParallelProgress sends progress output (everything written to
+// os.Stdout, including child processes) to stderr under -o json, so the JSON
+// report is the only thing on stdout. It returns the writer for the report and
+// a func that undoes the redirect.
+func redirectParallelProgress() (io.Writer, func()) {
+	stdout := os.Stdout
+	if GetOutput() != "json" {
+		return stdout, func() {}
+	}
+	os.Stdout = os.Stderr
+	return stdout, func() { os.Stdout = stdout }
+}
+
+// emitParallelReport builds the DAG report, writes report.json under logDir
+// and prints it to w as text, or as JSON with -o json.
+func emitParallelReport(w io.Writer, epics []parallelEpic, results []parallelResult, conflicts []parallelConflictPrediction, logDir string, start time.Time) error {
+	report := buildParallelReport(epics, results, parallelMaxConc, start, time.Now())
+	report.Conflicts = conflicts
+	if err := writeParallelReport(logDir, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write parallel report: %v\n", err)
+	}
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printParallelReport(w, epics, report)
+	return nil
+}
+
 // cleanupParallelWorktrees removes worktrees and deletes branches for successful epics.
+// Epics that never started have no worktree.
 func cleanupParallelWorktrees(worktrees []worktreeInfo, results []parallelResult) {
 	for i, wt := range worktrees {
+		if wt.path == "" {
+			continue
+		}
 		_ = exec.Command("git", "worktree", "remove", "--force", wt.path).Run()
 		if results[i].Success {
 			_ = exec.Command("git", "branch", "-d", wt.branch).Run()
@@ -219,6 +325,18 @@ func validateParallelPrereqs(args []string) ([]parallelEpic, string, string, err
 	if _, err := exec.LookPath(runtimeCmd); err != nil {
 		return nil, "", "", fmt.Errorf("runtime command %q not found on PATH", runtimeCmd)
 	}
+	if parallelMaxConc < 0 {
+		return nil, "", "", fmt.Errorf("--max-concurrency must be >= 0 (got %d)", parallelMaxConc)
+	}
+	if parallelNoMerge && parallelHasDependencies(epics) {
+		return nil, "", "", fmt.Errorf("--no-merge cannot be used with depends_on: dependents start from their merged prerequisites")
+	}
+	if err := validateConflictPolicy(parallelConflictPol); err != nil {
+		return nil, "", "", err
+	}
+	if parallelNoMerge && parallelConflictPol == parallelConflictSerialize {
+		return nil, "", "", fmt.Errorf("--no-merge cannot be used with --conflict-policy serialize: serialized epics start from their merged predecessors")
+	}
 
 	return epics, baseCwd, runtimeCmd, nil
 }
@@ -235,32 +353,41 @@ func parallelWorktreeRoot(baseCwd, runtimeCmd string) string {
 // On failure, cleans up previously created worktrees.
 func createParallelWorktrees(baseCwd string, epics []parallelEpic, runtimeCmd string) ([]worktreeInfo, error) {
 	worktrees := make([]worktreeInfo, len(epics))
-	worktreeRoot := parallelWorktreeRoot(baseCwd, runtimeCmd)
 	for i, e := range epics {
-		branch := "epic/" + e.Name
-		wtPath := filepath.Join(worktreeRoot, e.Name)
-
-		// Clean up stale worktree if it exists.
-		_ = exec.Command("git", "worktree", "remove", "--force", wtPath).Run()
-		// Clean up stale branch if it exists.
-		_ = exec.Command("git", "branch", "-D", branch).Run()
-
-		out, err := exec.Command("git", "worktree", "add", "-b", branch, wtPath).CombinedOutput()
+		wt, err := createParallelWorktree(baseCwd, e, runtimeCmd)
 		if err != nil {
 			// Clean up any worktrees we already created.
 			for j := 0; j < i; j++ {
 				_ = exec.Command("git", "worktree", "remove", "--force", worktrees[j].path).Run()
 				_ = exec.Command("git", "branch", "-D", worktrees[j].branch).Run()
 			}
-			return nil, fmt.Errorf("create worktree for %s: %s: %w", e.Name, string(out), err)
+			return nil, err
 		}
-		worktrees[i] = worktreeInfo{path: wtPath, branch: branch}
-		fmt.Printf("  worktree: %s → %s\n", e.Name, branch)
+		worktrees[i] = wt
 	}
 	fmt.Println()
 	return worktrees, nil
 }
 
+// createParallelWorktree creates the epic/<name> worktree for one epic from
+// the current HEAD, replacing any stale worktree or branch of the same name.
+func createParallelWorktree(baseCwd string, e parallelEpic, runtimeCmd string) (worktreeInfo, error) {
+	branch := "epic/" + e.Name
+	wtPath := filepath.Join(parallelWorktreeRoot(baseCwd, runtimeCmd), e.Name)
+
+	// Clean up stale worktree if it exists.
+	_ = exec.Command("git", "worktree", "remove", "--force", wtPath).Run()
+	// Clean up stale branch if it exists.
+	_ = exec.Command("git", "branch", "-D", branch).Run()
+
+	out, err := exec.Command("git", "worktree", "add", "-b", branch, wtPath).CombinedOutput()
+	if err != nil {
+		return worktreeInfo{}, fmt.Errorf("create worktree for %s: %s: %w", e.Name, string(out), err)
+	}
+	fmt.Printf("  worktree: %s → %s\n", e.Name, branch)
+	return worktreeInfo{path: wtPath, branch: branch}, nil
+}
+
 // setupTmuxSession sets up a tmux session for parallel epic visibility.
 // Returns the tmux command path (empty if tmux not requested) and any error.
 func setupTmuxSession(tmuxSession string) (string, error) {
@@ -283,18 +410,27 @@ func setupTmuxSession(tmuxSession string) (string, error) {
 	return tmuxCmd, nil
 }
 
-// spawnParallelEpics dispatches all epics concurrently via goroutines, using tmux or direct mode.
+// spawnParallelEpics dispatches epics via goroutines, using tmux or direct mode.
+// Epics start as soon as their depends_on prerequisites are merged, at most
+// --max-concurrency at a time; worktrees missing from the slice are created
+// at launch. A conflicting early merge is retried after resolve, when set.
 // Returns a results slice indexed by epic position.
-func spawnParallelEpics(epics []parallelEpic, worktrees []worktreeInfo, runtimeCmd, logDir, tmuxSession, tmuxCmd string, timeout time.Duration) []parallelResult {
-	results := make([]parallelResult, len(epics))
-	var wg sync.WaitGroup
-
-	for i, e := range epics {
-		wg.Add(1)
-		go func(idx int, epic parallelEpic, wt worktreeInfo) {
-			defer wg.Done()
+func spawnParallelEpics(baseCwd string, epics []parallelEpic, worktrees []worktreeInfo, runtimeCmd, logDir, tmuxSession, tmuxCmd string, timeout time.Duration, resolve parallelConflictResolver) []parallelResult {
+	hooks := parallelDAGHooks{
+		prepare: func(idx int) error {
+			if worktrees[idx].path != "" {
+				return nil
+			}
+			wt, err := createParallelWorktree(baseCwd, epics[idx], runtimeCmd)
+			if err != nil {
+				return err
+			}
+			worktrees[idx] = wt
+			return nil
+		},
+		run: func(idx int) parallelResult {
+			epic, wt := epics[idx], worktrees[idx]
 			start := time.Now()
-
 			logFile := filepath.Join(logDir, epic.Name+".log")
 			var result parallelResult
 			if parallelTmux {
@@ -302,20 +438,24 @@ func spawnParallelEpics(epics []parallelEpic, worktrees []worktreeInfo, runtimeC
 			} else {
 				result = runParallelEpic(epic, wt.path, wt.branch, runtimeCmd, logFile, timeout)
 			}
-			result.Duration = time.Since(start)
 			result.LogFile = logFile
-			results[idx] = result
 
 			status := "DONE"
 			if !result.Success {
 				status = "FAIL"
 			}
-			fmt.Printf("  [%s] %s (%s) — %s\n", status, epic.Name, result.Duration.Round(time.Second), wt.branch)
-		}(i, e, worktrees[i])
+			fmt.Printf("  [%s] %s (%s) — %s\n", status, epic.Name, time.Since(start).Round(time.Second), wt.branch)
+			return result
+		},
+	}
+	if !parallelNoMerge {
+		hooks.merge = func(idx int) error {
+			return mergeParallelPrerequisite(epics[idx], worktrees[idx], resolve)
+		}
 	}
 
 	fmt.Println("Running epics...")
-	wg.Wait()
+	results := scheduleParallelDAG(epics, parallelMaxConc, hooks)
 	fmt.Println()
 
 	// Clean up tmux setup window.
@@ -331,7 +471,10 @@ func spawnParallelEpics(epics []parallelEpic, worktrees []worktreeInfo, runtimeC
 func reportParallelResults(results []parallelResult, epics []parallelEpic, tmux bool, tmuxSession, tmuxCmd string) bool {
 	allSuccess := true
 	for _, r := range results {
-		if !r.Success {
+		if r.Skipped {
+			allSuccess = false
+			fmt.Printf("SKIP: %s — %v\n", r.Epic.Name, r.Error)
+		} else if !r.Success {
 			allSuccess = false
 			fmt.Printf("FAIL: %s — %v (log: %s)\n", r.Epic.Name, r.Error, r.LogFile)
 		} else {
@@ -348,27 +491,45 @@ func reportParallelResults(results []parallelResult, epics []parallelEpic, tmux
 }
 
 // mergeParallelWorktrees resolves merge order and merges successful epic branches.
-// Returns merged count and any merge conflict error.
-func mergeParallelWorktrees(epics []parallelEpic, results []parallelResult, worktrees []worktreeInfo) (int, error) {
+// A conflicting merge is aborted and retried after a resolve-conflict phase
+// when resolve is set. Returns merged count and any merge conflict error.
+func mergeParallelWorktrees(epics []parallelEpic, results []parallelResult, worktrees []worktreeInfo, resolve parallelConflictResolver) (int, error) {
 	mergeIndices := resolveMergeOrder(epics, results)
 
 	mergedCount := 0
-	for _, idx := range mergeIndices {
+	for pos, idx := range mergeIndices {
+		if results[idx].Merged {
+			mergedCount++ // merged early for its dependents
+			continue
+		}
 		if !results[idx].Success {
-			fmt.Printf("SKIP merge: %s (failed)\n", epics[idx].Name)
+			reason := "failed"
+			if results[idx].Skipped {
+				reason = "skipped"
+			}
+			fmt.Printf("SKIP merge: %s (%s)\n", epics[idx].Name, reason)
 			continue
 		}
 
 		branch := worktrees[idx].branch
-		msg := fmt.Sprintf("feat(%s): %s", epics[idx].Name, truncateGoal(epics[idx].Goal, 60))
-
-		out, err := exec.Command("git", "merge", branch, "--no-ff", "-m", msg).CombinedOutput()
+		out, err := mergeParallelBranch(epics[idx], branch)
+		if err != nil && resolve != nil {
+			_ = exec.Command("git", "merge", "--abort").Run()
+			fmt.Printf("MERGE CONFLICT: %s — running resolve-conflict phase\n", epics[idx].Name)
+			if rerr := resolve(epics[idx], worktrees[idx]); rerr != nil {
+				fmt.Printf("  resolve-conflict failed: %v\n", rerr)
+			}
+			// Either clean now, or reproduces the conflict for manual review.
+			out, err = mergeParallelBranch(epics[idx], branch)
+		}
 		if err != nil {
 			fmt.Printf("MERGE CONFLICT: %s — %s\n", epics[idx].Name, string(out))
 			fmt.Printf("  Resolve manually, then: git merge --continue\n")
 			fmt.Printf("  Remaining branches: ")
-			for _, j := range mergeIndices[mergedCount+1:] {
-				fmt.Printf("%s ", worktrees[j].branch)
+			for _, j := range mergeIndices[pos+1:] {
+				if results[j].Success && !results[j].Merged {
+					fmt.Printf("%s ", worktrees[j].branch)
+				}
 			}
 			fmt.Println()
 			return mergedCount, fmt.Errorf("merge conflict on %s: %w", branch, err)
@@ -381,6 +542,36 @@ func mergeParallelWorktrees(epics []parallelEpic, results []parallelResult, work
 	return mergedCount, nil
 }
 
+// mergeParallelBranch merges an epic branch into the current branch.
+func mergeParallelBranch(epic parallelEpic, branch string) ([]byte, error) {
+	msg := fmt.Sprintf("feat(%s): %s", epic.Name, truncateGoal(epic.Goal, 60))
+	return exec.Command("git", "merge", branch, "--no-ff", "-m", msg).CombinedOutput()
+}
+
+// mergeParallelPrerequisite merges a finished epic that others depend on, so
+// dependents branch from a HEAD that includes it. A conflict is retried after
+// a resolve-conflict phase when resolve is set; a remaining conflict is
+// aborted and reported as the epic's failure, which skips its dependents.
+func mergeParallelPrerequisite(epic parallelEpic, wt worktreeInfo, resolve parallelConflictResolver) error {
+	branch := wt.branch
+	out, err := mergeParallelBranch(epic, branch)
+	if err != nil && resolve != nil {
+		_ = exec.Command("git", "merge", "--abort").Run()
+		fmt.Printf("  [MERGE CONFLICT] %s — running resolve-conflict phase\n", epic.Name)
+		if rerr := resolve(epic, wt); rerr != nil {
+			fmt.Printf("  resolve-conflict failed: %v\n", rerr)
+		}
+		out, err = mergeParallelBranch(epic, branch)
+	}
+	if err != nil {
+		_ = exec.Command("git", "merge", "--abort").Run()
+		fmt.Printf("  [MERGE CONFLICT] %s — dependents will be skipped\n", epic.Name)
+		return fmt.Errorf("merge %s for dependents: %s: %w", branch, strings.TrimSpace(string(out)), err)
+	}
+	fmt.Printf("  [MERGED] %s (%s) — dependents may start\n", epic.Name, branch)
+	return nil
+}
+
 // runParallelEpic executes one epic in its worktree via ao rpi phased subprocess.
 func runParallelEpic(epic parallelEpic, worktreePath, branch, runtimeCmd, logFile string, timeout time.Duration) parallelResult {
 	result := parallelResult{
@@ -486,6 +677,9 @@ func resolveParallelEpics(args []string) ([]parallelEpic, error) {
 				mf.Epics[i].MergeOrder = i + 1
 			}
 		}
+		if err := validateParallelDAG(mf.Epics); err != nil {
+			return nil, err
+		}
 		return mf.Epics, nil
 	}
 
@@ -0,0 +1,495 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Conflict policies for the planning pass of ao rpi parallel.
+const (
+	parallelConflictOff       = "off"
+	parallelConflictWarn      = "warn"
+	parallelConflictSerialize = "serialize"
+)
+
+const (
+	// parallelCoChangeWindow is how much git history the planning pass mines
+	// for co-change coupling.
+	parallelCoChangeWindow = 90 * 24 * time.Hour
+	// parallelCoChangeMinCommits is how often two files must have changed
+	// together before touching one predicts touching the other (the same
+	// threshold ao mine uses for co-change clusters).
+	parallelCoChangeMinCommits = 3
+	// parallelCoChangeMaxFiles skips sweeping commits when mining co-change.
+	parallelCoChangeMaxFiles = 50
+)
+
+// Conflict risk levels, highest first.
+const (
+	parallelRiskHigh   = "high"   // both epics are predicted to edit the same files
+	parallelRiskMedium = "medium" // both epics are predicted to edit the same packages
+)
+
+// parallelFootprint is the predicted set of files and packages an epic edits.
+type parallelFootprint struct {
+	Files    map[string]string // path -> source (goal, plan, bead, co-change)
+	Packages map[string]bool
+}
+
+// parallelConflictPrediction is a pair of epics likely to conflict on merge.
+type parallelConflictPrediction struct {
+	A          string   `json:"a"`
+	B          string   `json:"b"`
+	Risk       string   `json:"risk"`
+	Files      []string `json:"files,omitempty"`
+	Packages   []string `json:"packages,omitempty"`
+	Serialized bool     `json:"serialized,omitempty"` // B was made to depend on A
+}
+
+// parallelPathToken matches path-like tokens: anything with a slash, or a
+// bare file name with a common source extension.
+var parallelPathToken = regexp.MustCompile(`[A-Za-z0-9_.\-]+(?:/[A-Za-z0-9_.\-]+)+/?|[A-Za-z0-9_\-]+\.(?:go|py|ts|tsx|js|jsx|rs|md|yaml|yml|json|sh|toml|sql|proto|html|css)\b`)
+
+// validateConflictPolicy checks the --conflict-policy flag value.
+func validateConflictPolicy(policy string) error {
+	switch policy {
+	case parallelConflictOff, parallelConflictWarn, parallelConflictSerialize:
+		return nil
+	default:
+		return fmt.Errorf("invalid --conflict-policy %q (valid: warn|serialize|off)", policy)
+	}
+}
+
+// parallelEpicDocuments returns the text the planning pass mines for an epic,
+// keyed by source: the goal, the plan file and the bead description.
+func parallelEpicDocuments(baseCwd string, epic parallelEpic) map[string]string {
+	docs := map[string]string{"goal": epic.Goal}
+	if epic.Plan != "" {
+		path := epic.Plan
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseCwd, path)
+		}
+		if data, err := os.ReadFile(path); err == nil {
+			docs["plan"] = string(data)
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: epic %s: could not read plan %s: %v\n", epic.Name, epic.Plan, err)
+		}
+	}
+	if epic.Bead != "" {
+		if text, err := bdShowDescription(epic.Bead); err == nil {
+			docs["bead"] = text
+		} else {
+			VerbosePrintf("Warning: epic %s: could not read bead %s: %v\n", epic.Name, epic.Bead, err)
+		}
+	}
+	return docs
+}
+
+// bdShowDescription returns the title, description and design notes of a
+// bead via bd show --json.
+func bdShowDescription(issueID string) (string, error) {
+	output, err := exec.Command("bd", "show", issueID, "--json").Output()
+	if err != nil {
+		return "", err
+	}
+	type bead struct {
+		Title              string `json:"title"`
+		Description        string `json:"description"`
+		Design             string `json:"design"`
+		AcceptanceCriteria string `json:"acceptance_criteria"`
+		Notes              string `json:"notes"`
+	}
+	var beads []bead
+	if err := json.Unmarshal(output, &beads); err != nil {
+		var single bead
+		if err2 := json.Unmarshal(output, &single); err2 != nil {
+			return "", err
+		}
+		beads = []bead{single}
+	}
+	var parts []string
+	for _, b := range beads {
+		parts = append(parts, b.Title, b.Description, b.Design, b.AcceptanceCriteria, b.Notes)
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+// parallelTrackedFiles lists the files git tracks in baseCwd.
+func parallelTrackedFiles(baseCwd string) ([]string, error) {
+	cmd := exec.Command("git", "ls-files")
+	cmd.Dir = baseCwd
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// predictParallelFootprint extracts the files and packages an epic mentions,
+// resolved against the tracked files, then adds files that historically
+// change together with them.
+func predictParallelFootprint(docs map[string]string, tracked []string, coChange map[string]map[string]int) parallelFootprint {
+	fp := parallelFootprint{Files: make(map[string]string), Packages: make(map[string]bool)}
+	trackedSet := make(map[string]bool, len(tracked))
+	dirs := make(map[string]bool)
+	byBase := make(map[string][]string)
+	for _, f := range tracked {
+		trackedSet[f] = true
+		byBase[filepath.Base(f)] = append(byBase[filepath.Base(f)], f)
+		for d := filepath.Dir(f); d != "."; d = filepath.Dir(d) {
+			dirs[d] = true
+		}
+	}
+
+	sources := make([]string, 0, len(docs))
+	for source := range docs {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		for _, token := range parallelPathToken.FindAllString(docs[source], -1) {
+			if strings.Contains(token, "://") {
+				continue
+			}
+			token = strings.TrimSuffix(strings.TrimPrefix(token, "./"), "/")
+			token = strings.TrimRight(token, ".")
+			switch {
+			case trackedSet[token]:
+				fp.addFile(token, source)
+			case dirs[token]:
+				fp.Packages[token] = true
+			case !strings.Contains(token, "/"):
+				// Bare file name: only trust it when it is unambiguous.
+				if matches := byBase[token]; len(matches) == 1 {
+					fp.addFile(matches[0], source)
+				}
+			case filepath.Ext(token) != "":
+				fp.addFile(token, source) // a file the epic will create
+			}
+		}
+	}
+
+	direct := make([]string, 0, len(fp.Files))
+	for f := range fp.Files {
+		direct = append(direct, f)
+	}
+	sort.Strings(direct)
+	for _, f := range direct {
+		for partner, n := range coChange[f] {
+			if n >= parallelCoChangeMinCommits {
+				if _, ok := fp.Files[partner]; !ok {
+					fp.addFile(partner, "co-change:"+f)
+				}
+			}
+		}
+	}
+	return fp
+}
+
+func (fp parallelFootprint) addFile(path, source string) {
+	if _, ok := fp.Files[path]; !ok {
+		fp.Files[path] = source
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		fp.Packages[dir] = true
+	}
+}
+
+// parallelAncestors returns, for every epic, the set of epics it transitively
+// depends on.
+func parallelAncestors(epics []parallelEpic) []map[int]bool {
+	index := parallelEpicIndex(epics)
+	ancestors := make([]map[int]bool, len(epics))
+	var visit func(i int) map[int]bool
+	visit = func(i int) map[int]bool {
+		if ancestors[i] != nil {
+			return ancestors[i]
+		}
+		ancestors[i] = make(map[int]bool)
+		for _, dep := range epics[i].DependsOn {
+			if d, ok := index[dep]; ok {
+				ancestors[i][d] = true
+				for a := range visit(d) {
+					ancestors[i][a] = true
+				}
+			}
+		}
+		return ancestors[i]
+	}
+	for i := range epics {
+		visit(i)
+	}
+	return ancestors
+}
+
+// predictParallelConflicts compares footprints of every pair of epics that
+// may run concurrently (neither depends on the other) and reports the pairs
+// that overlap, highest risk first. Pairs are ordered by merge order so A
+// merges before B.
+func predictParallelConflicts(epics []parallelEpic, footprints []parallelFootprint, mergeOrder []int) []parallelConflictPrediction {
+	rank := make([]int, len(epics))
+	for pos, idx := range mergeOrder {
+		rank[idx] = pos + 1
+	}
+	ancestors := parallelAncestors(epics)
+
+	var predictions []parallelConflictPrediction
+	for i := range epics {
+		for j := i + 1; j < len(epics); j++ {
+			if ancestors[i][j] || ancestors[j][i] {
+				continue // already serialized by depends_on
+			}
+			a, b := i, j
+			if rank[b] != 0 && (rank[a] == 0 || rank[b] < rank[a]) {
+				a, b = b, a
+			}
+			p := parallelConflictPrediction{A: epics[a].Name, B: epics[b].Name}
+			for f := range footprints[a].Files {
+				if _, ok := footprints[b].Files[f]; ok {
+					p.Files = append(p.Files, f)
+				}
+			}
+			for pkg := range footprints[a].Packages {
+				if footprints[b].Packages[pkg] {
+					p.Packages = append(p.Packages, pkg)
+				}
+			}
+			switch {
+			case len(p.Files) > 0:
+				p.Risk = parallelRiskHigh
+			case len(p.Packages) > 0:
+				p.Risk = parallelRiskMedium
+			default:
+				continue
+			}
+			sort.Strings(p.Files)
+			sort.Strings(p.Packages)
+			predictions = append(predictions, p)
+		}
+	}
+	sort.SliceStable(predictions, func(x, y int) bool {
+		return predictions[x].Risk == parallelRiskHigh && predictions[y].Risk != parallelRiskHigh
+	})
+	return predictions
+}
+
+// serializeParallelConflicts makes the later epic of every high-risk pair
+// depend on the earlier one, so it starts from a HEAD that already contains
+// the overlapping changes. Edges that would create a cycle are left out.
+func serializeParallelConflicts(epics []parallelEpic, predictions []parallelConflictPrediction) {
+	index := parallelEpicIndex(epics)
+	for k := range predictions {
+		p := &predictions[k]
+		if p.Risk != parallelRiskHigh {
+			continue
+		}
+		b := index[p.B]
+		if ancestors := parallelAncestors(epics); ancestors[b][index[p.A]] || ancestors[index[p.A]][b] {
+			p.Serialized = true // an earlier edge already orders them
+			continue
+		}
+		epics[b].DependsOn = append(slices.Clone(epics[b].DependsOn), p.A)
+		if err := validateParallelDAG(epics); err != nil {
+			epics[b].DependsOn = epics[b].DependsOn[:len(epics[b].DependsOn)-1]
+			continue
+		}
+		p.Serialized = true
+	}
+}
+
+// planParallelConflicts runs the planning pass: it predicts each epic's
+// footprint from its goal, plan file and bead plus git co-change history,
+// prints the overlapping pairs and, under the serialize policy, orders
+// high-risk pairs through depends_on.
+func planParallelConflicts(w io.Writer, baseCwd string, epics []parallelEpic, policy string) []parallelConflictPrediction {
+	if policy == parallelConflictOff || len(epics) < 2 {
+		return nil
+	}
+	tracked, err := parallelTrackedFiles(baseCwd)
+	if err != nil {
+		fmt.Fprintf(w, "Conflict prediction skipped: %v\n\n", err) //nolint:errcheck // CLI output
+		return nil
+	}
+	coChange, err := mineGitCoChange(baseCwd, parallelCoChangeWindow, parallelCoChangeMaxFiles)
+	if err != nil {
+		VerbosePrintf("Warning: co-change history unavailable: %v\n", err)
+	}
+
+	footprints := make([]parallelFootprint, len(epics))
+	for i, e := range epics {
+		footprints[i] = predictParallelFootprint(parallelEpicDocuments(baseCwd, e), tracked, coChange)
+	}
+	predictions := predictParallelConflicts(epics, footprints, resolveMergeOrder(epics, nil))
+	if policy == parallelConflictSerialize {
+		serializeParallelConflicts(epics, predictions)
+	}
+	printParallelConflicts(w, predictions, policy)
+	return predictions
+}
+
+// printParallelConflicts prints the planning pass result.
+func printParallelConflicts(w io.Writer, predictions []parallelConflictPrediction, policy string) {
+	if len(predictions) == 0 {
+		fmt.Fprintln(w, "Conflict prediction: no overlapping epics") //nolint:errcheck // CLI output
+		fmt.Fprintln(w)                                              //nolint:errcheck // CLI output
+		return
+	}
+	fmt.Fprintf(w, "Conflict prediction (%d overlapping pairs):\n", len(predictions)) //nolint:errcheck // CLI output
+	for _, p := range predictions {
+		overlap := "packages " + strings.Join(limitStrings(p.Packages, 5), ", ")
+		if len(p.Files) > 0 {
+			overlap = "files " + strings.Join(limitStrings(p.Files, 5), ", ")
+		}
+		action := ""
+		switch {
+		case p.Serialized:
+			action = fmt.Sprintf(" → serialized: %s waits for %s", p.B, p.A)
+		case p.Risk == parallelRiskHigh && policy == parallelConflictWarn:
+			action = " (use --conflict-policy serialize to order them)"
+		}
+		fmt.Fprintf(w, "  [%s] %s ↔ %s: %s%s\n", strings.ToUpper(p.Risk), p.A, p.B, overlap, action) //nolint:errcheck // CLI output
+	}
+	fmt.Fprintln(w) //nolint:errcheck // CLI output
+}
+
+// limitStrings returns at most n items, noting how many were left out.
+func limitStrings(items []string, n int) []string {
+	if len(items) <= n {
+		return items
+	}
+	return append(slices.Clone(items[:n]), fmt.Sprintf("+%d more", len(items)-n))
+}
+
+// parallelConflictResolver runs a resolve-conflict phase for an epic whose
+// merge into the base branch conflicted.
+type parallelConflictResolver func(epic parallelEpic, wt worktreeInfo) error
+
+// newParallelConflictResolver returns a resolver that merges the base HEAD
+// into the epic's worktree, asks the runtime to resolve the conflicted files
+// and commits the result, so the epic branch then merges cleanly.
+func newParallelConflictResolver(baseCwd, runtimeCmd, logDir string, timeout time.Duration) parallelConflictResolver {
+	return func(epic parallelEpic, wt worktreeInfo) error {
+		baseOut, err := exec.Command("git", "-C", baseCwd, "rev-parse", "HEAD").Output()
+		if err != nil {
+			return fmt.Errorf("resolve base HEAD: %w", err)
+		}
+		baseSHA := strings.TrimSpace(string(baseOut))
+		epicOut, err := exec.Command("git", "-C", wt.path, "rev-parse", "HEAD").Output()
+		if err != nil {
+			return fmt.Errorf("resolve %s HEAD: %w", wt.branch, err)
+		}
+		epicSHA := strings.TrimSpace(string(epicOut))
+
+		out, err := exec.Command("git", "-C", wt.path, "merge", "--no-ff", "--no-edit", baseSHA).CombinedOutput()
+		if err == nil {
+			return nil // the base merged cleanly into the epic branch
+		}
+		conflicted := parallelUnmergedFiles(wt.path)
+		if len(conflicted) == 0 {
+			abortParallelResolve(wt.path, epicSHA)
+			return fmt.Errorf("merge base into %s: %s", wt.branch, strings.TrimSpace(string(out)))
+		}
+
+		logFile := filepath.Join(logDir, epic.Name+"-resolve.log")
+		f, err := os.Create(logFile)
+		if err != nil {
+			abortParallelResolve(wt.path, epicSHA)
+			return fmt.Errorf("create resolve log: %w", err)
+		}
+		defer f.Close()
+
+		fmt.Printf("  [RESOLVE] %s: %d conflicted files (log: %s)\n", epic.Name, len(conflicted), logFile)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		prompt := buildResolveConflictPrompt(epic, conflicted)
+		cmd := exec.CommandContext(ctx, runtimeCmd, runtimeDirectCommandArgs(runtimeCmd, prompt)...)
+		cmd.Dir = wt.path
+		cmd.Stdout = f
+		cmd.Stderr = f
+		cmd.Env = append(cleanEnvNoClaude(), "AGENTOPS_RPI_NO_WORKTREE=1")
+		if err := cmd.Run(); err != nil {
+			abortParallelResolve(wt.path, epicSHA)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("resolve-conflict phase for %s timed out after %s (set --resolve-timeout to increase)", epic.Name, timeout)
+			}
+			return fmt.Errorf("resolve-conflict phase for %s: %w", epic.Name, err)
+		}
+
+		if remaining := parallelUnmergedFiles(wt.path); len(remaining) > 0 {
+			abortParallelResolve(wt.path, epicSHA)
+			return fmt.Errorf("resolve-conflict phase left %d conflicted files: %s", len(remaining), strings.Join(limitStrings(remaining, 5), ", "))
+		}
+		// A file can be staged, and even committed, with its conflict
+		// markers still in it; that is not a resolution.
+		if marked := parallelConflictMarkers(wt.path, baseSHA, conflicted); len(marked) > 0 {
+			abortParallelResolve(wt.path, epicSHA)
+			return fmt.Errorf("resolve-conflict phase left conflict markers in %d files: %s", len(marked), strings.Join(limitStrings(marked, 5), ", "))
+		}
+		if exec.Command("git", "-C", wt.path, "rev-parse", "-q", "--verify", "MERGE_HEAD").Run() == nil {
+			if out, err := exec.Command("git", "-C", wt.path, "commit", "-a", "--no-edit").CombinedOutput(); err != nil {
+				return fmt.Errorf("commit conflict resolution: %s: %w", strings.TrimSpace(string(out)), err)
+			}
+		}
+		return nil
+	}
+}
+
+// abortParallelResolve returns a worktree to epicSHA after a failed
+// resolution: it aborts a merge still in progress, or resets a merge the
+// resolve-conflict phase already committed.
+func abortParallelResolve(dir, epicSHA string) {
+	if exec.Command("git", "-C", dir, "rev-parse", "-q", "--verify", "MERGE_HEAD").Run() == nil {
+		_ = exec.Command("git", "-C", dir, "merge", "--abort").Run()
+		return
+	}
+	_ = exec.Command("git", "-C", dir, "reset", "--hard", epicSHA).Run()
+}
+
+// parallelConflictMarkers lists the files that git diff --check reports as
+// still holding conflict markers relative to base.
+func parallelConflictMarkers(dir, base string, files []string) []string {
+	args := append([]string{"-C", dir, "diff", "--check", base, "--"}, files...)
+	out, _ := exec.Command("git", args...).Output() // exits non-zero when it finds problems
+	var marked []string
+	for _, line := range strings.Split(string(out), "\n") {
+		file, _, ok := strings.Cut(line, ":")
+		if ok && strings.Contains(line, "leftover conflict marker") && !slices.Contains(marked, file) {
+			marked = append(marked, file)
+		}
+	}
+	return marked
+}
+
+// parallelUnmergedFiles lists files with unresolved conflicts in a worktree.
+func parallelUnmergedFiles(dir string) []string {
+	out, err := exec.Command("git", "-C", dir, "diff", "--name-only", "--diff-filter=U").Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(out))
+}
+
+// buildResolveConflictPrompt is the focused prompt for a resolve-conflict phase.
+func buildResolveConflictPrompt(epic parallelEpic, conflicted []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are resolving merge conflicts for the epic %q.\n\n", epic.Name)
+	fmt.Fprintf(&b, "Epic goal: %s\n\n", epic.Goal)
+	b.WriteString("The base branch, which now contains work from other epics, was merged into this worktree and conflicted in:\n")
+	for _, f := range conflicted {
+		fmt.Fprintf(&b, "  - %s\n", f)
+	}
+	b.WriteString("\nResolve every conflict so that both this epic's changes and the base branch's changes are preserved. ")
+	b.WriteString("Do not start new work. Build and run the tests that cover the conflicted files, then stage the files and complete the merge with `git commit --no-edit`.\n")
+	return b.String()
+}
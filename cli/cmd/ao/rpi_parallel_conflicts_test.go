@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPredictParallelFootprint(t *testing.T) {
+	tracked := []string{"cli/cmd/ao/rpi_parallel.go", "cli/cmd/ao/mine.go", "cli/internal/rpi/worktree.go", "docs/a/README.md", "docs/b/README.md"}
+	coChange := map[string]map[string]int{
+		"cli/cmd/ao/rpi_parallel.go": {"cli/docs/COMMANDS.md": 4, "cli/cmd/ao/mine.go": 1},
+	}
+	docs := map[string]string{
+		"goal": "Touch rpi_parallel.go and README.md, see https://example.com/x/y.",
+		"plan": "Edit `./cli/internal/rpi/` and create cli/internal/rpi/dag.go. Also and/or things.",
+	}
+	fp := predictParallelFootprint(docs, tracked, coChange)
+
+	var files []string
+	for f := range fp.Files {
+		files = append(files, f)
+	}
+	slices.Sort(files)
+	want := []string{"cli/cmd/ao/rpi_parallel.go", "cli/docs/COMMANDS.md", "cli/internal/rpi/dag.go"}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("files = %v, want %v (ambiguous README.md and URLs must be ignored)", files, want)
+	}
+	if fp.Files["cli/docs/COMMANDS.md"] != "co-change:cli/cmd/ao/rpi_parallel.go" {
+		t.Errorf("co-change source = %q", fp.Files["cli/docs/COMMANDS.md"])
+	}
+	for _, pkg := range []string{"cli/cmd/ao", "cli/internal/rpi", "cli/docs"} {
+		if !fp.Packages[pkg] {
+			t.Errorf("package %s missing from %v", pkg, fp.Packages)
+		}
+	}
+}
+
+func TestPredictParallelConflicts(t *testing.T) {
+	epics := []parallelEpic{
+		{Name: "api", MergeOrder: 2},
+		{Name: "ui", MergeOrder: 1},
+		{Name: "docs", MergeOrder: 3, DependsOn: []string{"api"}},
+		{Name: "infra", MergeOrder: 4},
+	}
+	footprint := func(files ...string) parallelFootprint {
+		fp := parallelFootprint{Files: map[string]string{}, Packages: map[string]bool{}}
+		for _, f := range files {
+			fp.addFile(f, "goal")
+		}
+		return fp
+	}
+	footprints := []parallelFootprint{
+		footprint("pkg/server/api.go"),
+		footprint("pkg/server/api.go", "web/app.ts"),
+		footprint("pkg/server/api.go"), // overlaps api, but depends on it
+		footprint("pkg/server/infra.go"),
+	}
+	got := predictParallelConflicts(epics, footprints, resolveMergeOrder(epics, nil))
+
+	if len(got) != 5 { // ui↔api, ui↔docs (high); api, ui, docs ↔ infra (medium)
+		t.Fatalf("predictions = %+v", got)
+	}
+	if got[0].A != "ui" || got[0].B != "api" || got[0].Risk != parallelRiskHigh || !reflect.DeepEqual(got[0].Files, []string{"pkg/server/api.go"}) {
+		t.Errorf("first prediction should be ui ↔ api on api.go, got %+v", got[0])
+	}
+	for _, p := range got {
+		if (p.A == "api" && p.B == "docs") || (p.A == "docs" && p.B == "api") {
+			t.Errorf("epics ordered by depends_on must not be reported: %+v", p)
+		}
+		if p.A == "api" && p.B == "infra" && p.Risk != parallelRiskMedium {
+			t.Errorf("shared package only should be medium risk: %+v", p)
+		}
+	}
+}
+
+func TestSerializeParallelConflicts(t *testing.T) {
+	epics := []parallelEpic{{Name: "a"}, {Name: "b"}, {Name: "c", DependsOn: []string{"b"}}}
+	predictions := []parallelConflictPrediction{
+		{A: "a", B: "b", Risk: parallelRiskHigh},
+		{A: "c", B: "a", Risk: parallelRiskHigh},   // already ordered once b waits for a
+		{A: "a", B: "c", Risk: parallelRiskMedium}, // medium risk is only warned about
+	}
+	serializeParallelConflicts(epics, predictions)
+
+	if !reflect.DeepEqual(epics[1].DependsOn, []string{"a"}) {
+		t.Errorf("b should wait for a: %v", epics[1].DependsOn)
+	}
+	// After b→a, c already transitively depends on a, so c↔a is already ordered.
+	if !predictions[1].Serialized || len(epics[0].DependsOn) != 0 {
+		t.Errorf("c ↔ a is ordered through b; no edge should be added: %+v %v", predictions[1], epics[0].DependsOn)
+	}
+	if predictions[2].Serialized {
+		t.Error("medium risk pairs must not be serialized")
+	}
+	if err := validateParallelDAG(epics); err != nil {
+		t.Fatalf("serialization produced an invalid DAG: %v", err)
+	}
+}
+
+func TestPrintParallelConflicts(t *testing.T) {
+	var buf bytes.Buffer
+	printParallelConflicts(&buf, []parallelConflictPrediction{
+		{A: "ui", B: "api", Risk: parallelRiskHigh, Files: []string{"a.go"}},
+		{A: "api", B: "infra", Risk: parallelRiskMedium, Packages: []string{"pkg/server"}},
+	}, parallelConflictWarn)
+	out := buf.String()
+	for _, want := range []string{"[HIGH] ui ↔ api: files a.go (use --conflict-policy serialize", "[MEDIUM] api ↔ infra: packages pkg/server"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestValidateConflictPolicy(t *testing.T) {
+	for _, ok := range []string{"warn", "serialize", "off"} {
+		if err := validateConflictPolicy(ok); err != nil {
+			t.Errorf("%s: %v", ok, err)
+		}
+	}
+	if err := validateConflictPolicy("block"); err == nil {
+		t.Error("unknown policy should fail")
+	}
+}
+
+// initConflictRepo creates a repo whose base branch and epic/feat branch both
+// changed shared.txt, checks out the epic branch in a worktree and returns
+// (repo, worktree).
+func initConflictRepo(t *testing.T) (string, string) {
+	t.Helper()
+	repo := t.TempDir()
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %v", args, out, err)
+		}
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git(repo, "init")
+	git(repo, "config", "user.email", "test@test.com")
+	git(repo, "config", "user.name", "Test")
+	write(filepath.Join(repo, "shared.txt"), "base\n")
+	git(repo, "add", ".")
+	git(repo, "commit", "-m", "init")
+
+	wt := filepath.Join(t.TempDir(), "feat")
+	git(repo, "worktree", "add", "-b", "epic/feat", wt)
+	git(wt, "config", "user.email", "test@test.com")
+	git(wt, "config", "user.name", "Test")
+	write(filepath.Join(wt, "shared.txt"), "feat\n")
+	git(wt, "commit", "-am", "feat")
+
+	write(filepath.Join(repo, "shared.txt"), "other epic\n")
+	git(repo, "commit", "-am", "other epic")
+	return repo, wt
+}
+
+func TestParallelConflictResolver_ResolvesAndMerges(t *testing.T) {
+	repo, wt := initConflictRepo(t)
+
+	// Fake runtime: refuses to nest inside a Claude session like the real
+	// CLI, then resolves by keeping both sides, stages and lets the resolver
+	// finish the merge commit.
+	t.Setenv("CLAUDECODE", "1")
+	runtime := filepath.Join(t.TempDir(), "fake-runtime")
+	script := "#!/bin/sh\n[ -n \"$CLAUDECODE\" ] && exit 1\nprintf 'other epic\\nfeat\\n' > shared.txt\ngit add shared.txt\n"
+	if err := os.WriteFile(runtime, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	epic := parallelEpic{Name: "feat", Goal: "feat work"}
+	resolve := newParallelConflictResolver(repo, runtime, t.TempDir(), time.Minute)
+	if err := mergeParallelPrerequisite(epic, worktreeInfo{path: wt, branch: "epic/feat"}, resolve); err != nil {
+		t.Fatalf("merge after resolve-conflict phase: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(repo, "shared.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "other epic\nfeat\n" {
+		t.Errorf("merged shared.txt = %q", data)
+	}
+}
+
+func TestParallelConflictResolver_FailureAbortsMerge(t *testing.T) {
+	repo, wt := initConflictRepo(t)
+
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	resolve := newParallelConflictResolver(repo, "true", t.TempDir(), time.Minute)
+	err := mergeParallelPrerequisite(parallelEpic{Name: "feat"}, worktreeInfo{path: wt, branch: "epic/feat"}, resolve)
+	if err == nil {
+		t.Fatal("unresolved conflict should fail the merge")
+	}
+	if files := parallelUnmergedFiles(repo); len(files) != 0 {
+		t.Errorf("base should be left clean, unmerged: %v", files)
+	}
+	if files := parallelUnmergedFiles(wt); len(files) != 0 {
+		t.Errorf("worktree merge should be aborted, unmerged: %v", files)
+	}
+}
+
+func TestParallelConflictResolver_RejectsLeftoverMarkers(t *testing.T) {
+	repo, wt := initConflictRepo(t)
+	before, err := exec.Command("git", "-C", wt, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fake runtime: stages the file with its markers and commits the merge.
+	runtime := filepath.Join(t.TempDir(), "fake-runtime")
+	script := "#!/bin/sh\ngit add shared.txt\ngit commit --no-edit -q\n"
+	if err := os.WriteFile(runtime, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	resolve := newParallelConflictResolver(repo, runtime, t.TempDir(), time.Minute)
+	err = resolve(parallelEpic{Name: "feat"}, worktreeInfo{path: wt, branch: "epic/feat"})
+	if err == nil || !strings.Contains(err.Error(), "conflict markers") {
+		t.Fatalf("err = %v, want leftover conflict markers", err)
+	}
+	after, err := exec.Command("git", "-C", wt, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("committed marker merge should be rolled back: HEAD %s -> %s", before, after)
+	}
+}
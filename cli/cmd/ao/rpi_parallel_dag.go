@@ -0,0 +1,387 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// validateParallelDAG checks the depends_on edges of a manifest: every
+// prerequisite must name another epic and the graph must be acyclic.
+func validateParallelDAG(epics []parallelEpic) error {
+	index := parallelEpicIndex(epics)
+	for _, e := range epics {
+		for _, dep := range e.DependsOn {
+			if dep == e.Name {
+				return fmt.Errorf("epic %q depends on itself", e.Name)
+			}
+			if _, ok := index[dep]; !ok {
+				return fmt.Errorf("epic %q depends on unknown epic %q", e.Name, dep)
+			}
+		}
+	}
+	_, err := parallelTopoOrder(epics)
+	return err
+}
+
+// parallelEpicIndex maps epic names to their manifest position.
+func parallelEpicIndex(epics []parallelEpic) map[string]int {
+	index := make(map[string]int, len(epics))
+	for i, e := range epics {
+		index[e.Name] = i
+	}
+	return index
+}
+
+// parallelHasDependencies reports whether any epic declares depends_on.
+func parallelHasDependencies(epics []parallelEpic) bool {
+	for _, e := range epics {
+		if len(e.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parallelTopoOrder returns epic indices grouped into levels: level 0 has no
+// prerequisites, level N depends only on earlier levels. Manifest order is
+// kept within a level. Unknown prerequisites are ignored here (see
+// validateParallelDAG); a cycle is an error.
+func parallelTopoOrder(epics []parallelEpic) ([][]int, error) {
+	index := parallelEpicIndex(epics)
+	placed := make([]bool, len(epics))
+	var levels [][]int
+	for remaining := len(epics); remaining > 0; {
+		var current []int
+		for i, e := range epics {
+			if placed[i] {
+				continue
+			}
+			ready := true
+			for _, dep := range e.DependsOn {
+				if d, ok := index[dep]; ok && !placed[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				current = append(current, i)
+			}
+		}
+		if len(current) == 0 {
+			var cycle []string
+			for i, e := range epics {
+				if !placed[i] {
+					cycle = append(cycle, e.Name)
+				}
+			}
+			return nil, fmt.Errorf("depends_on has a cycle among epics: %s", strings.Join(cycle, ", "))
+		}
+		for _, i := range current {
+			placed[i] = true
+		}
+		levels = append(levels, current)
+		remaining -= len(current)
+	}
+	return levels, nil
+}
+
+// parallelDAGHooks are the side effects of DAG scheduling, split out so the
+// scheduler can be tested without git or a runtime.
+type parallelDAGHooks struct {
+	// prepare runs on the scheduler goroutine right before an epic launches
+	// (e.g. to create its worktree once prerequisites are merged).
+	prepare func(idx int) error
+	// run executes one epic; it is called on its own goroutine.
+	run func(idx int) parallelResult
+	// merge integrates a successful epic that others depend on before its
+	// dependents start. Nil disables early merging.
+	merge func(idx int) error
+}
+
+// scheduleParallelDAG runs epics respecting depends_on and maxConcurrency
+// (0 = unlimited). An epic starts only after all its prerequisites succeeded
+// (and were merged, when merge is set); an epic downstream of a failure is
+// skipped with the failing prerequisite as reason. Results are indexed by
+// epic position.
+func scheduleParallelDAG(epics []parallelEpic, maxConcurrency int, hooks parallelDAGHooks) []parallelResult {
+	const (
+		statePending = iota
+		stateRunning
+		stateFinished
+	)
+	index := parallelEpicIndex(epics)
+	hasDependents := make([]bool, len(epics))
+	for _, e := range epics {
+		for _, dep := range e.DependsOn {
+			hasDependents[index[dep]] = true
+		}
+	}
+
+	type completion struct {
+		idx    int
+		result parallelResult
+	}
+	results := make([]parallelResult, len(epics))
+	states := make([]int, len(epics))
+	done := make(chan completion)
+	active := 0
+
+	for {
+		// Propagate failures downstream until nothing changes.
+		for changed := true; changed; {
+			changed = false
+			for i, e := range epics {
+				if states[i] != statePending {
+					continue
+				}
+				for _, dep := range e.DependsOn {
+					d := index[dep]
+					if states[d] == stateFinished && !results[d].Success {
+						now := time.Now()
+						results[i] = parallelResult{
+							Epic:       e,
+							Skipped:    true,
+							Error:      fmt.Errorf("skipped: prerequisite %s did not succeed", dep),
+							StartedAt:  now,
+							FinishedAt: now,
+						}
+						states[i] = stateFinished
+						changed = true
+						break
+					}
+				}
+			}
+		}
+
+		prepareFailed := false
+		for i, e := range epics {
+			if states[i] != statePending || (maxConcurrency > 0 && active >= maxConcurrency) {
+				continue
+			}
+			ready := true
+			for _, dep := range e.DependsOn {
+				if d := index[dep]; states[d] != stateFinished || !results[d].Success {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				continue
+			}
+			if hooks.prepare != nil {
+				if err := hooks.prepare(i); err != nil {
+					now := time.Now()
+					results[i] = parallelResult{Epic: e, Error: err, StartedAt: now, FinishedAt: now}
+					states[i] = stateFinished
+					prepareFailed = true
+					continue
+				}
+			}
+			states[i] = stateRunning
+			active++
+			go func(idx int) {
+				start := time.Now()
+				result := hooks.run(idx)
+				result.StartedAt = start
+				result.FinishedAt = time.Now()
+				result.Duration = result.FinishedAt.Sub(start)
+				done <- completion{idx: idx, result: result}
+			}(i)
+		}
+		if prepareFailed {
+			continue // re-evaluate skips before waiting
+		}
+		if active == 0 {
+			break
+		}
+
+		c := <-done
+		active--
+		if c.result.Success && hooks.merge != nil && hasDependents[c.idx] {
+			if err := hooks.merge(c.idx); err != nil {
+				c.result.Success = false
+				c.result.Error = err
+			} else {
+				c.result.Merged = true
+			}
+		}
+		results[c.idx] = c.result
+		states[c.idx] = stateFinished
+	}
+	return results
+}
+
+// parallelReport is the final DAG report written to report.json and printed
+// with -o json.
+type parallelReport struct {
+	StartedAt       string               `json:"started_at"`
+	FinishedAt      string               `json:"finished_at"`
+	WallClockSecs   float64              `json:"wall_clock_seconds"`
+	MaxConcurrency  int                  `json:"max_concurrency"`
+	Levels          [][]string           `json:"levels"`
+	CriticalPath    []string             `json:"critical_path"`
+	CriticalPathSec float64              `json:"critical_path_seconds"`
+	Epics           []parallelReportEpic `json:"epics"`
+
+	Conflicts []parallelConflictPrediction `json:"conflicts,omitempty"` // from the planning pass
+}
+
+// parallelReportEpic is one epic's row in the parallel report.
+type parallelReportEpic struct {
+	Name         string   `json:"name"`
+	DependsOn    []string `json:"depends_on,omitempty"`
+	Status       string   `json:"status"` // succeeded, failed, skipped
+	Reason       string   `json:"reason,omitempty"`
+	Branch       string   `json:"branch,omitempty"`
+	CommitSHA    string   `json:"commit_sha,omitempty"`
+	StartOffset  float64  `json:"start_offset_seconds"`
+	DurationSecs float64  `json:"duration_seconds"`
+	LogFile      string   `json:"log_file,omitempty"`
+}
+
+// parallelResultStatus classifies a result for reporting.
+func parallelResultStatus(r parallelResult) string {
+	switch {
+	case r.Skipped:
+		return "skipped"
+	case r.Success:
+		return "succeeded"
+	default:
+		return "failed"
+	}
+}
+
+// buildParallelReport summarizes a finished schedule.
+func buildParallelReport(epics []parallelEpic, results []parallelResult, maxConcurrency int, start, end time.Time) parallelReport {
+	report := parallelReport{
+		StartedAt:      start.Format(time.RFC3339),
+		FinishedAt:     end.Format(time.RFC3339),
+		WallClockSecs:  end.Sub(start).Seconds(),
+		MaxConcurrency: maxConcurrency,
+	}
+	levels, _ := parallelTopoOrder(epics)
+	for _, level := range levels {
+		names := make([]string, 0, len(level))
+		for _, i := range level {
+			names = append(names, epics[i].Name)
+		}
+		report.Levels = append(report.Levels, names)
+	}
+	path, total := parallelCriticalPath(epics, results)
+	for _, i := range path {
+		report.CriticalPath = append(report.CriticalPath, epics[i].Name)
+	}
+	report.CriticalPathSec = total.Seconds()
+
+	for i, e := range epics {
+		r := results[i]
+		row := parallelReportEpic{
+			Name:         e.Name,
+			DependsOn:    e.DependsOn,
+			Status:       parallelResultStatus(r),
+			Branch:       r.Branch,
+			CommitSHA:    r.CommitSHA,
+			DurationSecs: r.Duration.Seconds(),
+			LogFile:      r.LogFile,
+		}
+		if r.Error != nil {
+			row.Reason = r.Error.Error()
+		}
+		if !r.StartedAt.IsZero() {
+			row.StartOffset = r.StartedAt.Sub(start).Seconds()
+		}
+		report.Epics = append(report.Epics, row)
+	}
+	return report
+}
+
+// parallelCriticalPath returns the dependency chain with the largest summed
+// epic duration, and that sum. Skipped epics count as zero.
+func parallelCriticalPath(epics []parallelEpic, results []parallelResult) ([]int, time.Duration) {
+	levels, err := parallelTopoOrder(epics)
+	if err != nil || len(epics) == 0 {
+		return nil, 0
+	}
+	index := parallelEpicIndex(epics)
+	finish := make([]time.Duration, len(epics))
+	prev := make([]int, len(epics))
+	for _, level := range levels {
+		for _, i := range level {
+			prev[i] = -1
+			var longest time.Duration
+			for _, dep := range epics[i].DependsOn {
+				if d, ok := index[dep]; ok && (prev[i] == -1 || finish[d] > longest) {
+					longest, prev[i] = finish[d], d
+				}
+			}
+			finish[i] = longest + results[i].Duration
+		}
+	}
+	end := 0
+	for i := range finish {
+		if finish[i] >= finish[end] { // on ties prefer the later (deeper) epic
+			end = i
+		}
+	}
+	var path []int
+	for i := end; i != -1; i = prev[i] {
+		path = append(path, i)
+	}
+	slices.Reverse(path)
+	return path, finish[end]
+}
+
+// printParallelDAG prints epics level by level with their prerequisites.
+func printParallelDAG(w io.Writer, epics []parallelEpic) {
+	levels, err := parallelTopoOrder(epics)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "DAG (%d epics, %d levels):\n", len(epics), len(levels)) //nolint:errcheck // CLI output
+	for n, level := range levels {
+		for _, i := range level {
+			line := fmt.Sprintf("  L%d  %s", n, epics[i].Name)
+			if deps := epics[i].DependsOn; len(deps) > 0 {
+				line += "  ← " + strings.Join(deps, ", ")
+			}
+			fmt.Fprintln(w, line) //nolint:errcheck // CLI output
+		}
+	}
+}
+
+// printParallelReport prints the DAG, critical path and per-epic wall-clock.
+func printParallelReport(w io.Writer, epics []parallelEpic, report parallelReport) {
+	printParallelDAG(w, epics)
+	fmt.Fprintf(w, "\nCritical path: %s (%s)\n", strings.Join(report.CriticalPath, " → "), //nolint:errcheck // CLI output
+		(time.Duration(report.CriticalPathSec * float64(time.Second))).Round(time.Second))
+	fmt.Fprintf(w, "Wall-clock: %s\n\n", (time.Duration(report.WallClockSecs * float64(time.Second))).Round(time.Second)) //nolint:errcheck // CLI output
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "EPIC\tSTATUS\tSTART\tDURATION\tREASON") //nolint:errcheck // CLI output
+	for _, e := range report.Epics {
+		start, duration := "-", "-"
+		if e.Status != "skipped" {
+			start = "+" + (time.Duration(e.StartOffset * float64(time.Second))).Round(time.Second).String()
+			duration = (time.Duration(e.DurationSecs * float64(time.Second))).Round(time.Second).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.Name, e.Status, start, duration, e.Reason) //nolint:errcheck // CLI output
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(w) //nolint:errcheck // CLI output
+}
+
+// writeParallelReport persists the report as report.json in logDir.
+func writeParallelReport(logDir string, report parallelReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal parallel report: %w", err)
+	}
+	return os.WriteFile(filepath.Join(logDir, "report.json"), append(data, '\n'), 0o644)
+}
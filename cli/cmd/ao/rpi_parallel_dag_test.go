@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidateParallelDAG(t *testing.T) {
+	tests := []struct {
+		name    string
+		epics   []parallelEpic
+		wantErr string
+	}{
+		{"no deps", []parallelEpic{{Name: "a"}, {Name: "b"}}, ""},
+		{"chain", []parallelEpic{{Name: "a"}, {Name: "b", DependsOn: []string{"a"}}}, ""},
+		{"self", []parallelEpic{{Name: "a", DependsOn: []string{"a"}}}, "depends on itself"},
+		{"unknown", []parallelEpic{{Name: "a", DependsOn: []string{"zzz"}}}, "unknown epic"},
+		{"cycle", []parallelEpic{
+			{Name: "a", DependsOn: []string{"c"}},
+			{Name: "b", DependsOn: []string{"a"}},
+			{Name: "c", DependsOn: []string{"b"}},
+			{Name: "d"},
+		}, "cycle among epics: a, b, c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateParallelDAG(tt.epics)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParallelTopoOrder_Levels(t *testing.T) {
+	epics := []parallelEpic{
+		{Name: "docs", DependsOn: []string{"api", "ui"}},
+		{Name: "api"},
+		{Name: "ui", DependsOn: []string{"api"}},
+		{Name: "cli"},
+	}
+	levels, err := parallelTopoOrder(epics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]int{{1, 3}, {2}, {0}}; !reflect.DeepEqual(levels, want) {
+		t.Errorf("levels = %v, want %v", levels, want)
+	}
+}
+
+func TestResolveParallelEpics_ManifestCycle(t *testing.T) {
+	data, _ := json.Marshal(parallelManifestFile{Epics: []parallelEpic{
+		{Name: "a", Goal: "g", DependsOn: []string{"b"}},
+		{Name: "b", Goal: "g", DependsOn: []string{"a"}},
+	}})
+	manifestPath := filepath.Join(t.TempDir(), "epics.json")
+	if err := os.WriteFile(manifestPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	parallelManifest = manifestPath
+	defer func() { parallelManifest = "" }()
+
+	if _, err := resolveParallelEpics(nil); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestScheduleParallelDAG_OrderAndSkips(t *testing.T) {
+	epics := []parallelEpic{
+		{Name: "api"},
+		{Name: "ui", DependsOn: []string{"api"}},
+		{Name: "broken"},
+		{Name: "docs", DependsOn: []string{"ui", "broken"}},
+		{Name: "release", DependsOn: []string{"docs"}},
+	}
+	var mu sync.Mutex
+	var events []string
+	record := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, s)
+	}
+	results := scheduleParallelDAG(epics, 1, parallelDAGHooks{
+		prepare: func(idx int) error { record("prepare " + epics[idx].Name); return nil },
+		run: func(idx int) parallelResult {
+			record("run " + epics[idx].Name)
+			if epics[idx].Name == "broken" {
+				return parallelResult{Epic: epics[idx], Error: fmt.Errorf("boom")}
+			}
+			return parallelResult{Epic: epics[idx], Success: true}
+		},
+		merge: func(idx int) error { record("merge " + epics[idx].Name); return nil },
+	})
+
+	want := []string{"prepare api", "run api", "merge api", "prepare ui", "run ui", "merge ui", "prepare broken", "run broken"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v\nwant %v", events, want)
+	}
+	for _, name := range []string{"docs", "release"} {
+		r := results[parallelEpicIndex(epics)[name]]
+		if !r.Skipped || r.Success || r.Error == nil {
+			t.Errorf("%s should be skipped: %+v", name, r)
+		}
+	}
+	if !strings.Contains(results[3].Error.Error(), "prerequisite broken") {
+		t.Errorf("docs skip reason = %v", results[3].Error)
+	}
+	if !results[0].Merged || results[2].Merged {
+		t.Errorf("only prerequisites that succeeded should merge early: api=%v broken=%v", results[0].Merged, results[2].Merged)
+	}
+}
+
+func TestScheduleParallelDAG_MaxConcurrency(t *testing.T) {
+	epics := []parallelEpic{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}
+	var mu sync.Mutex
+	running, peak := 0, 0
+	scheduleParallelDAG(epics, 2, parallelDAGHooks{
+		run: func(idx int) parallelResult {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return parallelResult{Epic: epics[idx], Success: true}
+		},
+	})
+	if peak != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak)
+	}
+}
+
+func TestScheduleParallelDAG_MergeFailureSkipsDependents(t *testing.T) {
+	epics := []parallelEpic{{Name: "a"}, {Name: "b", DependsOn: []string{"a"}}}
+	results := scheduleParallelDAG(epics, 0, parallelDAGHooks{
+		run:   func(idx int) parallelResult { return parallelResult{Epic: epics[idx], Success: true} },
+		merge: func(int) error { return fmt.Errorf("conflict") },
+	})
+	if results[0].Success || results[0].Error == nil {
+		t.Errorf("merge failure should fail the prerequisite: %+v", results[0])
+	}
+	if !results[1].Skipped {
+		t.Errorf("dependent should be skipped: %+v", results[1])
+	}
+}
+
+func TestParallelCriticalPathAndReport(t *testing.T) {
+	epics := []parallelEpic{
+		{Name: "api"},
+		{Name: "cli"},
+		{Name: "ui", DependsOn: []string{"api"}},
+		{Name: "docs", DependsOn: []string{"ui", "cli"}},
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := []parallelResult{
+		{Epic: epics[0], Success: true, StartedAt: start, Duration: 10 * time.Minute},
+		{Epic: epics[1], Success: true, StartedAt: start, Duration: 25 * time.Minute},
+		{Epic: epics[2], Success: true, StartedAt: start.Add(10 * time.Minute), Duration: 20 * time.Minute},
+		{Epic: epics[3], Skipped: true, Error: fmt.Errorf("skipped: prerequisite ui did not succeed")},
+	}
+	path, total := parallelCriticalPath(epics, results)
+	if !reflect.DeepEqual(path, []int{0, 2, 3}) || total != 30*time.Minute {
+		t.Errorf("critical path = %v (%s), want [0 2 3] (30m)", path, total)
+	}
+
+	report := buildParallelReport(epics, results, 2, start, start.Add(31*time.Minute))
+	if !reflect.DeepEqual(report.CriticalPath, []string{"api", "ui", "docs"}) {
+		t.Errorf("report critical path = %v", report.CriticalPath)
+	}
+	if !reflect.DeepEqual(report.Levels, [][]string{{"api", "cli"}, {"ui"}, {"docs"}}) {
+		t.Errorf("levels = %v", report.Levels)
+	}
+	if report.Epics[2].StartOffset != 600 || report.Epics[3].Status != "skipped" {
+		t.Errorf("epics = %+v", report.Epics)
+	}
+
+	var buf bytes.Buffer
+	printParallelReport(&buf, epics, report)
+	out := buf.String()
+	for _, want := range []string{"L2  docs  ← ui, cli", "Critical path: api → ui → docs (30m0s)", "Wall-clock: 31m0s", "ui    succeeded  +10m0s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+
+	logDir := t.TempDir()
+	if err := writeParallelReport(logDir, report); err != nil {
+		t.Fatal(err)
+	}
+	var decoded parallelReport
+	data, err := os.ReadFile(filepath.Join(logDir, "report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.CriticalPathSec != 1800 {
+		t.Errorf("report.json = %s (%v)", data, err)
+	}
+}
+
+func TestEmitParallelReport_JSONIsOnlyStdout(t *testing.T) {
+	origOutput, origStdout := output, os.Stdout
+	output = "json"
+	t.Cleanup(func() { output, os.Stdout = origOutput, origStdout })
+
+	reportOut, restore := redirectParallelProgress()
+	if reportOut != origStdout || os.Stdout != os.Stderr {
+		t.Fatal("-o json should send progress to stderr and keep stdout for the report")
+	}
+	restore()
+	if os.Stdout != origStdout {
+		t.Fatal("restore should put stdout back")
+	}
+
+	epics := []parallelEpic{{Name: "api"}}
+	results := []parallelResult{{Epic: epics[0], Success: true, StartedAt: time.Now(), Duration: time.Minute}}
+	var buf bytes.Buffer
+	if err := emitParallelReport(&buf, epics, results, nil, t.TempDir(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var decoded parallelReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded.Epics) != 1 {
+		t.Errorf("report output is not a single JSON document: %v\n%s", err, buf.String())
+	}
+}
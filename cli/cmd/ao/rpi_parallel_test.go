@@ -400,7 +400,7 @@ func TestMergeParallelWorktrees_SkipsFailedEpics(t *testing.T) {
 	}
 	defer func() { _ = os.Chdir(origDir) }()
 
-	mergedCount, err := mergeParallelWorktrees(epics, results, worktrees)
+	mergedCount, err := mergeParallelWorktrees(epics, results, worktrees, nil)
 	if err != nil {
 		t.Fatalf("mergeParallelWorktrees: %v", err)
 	}
@@ -453,7 +453,7 @@ func TestReportParallelResults_SomeFailed(t *testing.T) {
 func TestSpawnParallelEpics_ArgumentConstruction(t *testing.T) {
 	// spawnParallelEpics uses goroutines internally. We verify it returns
 	// results of the right length even with an empty epic list.
-	results := spawnParallelEpics(nil, nil, "echo", t.TempDir(), "", "", 1)
+	results := spawnParallelEpics(t.TempDir(), nil, nil, "echo", t.TempDir(), "", "", 1, nil)
 	if len(results) != 0 {
 		t.Errorf("expected 0 results for nil epics, got %d", len(results))
 	}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -38,6 +39,11 @@ var (
 	phasedRuntimeMode          string
 	phasedRuntimeCommand       string
 	phasedTmuxWorkers          int
+	phasedSandboxRuntime       string
+	phasedSandboxImage         string
+	phasedSandboxNetwork       string
+	phasedSandboxCPUs          string
+	phasedSandboxMemory        string
 	phasedNoBudget             bool
 	phasedBudgetSpec           string
 	phasedNoDashboard          bool
@@ -72,12 +78,24 @@ Between phases, the CLI reads filesystem artifacts, constructs prompts
 via templates, and spawns the next session. Retry loops for gate failures
 are handled within the session (discovery) or across sessions (validation).
 
+With --runtime sandbox, each phase session runs inside podman, docker, or
+bubblewrap (whichever is installed) with only the worktree mounted writable,
+the --sandbox-network policy applied, and optional CPU/memory limits.
+Container runtimes need --sandbox-image with the runtime command installed.
+In a linked worktree the repository's shared git directory is mounted too,
+read-only except for the objects, refs and logs a commit writes; hooks and
+config stay read-only. Network egress uses the container's default network;
+bubblewrap cannot filter traffic, so with bwrap any policy other than none
+shares the host network. Containers are named ao-rpi-<run>-p<phase>-a<n> and
+stopped by name when a phase times out or is cancelled.
+
 Examples:
   ao rpi phased "add user authentication"       # full lifecycle (3 sessions)
   ao rpi phased --from=implementation "add auth" # skip to crank (needs epic)
   ao rpi phased --from=validation                # just vibe + post-mortem
   ao rpi phased --dry-run "add auth"             # show prompts without spawning
-  ao rpi phased --fast-path "fix typo"           # force --quick for gates`,
+  ao rpi phased --fast-path "fix typo"           # force --quick for gates
+  ao rpi phased --runtime sandbox --sandbox-image ghcr.io/acme/claude --sandbox-network none "add auth"`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: runRPIPhased,
 	}
@@ -98,9 +116,14 @@ Examples:
 	phasedCmd.Flags().BoolVar(&phasedSwarmFirst, "swarm-first", true, "Default each phase to swarm/agent-team execution; fall back to direct execution if swarm runtime is unavailable")
 	phasedCmd.Flags().BoolVar(&phasedAutoCleanStale, "auto-clean-stale", false, "Run stale-run cleanup before starting phased execution")
 	phasedCmd.Flags().DurationVar(&phasedAutoCleanStaleAfter, "auto-clean-stale-after", 24*time.Hour, "Only clean stale runs older than this age when auto-clean is enabled")
-	phasedCmd.Flags().StringVar(&phasedRuntimeMode, "runtime", "auto", "Phase runtime mode: auto|direct|stream|tmux")
+	phasedCmd.Flags().StringVar(&phasedRuntimeMode, "runtime", "auto", "Phase runtime mode: auto|direct|stream|tmux|sandbox")
 	phasedCmd.Flags().StringVar(&phasedRuntimeCommand, "runtime-cmd", "claude", "Runtime command used for phase prompts (Claude uses '-p'; Codex uses 'exec')")
 	phasedCmd.Flags().IntVar(&phasedTmuxWorkers, "tmux-workers", 1, "When --runtime tmux, number of worker sessions spawned per phase")
+	phasedCmd.Flags().StringVar(&phasedSandboxRuntime, "sandbox-runtime", sandboxRuntimeAuto, "When --runtime sandbox, isolation runtime: auto|podman|docker|bwrap (auto prefers podman, docker, then bwrap)")
+	phasedCmd.Flags().StringVar(&phasedSandboxImage, "sandbox-image", "", "When --runtime sandbox, container image that provides the runtime command (required for podman/docker)")
+	phasedCmd.Flags().StringVar(&phasedSandboxNetwork, "sandbox-network", sandboxNetworkEgress, "When --runtime sandbox, network policy: none|egress|host (with bwrap, egress is the host network)")
+	phasedCmd.Flags().StringVar(&phasedSandboxCPUs, "sandbox-cpus", "", "When --runtime sandbox, CPU limit per phase (e.g. 2 or 0.5)")
+	phasedCmd.Flags().StringVar(&phasedSandboxMemory, "sandbox-memory", "", "When --runtime sandbox, memory limit per phase (e.g. 512m or 4g)")
 	phasedCmd.Flags().BoolVar(&phasedNoDashboard, "no-dashboard", false, "Disable auto-opening the web dashboard")
 
 	rpiCmd.AddCommand(phasedCmd)
@@ -141,6 +164,11 @@ func runRPIPhased(cmd *cobra.Command, args []string) error {
 		RuntimeMode:          phasedRuntimeMode,
 		RuntimeCommand:       phasedRuntimeCommand,
 		TmuxWorkers:          phasedTmuxWorkers,
+		SandboxRuntime:       phasedSandboxRuntime,
+		SandboxImage:         phasedSandboxImage,
+		SandboxNetwork:       phasedSandboxNetwork,
+		SandboxCPUs:          phasedSandboxCPUs,
+		SandboxMemory:        phasedSandboxMemory,
 		NoBudget:             phasedNoBudget,
 		BudgetSpec:           phasedBudgetSpec,
 		NoDashboard:          phasedNoDashboard,
@@ -231,6 +259,15 @@ func preflightOpts(opts *phasedEngineOptions) error {
 			return fmt.Errorf("tmux executable %q not found on PATH (required for runtime=tmux)", opts.TmuxCommand)
 		}
 	}
+	if opts.RuntimeMode == "sandbox" {
+		plan, err := newSandboxPlan(sandboxConfigFromOptions(*opts))
+		if err != nil {
+			return err
+		}
+		if plan.isContainer() {
+			return nil // the runtime command comes from the image, not the host PATH
+		}
+	}
 	return preflightRuntimeAvailability(opts.RuntimeCommand)
 }
 
@@ -265,6 +302,9 @@ func initExecutorAndPersist(spawnCwd, logPath, statusPath string, allPhases []Ph
 // initPhasedState resolves the goal and start phase from args, creates the
 // phasedState, applies complexity fast-path, and resumes from prior state if needed.
 func initPhasedState(cwd string, opts phasedEngineOptions, args []string) (*phasedState, int, string, error) {
+	if opts.Resume != nil {
+		return restorePhasedState(opts)
+	}
 	goal, startPhase, err := resolveGoalAndStartPhase(opts, args, cwd)
 	if err != nil {
 		return nil, 0, "", err
@@ -345,6 +385,11 @@ func runRPIPhasedWithOpts(ctx context.Context, opts phasedEngineOptions, args []
 	}); evErr != nil {
 		VerbosePrintf("Warning: could not emit run.started event: %v\n", evErr)
 	}
+	if opts.Resume != nil {
+		emitRunResumed(spawnCwd, logPath, state, opts.Resume)
+	} else {
+		recordRunCheckpoint(spawnCwd, state, startPhase, "run started")
+	}
 
 	// Start embedded dashboard server (unless --no-dashboard, --dry-run, or pipe).
 	var dashSrv *http.Server
@@ -364,9 +409,15 @@ func runRPIPhasedWithOpts(ctx context.Context, opts phasedEngineOptions, args []
 	}
 
 	runStart := time.Now()
+	state.control = newRPIRunController(spawnCwd, state, logPath)
 
 	if err := runPhaseLoopWithBudgets(ctx, cwd, spawnCwd, state, startPhase, opts, statusPath, allPhases, logPath); err != nil {
-		saveTerminalState(spawnCwd, state, "failed", err.Error())
+		status := "failed"
+		var abortErr *rpiAbortError
+		if errors.As(err, &abortErr) {
+			status = "aborted"
+		}
+		saveTerminalState(spawnCwd, state, status, err.Error())
 		emitRunCompleted(spawnCwd, state, runStart)
 		if proofErr := updateExecutionPacketProof(spawnCwd, state); proofErr != nil {
 			VerbosePrintf("Warning: could not refresh failed-run proof artifact set: %v\n", proofErr)
@@ -474,6 +525,14 @@ func handleBudgetTimeout(spawnCwd string, state *phasedState, p phase, budget ti
 func runPhaseLoopWithBudgets(ctx context.Context, cwd, spawnCwd string, state *phasedState, startPhase int, opts phasedEngineOptions, statusPath string, allPhases []PhaseProgress, logPath string) error {
 	for i := startPhase; i <= len(phases); i++ {
 		p := phases[i-1]
+		// Safe point: honor pause/abort, and skip-phase for the phase about to start.
+		if err := state.control.await(ctx, state, p.Num); err != nil {
+			return err
+		}
+		if cmd, ok := state.control.takeSkip(state, p.Num); ok {
+			skipPhaseByCommand(spawnCwd, state, p, logPath, cmd)
+			continue
+		}
 		if p.Num == 3 && state.FastPath && state.Complexity == ComplexityFast {
 			fmt.Printf("\n--- Phase 3: validation (skipped — complexity: fast) ---\n")
 			logPhaseTransition(logPath, state.RunID, "validation", "skipped — complexity: fast")
@@ -4,6 +4,7 @@ import (
 	"cmp"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -39,6 +40,11 @@ type phasedEngineOptions struct {
 	BDCommand            string
 	TmuxCommand          string
 	TmuxWorkers          int
+	SandboxRuntime       string // runtime=sandbox: auto|podman|docker|bwrap
+	SandboxImage         string // runtime=sandbox: container image providing the runtime
+	SandboxNetwork       string // runtime=sandbox: none|egress|host
+	SandboxCPUs          string // runtime=sandbox: CPU limit (empty = unlimited)
+	SandboxMemory        string // runtime=sandbox: memory limit (empty = unlimited)
 	NoBudget             bool
 	BudgetSpec           string
 	WorkingDir           string `json:"-"` // runtime-only; base directory for repo/worktree resolution
@@ -46,6 +52,7 @@ type phasedEngineOptions struct {
 	NoDashboard          bool
 	StdoutWriter         io.Writer             `json:"-"` // runtime-only; suppresses raw Claude output when dashboard active
 	OnSpawnCwdReady      func(spawnCwd string) `json:"-"` // called after worktree resolved; serve mode uses this to update mux root
+	Resume               *rpiResumePoint       `json:"-"` // runtime-only; set by ao rpi resume to continue a recorded run
 }
 
 // defaultPhasedEngineOptions returns options matching the default cobra flag values.
@@ -66,6 +73,8 @@ func defaultPhasedEngineOptions() phasedEngineOptions {
 		BDCommand:            "bd",
 		TmuxCommand:          "tmux",
 		TmuxWorkers:          1,
+		SandboxRuntime:       sandboxRuntimeAuto,
+		SandboxNetwork:       sandboxNetworkEgress,
 		NoBudget:             false,
 		BudgetSpec:           "",
 	}
@@ -107,10 +116,15 @@ type phasedState struct {
 	RunID           string              `json:"run_id,omitempty"`
 	OrchestratorPID int                 `json:"orchestrator_pid,omitempty"`
 	Backend         string              `json:"backend,omitempty"`
-	TerminalStatus  string              `json:"terminal_status,omitempty"` // interrupted, failed, stale, completed
+	TerminalStatus  string              `json:"terminal_status,omitempty"` // interrupted, failed, aborted, stale, completed
 	TerminalReason  string              `json:"terminal_reason,omitempty"`
 	TerminatedAt    string              `json:"terminated_at,omitempty"`
+	PausedAt        string              `json:"paused_at,omitempty"`   // set while a pause control command is in effect
+	LastCommit      string              `json:"last_commit,omitempty"` // HEAD of the run's checkout at the last checkpoint
 	Opts            phasedEngineOptions `json:"opts"`
+
+	control    *rpiRunController // runtime-only; nil when the run ignores control commands
+	resumeGate int               // runtime-only; phase whose session already completed on a resumed run
 }
 
 // retryContext holds context for retrying a failed gate.
@@ -275,10 +289,10 @@ func effectiveTmuxCommand(command string) string {
 
 func validateRuntimeMode(mode string) error {
 	switch normalizeRuntimeMode(mode) {
-	case "auto", "direct", "stream", "tmux":
+	case "auto", "direct", "stream", "tmux", "sandbox":
 		return nil
 	default:
-		return fmt.Errorf("invalid runtime %q (valid: auto|direct|stream|tmux)", mode)
+		return fmt.Errorf("invalid runtime %q (valid: auto|direct|stream|tmux|sandbox)", mode)
 	}
 }
 
@@ -320,6 +334,27 @@ func parsePhaseBudgetSpec(spec string) (map[int]time.Duration, error) {
 	return budgets, nil
 }
 
+// mergePhaseBudgetSpec overlays the budgets in update onto spec and returns
+// the combined spec using canonical phase names.
+func mergePhaseBudgetSpec(spec, update string) (string, error) {
+	budgets, err := parsePhaseBudgetSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	overrides, err := parsePhaseBudgetSpec(update)
+	if err != nil {
+		return "", err
+	}
+	maps.Copy(budgets, overrides)
+	var entries []string
+	for _, p := range phases {
+		if budget, ok := budgets[p.Num]; ok {
+			entries = append(entries, fmt.Sprintf("%s:%d", p.Name, int(budget.Seconds())))
+		}
+	}
+	return strings.Join(entries, ","), nil
+}
+
 // defaultPhaseBudgetForComplexity returns the default budget for a consolidated phase.
 // Implementation (phase 2) remains unbounded; crank has its own wave/backpressure limits.
 func defaultPhaseBudgetForComplexity(complexity ComplexityLevel, phaseNum int) time.Duration {
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// rpiControlPollInterval is how often a paused run re-reads its command log.
+// Package-level for testability.
+var rpiControlPollInterval = 2 * time.Second
+
+// rpiRetryGateGrace is how long a gate that would escalate waits for a
+// retry-gate command before the run fails. Package-level for testability.
+var rpiRetryGateGrace = 2 * time.Minute
+
+// rpiAbortError is returned by the phase loop when an abort command is honored.
+type rpiAbortError struct {
+	CommandID string
+	Reason    string
+}
+
+func (e *rpiAbortError) Error() string {
+	return "aborted by operator: " + e.Reason
+}
+
+// rpiRunController polls a run's commands.jsonl for control commands and
+// applies them at the safe points of the phased engine. A nil controller
+// ignores all commands, so engine helpers can be called without one.
+type rpiRunController struct {
+	root    string   // spawn cwd; acknowledgements are written (and mirrored) here
+	roots   []string // every root a command may have been appended under
+	runID   string
+	logPath string
+	seen    map[string]struct{}
+	pending []RPIC2Command // accepted commands not yet honored, in arrival order
+	paused  bool
+}
+
+// newRPIRunController creates the controller for a run. Commands that already
+// have an ack or rejected event (for example from before a restart) are
+// treated as consumed.
+func newRPIRunController(spawnCwd string, state *phasedState, logPath string) *rpiRunController {
+	c := &rpiRunController{
+		root:    spawnCwd,
+		roots:   artifactRootsForState(spawnCwd, state),
+		runID:   state.RunID,
+		logPath: logPath,
+		seen:    make(map[string]struct{}),
+	}
+	events, err := loadRPIC2Events(spawnCwd, state.RunID)
+	if err != nil {
+		VerbosePrintf("Warning: could not read events for control commands: %v\n", err)
+	}
+	for _, ev := range events {
+		if ev.CommandID != "" && strings.HasPrefix(ev.Type, "command.") &&
+			(strings.HasSuffix(ev.Type, ".ack") || strings.HasSuffix(ev.Type, ".rejected")) {
+			c.seen[ev.CommandID] = struct{}{}
+		}
+	}
+	return c
+}
+
+// rpiControlRequestFromCommand maps a queued command back to its request.
+func rpiControlRequestFromCommand(cmd RPIC2Command) rpiControlRequest {
+	req := rpiControlRequest{RunID: cmd.RunID, Kind: cmd.Kind, Phase: cmd.Phase}
+	if cmd.Kind == rpiControlSetBudget {
+		req.Budget = cmd.Message
+	} else {
+		req.Reason = cmd.Message
+	}
+	return req
+}
+
+// sync reads new control commands. set-budget is applied immediately; the
+// rest are queued until the engine reaches a point where they can be honored.
+func (c *rpiRunController) sync(state *phasedState) {
+	for _, root := range c.roots {
+		commands, err := loadRPIC2Commands(root, c.runID)
+		if err != nil {
+			VerbosePrintf("Warning: could not read control commands from %s: %v\n", root, err)
+			continue
+		}
+		for _, cmd := range commands {
+			if !slices.Contains(rpiControlKinds, cmd.Kind) {
+				continue // nudges and other commands are handled elsewhere
+			}
+			if _, ok := c.seen[cmd.CommandID]; ok {
+				continue
+			}
+			c.seen[cmd.CommandID] = struct{}{}
+			if err := validateRPIControlRequest(rpiControlRequestFromCommand(cmd)); err != nil {
+				c.reject(state, cmd, err.Error())
+				continue
+			}
+			if cmd.Kind == rpiControlSetBudget {
+				c.applyBudget(state, cmd)
+				continue
+			}
+			c.pending = append(c.pending, cmd)
+		}
+	}
+}
+
+// take removes and returns the first pending command of kind accepted by match.
+func (c *rpiRunController) take(kind string, match func(RPIC2Command) bool) (RPIC2Command, bool) {
+	for i, cmd := range c.pending {
+		if cmd.Kind == kind && (match == nil || match(cmd)) {
+			c.pending = slices.Delete(c.pending, i, i+1)
+			return cmd, true
+		}
+	}
+	return RPIC2Command{}, false
+}
+
+// await is the safe-point check. It returns an *rpiAbortError when an abort
+// is pending, and blocks while the run is paused, persisting the paused state
+// and keeping the heartbeat fresh until a resume or abort arrives.
+func (c *rpiRunController) await(ctx context.Context, state *phasedState, phaseNum int) error {
+	if c == nil {
+		return nil
+	}
+	for {
+		c.sync(state)
+		if cmd, ok := c.take(rpiControlAbort, nil); ok {
+			c.ack(state, cmd, phaseNum, "run aborted: "+cmd.Message)
+			logPhaseTransition(c.logPath, c.runID, "control", fmt.Sprintf("ABORT command=%s reason=%q", cmd.CommandID, cmd.Message))
+			return &rpiAbortError{CommandID: cmd.CommandID, Reason: cmd.Message}
+		}
+		if !c.paused {
+			if cmd, ok := c.take(rpiControlPause, nil); ok {
+				c.setPaused(state, true)
+				c.ack(state, cmd, phaseNum, fmt.Sprintf("run paused before phase %d", phaseNum))
+				fmt.Printf("Run paused by control command %s; waiting for resume or abort\n", cmd.CommandID)
+			}
+		}
+		if c.paused {
+			if cmd, ok := c.take(rpiControlResume, nil); ok {
+				c.setPaused(state, false)
+				c.ack(state, cmd, phaseNum, "run resumed")
+				fmt.Printf("Run resumed by control command %s\n", cmd.CommandID)
+			}
+		}
+		if !c.paused {
+			for {
+				cmd, ok := c.take(rpiControlResume, nil)
+				if !ok {
+					break
+				}
+				c.reject(state, cmd, "run is not paused")
+			}
+			return nil
+		}
+
+		updateRunHeartbeat(c.root, c.runID)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rpiControlPollInterval):
+		}
+	}
+}
+
+// takeSkip returns a pending skip-phase command for phaseNum, which is about
+// to start. Commands for phases that already started are rejected.
+func (c *rpiRunController) takeSkip(state *phasedState, phaseNum int) (RPIC2Command, bool) {
+	if c == nil {
+		return RPIC2Command{}, false
+	}
+	for {
+		cmd, ok := c.take(rpiControlSkipPhase, func(cmd RPIC2Command) bool { return cmd.Phase != 0 && cmd.Phase < phaseNum })
+		if !ok {
+			break
+		}
+		c.reject(state, cmd, fmt.Sprintf("phase %d already started", cmd.Phase))
+	}
+	return c.take(rpiControlSkipPhase, func(cmd RPIC2Command) bool { return cmd.Phase == 0 || cmd.Phase == phaseNum })
+}
+
+// takeRetryGate returns a pending retry-gate command for phaseNum, granting
+// one more gate retry in place of an escalation.
+func (c *rpiRunController) takeRetryGate(state *phasedState, phaseNum int) (RPIC2Command, bool) {
+	if c == nil {
+		return RPIC2Command{}, false
+	}
+	c.sync(state)
+	return c.take(rpiControlRetryGate, func(cmd RPIC2Command) bool { return cmd.Phase == 0 || cmd.Phase == phaseNum })
+}
+
+// awaitRetryGate waits up to rpiRetryGateGrace for a retry-gate command for
+// phaseNum, honoring pause and abort while it waits and keeping the heartbeat
+// fresh. A nil controller returns immediately without a command.
+func (c *rpiRunController) awaitRetryGate(ctx context.Context, state *phasedState, phaseNum int) (RPIC2Command, bool, error) {
+	if c == nil {
+		return RPIC2Command{}, false, nil
+	}
+	deadline := time.Now().Add(rpiRetryGateGrace)
+	for {
+		if err := c.await(ctx, state, phaseNum); err != nil {
+			return RPIC2Command{}, false, err
+		}
+		if cmd, ok := c.takeRetryGate(state, phaseNum); ok {
+			return cmd, true, nil
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return RPIC2Command{}, false, nil
+		}
+		wait = minPositiveDuration(wait, rpiControlPollInterval)
+		updateRunHeartbeat(c.root, c.runID)
+		select {
+		case <-ctx.Done():
+			return RPIC2Command{}, false, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// applyBudget merges a set-budget command into the run's budget spec.
+func (c *rpiRunController) applyBudget(state *phasedState, cmd RPIC2Command) {
+	merged, err := mergePhaseBudgetSpec(state.Opts.BudgetSpec, cmd.Message)
+	if err != nil {
+		c.reject(state, cmd, err.Error())
+		return
+	}
+	state.Opts.BudgetSpec = merged
+	state.Opts.NoBudget = false
+	if err := savePhasedState(c.root, state); err != nil {
+		VerbosePrintf("Warning: could not persist budget override: %v\n", err)
+	}
+	logPhaseTransition(c.logPath, c.runID, "control", fmt.Sprintf("budget set to %s by command %s", merged, cmd.CommandID))
+	c.ack(state, cmd, state.Phase, "budget set to "+merged+" (applies from the next phase)")
+}
+
+func (c *rpiRunController) setPaused(state *phasedState, paused bool) {
+	c.paused = paused
+	state.PausedAt = ""
+	if paused {
+		state.PausedAt = time.Now().Format(time.RFC3339)
+	}
+	if err := savePhasedState(c.root, state); err != nil {
+		VerbosePrintf("Warning: could not persist paused state: %v\n", err)
+	}
+	logPhaseTransition(c.logPath, c.runID, "control", fmt.Sprintf("paused=%v", paused))
+}
+
+// ack records that cmd was honored.
+func (c *rpiRunController) ack(state *phasedState, cmd RPIC2Command, phaseNum int, message string) {
+	c.emit(state, cmd, phaseNum, "ack", message)
+}
+
+// reject records that cmd could not be honored.
+func (c *rpiRunController) reject(state *phasedState, cmd RPIC2Command, reason string) {
+	fmt.Printf("Control command %s (%s) rejected: %s\n", cmd.CommandID, cmd.Kind, reason)
+	c.emit(state, cmd, state.Phase, "rejected", reason)
+}
+
+func (c *rpiRunController) emit(state *phasedState, cmd RPIC2Command, phaseNum int, status, message string) {
+	if _, err := appendRPIC2Event(c.root, rpiC2EventInput{
+		RunID:     c.runID,
+		CommandID: cmd.CommandID,
+		Phase:     phaseNum,
+		Backend:   state.Backend,
+		Source:    "rpi_control",
+		Type:      "command." + cmd.Kind + "." + status,
+		Message:   message,
+		Details:   map[string]any{"kind": cmd.Kind, "status": status},
+	}); err != nil {
+		VerbosePrintf("Warning: could not append %s %s event: %v\n", cmd.Kind, status, err)
+	}
+}
+
+// skipPhaseByCommand records a phase skipped by a skip-phase command, writing
+// a "skipped" phase result so later phases can proceed.
+func skipPhaseByCommand(spawnCwd string, state *phasedState, p phase, logPath string, cmd RPIC2Command) {
+	fmt.Printf("\n--- Phase %d: %s (skipped — control command %s) ---\n", p.Num, p.Name, cmd.CommandID)
+	logPhaseTransition(logPath, state.RunID, p.Name, "skipped by control command "+cmd.CommandID)
+	now := time.Now().Format(time.RFC3339)
+	if err := writePhaseResult(spawnCwd, &phaseResult{
+		SchemaVersion: 1,
+		RunID:         state.RunID,
+		Phase:         p.Num,
+		PhaseName:     p.Name,
+		Status:        "skipped",
+		StartedAt:     now,
+		CompletedAt:   now,
+	}); err != nil {
+		VerbosePrintf("Warning: could not write skipped phase result: %v\n", err)
+	}
+	state.control.ack(state, cmd, p.Num, fmt.Sprintf("phase %d (%s) skipped", p.Num, p.Name))
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newControlTestRun returns a run state rooted in a temp dir and a controller for it.
+func newControlTestRun(t *testing.T) (string, *phasedState, *rpiRunController) {
+	t.Helper()
+	root := t.TempDir()
+	state := newTestPhasedState().WithRunID("run-control").WithPhase(2)
+	logPath := filepath.Join(root, "phased-orchestration.log")
+	return root, state, newRPIRunController(root, state, logPath)
+}
+
+func queueControl(t *testing.T, root string, req rpiControlRequest) RPIC2Command {
+	t.Helper()
+	req.RunID = "run-control"
+	cmd, err := queueRPIControlCommand(root, req, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+// controlEventTypes returns the control ack/rejected event types keyed by command ID.
+func controlEventTypes(t *testing.T, root string) map[string]string {
+	t.Helper()
+	events, err := loadRPIC2Events(root, "run-control")
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := make(map[string]string)
+	for _, ev := range events {
+		if ev.Source == "rpi_control" {
+			types[ev.CommandID] = ev.Type
+		}
+	}
+	return types
+}
+
+func TestRPIRunController_NilIsNoop(t *testing.T) {
+	var c *rpiRunController
+	if err := c.await(context.Background(), newTestPhasedState(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.takeSkip(newTestPhasedState(), 1); ok {
+		t.Fatal("nil controller should not skip")
+	}
+	if _, ok := c.takeRetryGate(newTestPhasedState(), 1); ok {
+		t.Fatal("nil controller should not grant retries")
+	}
+	if _, ok, err := c.awaitRetryGate(context.Background(), newTestPhasedState(), 1); ok || err != nil {
+		t.Fatalf("nil controller awaitRetryGate = %v, %v", ok, err)
+	}
+}
+
+// shortenRetryGateWait makes awaitRetryGate poll quickly within grace.
+func shortenRetryGateWait(t *testing.T, grace time.Duration) {
+	t.Helper()
+	origPoll, origGrace := rpiControlPollInterval, rpiRetryGateGrace
+	rpiControlPollInterval = 10 * time.Millisecond
+	rpiRetryGateGrace = grace
+	t.Cleanup(func() { rpiControlPollInterval, rpiRetryGateGrace = origPoll, origGrace })
+}
+
+func TestRPIRunController_PauseResume(t *testing.T) {
+	orig := rpiControlPollInterval
+	rpiControlPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { rpiControlPollInterval = orig })
+
+	root, state, c := newControlTestRun(t)
+	pause := queueControl(t, root, rpiControlRequest{Kind: rpiControlPause})
+
+	done := make(chan error, 1)
+	go func() { done <- c.await(context.Background(), state, 2) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for controlEventTypes(t, root)[pause.CommandID] == "" {
+		if time.Now().After(deadline) {
+			t.Fatal("pause was not acknowledged")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("await returned while paused: %v", err)
+	default:
+	}
+	saved, err := loadPhasedState(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.PausedAt == "" {
+		t.Error("paused state should be persisted")
+	}
+
+	resume := queueControl(t, root, rpiControlRequest{Kind: rpiControlResume})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("await did not return after resume")
+	}
+	types := controlEventTypes(t, root)
+	if types[pause.CommandID] != "command.pause.ack" || types[resume.CommandID] != "command.resume.ack" {
+		t.Errorf("events = %v", types)
+	}
+	if state.PausedAt != "" {
+		t.Errorf("PausedAt should be cleared, got %q", state.PausedAt)
+	}
+}
+
+func TestRPIRunController_Abort(t *testing.T) {
+	root, state, c := newControlTestRun(t)
+	abort := queueControl(t, root, rpiControlRequest{Kind: rpiControlAbort, Reason: "wrong goal"})
+
+	err := c.await(context.Background(), state, 2)
+	var abortErr *rpiAbortError
+	if !errors.As(err, &abortErr) || abortErr.Reason != "wrong goal" {
+		t.Fatalf("err = %v, want abort", err)
+	}
+	if got := controlEventTypes(t, root)[abort.CommandID]; got != "command.abort.ack" {
+		t.Errorf("abort event = %q", got)
+	}
+}
+
+func TestRPIRunController_SkipPhase(t *testing.T) {
+	root, state, c := newControlTestRun(t)
+	stale := queueControl(t, root, rpiControlRequest{Kind: rpiControlSkipPhase, Phase: 1})
+	skip := queueControl(t, root, rpiControlRequest{Kind: rpiControlSkipPhase, Phase: 3})
+	resume := queueControl(t, root, rpiControlRequest{Kind: rpiControlResume})
+
+	if err := c.await(context.Background(), state, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.takeSkip(state, 2); ok {
+		t.Fatal("skip for phase 3 must not apply to phase 2")
+	}
+	cmd, ok := c.takeSkip(state, 3)
+	if !ok || cmd.CommandID != skip.CommandID {
+		t.Fatalf("takeSkip(3) = %+v, %v", cmd, ok)
+	}
+	types := controlEventTypes(t, root)
+	if types[stale.CommandID] != "command.skip-phase.rejected" {
+		t.Errorf("skip for a started phase should be rejected: %v", types)
+	}
+	if types[resume.CommandID] != "command.resume.rejected" {
+		t.Errorf("resume of a running run should be rejected: %v", types)
+	}
+}
+
+func TestRPIRunController_AwaitRetryGateDuringGrace(t *testing.T) {
+	shortenRetryGateWait(t, 5*time.Second)
+	root, state, c := newControlTestRun(t)
+
+	type result struct {
+		cmd RPIC2Command
+		ok  bool
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		cmd, ok, err := c.awaitRetryGate(context.Background(), state, 2)
+		done <- result{cmd, ok, err}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case r := <-done:
+		t.Fatalf("awaitRetryGate returned before a command arrived: %+v", r)
+	default:
+	}
+	retry := queueControl(t, root, rpiControlRequest{Kind: rpiControlRetryGate, Phase: 2})
+	select {
+	case r := <-done:
+		if r.err != nil || !r.ok || r.cmd.CommandID != retry.CommandID {
+			t.Fatalf("awaitRetryGate = %+v", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("retry-gate queued during the grace period was not picked up")
+	}
+}
+
+func TestRPIRunController_AwaitRetryGateExpires(t *testing.T) {
+	shortenRetryGateWait(t, 30*time.Millisecond)
+	root, state, c := newControlTestRun(t)
+	queueControl(t, root, rpiControlRequest{Kind: rpiControlRetryGate, Phase: 3})
+
+	if _, ok, err := c.awaitRetryGate(context.Background(), state, 2); ok || err != nil {
+		t.Fatalf("awaitRetryGate = %v, %v; want expiry without a command", ok, err)
+	}
+}
+
+func TestRPIRunController_AwaitRetryGateAbort(t *testing.T) {
+	shortenRetryGateWait(t, 5*time.Second)
+	root, state, c := newControlTestRun(t)
+	queueControl(t, root, rpiControlRequest{Kind: rpiControlAbort, Reason: "give up"})
+
+	_, ok, err := c.awaitRetryGate(context.Background(), state, 2)
+	var abortErr *rpiAbortError
+	if ok || !errors.As(err, &abortErr) {
+		t.Fatalf("awaitRetryGate = %v, %v; want abort", ok, err)
+	}
+}
+
+func TestRPIRunController_SetBudget(t *testing.T) {
+	root, state, c := newControlTestRun(t)
+	state.Opts.BudgetSpec = "discovery:300,validation:600"
+	state.Opts.NoBudget = true
+	budget := queueControl(t, root, rpiControlRequest{Kind: rpiControlSetBudget, Budget: "validate:900,implementation:1200"})
+
+	if err := c.await(context.Background(), state, 2); err != nil {
+		t.Fatal(err)
+	}
+	if want := "discovery:300,implementation:1200,validation:900"; state.Opts.BudgetSpec != want {
+		t.Errorf("BudgetSpec = %q, want %q", state.Opts.BudgetSpec, want)
+	}
+	if state.Opts.NoBudget {
+		t.Error("set-budget should re-enable budgets")
+	}
+	if got := controlEventTypes(t, root)[budget.CommandID]; got != "command.set-budget.ack" {
+		t.Errorf("set-budget event = %q", got)
+	}
+}
+
+func TestRPIRunController_SkipsAcknowledgedOnRestart(t *testing.T) {
+	root, state, c := newControlTestRun(t)
+	queueControl(t, root, rpiControlRequest{Kind: rpiControlAbort, Reason: "stop"})
+	if err := c.await(context.Background(), state, 2); err == nil {
+		t.Fatal("expected abort")
+	}
+
+	restarted := newRPIRunController(root, state, c.logPath)
+	if err := restarted.await(context.Background(), state, 2); err != nil {
+		t.Fatalf("acknowledged abort should not be replayed: %v", err)
+	}
+}
+
+func TestMergePhaseBudgetSpec(t *testing.T) {
+	got, err := mergePhaseBudgetSpec("", "research:60")
+	if err != nil || got != "discovery:60" {
+		t.Fatalf("merge into empty = %q, %v", got, err)
+	}
+	if _, err := mergePhaseBudgetSpec("discovery:60", "bogus:1"); err == nil {
+		t.Fatal("invalid update should fail")
+	}
+}
@@ -18,6 +18,10 @@ import (
 // policy overrides without capping normal retries.
 const maxGateRetryDepth = 5
 
+// gatePolicyDecisionEvent is the C2 event type recording a MemRL policy
+// decision for a failed gate.
+const gatePolicyDecisionEvent = "gate.policy.decision"
+
 // shouldForceEscalation returns true when the attempt count exceeds the hard ceiling.
 func shouldForceEscalation(attempt int) bool {
 	return attempt > maxGateRetryDepth
@@ -304,12 +308,12 @@ func classifyGateFailureClass(phaseNum int, gateErr *gateFailError) types.MemRLF
 	return classifyByVerdict(verdict)
 }
 
-func resolveGateRetryAction(state *phasedState, phaseNum int, gateErr *gateFailError, attempt int) (types.MemRLAction, types.MemRLPolicyDecision) {
+func resolveGateRetryAction(contract types.MemRLPolicyContract, state *phasedState, phaseNum int, gateErr *gateFailError, attempt int) (types.MemRLAction, types.MemRLPolicyDecision) {
 	mode := types.GetMemRLMode()
 	failureClass := classifyGateFailureClass(phaseNum, gateErr)
 	metadataPresent := gateErr != nil && strings.TrimSpace(gateErr.Verdict) != ""
 
-	decision := types.EvaluateDefaultMemRLPolicy(types.MemRLPolicyInput{
+	decision := types.EvaluateMemRLPolicy(contract, types.MemRLPolicyInput{
 		Mode:            mode,
 		FailureClass:    failureClass,
 		Attempt:         attempt,
@@ -330,6 +334,11 @@ func handleGateRetry(ctx context.Context, cwd string, state *phasedState, phaseN
 	phaseName := phases[phaseNum-1].Name
 	attemptKey := fmt.Sprintf("phase_%d", phaseNum)
 
+	// Safe point between gate attempts: honor pause/abort before deciding.
+	if err := state.control.await(ctx, state, phaseNum); err != nil {
+		return false, err
+	}
+
 	state.Attempts[attemptKey]++
 	attempt := state.Attempts[attemptKey]
 
@@ -342,11 +351,25 @@ func handleGateRetry(ctx context.Context, cwd string, state *phasedState, phaseN
 
 	maybeUpdateLiveStatus(state, statusPath, allPhases, phaseNum, "retrying after "+gateErr.Verdict, attempt, "")
 
-	action, decision := resolveGateRetryAction(state, phaseNum, gateErr, attempt)
+	action, decision := resolveGateRetryAction(gateMemRLContract(cwd), state, phaseNum, gateErr, attempt)
 	logGateRetryMemRL(logPath, state.RunID, phaseName, decision, action)
+	recordGatePolicyDecision(cwd, state, phaseNum, attempt, gateErr, decision, action)
 
 	if action == types.MemRLActionEscalate {
-		return performGateEscalation(state, phaseNum, attempt, gateErr, decision, action, phaseName, logPath, statusPath, allPhases)
+		if state.control != nil {
+			fmt.Printf("%s would escalate (attempt %d); waiting up to %s for a retry-gate command\n", phaseName, attempt, rpiRetryGateGrace)
+			maybeUpdateLiveStatus(state, statusPath, allPhases, phaseNum, "awaiting retry-gate before escalation", attempt, gateErr.Report)
+			logPhaseTransition(logPath, state.RunID, phaseName, fmt.Sprintf("awaiting retry-gate for up to %s before escalating", rpiRetryGateGrace))
+		}
+		cmd, ok, err := state.control.awaitRetryGate(ctx, state, phaseNum)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return performGateEscalation(state, phaseNum, attempt, gateErr, decision, action, phaseName, logPath, statusPath, allPhases)
+		}
+		state.control.ack(state, cmd, phaseNum, fmt.Sprintf("%s gate retry %d granted instead of escalation", phaseName, attempt))
+		logPhaseTransition(logPath, state.RunID, phaseName, "escalation overridden by retry-gate command "+cmd.CommandID)
 	}
 
 	fmt.Printf("%s: %s (attempt %d/%d) — retrying\n", phaseName, gateErr.Verdict, attempt, state.Opts.MaxRetries)
@@ -388,6 +411,7 @@ func handleGateRetry(ctx context.Context, cwd string, state *phasedState, phaseN
 	if err := executeWithStatus(ctx, executor, state, statusPath, allPhases, phaseNum, attempt, rerunPrompt, spawnCwd, "re-running phase", "rerun failed"); err != nil {
 		return false, fmt.Errorf("rerun failed: %w", err)
 	}
+	recordRunCheckpoint(spawnCwd, state, phaseNum, "gate retry")
 
 	return verifyGateAfterRetry(ctx, cwd, state, phaseNum, logPath, spawnCwd, statusPath, allPhases, executor, attempt)
 }
@@ -417,17 +441,53 @@ func logGateRetryMemRL(logPath, runID, phaseName string, decision types.MemRLPol
 		runID,
 		phaseName,
 		fmt.Sprintf(
-			"memrl policy mode=%s failure_class=%s attempt_bucket=%s policy_action=%s selected_action=%s rule=%s",
+			"memrl policy mode=%s failure_class=%s attempt_bucket=%s policy_action=%s selected_action=%s rule=%s contract=%s",
 			decision.Mode,
 			decision.FailureClass,
 			decision.AttemptBucket,
 			decision.Action,
 			action,
 			decision.RuleID,
+			decision.ContractHash,
 		),
 	)
 }
 
+// gatePolicyRecord is the details payload of a gate.policy.decision event:
+// the policy input and decision for one failed gate attempt, and the action
+// the orchestrator actually took. ao memrl replay re-evaluates these.
+type gatePolicyRecord struct {
+	PhaseName  string `json:"phase_name"`
+	Verdict    string `json:"verdict,omitempty"`
+	Attempt    int    `json:"attempt"`
+	MaxRetries int    `json:"max_retries,omitempty"`
+	types.MemRLPolicyDecision
+	SelectedAction types.MemRLAction `json:"selected_action"`
+}
+
+// recordGatePolicyDecision appends the policy decision for a gate retry to
+// the run's C2 events, if mode is not off.
+func recordGatePolicyDecision(cwd string, state *phasedState, phaseNum, attempt int, gateErr *gateFailError, decision types.MemRLPolicyDecision, action types.MemRLAction) {
+	if decision.Mode == types.MemRLModeOff {
+		return
+	}
+	phaseName := phases[phaseNum-1].Name
+	_, _ = appendRPIC2Event(cwd, rpiC2EventInput{
+		RunID:   state.RunID,
+		Phase:   phaseNum,
+		Type:    gatePolicyDecisionEvent,
+		Message: fmt.Sprintf("%s policy %s via %s (selected %s)", phaseName, decision.Action, decision.RuleID, action),
+		Details: gatePolicyRecord{
+			PhaseName:           phaseName,
+			Verdict:             gateErr.Verdict,
+			Attempt:             attempt,
+			MaxRetries:          state.Opts.MaxRetries,
+			MemRLPolicyDecision: decision,
+			SelectedAction:      action,
+		},
+	})
+}
+
 // performGateEscalation handles the escalation path when the retry action is escalate.
 // Returns (false, nil) to signal escalation without error (caller will handle reporting).
 func performGateEscalation(state *phasedState, phaseNum, attempt int, gateErr *gateFailError, decision types.MemRLPolicyDecision, action types.MemRLAction, phaseName, logPath, statusPath string, allPhases []PhaseProgress) (bool, error) {
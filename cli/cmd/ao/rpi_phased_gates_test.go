@@ -90,7 +90,7 @@ func TestResolveGateRetryAction_ModeOffUsesLegacy(t *testing.T) {
 	state := newTestPhasedState().WithMaxRetries(3)
 	gateErr := &gateFailError{Phase: 1, Verdict: "FAIL"}
 
-	action, _ := resolveGateRetryAction(state, 1, gateErr, 1)
+	action, _ := resolveGateRetryAction(types.DefaultMemRLPolicyContract(), state, 1, gateErr, 1)
 	// Legacy: attempt 1 < maxRetries 3 => retry
 	if action != types.MemRLActionRetry {
 		t.Errorf("action = %q, want %q", action, types.MemRLActionRetry)
@@ -102,7 +102,7 @@ func TestResolveGateRetryAction_ModeOffEscalatesAtMax(t *testing.T) {
 	state := newTestPhasedState().WithMaxRetries(2)
 	gateErr := &gateFailError{Phase: 1, Verdict: "FAIL"}
 
-	action, _ := resolveGateRetryAction(state, 1, gateErr, 2)
+	action, _ := resolveGateRetryAction(types.DefaultMemRLPolicyContract(), state, 1, gateErr, 2)
 	// Legacy: attempt 2 >= maxRetries 2 => escalate
 	if action != types.MemRLActionEscalate {
 		t.Errorf("action = %q, want %q", action, types.MemRLActionEscalate)
@@ -161,26 +161,35 @@ func runSinglePhase(ctx context.Context, cwd, spawnCwd string, state *phasedStat
 		VerbosePrintf("Warning: could not persist phase start state: %v\n", err)
 	}
 
-	prompt, err := buildPromptForPhase(spawnCwd, p.Num, state, nil)
-	if err != nil {
-		return fmt.Errorf("build prompt for phase %d: %w", p.Num, err)
-	}
+	if state.resumeGate == p.Num {
+		// Resumed run whose session already completed: go straight to the gate
+		// so retry counters continue instead of re-running the phase.
+		state.resumeGate = 0
+		fmt.Printf("Phase %d: session already completed — re-checking gate\n", p.Num)
+		logPhaseTransition(logPath, state.RunID, p.Name, "resumed at gate")
+	} else {
+		prompt, err := buildPromptForPhase(spawnCwd, p.Num, state, nil)
+		if err != nil {
+			return fmt.Errorf("build prompt for phase %d: %w", p.Num, err)
+		}
 
-	logPhaseTransition(logPath, state.RunID, p.Name, "started")
-	retryKey := fmt.Sprintf("phase_%d", p.Num)
-	maybeLiveStatus(opts, statusPath, allPhases, p.Num, "starting", state.Attempts[retryKey], "")
+		logPhaseTransition(logPath, state.RunID, p.Name, "started")
+		retryKey := fmt.Sprintf("phase_%d", p.Num)
+		maybeLiveStatus(opts, statusPath, allPhases, p.Num, "starting", state.Attempts[retryKey], "")
 
-	if handleDryRunPhase(cwd, state, startPhase, p, opts, prompt, logPath) {
-		return nil
-	}
+		if handleDryRunPhase(cwd, state, startPhase, p, opts, prompt, logPath) {
+			return nil
+		}
 
-	if err := executePhaseSession(ctx, spawnCwd, state, p, opts, statusPath, allPhases, logPath, prompt, executor); err != nil {
-		if !rescuePhaseOnTimeout(spawnCwd, p, err) {
-			return err
+		if err := executePhaseSession(ctx, spawnCwd, state, p, opts, statusPath, allPhases, logPath, prompt, executor); err != nil {
+			if !rescuePhaseOnTimeout(spawnCwd, p, err) {
+				return err
+			}
+			// Phase timed out but wrote its summary — treat as complete and fall through
+			// to post-phase gate so the orchestrator can validate and continue.
+			logPhaseTransition(logPath, state.RunID, p.Name, "timeout-rescued: summary artifact found, continuing")
 		}
-		// Phase timed out but wrote its summary — treat as complete and fall through
-		// to post-phase gate so the orchestrator can validate and continue.
-		logPhaseTransition(logPath, state.RunID, p.Name, "timeout-rescued: summary artifact found, continuing")
+		recordRunCheckpoint(spawnCwd, state, p.Num, "session completed")
 	}
 
 	if err := handlePostPhaseGate(ctx, spawnCwd, state, p, logPath, statusPath, allPhases, executor); err != nil {
@@ -222,6 +231,7 @@ func runSinglePhase(ctx context.Context, cwd, spawnCwd string, state *phasedStat
 		VerbosePrintf("Warning: could not refresh execution packet proof: %v\n", err)
 	}
 
+	recordRunCheckpoint(spawnCwd, state, p.Num, rpiCheckpointPhaseCompleted)
 	if err := savePhasedState(spawnCwd, state); err != nil {
 		VerbosePrintf("Warning: could not save state: %v\n", err)
 	}
@@ -236,6 +246,7 @@ func logAndFailPhase(state *phasedState, phaseName, logPath, spawnCwd string, er
 	state.TerminalStatus = "failed"
 	state.TerminalReason = fmt.Sprintf("phase %s: %v", phaseName, err)
 	state.TerminatedAt = time.Now().Format(time.RFC3339)
+	recordRunCheckpoint(spawnCwd, state, state.Phase, "phase failed")
 	if saveErr := savePhasedState(spawnCwd, state); saveErr != nil {
 		VerbosePrintf("Warning: could not persist terminal state: %v\n", saveErr)
 	}
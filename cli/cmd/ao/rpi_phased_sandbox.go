@@ -0,0 +1,486 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sandbox runtimes accepted by --sandbox-runtime.
+const (
+	sandboxRuntimeAuto   = "auto"
+	sandboxRuntimePodman = "podman"
+	sandboxRuntimeDocker = "docker"
+	sandboxRuntimeBwrap  = "bwrap"
+)
+
+// Sandbox network policies accepted by --sandbox-network.
+const (
+	sandboxNetworkNone   = "none"   // no network at all
+	sandboxNetworkEgress = "egress" // container default network; bwrap shares the host network
+	sandboxNetworkHost   = "host"   // share the host network namespace
+)
+
+// sandboxAutoOrder is the order runtimes are tried with --sandbox-runtime auto
+// when an image is configured. Without an image only bwrap can run.
+var sandboxAutoOrder = []string{sandboxRuntimePodman, sandboxRuntimeDocker, sandboxRuntimeBwrap}
+
+// sandboxEnvPrefixes lists the environment variables forwarded into a
+// container. Everything else in the host environment stays outside.
+var sandboxEnvPrefixes = []string{"ANTHROPIC_", "OPENAI_", "AGENTOPS_"}
+
+// sandboxGitWritable lists the directories of a linked worktree's shared git
+// directory that a commit writes to. The rest of it, notably hooks and
+// config, is mounted read-only so a phase cannot plant code that runs later
+// on the host.
+var sandboxGitWritable = []string{"objects", "refs", "logs"}
+
+// sandboxStopTimeout is how long a cancelled container gets to stop before
+// the runtime kills it; sandboxWaitDelay bounds how long the client process
+// may linger after that.
+const (
+	sandboxStopTimeout = 10 * time.Second
+	sandboxWaitDelay   = 30 * time.Second
+)
+
+// sandboxSystemDirs are mounted read-only by bwrap so the runtime can find
+// its interpreter and shared libraries. Missing directories are skipped.
+var sandboxSystemDirs = []string{"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64", "/etc", "/opt", "/nix"}
+
+// sandboxConfig is the user-facing sandbox policy for runtime=sandbox.
+type sandboxConfig struct {
+	Runtime string // auto|podman|docker|bwrap
+	Image   string // container image providing the runtime; required for podman/docker
+	Network string // none|egress|host
+	CPUs    string // CPU limit, e.g. "2" or "1.5"; empty means unlimited
+	Memory  string // memory limit, e.g. "4g" or "512m"; empty means unlimited
+}
+
+// sandboxConfigFromOptions extracts the sandbox policy from engine options,
+// filling in the defaults.
+func sandboxConfigFromOptions(opts phasedEngineOptions) sandboxConfig {
+	return sandboxConfig{
+		Runtime: cmp.Or(strings.ToLower(strings.TrimSpace(opts.SandboxRuntime)), sandboxRuntimeAuto),
+		Image:   strings.TrimSpace(opts.SandboxImage),
+		Network: cmp.Or(strings.ToLower(strings.TrimSpace(opts.SandboxNetwork)), sandboxNetworkEgress),
+		CPUs:    strings.TrimSpace(opts.SandboxCPUs),
+		Memory:  strings.TrimSpace(opts.SandboxMemory),
+	}
+}
+
+// sandboxPlan is a validated sandbox policy bound to an installed runtime.
+type sandboxPlan struct {
+	config        sandboxConfig
+	runtime       string // podman|docker|bwrap
+	binary        string // resolved path of the runtime binary
+	cpus          float64
+	memoryBytes   int64
+	containerName string // --name of the next container; set per launch
+}
+
+// newSandboxPlan validates config and resolves which sandbox runtime to use.
+func newSandboxPlan(config sandboxConfig) (*sandboxPlan, error) {
+	plan := &sandboxPlan{config: config}
+	switch config.Network {
+	case sandboxNetworkNone, sandboxNetworkEgress, sandboxNetworkHost:
+	default:
+		return nil, fmt.Errorf("invalid sandbox network %q (valid: none|egress|host)", config.Network)
+	}
+	var err error
+	if plan.cpus, err = parseSandboxCPUs(config.CPUs); err != nil {
+		return nil, err
+	}
+	if plan.memoryBytes, err = parseSandboxMemory(config.Memory); err != nil {
+		return nil, err
+	}
+	if plan.runtime, plan.binary, err = resolveSandboxRuntime(config); err != nil {
+		return nil, err
+	}
+	if plan.runtime == sandboxRuntimeBwrap && plan.hasLimits() {
+		if _, err := lookPath("systemd-run"); err != nil {
+			return nil, fmt.Errorf("sandbox CPU/memory limits with bwrap require systemd-run on PATH (or use --sandbox-image with podman/docker)")
+		}
+	}
+	return plan, nil
+}
+
+// resolveSandboxRuntime picks the sandbox runtime binary. An explicit runtime
+// must be installed; auto tries podman, docker, then bwrap when an image is
+// configured, and only bwrap otherwise.
+func resolveSandboxRuntime(config sandboxConfig) (string, string, error) {
+	candidates := []string{config.Runtime}
+	switch config.Runtime {
+	case sandboxRuntimeAuto:
+		candidates = []string{sandboxRuntimeBwrap}
+		if config.Image != "" {
+			candidates = sandboxAutoOrder
+		}
+	case sandboxRuntimePodman, sandboxRuntimeDocker:
+		if config.Image == "" {
+			return "", "", fmt.Errorf("--sandbox-image is required for sandbox runtime %s", config.Runtime)
+		}
+	case sandboxRuntimeBwrap:
+	default:
+		return "", "", fmt.Errorf("invalid sandbox runtime %q (valid: auto|podman|docker|bwrap)", config.Runtime)
+	}
+	for _, name := range candidates {
+		if path, err := lookPath(name); err == nil {
+			return name, path, nil
+		}
+	}
+	if config.Runtime == sandboxRuntimeAuto && config.Image == "" {
+		return "", "", fmt.Errorf("no sandbox runtime found: bwrap is not on PATH (set --sandbox-image to run phases in podman or docker)")
+	}
+	return "", "", fmt.Errorf("no sandbox runtime found on PATH (tried %s)", strings.Join(candidates, ", "))
+}
+
+// parseSandboxCPUs parses a CPU count such as "2" or "0.5". Empty means no limit.
+func parseSandboxCPUs(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	cpus, err := strconv.ParseFloat(s, 64)
+	if err != nil || cpus <= 0 {
+		return 0, fmt.Errorf("invalid sandbox CPU limit %q (want a positive number such as 2 or 0.5)", s)
+	}
+	return cpus, nil
+}
+
+// parseSandboxMemory parses a memory size with an optional k/m/g suffix
+// (base 1024) into bytes. Empty means no limit.
+func parseSandboxMemory(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	num := strings.TrimSuffix(strings.ToLower(s), "b")
+	multiplier := int64(1)
+	if n := len(num); n > 0 {
+		switch num[n-1] {
+		case 'k':
+			multiplier = 1 << 10
+		case 'm':
+			multiplier = 1 << 20
+		case 'g':
+			multiplier = 1 << 30
+		}
+		if multiplier > 1 {
+			num = num[:n-1]
+		}
+	}
+	value, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid sandbox memory limit %q (want a size such as 512m or 4g)", s)
+	}
+	return value * multiplier, nil
+}
+
+func (p *sandboxPlan) hasLimits() bool {
+	return p.cpus > 0 || p.memoryBytes > 0
+}
+
+func (p *sandboxPlan) isContainer() bool {
+	return p.runtime != sandboxRuntimeBwrap
+}
+
+// describe summarizes the plan for the phase.sandbox.prepared event.
+func (p *sandboxPlan) describe(cwd string) string {
+	parts := []string{"runtime=" + p.runtime}
+	if p.isContainer() {
+		parts = append(parts, "image="+p.config.Image)
+	}
+	parts = append(parts, "network="+p.config.Network)
+	if p.cpus > 0 {
+		parts = append(parts, "cpus="+strconv.FormatFloat(p.cpus, 'f', -1, 64))
+	}
+	if p.memoryBytes > 0 {
+		parts = append(parts, "memory="+strconv.FormatInt(p.memoryBytes, 10))
+	}
+	var mounts []string
+	for _, m := range sandboxMounts(cwd) {
+		mounts = append(mounts, m.String())
+	}
+	parts = append(parts, "mount="+strings.Join(mounts, ","))
+	return strings.Join(parts, " ")
+}
+
+// launch is the runtimeLauncher that runs executable inside the sandbox.
+func (p *sandboxPlan) launch(ctx context.Context, cwd, executable string, args []string) (*exec.Cmd, error) {
+	var argv []string
+	if p.isContainer() {
+		argv = p.containerArgs(cwd, executable, args, cleanEnvNoClaude())
+	} else {
+		hostExe, err := lookPath(executable)
+		if err != nil {
+			return nil, fmt.Errorf("runtime executable %q not found on PATH (required for sandbox runtime bwrap)", executable)
+		}
+		argv = p.bwrapArgs(cwd, hostExe, args)
+	}
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = cwd
+	cmd.Env = cleanEnvNoClaude()
+	if p.isContainer() && p.containerName != "" {
+		// Killing the podman/docker client would leave the container
+		// running, so cancellation stops the container by name and lets
+		// the client exit with it.
+		name := p.containerName
+		cmd.Cancel = func() error {
+			stop := exec.Command(p.binary, "stop", "-t", strconv.Itoa(int(sandboxStopTimeout.Seconds())), name)
+			if err := stop.Run(); err != nil {
+				VerbosePrintf("Warning: %s stop %s: %v\n", p.runtime, name, err)
+				return cmd.Process.Kill()
+			}
+			return nil
+		}
+	}
+	cmd.WaitDelay = sandboxWaitDelay
+	return cmd, nil
+}
+
+// sandboxContainerName is the container name for a phase session: run ID,
+// phase and attempt, so a stuck container can be found and stopped.
+func sandboxContainerName(runID string, phaseNum, attempt int) string {
+	safe := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, runID)
+	return fmt.Sprintf("ao-rpi-%s-p%d-a%d", cmp.Or(safe, "run"), phaseNum, attempt)
+}
+
+// containerArgs builds the podman/docker command line (including the binary)
+// that runs executable in the configured image with only the worktree mounted.
+// The container runs as the invoking user so files written to the worktree
+// keep their ownership.
+func (p *sandboxPlan) containerArgs(cwd, executable string, args, env []string) []string {
+	argv := []string{p.binary, "run", "--rm", "-i", "--init"}
+	if p.containerName != "" {
+		argv = append(argv, "--name", p.containerName)
+	}
+	if p.runtime == sandboxRuntimePodman {
+		argv = append(argv, "--userns=keep-id")
+	} else {
+		argv = append(argv, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+	switch p.config.Network {
+	case sandboxNetworkNone:
+		argv = append(argv, "--network", "none")
+	case sandboxNetworkHost:
+		argv = append(argv, "--network", "host")
+	}
+	if p.cpus > 0 {
+		argv = append(argv, "--cpus", strconv.FormatFloat(p.cpus, 'f', -1, 64))
+	}
+	if p.memoryBytes > 0 {
+		argv = append(argv, "--memory", strconv.FormatInt(p.memoryBytes, 10))
+	}
+	for _, m := range sandboxMounts(cwd) {
+		argv = append(argv, "-v", m.String())
+	}
+	argv = append(argv, "-w", cwd, "-e", "HOME=/tmp")
+	for _, name := range sandboxPassthroughEnv(env) {
+		argv = append(argv, "-e", name)
+	}
+	argv = append(argv, p.config.Image, executable)
+	return append(argv, args...)
+}
+
+// bwrapArgs builds the bubblewrap command line (including the binary) that
+// runs hostExe with the system directories read-only, an empty home and
+// /tmp, and only the worktree writable. Any network policy but none shares
+// the host network: bwrap cannot filter traffic. Limits are applied through a
+// transient systemd scope because bwrap has no cgroup support of its own.
+func (p *sandboxPlan) bwrapArgs(cwd, hostExe string, args []string) []string {
+	var argv []string
+	if p.hasLimits() {
+		argv = append(argv, "systemd-run", "--user", "--scope", "--quiet", "--collect")
+		if p.cpus > 0 {
+			argv = append(argv, "-p", fmt.Sprintf("CPUQuota=%d%%", int(p.cpus*100)))
+		}
+		if p.memoryBytes > 0 {
+			argv = append(argv, "-p", "MemoryMax="+strconv.FormatInt(p.memoryBytes, 10))
+		}
+		argv = append(argv, "--")
+	}
+	argv = append(argv, p.binary)
+	for _, dir := range sandboxSystemDirs {
+		argv = append(argv, "--ro-bind-try", dir, dir)
+	}
+	argv = append(argv, "--proc", "/proc", "--dev", "/dev", "--tmpfs", "/tmp")
+	if home, err := os.UserHomeDir(); err == nil {
+		argv = append(argv, "--tmpfs", home)
+	}
+	// The runtime may live outside the system directories (e.g. ~/.local/bin),
+	// so its directory and that of its symlink target are mounted read-only.
+	for _, dir := range sandboxExecutableDirs(hostExe) {
+		argv = append(argv, "--ro-bind", dir, dir)
+	}
+	for _, m := range sandboxMounts(cwd) {
+		bind := "--bind"
+		if m.readOnly {
+			bind = "--ro-bind"
+		}
+		argv = append(argv, bind, m.path, m.path)
+	}
+	argv = append(argv, "--chdir", cwd, "--unshare-all", "--die-with-parent")
+	if p.config.Network != sandboxNetworkNone {
+		// bwrap cannot restrict egress; any network policy shares the host network.
+		argv = append(argv, "--share-net")
+	}
+	argv = append(argv, "--", hostExe)
+	return append(argv, args...)
+}
+
+// sandboxExecutableDirs returns the directories of exe and its resolved
+// symlink target that are not already covered by sandboxSystemDirs.
+func sandboxExecutableDirs(exe string) []string {
+	var dirs []string
+	candidates := []string{filepath.Dir(exe)}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		candidates = append(candidates, filepath.Dir(resolved))
+	}
+	for _, dir := range candidates {
+		if sandboxUnderSystemDir(dir) || slices.Contains(dirs, dir) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+func sandboxUnderSystemDir(dir string) bool {
+	for _, sys := range sandboxSystemDirs {
+		if dir == sys || strings.HasPrefix(dir, sys+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sandboxMount is one host directory mounted at the same path in the sandbox.
+type sandboxMount struct {
+	path     string
+	readOnly bool
+}
+
+// String renders the mount as a podman/docker -v value.
+func (m sandboxMount) String() string {
+	if m.readOnly {
+		return m.path + ":" + m.path + ":ro"
+	}
+	return m.path + ":" + m.path
+}
+
+// sandboxMounts lists the mounts for a phase, in mount order: the worktree
+// and, for a linked git worktree, the shared git directory it records
+// commits in. That directory is read-only except for the worktree's own git
+// directory and the sandboxGitWritable parts a commit writes to.
+func sandboxMounts(cwd string) []sandboxMount {
+	mounts := []sandboxMount{{path: cwd}}
+	gitDir, common := linkedGitDirs(cwd)
+	if common == "" {
+		return mounts
+	}
+	mounts = append(mounts, sandboxMount{path: common, readOnly: true})
+	for _, name := range sandboxGitWritable {
+		dir := filepath.Join(common, name)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			mounts = append(mounts, sandboxMount{path: dir})
+		}
+	}
+	if gitDir != common {
+		mounts = append(mounts, sandboxMount{path: gitDir})
+	}
+	return mounts
+}
+
+// linkedGitDirs returns the git directory and git common directory of a
+// linked worktree at cwd when the common directory lies outside cwd, or ""
+// for both in a regular checkout.
+func linkedGitDirs(cwd string) (gitDir, common string) {
+	data, err := os.ReadFile(filepath.Join(cwd, ".git"))
+	if err != nil {
+		return "", "" // a .git directory (or none) needs no extra mount
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", ""
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(cwd, gitDir)
+	}
+	gitDir = filepath.Clean(gitDir)
+	common = gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitDir, common)
+		}
+	}
+	common = filepath.Clean(common)
+	if rel, err := filepath.Rel(cwd, common); err == nil && !strings.HasPrefix(rel, "..") {
+		return "", ""
+	}
+	return gitDir, common
+}
+
+// sandboxPassthroughEnv returns the names of env entries forwarded into a
+// container (values are taken from the client environment by podman/docker).
+func sandboxPassthroughEnv(env []string) []string {
+	var names []string
+	for _, entry := range env {
+		name, _, _ := strings.Cut(entry, "=")
+		for _, prefix := range sandboxEnvPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// sandboxExecutor implements PhaseExecutor by running the stream backend
+// inside a container (podman/docker) or a bubblewrap jail. Stream events,
+// fallback to direct execution, and exit codes are those of streamExecutor;
+// only the process launch differs.
+type sandboxExecutor struct {
+	config   sandboxConfig
+	stream   *streamExecutor
+	launches map[int]int // containers started per phase, for their names
+}
+
+func (s *sandboxExecutor) Name() string { return "sandbox" }
+
+func (s *sandboxExecutor) Execute(ctx context.Context, prompt, cwd, runID string, phaseNum int) error {
+	plan, err := newSandboxPlan(s.config)
+	if err != nil {
+		return err
+	}
+	if _, err := appendRPIC2Event(cwd, rpiC2EventInput{
+		RunID: runID, Phase: phaseNum, Backend: "sandbox", Source: "runtime_sandbox",
+		Type:    "phase.sandbox.prepared",
+		Message: fmt.Sprintf("phase %d sandbox: %s", phaseNum, plan.describe(cwd)),
+	}); err != nil {
+		VerbosePrintf("Warning: could not append sandbox event: %v\n", err)
+	}
+	stream := *s.stream
+	stream.launcher = func(ctx context.Context, cwd, executable string, args []string) (*exec.Cmd, error) {
+		if s.launches == nil {
+			s.launches = make(map[int]int)
+		}
+		s.launches[phaseNum]++
+		plan.containerName = sandboxContainerName(runID, phaseNum, s.launches[phaseNum])
+		return plan.launch(ctx, cwd, executable, args)
+	}
+	return stream.Execute(ctx, prompt, cwd, runID, phaseNum)
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubSandboxLookPath makes only the named binaries resolvable.
+func stubSandboxLookPath(t *testing.T, installed ...string) {
+	t.Helper()
+	orig := lookPath
+	t.Cleanup(func() { lookPath = orig })
+	lookPath = func(name string) (string, error) {
+		if slices.Contains(installed, name) {
+			return "/usr/bin/" + name, nil
+		}
+		return "", fmt.Errorf("%s: not found", name)
+	}
+}
+
+func TestResolveSandboxRuntime(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    sandboxConfig
+		installed []string
+		want      string
+		wantErr   string
+	}{
+		{"auto prefers podman", sandboxConfig{Runtime: "auto", Image: "img"}, []string{"podman", "docker", "bwrap"}, "podman", ""},
+		{"auto falls back to docker", sandboxConfig{Runtime: "auto", Image: "img"}, []string{"docker", "bwrap"}, "docker", ""},
+		{"auto without image uses bwrap", sandboxConfig{Runtime: "auto"}, []string{"podman", "bwrap"}, "bwrap", ""},
+		{"auto without image or bwrap", sandboxConfig{Runtime: "auto"}, []string{"podman"}, "", "--sandbox-image"},
+		{"explicit docker needs image", sandboxConfig{Runtime: "docker"}, []string{"docker"}, "", "--sandbox-image is required"},
+		{"explicit runtime missing", sandboxConfig{Runtime: "podman", Image: "img"}, []string{"docker"}, "", "tried podman"},
+		{"unknown runtime", sandboxConfig{Runtime: "lxc"}, nil, "", "invalid sandbox runtime"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubSandboxLookPath(t, tt.installed...)
+			got, _, err := resolveSandboxRuntime(tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("runtime = %q, err = %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSandboxPlan_Validation(t *testing.T) {
+	stubSandboxLookPath(t, "bwrap")
+	for _, config := range []sandboxConfig{
+		{Runtime: "auto", Network: "open"},
+		{Runtime: "auto", Network: "none", CPUs: "-1"},
+		{Runtime: "auto", Network: "none", Memory: "lots"},
+		{Runtime: "auto", Network: "none", Memory: "1g"}, // bwrap limits need systemd-run
+	} {
+		if _, err := newSandboxPlan(config); err == nil {
+			t.Errorf("newSandboxPlan(%+v) should fail", config)
+		}
+	}
+}
+
+func TestParseSandboxMemory(t *testing.T) {
+	for in, want := range map[string]int64{"": 0, "1024": 1024, "512m": 512 << 20, "4G": 4 << 30, "2gb": 2 << 30, "64k": 64 << 10} {
+		got, err := parseSandboxMemory(in)
+		if err != nil || got != want {
+			t.Errorf("parseSandboxMemory(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := parseSandboxMemory("0m"); err == nil {
+		t.Error("zero memory should be rejected")
+	}
+}
+
+func TestSandboxContainerArgs(t *testing.T) {
+	stubSandboxLookPath(t, "podman")
+	plan, err := newSandboxPlan(sandboxConfig{Runtime: "auto", Image: "ghcr.io/acme/claude", Network: "none", CPUs: "1.5", Memory: "2g"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt := t.TempDir()
+	env := []string{"ANTHROPIC_API_KEY=k", "AWS_SECRET_ACCESS_KEY=s", "AGENTOPS_RPI_RUNTIME=stream", "PATH=/bin"}
+	plan.containerName = sandboxContainerName("a1b2c3d4e5f6", 2, 1)
+	got := strings.Join(plan.containerArgs(wt, "claude", []string{"-p", "go"}, env), " ")
+	want := "/usr/bin/podman run --rm -i --init --name ao-rpi-a1b2c3d4e5f6-p2-a1 --userns=keep-id --network none --cpus 1.5 --memory 2147483648 " +
+		"-v " + wt + ":" + wt + " -w " + wt + " -e HOME=/tmp -e AGENTOPS_RPI_RUNTIME -e ANTHROPIC_API_KEY ghcr.io/acme/claude claude -p go"
+	if got != want {
+		t.Errorf("containerArgs:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestSandboxBwrapArgs(t *testing.T) {
+	stubSandboxLookPath(t, "bwrap")
+	wt := t.TempDir()
+	exe := filepath.Join(t.TempDir(), "claude")
+
+	plan, err := newSandboxPlan(sandboxConfig{Runtime: "auto", Network: "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(plan.bwrapArgs(wt, exe, []string{"-p", "go"}), " ")
+	for _, want := range []string{
+		"/usr/bin/bwrap --ro-bind-try /usr /usr",
+		"--ro-bind " + filepath.Dir(exe) + " " + filepath.Dir(exe),
+		"--bind " + wt + " " + wt + " --chdir " + wt + " --unshare-all --die-with-parent -- " + exe + " -p go",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("bwrap args missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "--share-net") {
+		t.Errorf("network=none must not share the network:\n%s", got)
+	}
+
+	stubSandboxLookPath(t, "bwrap", "systemd-run")
+	plan, err = newSandboxPlan(sandboxConfig{Runtime: "bwrap", Network: "egress", CPUs: "2", Memory: "1g"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = strings.Join(plan.bwrapArgs(wt, exe, nil), " ")
+	if !strings.HasPrefix(got, "systemd-run --user --scope --quiet --collect -p CPUQuota=200% -p MemoryMax=1073741824 -- /usr/bin/bwrap") {
+		t.Errorf("limits should wrap bwrap in a systemd scope:\n%s", got)
+	}
+	if !strings.Contains(got, "--share-net") {
+		t.Errorf("network=egress should share the network:\n%s", got)
+	}
+}
+
+func TestSandboxMounts_LinkedWorktree(t *testing.T) {
+	repo := t.TempDir()
+	gitDir := filepath.Join(repo, ".git", "worktrees", "wt")
+	if err := os.MkdirAll(gitDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "commondir"), []byte("../..\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wt := t.TempDir()
+	if err := os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+gitDir+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	common := filepath.Join(repo, ".git")
+	for _, dir := range []string{"objects", "refs", "hooks"} {
+		if err := os.MkdirAll(filepath.Join(common, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := sandboxMounts(wt)
+	want := []sandboxMount{
+		{path: wt},
+		{path: common, readOnly: true},
+		{path: filepath.Join(common, "objects")},
+		{path: filepath.Join(common, "refs")},
+		{path: gitDir},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("sandboxMounts = %v, want %v", got, want)
+	}
+	if got := sandboxMounts(repo); !slices.Equal(got, []sandboxMount{{path: repo}}) {
+		t.Errorf("regular checkout needs only itself, got %v", got)
+	}
+	if v := want[1].String(); v != common+":"+common+":ro" {
+		t.Errorf("read-only -v value = %q", v)
+	}
+}
+
+func TestSandboxLaunch_CancelStopsContainer(t *testing.T) {
+	dir := t.TempDir()
+	record := filepath.Join(dir, "calls")
+	runtime := filepath.Join(dir, "podman")
+	pid := filepath.Join(dir, "pid")
+	// "run" stands in for the container; "stop" ends it the way the real
+	// runtime would, which lets the client exit.
+	script := "#!/bin/sh\necho \"$@\" >> " + record + "\n" +
+		"if [ \"$1\" = run ]; then echo $$ > " + pid + "; exec sleep 30; fi\n" +
+		"kill $(cat " + pid + ")\n"
+	if err := os.WriteFile(runtime, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plan := &sandboxPlan{
+		config:        sandboxConfig{Image: "img", Network: sandboxNetworkNone},
+		runtime:       sandboxRuntimePodman,
+		binary:        runtime,
+		containerName: sandboxContainerName("run/1", 3, 2),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd, err := plan.launch(ctx, t.TempDir(), "claude", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+	_ = cmd.Wait()
+
+	data, err := os.ReadFile(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "stop -t 10 ao-rpi-run-1-p3-a2") {
+		t.Errorf("cancel should stop the container by name, calls:\n%s", data)
+	}
+}
+
+func TestSelectExecutorFromCaps_Sandbox(t *testing.T) {
+	opts := defaultPhasedEngineOptions()
+	opts.SandboxImage = "img"
+	opts.SandboxNetwork = "NONE"
+	executor, reason := selectExecutorFromCaps(backendCapabilities{RuntimeMode: "sandbox"}, "", nil, opts)
+	if executor.Name() != "sandbox" || reason != "runtime=sandbox" {
+		t.Fatalf("executor = %q (%s)", executor.Name(), reason)
+	}
+	sandbox, ok := executor.(*sandboxExecutor)
+	if !ok {
+		t.Fatalf("expected *sandboxExecutor, got %T", executor)
+	}
+	if sandbox.config.Runtime != "auto" || sandbox.config.Network != "none" || sandbox.config.Image != "img" {
+		t.Errorf("config = %+v", sandbox.config)
+	}
+	if sandbox.stream == nil || sandbox.stream.phaseTimeout != opts.PhaseTimeout {
+		t.Errorf("sandbox should wrap a stream executor with the phase timeout: %+v", sandbox.stream)
+	}
+}
+
+func TestPreflightOpts_SandboxContainerSkipsHostRuntime(t *testing.T) {
+	stubSandboxLookPath(t, "docker")
+	opts := defaultPhasedEngineOptions()
+	opts.RuntimeMode = "sandbox"
+	opts.SandboxImage = "img"
+	if err := preflightOpts(&opts); err != nil {
+		t.Fatalf("container sandbox should not need claude on the host: %v", err)
+	}
+
+	opts.SandboxImage = ""
+	if err := preflightOpts(&opts); err == nil {
+		t.Fatal("sandbox without image or bwrap should fail preflight")
+	}
+}
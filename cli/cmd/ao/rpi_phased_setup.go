@@ -147,27 +147,33 @@ func setupWorktreeLifecycle(cwd, originalCwd string, opts phasedEngineOptions, s
 	spawnCwd := cwd
 	noopCleanup := func(bool, string) error { return nil }
 
-	if opts.NoWorktree || GetDryRun() || state.WorktreePath != "" {
+	// --from reuses a previous worktree without owning it; ao rpi resume takes
+	// over the recorded worktree, including merge and removal on success.
+	if opts.NoWorktree || GetDryRun() || (state.WorktreePath != "" && opts.Resume == nil) {
 		return spawnCwd, noopCleanup, nil
 	}
 
-	worktreePath, runID, err := createWorktree(cwd)
-	if err != nil {
-		return "", noopCleanup, fmt.Errorf("create worktree: %w", err)
-	}
+	worktreePath, runID := state.WorktreePath, ""
+	if worktreePath == "" {
+		var err error
+		worktreePath, runID, err = createWorktree(cwd)
+		if err != nil {
+			return "", noopCleanup, fmt.Errorf("create worktree: %w", err)
+		}
 
-	spawnCwd = worktreePath
-	state.WorktreePath = worktreePath
-	if state.RunID == "" {
-		state.RunID = runID
+		state.WorktreePath = worktreePath
+		if state.RunID == "" {
+			state.RunID = runID
+		}
+		fmt.Printf("Worktree created: %s (detached)\n", worktreePath)
+		_, _ = appendRPIC2Event(worktreePath, rpiC2EventInput{
+			RunID:   state.RunID,
+			Type:    "worktree.created",
+			Message: fmt.Sprintf("Worktree created: %s", worktreePath),
+			Details: map[string]string{"path": worktreePath, "run_id": runID},
+		})
 	}
-	fmt.Printf("Worktree created: %s (detached)\n", worktreePath)
-	_, _ = appendRPIC2Event(worktreePath, rpiC2EventInput{
-		RunID:   state.RunID,
-		Type:    "worktree.created",
-		Message: fmt.Sprintf("Worktree created: %s", worktreePath),
-		Details: map[string]string{"path": worktreePath, "run_id": runID},
-	})
+	spawnCwd = worktreePath
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
@@ -178,6 +184,7 @@ func setupWorktreeLifecycle(cwd, originalCwd string, opts phasedEngineOptions, s
 			state.TerminalStatus = "interrupted"
 			state.TerminalReason = fmt.Sprintf("signal: %v", sig)
 			state.TerminatedAt = time.Now().Format(time.RFC3339)
+			recordRunCheckpoint(spawnCwd, state, state.Phase, "interrupted")
 			_ = savePhasedState(spawnCwd, state)
 			os.Exit(1)
 		}
@@ -236,7 +243,7 @@ func initializeRunArtifacts(spawnCwd string, startPhase int, state *phasedState,
 	statusPath := filepath.Join(stateDir, "live-status.md")
 	var allPhases []PhaseProgress
 
-	if startPhase == 1 {
+	if startPhase == 1 && opts.Resume == nil {
 		cleanPhaseSummaries(stateDir)
 	}
 
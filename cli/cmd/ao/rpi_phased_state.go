@@ -65,9 +65,10 @@ func writePhaseResult(cwd string, result *phaseResult) error {
 
 // validatePriorPhaseResult checks that phase-{expectedPhase}-result.json exists
 // and has a continuable status. Called at the start of phases 2 and 3.
-// Both "completed" and "time_boxed" are treated as continuation signals —
-// time_boxed means the phase ran but did not finish within its budget,
-// and the next phase should proceed with whatever was accomplished.
+// "completed", "time_boxed" and "skipped" are treated as continuation signals —
+// time_boxed means the phase ran but did not finish within its budget, and
+// skipped means an operator skipped it with a skip-phase control command; in
+// both cases the next phase should proceed with whatever was accomplished.
 func validatePriorPhaseResult(cwd string, expectedPhase int) error {
 	resultPath := filepath.Join(cwd, ".agents", "rpi", fmt.Sprintf(phaseResultFileFmt, expectedPhase))
 	data, err := os.ReadFile(resultPath)
@@ -80,8 +81,10 @@ func validatePriorPhaseResult(cwd string, expectedPhase int) error {
 		return fmt.Errorf("prior phase %d result is malformed: %w", expectedPhase, err)
 	}
 
-	if result.Status != "completed" && result.Status != "time_boxed" {
-		return fmt.Errorf("prior phase %d has status %q (expected %q or %q)", expectedPhase, result.Status, "completed", "time_boxed")
+	switch result.Status {
+	case "completed", "time_boxed", "skipped":
+	default:
+		return fmt.Errorf("prior phase %d has status %q (expected %q, %q or %q)", expectedPhase, result.Status, "completed", "time_boxed", "skipped")
 	}
 
 	return nil
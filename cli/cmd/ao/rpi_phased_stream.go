@@ -19,11 +19,13 @@ import (
 //
 //	stream  — stream-json execution with live parsing and fallback semantics.
 //	direct  — plain prompt execution without stream parsing.
+//	tmux    — detached mayor/worker tmux sessions.
+//	sandbox — stream execution inside a container or bubblewrap jail.
 //
 // The chosen backend is recorded in phasedState.Backend, emitted to stdout, and
 // appended to the orchestration log so every run has a traceable selection record.
 type PhaseExecutor interface {
-	// Name returns the backend identifier ("direct" or "stream").
+	// Name returns the backend identifier ("direct", "stream", "tmux" or "sandbox").
 	Name() string
 	// Execute runs the phase prompt and blocks until the session completes.
 	// The context carries cancellation signals (e.g. SIGINT/SIGTERM) from the
@@ -34,7 +36,8 @@ type PhaseExecutor interface {
 type directExecutor struct {
 	runtimeCommand string
 	phaseTimeout   time.Duration
-	stdoutWriter   io.Writer // defaults to os.Stdout; set to io.Discard when dashboard active
+	stdoutWriter   io.Writer       // defaults to os.Stdout; set to io.Discard when dashboard active
+	launcher       runtimeLauncher // defaults to hostRuntimeLauncher
 }
 
 func (d *directExecutor) Name() string { return "direct" }
@@ -54,7 +57,7 @@ func (d *directExecutor) Execute(ctx context.Context, prompt, cwd, runID string,
 	}); err != nil {
 		VerbosePrintf("Warning: could not append direct start event: %v\n", err)
 	}
-	execErr := spawnRuntimeDirectWithWriter(d.runtimeCommand, prompt, cwd, phaseNum, d.phaseTimeout, d.effectiveStdoutWriter())
+	execErr := spawnRuntimeDirectWithWriter(d.launcher, d.runtimeCommand, prompt, cwd, phaseNum, d.phaseTimeout, d.effectiveStdoutWriter())
 	evType, evMsg := "phase.direct.completed", fmt.Sprintf("phase %d direct session completed", phaseNum)
 	if execErr != nil {
 		evType, evMsg = "phase.direct.failed", execErr.Error()
@@ -76,7 +79,8 @@ type streamExecutor struct {
 	stallTimeout         time.Duration
 	streamStartupTimeout time.Duration
 	stallCheckInterval   time.Duration
-	stdoutWriter         io.Writer // defaults to os.Stdout; set to io.Discard when dashboard active
+	stdoutWriter         io.Writer       // defaults to os.Stdout; set to io.Discard when dashboard active
+	launcher             runtimeLauncher // defaults to hostRuntimeLauncher
 }
 
 func (s *streamExecutor) Name() string { return "stream" }
@@ -89,7 +93,7 @@ func (s *streamExecutor) effectiveStdoutWriter() io.Writer {
 }
 
 func (s *streamExecutor) Execute(ctx context.Context, prompt, cwd, runID string, phaseNum int) error {
-	err := spawnRuntimePhaseWithStream(s.runtimeCommand, prompt, cwd, runID, phaseNum, s.statusPath, s.allPhases, s.phaseTimeout, s.stallTimeout, s.streamStartupTimeout, s.stallCheckInterval, s.effectiveStdoutWriter())
+	err := spawnRuntimePhaseWithStream(s.launcher, s.runtimeCommand, prompt, cwd, runID, phaseNum, s.statusPath, s.allPhases, s.phaseTimeout, s.stallTimeout, s.streamStartupTimeout, s.stallCheckInterval, s.effectiveStdoutWriter())
 	if err == nil {
 		return nil
 	}
@@ -104,7 +108,7 @@ func (s *streamExecutor) Execute(ctx context.Context, prompt, cwd, runID string,
 	}); evErr != nil {
 		VerbosePrintf("Warning: could not append fallback start event: %v\n", evErr)
 	}
-	directErr := spawnRuntimeDirectWithWriter(s.runtimeCommand, prompt, cwd, phaseNum, s.phaseTimeout, s.effectiveStdoutWriter())
+	directErr := spawnRuntimeDirectWithWriter(s.launcher, s.runtimeCommand, prompt, cwd, phaseNum, s.phaseTimeout, s.effectiveStdoutWriter())
 	evType, evMsg := "phase.direct.completed", fmt.Sprintf("phase %d direct fallback completed", phaseNum)
 	if directErr != nil {
 		evType, evMsg = "phase.direct.failed", directErr.Error()
@@ -187,7 +191,7 @@ func formatRuntimePromptInvocation(command, prompt string) string {
 type backendCapabilities struct {
 	// LiveStatusEnabled is true when --live-status flag is set.
 	LiveStatusEnabled bool
-	// RuntimeMode is one of auto|direct|stream.
+	// RuntimeMode is one of auto|direct|stream|tmux|sandbox.
 	RuntimeMode string
 }
 
@@ -208,7 +212,9 @@ func probeBackendCapabilities(liveStatus bool, runtimeMode string) backendCapabi
 // Selection order (first match wins):
 //  1. runtime=stream — always stream
 //  2. runtime=direct — always direct
-//  3. runtime=auto   — stream when live-status enabled, otherwise direct
+//  3. runtime=tmux   — always tmux
+//  4. runtime=sandbox — stream inside a container or bubblewrap jail
+//  5. runtime=auto   — stream (falls back to direct per phase)
 func selectExecutorFromCaps(caps backendCapabilities, statusPath string, allPhases []PhaseProgress, opts phasedEngineOptions) (PhaseExecutor, string) {
 	stdWriter := opts.StdoutWriter
 	if stdWriter == nil {
@@ -241,6 +247,20 @@ func selectExecutorFromCaps(caps backendCapabilities, statusPath string, allPhas
 			pollInterval:   5 * time.Second,
 			workerCount:    opts.TmuxWorkers,
 		}, "runtime=tmux"
+	case "sandbox":
+		return &sandboxExecutor{
+			config: sandboxConfigFromOptions(opts),
+			stream: &streamExecutor{
+				runtimeCommand:       opts.RuntimeCommand,
+				statusPath:           statusPath,
+				allPhases:            allPhases,
+				phaseTimeout:         opts.PhaseTimeout,
+				stallTimeout:         opts.StallTimeout,
+				streamStartupTimeout: opts.StreamStartupTimeout,
+				stallCheckInterval:   opts.StallCheckInterval,
+				stdoutWriter:         stdWriter,
+			},
+		}, "runtime=sandbox"
 	default: // auto
 		// Always use stream for live JSONL output visibility.
 		// streamExecutor falls back to direct if the runtime does not
@@ -295,9 +315,23 @@ func spawnClaudePhase(prompt, cwd, _ string, phaseNum int) error {
 	return spawnDirectFn(prompt, cwd, phaseNum)
 }
 
+// runtimeLauncher builds the process for one runtime session in cwd.
+// hostRuntimeLauncher runs the runtime directly; the sandbox launcher wraps
+// it in a container or bubblewrap jail. Stdio is wired by the caller.
+type runtimeLauncher func(ctx context.Context, cwd, executable string, args []string) (*exec.Cmd, error)
+
+// hostRuntimeLauncher runs the runtime on the host with a clean environment.
+func hostRuntimeLauncher(ctx context.Context, cwd, executable string, args []string) (*exec.Cmd, error) {
+	cmd := exec.CommandContext(ctx, executable, args...)
+	cmd.Dir = cwd
+	cmd.Env = cleanEnvNoClaude()
+	return cmd, nil
+}
+
 // spawnRuntimeDirectWithWriter runs <runtimeCommand> -p directly, routing stdout to stdoutWriter.
 // phaseTimeout controls the maximum runtime; pass 0 to disable the timeout.
-func spawnRuntimeDirectWithWriter(runtimeCommand, prompt, cwd string, phaseNum int, phaseTimeout time.Duration, stdoutWriter io.Writer) error {
+// A nil launch runs the runtime on the host.
+func spawnRuntimeDirectWithWriter(launch runtimeLauncher, runtimeCommand, prompt, cwd string, phaseNum int, phaseTimeout time.Duration, stdoutWriter io.Writer) error {
 	command := effectiveRuntimeCommand(runtimeCommand)
 	executable, _ := splitRuntimeCommand(command)
 	if executable == "" {
@@ -312,13 +346,17 @@ func spawnRuntimeDirectWithWriter(runtimeCommand, prompt, cwd string, phaseNum i
 	}
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, executable, args...)
-	cmd.Dir = cwd
+	if launch == nil {
+		launch = hostRuntimeLauncher
+	}
+	cmd, err := launch(ctx, cwd, executable, args)
+	if err != nil {
+		return err
+	}
 	cmd.Stdout = stdoutWriter
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
-	cmd.Env = cleanEnvNoClaude()
-	err := cmd.Run()
+	err = cmd.Run()
 	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 		return fmt.Errorf("phase %d timed out after %s (set --phase-timeout to increase)", phaseNum, phaseTimeout)
 	}
@@ -334,7 +372,7 @@ func spawnRuntimeDirectWithWriter(runtimeCommand, prompt, cwd string, phaseNum i
 
 // spawnRuntimeDirectImpl is the backward-compatible wrapper using os.Stdout.
 func spawnRuntimeDirectImpl(runtimeCommand, prompt, cwd string, phaseNum int, phaseTimeout time.Duration) error {
-	return spawnRuntimeDirectWithWriter(runtimeCommand, prompt, cwd, phaseNum, phaseTimeout, os.Stdout)
+	return spawnRuntimeDirectWithWriter(nil, runtimeCommand, prompt, cwd, phaseNum, phaseTimeout, os.Stdout)
 }
 
 // spawnClaudeDirectImpl is the legacy wrapper pinned to the default runtime.
@@ -356,7 +394,7 @@ type streamWatchdogState struct {
 	lastActivityUnix atomic.Int64
 }
 
-func spawnRuntimePhaseWithStream(runtimeCommand, prompt, cwd, runID string, phaseNum int, statusPath string, allPhases []PhaseProgress, phaseTimeout, stallTimeout, streamStartupTimeout, checkInterval time.Duration, stdoutWriter io.Writer) error {
+func spawnRuntimePhaseWithStream(launch runtimeLauncher, runtimeCommand, prompt, cwd, runID string, phaseNum int, statusPath string, allPhases []PhaseProgress, phaseTimeout, stallTimeout, streamStartupTimeout, checkInterval time.Duration, stdoutWriter io.Writer) error {
 	command := effectiveRuntimeCommand(runtimeCommand)
 	executable, _ := splitRuntimeCommand(command)
 	if executable == "" {
@@ -381,10 +419,14 @@ func spawnRuntimePhaseWithStream(runtimeCommand, prompt, cwd, runID string, phas
 
 	startStreamWatchdogs(stallCtx, stallCancel, watchdog, startedAt, effectiveCheckInterval, stallTimeout, streamStartupTimeout)
 
-	cmd := exec.CommandContext(stallCtx, executable, args...)
-	cmd.Dir = cwd
+	if launch == nil {
+		launch = hostRuntimeLauncher
+	}
+	cmd, err := launch(stallCtx, cwd, executable, args)
+	if err != nil {
+		return err
+	}
 	cmd.Stderr = os.Stderr
-	cmd.Env = cleanEnvNoClaude()
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -574,7 +616,7 @@ func classifyStreamResult(ctx, stallCtx context.Context, command string, phaseNu
 
 // spawnClaudePhaseWithStream is the legacy wrapper pinned to the default runtime.
 func spawnClaudePhaseWithStream(prompt, cwd, runID string, phaseNum int, statusPath string, allPhases []PhaseProgress, phaseTimeout, stallTimeout, streamStartupTimeout, checkInterval time.Duration) error {
-	return spawnRuntimePhaseWithStream("claude", prompt, cwd, runID, phaseNum, statusPath, allPhases, phaseTimeout, stallTimeout, streamStartupTimeout, checkInterval, os.Stdout)
+	return spawnRuntimePhaseWithStream(nil, "claude", prompt, cwd, runID, phaseNum, statusPath, allPhases, phaseTimeout, stallTimeout, streamStartupTimeout, checkInterval, os.Stdout)
 }
 
 func updateLivePhaseStatus(statusPath string, allPhases []PhaseProgress, phaseNum int, action string, retries int, lastErr string) {
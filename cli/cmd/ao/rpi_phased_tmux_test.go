@@ -84,7 +84,7 @@ func TestSelectExecutorFromCaps_Tmux(t *testing.T) {
 }
 
 func TestValidateRuntimeMode_Tmux(t *testing.T) {
-	for _, mode := range []string{"tmux", "TMUX", "auto", "direct", "stream"} {
+	for _, mode := range []string{"tmux", "TMUX", "auto", "direct", "stream", "sandbox"} {
 		if err := validateRuntimeMode(mode); err != nil {
 			t.Fatalf("validateRuntimeMode(%q): %v", mode, err)
 		}
@@ -674,7 +674,7 @@ func TestResolveGateRetryAction_ModeOffParity(t *testing.T) {
 		Report:  "vibe.md",
 	}
 
-	action1, decision1 := resolveGateRetryAction(state, 3, gateErr, 1)
+	action1, decision1 := resolveGateRetryAction(types.DefaultMemRLPolicyContract(), state, 3, gateErr, 1)
 	if action1 != types.MemRLActionRetry {
 		t.Fatalf("mode=off attempt=1 action=%q, want retry", action1)
 	}
@@ -682,7 +682,7 @@ func TestResolveGateRetryAction_ModeOffParity(t *testing.T) {
 		t.Fatalf("mode=off decision mode=%q, want off", decision1.Mode)
 	}
 
-	action3, _ := resolveGateRetryAction(state, 3, gateErr, 3)
+	action3, _ := resolveGateRetryAction(types.DefaultMemRLPolicyContract(), state, 3, gateErr, 3)
 	if action3 != types.MemRLActionEscalate {
 		t.Fatalf("mode=off attempt=max action=%q, want escalate", action3)
 	}
@@ -698,7 +698,7 @@ func TestResolveGateRetryAction_EnforceCrankBlockedEscalatesEarly(t *testing.T)
 		Report:  "crank.md",
 	}
 
-	action, decision := resolveGateRetryAction(state, 2, gateErr, 1)
+	action, decision := resolveGateRetryAction(types.DefaultMemRLPolicyContract(), state, 2, gateErr, 1)
 	if action != types.MemRLActionEscalate {
 		t.Fatalf("mode=enforce crank BLOCKED attempt=1 action=%q, want escalate", action)
 	}
@@ -0,0 +1,481 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// rpiCheckpointEvent is the C2 event recording the HEAD of the run's checkout
+// at a checkpoint. ao rpi resume compares the latest one with the worktree.
+const rpiCheckpointEvent = "run.checkpoint"
+
+// rpiCheckpointPhaseCompleted is the checkpoint reason recorded once a phase
+// has passed its gate.
+const rpiCheckpointPhaseCompleted = "phase completed"
+
+var (
+	rpiResumeForce bool
+	rpiResumeShow  bool
+)
+
+func init() {
+	resumeCmd := &cobra.Command{
+		Use:   "resume <run-id>",
+		Short: "Resume an interrupted phased RPI run where it stopped",
+		Long: `Resume an interrupted, failed or aborted phased RPI run where it stopped.
+
+The engine state is rebuilt from the run's state snapshot, the RPI ledger and
+the run's C2 events: the current phase, whether that phase's session already
+completed (in which case the run re-enters the gate instead of re-running the
+session), gate attempt counts, the last verdicts and the worktree path.
+
+The run continues with the same run ID, in the same worktree and with the
+options it was started with. A run.resumed event is appended to the run's
+events and a resumed transition to the ledger.
+
+Commits made after the last checkpoint are expected: agents commit throughout
+a phase. Resume refuses only when the last commit the run recorded is no
+longer an ancestor of the worktree HEAD (history was reset or rewritten) or
+the worktree is on a different branch than at that checkpoint, since the
+recorded state then no longer describes the checkout. Inspect the worktree
+and pass --force to resume from its current HEAD.
+
+To continue a run that is still active but paused, use
+ao rpi control <run-id> resume instead.
+
+Examples:
+  ao rpi resume 760fc86f0c0f
+  ao rpi resume 760fc86f0c0f --show
+  ao rpi resume 760fc86f0c0f --show -o json
+  ao rpi resume 760fc86f0c0f --dry-run
+  ao rpi resume 760fc86f0c0f --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRPIResume,
+	}
+	resumeCmd.Flags().BoolVar(&rpiResumeForce, "force", false, "Resume even if the worktree has diverged from the last recorded commit")
+	resumeCmd.Flags().BoolVar(&rpiResumeShow, "show", false, "Print the reconstructed state without resuming")
+	rpiCmd.AddCommand(resumeCmd)
+}
+
+// rpiResumePoint is the engine state of an interrupted phased run, rebuilt
+// from its state snapshot, RPI ledger and C2 events.
+type rpiResumePoint struct {
+	RunID        string            `json:"run_id"`
+	Goal         string            `json:"goal"`
+	Root         string            `json:"root"`   // directory holding the run's state, ledger and events
+	Status       string            `json:"status"` // status before resuming
+	Phase        int               `json:"phase"`
+	PhaseName    string            `json:"phase_name,omitempty"`
+	AtGate       bool              `json:"at_gate"` // the phase session completed; resume at its gate
+	Completed    bool              `json:"completed"`
+	Attempts     map[string]int    `json:"attempts"`
+	Verdicts     map[string]string `json:"verdicts"`
+	WorktreePath string            `json:"worktree_path,omitempty"`
+	LastCommit   string            `json:"last_commit,omitempty"`
+	LastBranch   string            `json:"last_branch,omitempty"` // branch checked out at the last checkpoint
+	Head         string            `json:"head,omitempty"`
+	HeadBranch   string            `json:"head_branch,omitempty"`
+	HeadDescends bool              `json:"head_descends"` // LastCommit is an ancestor of (or equal to) Head
+
+	state *phasedState
+}
+
+func runRPIResume(cmd *cobra.Command, args []string) error {
+	runID := strings.TrimSpace(args[0])
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	point, err := loadRPIResumePoint(cwd, runID)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if rpiResumeShow {
+		if GetOutput() == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(point)
+		}
+		printRPIResumePoint(w, point)
+		return nil
+	}
+
+	if err := checkRPIResumable(point, rpiResumeForce); err != nil {
+		return err
+	}
+	printRPIResumePoint(w, point)
+	if point.LastCommit == "" {
+		fmt.Fprintf(w, "Warning: run %s recorded no commit; cannot verify the worktree has not diverged\n", point.RunID) //nolint:errcheck // CLI output
+	}
+	if GetDryRun() {
+		// A dry-run engine pass would record terminal state on the real run.
+		fmt.Fprintf(w, "[dry-run] Would resume run %s at %s\n", point.RunID, describeRPIResumePhase(point)) //nolint:errcheck // CLI output
+		return nil
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	return runRPIPhasedWithOpts(ctx, rpiResumeOptions(point), nil)
+}
+
+// loadRPIResumePoint locates run runID from cwd and reconstructs its state.
+func loadRPIResumePoint(cwd, runID string) (*rpiResumePoint, error) {
+	state, root, err := locateRunMetadata(cwd, runID)
+	if err != nil {
+		return nil, fmt.Errorf("locate run %s: %w", runID, err)
+	}
+	records, err := LoadRPILedgerRecords(root)
+	if err != nil {
+		return nil, fmt.Errorf("load RPI ledger: %w", err)
+	}
+	events, err := loadRPIC2Events(root, runID)
+	if err != nil {
+		return nil, fmt.Errorf("load events for run %s: %w", runID, err)
+	}
+
+	point := reconstructRPIResumePoint(state, root, records, events)
+	point.Status = rpiResumeStatus(state, rpiRunStillActive(root, state))
+	dir := cmp.Or(point.WorktreePath, root)
+	if head, err := gitOutputInDir(dir, "rev-parse", "HEAD"); err == nil {
+		point.Head = head
+	}
+	point.HeadBranch = gitCurrentBranch(dir)
+	if point.LastCommit != "" && point.Head != "" {
+		_, err := gitOutputInDir(dir, "merge-base", "--is-ancestor", point.LastCommit, point.Head)
+		point.HeadDescends = err == nil
+	}
+	return point, nil
+}
+
+// reconstructRPIResumePoint folds the run's ledger records and C2 events over
+// its state snapshot. The snapshot is only as fresh as its last save, while
+// the ledger and events are appended at every transition, so they take
+// precedence.
+func reconstructRPIResumePoint(state *phasedState, root string, records []RPILedgerRecord, events []RPIC2Event) *rpiResumePoint {
+	point := &rpiResumePoint{
+		RunID:        state.RunID,
+		Goal:         state.Goal,
+		Root:         root,
+		Phase:        state.Phase,
+		Completed:    state.TerminalStatus == "completed",
+		Attempts:     make(map[string]int),
+		Verdicts:     make(map[string]string),
+		WorktreePath: state.WorktreePath,
+		LastCommit:   state.LastCommit,
+		state:        state,
+	}
+	maps.Copy(point.Attempts, state.Attempts)
+	maps.Copy(point.Verdicts, state.Verdicts)
+	setAttempt := func(phaseNum, attempt int) {
+		key := fmt.Sprintf("phase_%d", phaseNum)
+		point.Attempts[key] = max(point.Attempts[key], attempt)
+	}
+	done := make(map[int]bool)
+
+	for _, rec := range records {
+		if rec.RunID != state.RunID {
+			continue
+		}
+		if rec.Phase == "complete" {
+			point.Completed = true
+			continue
+		}
+		phaseNum := phaseNameToNum(rec.Phase)
+		if phaseNum == 0 {
+			continue
+		}
+		var payload struct {
+			Details string `json:"details"`
+		}
+		_ = json.Unmarshal(rec.Details, &payload)
+		switch rec.Action {
+		case "started":
+			point.Phase, point.AtGate = phaseNum, false
+		case "completed", "timeout-rescued", "resumed":
+			point.Phase, point.AtGate = phaseNum, true
+		case "skipped":
+			done[phaseNum] = true
+		case "retry":
+			var attempt int
+			if _, err := fmt.Sscanf(payload.Details, "RETRY attempt %d", &attempt); err == nil {
+				setAttempt(phaseNum, attempt)
+			}
+		}
+		for prefix, key := range ledgerVerdictPrefixes {
+			if rest, ok := strings.CutPrefix(payload.Details, prefix); ok {
+				if fields := strings.Fields(rest); len(fields) > 0 {
+					point.Verdicts[key] = fields[0]
+				}
+			}
+		}
+	}
+
+	for _, ev := range events {
+		var details struct {
+			Attempt int    `json:"attempt"`
+			Verdict string `json:"verdict"`
+			Path    string `json:"path"`
+			Commit  string `json:"commit"`
+			Branch  string `json:"branch"`
+			Reason  string `json:"reason"`
+		}
+		_ = json.Unmarshal(ev.Details, &details)
+		switch ev.Type {
+		case "gate.retry.attempt":
+			setAttempt(ev.Phase, details.Attempt)
+		case "gate.discovery.verdict":
+			point.Verdicts["pre_mortem"] = cmp.Or(details.Verdict, point.Verdicts["pre_mortem"])
+		case "gate.validation.verdict":
+			point.Verdicts["vibe"] = cmp.Or(details.Verdict, point.Verdicts["vibe"])
+		case "worktree.created", "worktree.resumed":
+			point.WorktreePath = cmp.Or(details.Path, point.WorktreePath)
+		case rpiCheckpointEvent:
+			if details.Commit != "" {
+				point.LastCommit, point.LastBranch = details.Commit, details.Branch
+			}
+			if details.Reason == rpiCheckpointPhaseCompleted {
+				done[ev.Phase] = true
+			}
+		}
+	}
+
+	point.Phase = max(point.Phase, 1)
+	for point.Phase <= len(phases) && done[point.Phase] {
+		point.Phase++
+		point.AtGate = false
+	}
+	if point.Phase <= len(phases) {
+		point.PhaseName = phases[point.Phase-1].Name
+	}
+	return point
+}
+
+// ledgerVerdictPrefixes maps orchestration log details that carry a council
+// verdict to the verdict key they set.
+var ledgerVerdictPrefixes = map[string]string{
+	"pre-mortem verdict: ":  "pre_mortem",
+	"vibe verdict: ":        "vibe",
+	"post-mortem verdict: ": "post_mortem",
+}
+
+// rpiResumeStatus reports the status of a run before it is resumed. A run
+// killed without writing terminal metadata is interrupted.
+func rpiResumeStatus(state *phasedState, active bool) string {
+	switch {
+	case state.TerminalStatus != "":
+		return state.TerminalStatus
+	case active && state.PausedAt != "":
+		return "paused"
+	case active:
+		return "running"
+	}
+	return "interrupted"
+}
+
+// rpiRunStillActive reports whether the run's orchestrator is still alive. A
+// recorded orchestrator PID that is gone overrides a recent heartbeat, so a
+// killed run can be resumed right away.
+func rpiRunStillActive(root string, state *phasedState) bool {
+	active, _ := determineRunLiveness(root, state)
+	if !active || state.OrchestratorPID <= 0 {
+		return active
+	}
+	procs, err := listProcesses()
+	if err != nil {
+		return active
+	}
+	return processExists(state.OrchestratorPID, procs)
+}
+
+// checkRPIResumable refuses runs that are finished, still running, or whose
+// worktree no longer continues from the last recorded commit (unless force).
+// HEAD moving ahead of the checkpoint is normal; a checkpoint that is no
+// longer an ancestor of HEAD, or a different branch, is not.
+func checkRPIResumable(point *rpiResumePoint, force bool) error {
+	switch {
+	case point.Completed:
+		return fmt.Errorf("run %s already completed; nothing to resume", point.RunID)
+	case point.Status == "running" || point.Status == "paused":
+		return fmt.Errorf("run %s is still %s; use ao rpi control %s resume to continue a paused run", point.RunID, point.Status, point.RunID)
+	case point.Phase > len(phases):
+		return fmt.Errorf("all phases of run %s finished; nothing to resume", point.RunID)
+	}
+
+	dir := cmp.Or(point.WorktreePath, point.Root)
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("worktree %s of run %s no longer exists (was it removed?)", dir, point.RunID)
+	}
+	if point.LastCommit == "" || force {
+		return nil
+	}
+	if point.Head == "" {
+		return fmt.Errorf("cannot read HEAD of %s to compare with recorded commit %s (use --force to resume anyway)", dir, shortCommit(point.LastCommit))
+	}
+	if point.LastBranch != "" && point.HeadBranch != point.LastBranch {
+		return fmt.Errorf("worktree %s has diverged: it is on %s, but run %s last checkpointed on %s; inspect it and rerun with --force to resume from HEAD",
+			dir, cmp.Or(point.HeadBranch, "a detached HEAD"), point.RunID, point.LastBranch)
+	}
+	if point.Head != point.LastCommit && !point.HeadDescends {
+		return fmt.Errorf("worktree %s has diverged from the last recorded commit (recorded %s is not an ancestor of HEAD %s; history was reset or rewritten); inspect it and rerun with --force to resume from HEAD",
+			dir, shortCommit(point.LastCommit), shortCommit(point.Head))
+	}
+	return nil
+}
+
+// rpiResumeOptions returns the engine options to continue the run: the options
+// it was started with, pinned to its run ID, checkout and resume phase.
+func rpiResumeOptions(point *rpiResumePoint) phasedEngineOptions {
+	opts := point.state.Opts
+	if opts.From == "" {
+		// State written before options were persisted.
+		opts = defaultPhasedEngineOptions()
+	}
+	opts.From = point.PhaseName
+	opts.RunID = point.RunID
+	opts.AutoCleanStale = false // never let cleanup race the worktree being resumed
+	opts.NoWorktree = point.WorktreePath == ""
+	opts.WorkingDir = point.Root
+	if point.WorktreePath != "" {
+		if repoRoot := inferSupervisorRepoRoot(point.WorktreePath, point.RunID); repoRoot != "" {
+			opts.WorkingDir = repoRoot
+		}
+	}
+	opts.Resume = point
+	return opts
+}
+
+// restorePhasedState builds the engine state for a resumed run from
+// opts.Resume and returns it with the start phase and spawn directory.
+func restorePhasedState(opts phasedEngineOptions) (*phasedState, int, string, error) {
+	point := opts.Resume
+	restored := *point.state
+	state := &restored
+	state.Opts = opts
+	state.Phase = point.Phase
+	state.StartPhase = point.Phase
+	state.Attempts = point.Attempts
+	state.Verdicts = point.Verdicts
+	state.WorktreePath = point.WorktreePath
+	state.LastCommit = point.LastCommit
+	state.TerminalStatus, state.TerminalReason, state.TerminatedAt = "", "", ""
+	state.PausedAt = ""
+	if point.AtGate {
+		state.resumeGate = point.Phase
+	}
+	if state.Complexity == "" {
+		state.Complexity = classifyComplexity(state.Goal)
+	}
+	fmt.Printf("RPI mode: rpi-phased (resuming run %s, complexity: %s)\n", state.RunID, state.Complexity)
+
+	spawnCwd := cmp.Or(point.WorktreePath, point.Root)
+	if err := attachAutodevProgram(spawnCwd, state); err != nil {
+		return nil, 0, "", err
+	}
+	return state, point.Phase, spawnCwd, nil
+}
+
+// emitRunResumed records that the run was resumed: a run.resumed C2 event, a
+// resumed ledger transition and a checkpoint at the current HEAD.
+func emitRunResumed(spawnCwd, logPath string, state *phasedState, point *rpiResumePoint) {
+	if _, err := appendRPIC2Event(spawnCwd, rpiC2EventInput{
+		RunID: state.RunID, Phase: point.Phase, Backend: state.Backend, Source: "orchestrator",
+		Type: "run.resumed", Message: fmt.Sprintf("Resumed at %s", describeRPIResumePhase(point)),
+		Details: map[string]any{
+			"phase": point.Phase, "at_gate": point.AtGate, "previous_status": point.Status,
+			"attempts": point.Attempts, "verdicts": point.Verdicts,
+			"worktree_path": point.WorktreePath, "last_commit": point.LastCommit, "head": point.Head,
+		},
+	}); err != nil {
+		VerbosePrintf("Warning: could not emit run.resumed event: %v\n", err)
+	}
+	logPhaseTransition(logPath, state.RunID, "resume", fmt.Sprintf("resumed phase=%s at_gate=%v previous_status=%s commit=%s",
+		point.PhaseName, point.AtGate, point.Status, shortCommit(point.LastCommit)))
+	recordRunCheckpoint(spawnCwd, state, point.Phase, "resumed")
+}
+
+// recordRunCheckpoint records the HEAD of the run's checkout in state and as
+// a run.checkpoint C2 event. Checkouts that are not git repositories have
+// nothing to anchor a resume on and are skipped.
+func recordRunCheckpoint(spawnCwd string, state *phasedState, phaseNum int, reason string) {
+	head, err := gitOutputInDir(spawnCwd, "rev-parse", "HEAD")
+	if err != nil || head == "" {
+		return
+	}
+	state.LastCommit = head
+	details := map[string]string{"commit": head, "reason": reason}
+	if branch := gitCurrentBranch(spawnCwd); branch != "" {
+		details["branch"] = branch
+	}
+	if _, err := appendRPIC2Event(spawnCwd, rpiC2EventInput{
+		RunID: state.RunID, Phase: phaseNum, Backend: state.Backend, Source: "orchestrator",
+		Type: rpiCheckpointEvent, Message: fmt.Sprintf("%s at %s", reason, shortCommit(head)),
+		Details: details,
+	}); err != nil {
+		VerbosePrintf("Warning: could not emit %s event: %v\n", rpiCheckpointEvent, err)
+	}
+}
+
+// gitCurrentBranch returns the branch checked out in dir, or "" on a
+// detached HEAD.
+func gitCurrentBranch(dir string) string {
+	branch, err := gitOutputInDir(dir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return branch
+}
+
+func describeRPIResumePhase(point *rpiResumePoint) string {
+	if point.PhaseName == "" {
+		return "end of run"
+	}
+	if point.AtGate {
+		return fmt.Sprintf("phase %d (%s) gate", point.Phase, point.PhaseName)
+	}
+	return fmt.Sprintf("phase %d (%s)", point.Phase, point.PhaseName)
+}
+
+func printRPIResumePoint(w io.Writer, point *rpiResumePoint) {
+	fmt.Fprintf(w, "Run %s (%s): %s\n", point.RunID, point.Status, point.Goal) //nolint:errcheck // CLI output
+	fmt.Fprintf(w, "  Resume at: %s\n", describeRPIResumePhase(point))         //nolint:errcheck // CLI output
+	if point.WorktreePath != "" {
+		fmt.Fprintf(w, "  Worktree:  %s\n", point.WorktreePath) //nolint:errcheck // CLI output
+	}
+	if point.LastCommit != "" {
+		fmt.Fprintf(w, "  Commit:    %s (HEAD %s)\n", shortCommit(point.LastCommit), cmp.Or(shortCommit(point.Head), "unknown")) //nolint:errcheck // CLI output
+	}
+	if len(point.Attempts) > 0 {
+		var parts []string
+		for _, key := range slices.Sorted(maps.Keys(point.Attempts)) {
+			parts = append(parts, fmt.Sprintf("%s=%d", key, point.Attempts[key]))
+		}
+		fmt.Fprintf(w, "  Attempts:  %s (max %d)\n", strings.Join(parts, " "), point.state.Opts.MaxRetries) //nolint:errcheck // CLI output
+	}
+	if len(point.Verdicts) > 0 {
+		var parts []string
+		for _, key := range slices.Sorted(maps.Keys(point.Verdicts)) {
+			parts = append(parts, key+"="+point.Verdicts[key])
+		}
+		fmt.Fprintf(w, "  Verdicts:  %s\n", strings.Join(parts, " ")) //nolint:errcheck // CLI output
+	}
+}
+
+func shortCommit(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initResumeRepo creates a git repo with one commit and returns its path.
+func initResumeRepo(t *testing.T) string {
+	t.Helper()
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %v", args, out, err)
+		}
+	}
+	return repo
+}
+
+// writeInterruptedRun records a run the way the engine does: it passed
+// discovery, then failed the implementation gate twice before being killed
+// while the state snapshot still said phase 1.
+func writeInterruptedRun(t *testing.T, root string) *phasedState {
+	t.Helper()
+	logPath := filepath.Join(root, ".agents", "rpi", "phased-orchestration.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	state := newTestPhasedState().WithRunID("resume-run").WithGoal("add resume").WithPhase(1).WithMaxRetries(3)
+	if err := savePhasedState(root, state); err != nil {
+		t.Fatal(err)
+	}
+
+	logPhaseTransition(logPath, state.RunID, "discovery", "started")
+	logPhaseTransition(logPath, state.RunID, "discovery", "completed in 3m0s")
+	logPhaseTransition(logPath, state.RunID, "discovery", "pre-mortem verdict: WARN report=.agents/council/pm.md")
+	recordRunCheckpoint(root, state, 1, rpiCheckpointPhaseCompleted)
+	logPhaseTransition(logPath, state.RunID, "implementation", "started")
+	logPhaseTransition(logPath, state.RunID, "implementation", "completed in 9m0s")
+	for attempt := 1; attempt <= 2; attempt++ {
+		logPhaseTransition(logPath, state.RunID, "implementation", fmt.Sprintf("RETRY attempt %d/3 verdict=PARTIAL report=bd", attempt))
+	}
+	if _, err := appendRPIC2Event(root, rpiC2EventInput{
+		RunID: state.RunID, Phase: 2, Type: "gate.retry.attempt",
+		Details: map[string]any{"attempt": 2, "max_retries": 3, "verdict": "PARTIAL"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	recordRunCheckpoint(root, state, 2, "gate retry")
+	return state
+}
+
+func TestReconstructRPIResumePoint_FailedGate(t *testing.T) {
+	root := initResumeRepo(t)
+	writeInterruptedRun(t, root)
+
+	point, err := loadRPIResumePoint(root, "resume-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if point.Phase != 2 || !point.AtGate {
+		t.Errorf("resume at phase %d (at gate %v), want implementation gate", point.Phase, point.AtGate)
+	}
+	if point.Attempts["phase_2"] != 2 {
+		t.Errorf("attempts = %v, want phase_2=2", point.Attempts)
+	}
+	if point.Verdicts["pre_mortem"] != "WARN" {
+		t.Errorf("verdicts = %v, want pre_mortem=WARN", point.Verdicts)
+	}
+	if point.Status != "interrupted" || point.LastCommit == "" || point.LastCommit != point.Head {
+		t.Errorf("status=%s last_commit=%q head=%q", point.Status, point.LastCommit, point.Head)
+	}
+	if err := checkRPIResumable(point, false); err != nil {
+		t.Errorf("untouched checkout should be resumable: %v", err)
+	}
+}
+
+func TestReconstructRPIResumePoint_AdvancesPastCompletedPhases(t *testing.T) {
+	state := newTestPhasedState().WithRunID("r1").WithPhase(2)
+	events := []RPIC2Event{
+		{RunID: "r1", Phase: 2, Type: rpiCheckpointEvent, Details: []byte(`{"commit":"abc","reason":"phase completed"}`)},
+		{RunID: "r1", Type: "worktree.created", Details: []byte(`{"path":"/tmp/repo-rpi-r1"}`)},
+	}
+	point := reconstructRPIResumePoint(state, "/tmp/repo", nil, events)
+	if point.Phase != 3 || point.AtGate || point.PhaseName != "validation" {
+		t.Errorf("point = %+v, want validation from the start", point)
+	}
+	if point.WorktreePath != "/tmp/repo-rpi-r1" || point.LastCommit != "abc" {
+		t.Errorf("worktree=%q commit=%q", point.WorktreePath, point.LastCommit)
+	}
+
+	records := []RPILedgerRecord{{RunID: "r1", Phase: "validation", Action: "skipped", Details: []byte(`{"details":"skipped — complexity: fast"}`)}}
+	point = reconstructRPIResumePoint(state, "/tmp/repo", records, events)
+	if err := checkRPIResumable(point, false); err == nil || !strings.Contains(err.Error(), "nothing to resume") {
+		t.Errorf("run past its last phase should not resume, got %v", err)
+	}
+}
+
+func TestCheckRPIResumable(t *testing.T) {
+	root := initResumeRepo(t)
+	writeInterruptedRun(t, root)
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", root}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %v", args, out, err)
+		}
+	}
+	load := func() *rpiResumePoint {
+		t.Helper()
+		point, err := loadRPIResumePoint(root, "resume-run")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return point
+	}
+
+	// Agents commit throughout a phase: HEAD ahead of the checkpoint resumes.
+	git("commit", "--allow-empty", "-m", "agent work")
+	point := load()
+	if point.Head == point.LastCommit || !point.HeadDescends || point.LastBranch == "" {
+		t.Fatalf("point = %+v, want HEAD descending from the checkpoint on a recorded branch", point)
+	}
+	if err := checkRPIResumable(point, false); err != nil {
+		t.Errorf("HEAD descending from the checkpoint should be resumable: %v", err)
+	}
+
+	// Rewriting the checkpoint away is a divergence: replace the branch with
+	// an unrelated root commit.
+	rewritten, err := exec.Command("git", "-C", root, "commit-tree", "HEAD^{tree}", "-m", "rewritten").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	git("reset", "-q", "--hard", strings.TrimSpace(string(rewritten)))
+	point = load()
+	if err := checkRPIResumable(point, false); err == nil || !strings.Contains(err.Error(), "not an ancestor") {
+		t.Errorf("rewritten history should be refused, got %v", err)
+	}
+	if err := checkRPIResumable(point, true); err != nil {
+		t.Errorf("--force should allow a diverged worktree: %v", err)
+	}
+
+	// So is switching branches, even to one that contains the checkpoint.
+	git("checkout", "-q", "-b", "elsewhere", point.LastCommit)
+	if err := checkRPIResumable(load(), false); err == nil || !strings.Contains(err.Error(), "is on elsewhere") {
+		t.Errorf("other branch should be refused, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		point   rpiResumePoint
+		wantErr string
+	}{
+		{"completed", rpiResumePoint{RunID: "r", Phase: 3, Completed: true}, "already completed"},
+		{"running", rpiResumePoint{RunID: "r", Phase: 2, Status: "running"}, "still running"},
+		{"worktree gone", rpiResumePoint{RunID: "r", Phase: 2, WorktreePath: filepath.Join(root, "missing")}, "no longer exists"},
+	} {
+		if err := checkRPIResumable(&tc.point, true); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestRunSinglePhase_ResumedGateSkipsSession(t *testing.T) {
+	tmp := t.TempDir()
+	rpiDir := filepath.Join(tmp, ".agents", "rpi")
+	councilDir := filepath.Join(tmp, ".agents", "council")
+	for _, dir := range []string{rpiDir, councilDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(councilDir, "2026-03-05-vibe-resume.md"), []byte("# Vibe\n## Council Verdict: FAIL\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MEMRL_MODE", "off")
+
+	state := newTestPhasedState().WithRunID("resume-gate").WithMaxRetries(3).WithPhase(3).WithStartPhase(3)
+	state.Attempts["phase_3"] = maxGateRetryDepth
+	state.resumeGate = 3
+	executor := &fakeExecutor{}
+	logPath := filepath.Join(rpiDir, "phased-orchestration.log")
+
+	err := runSinglePhase(context.Background(), tmp, tmp, state, 3, phases[2], state.Opts, filepath.Join(rpiDir, "live-status.md"), buildAllPhases(phases), logPath, executor)
+	if err == nil || !strings.Contains(err.Error(), "gate failed after max retries") {
+		t.Fatalf("err = %v, want gate escalation", err)
+	}
+	if executor.executed {
+		t.Error("resumed gate must not re-run the phase session")
+	}
+	if state.Attempts["phase_3"] != maxGateRetryDepth+1 || state.resumeGate != 0 {
+		t.Errorf("attempts = %v resumeGate = %d; the counter should continue and the resume marker clear", state.Attempts, state.resumeGate)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil || !strings.Contains(string(data), "validation: resumed at gate") {
+		t.Errorf("log should record the gate resume: %s (%v)", data, err)
+	}
+}
+
+func TestRPIResumeOptions(t *testing.T) {
+	state := newTestPhasedState().WithRunID("r1")
+	state.Opts = defaultPhasedEngineOptions()
+	state.Opts.AutoCleanStale = true
+	state.Opts.MaxRetries = 5
+	point := &rpiResumePoint{RunID: "r1", Root: "/repo", Phase: 2, PhaseName: "implementation", state: state}
+
+	opts := rpiResumeOptions(point)
+	if opts.From != "implementation" || opts.RunID != "r1" || opts.Resume != point {
+		t.Errorf("opts = %+v", opts)
+	}
+	if opts.AutoCleanStale || !opts.NoWorktree || opts.WorkingDir != "/repo" || opts.MaxRetries != 5 {
+		t.Errorf("resume must keep recorded options, stay in place and skip stale cleanup: %+v", opts)
+	}
+
+	restored, startPhase, spawnCwd, err := restorePhasedState(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if startPhase != 2 || spawnCwd != "/repo" || restored.StartPhase != 2 || restored.resumeGate != 0 {
+		t.Errorf("restored phase=%d spawn=%s state=%+v", startPhase, spawnCwd, restored)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -93,7 +94,12 @@ Watch mode (ao rpi serve [run-id]):
   ao rpi serve --no-open            # start server without opening browser
 
 The dashboard streams events via Server-Sent Events (SSE) and also polls
-/runs and /state for discovery and reconciliation. It does not use WebSockets.`,
+/runs and /state for discovery and reconciliation. It does not use WebSockets.
+
+Control commands (see ao rpi control) can be queued with POST /control/<kind>
+and a JSON body such as {"run_id": "760fc86f0c0f", "reason": "wrong goal"}.
+Only requests addressed to a loopback host (localhost, 127.0.0.1 or [::1])
+from a page on one are accepted.`,
 		RunE: runRPIServe,
 	}
 	serveCmd.Flags().IntVar(&rpiServePort, "port", 7799, "Port to listen on")
@@ -340,6 +346,14 @@ func buildServeMux(root *serveMuxRoot, runID string) *http.ServeMux {
 		}
 		serveRPIArtifact(w, r, root.get(), runID)
 	})
+	mux.HandleFunc("/control/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			setControlCORSHeaders(w, r)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		serveRPIControl(w, r, root.get(), runID)
+	})
 	return mux
 }
 
@@ -635,6 +649,75 @@ func serveRPIArtifact(w http.ResponseWriter, r *http.Request, root, defaultRunID
 	_ = json.NewEncoder(w).Encode(content)
 }
 
+// serveRPIControl queues a control command from POST /control/<kind>. The
+// JSON body carries run_id (defaulting to the watched run), phase, reason and
+// budget; the kind comes from the path.
+func serveRPIControl(w http.ResponseWriter, r *http.Request, root, defaultRunID string) {
+	setControlCORSHeaders(w, r)
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" && !isLocalhostOrigin(origin) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	// A DNS-rebound page is same-origin with the server, so it sends no
+	// foreign Origin; its Host header still names the attacker's domain.
+	if !isLoopbackHost(r.Host) {
+		http.Error(w, "host not allowed", http.StatusForbidden)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req rpiControlRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Kind = strings.TrimPrefix(r.URL.Path, "/control/")
+	req.RunID = strings.TrimSpace(req.RunID)
+	if req.RunID == "" {
+		req.RunID = strings.TrimSpace(r.URL.Query().Get("run-id"))
+	}
+	if req.RunID == "" {
+		req.RunID = defaultRunID
+	}
+	if strings.Contains(req.RunID, "..") || strings.Contains(req.RunID, "/") || strings.Contains(req.RunID, "\\") {
+		http.Error(w, "invalid run_id", http.StatusBadRequest)
+		return
+	}
+
+	state, stateRoot := resolveServeRun(root, req.RunID)
+	if state == nil {
+		http.Error(w, fmt.Sprintf("run %q not found", req.RunID), http.StatusNotFound)
+		return
+	}
+	if state.TerminalStatus != "" {
+		http.Error(w, fmt.Sprintf("run %s is %s", req.RunID, state.TerminalStatus), http.StatusConflict)
+		return
+	}
+	record, err := queueRPIControlCommand(stateRoot, req, "serve")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(record)
+}
+
+// setControlCORSHeaders is setCORSHeaders for the POST-only control endpoint.
+func setControlCORSHeaders(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w, r)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+}
+
 func setCORSHeaders(w http.ResponseWriter, r ...*http.Request) {
 	origin := ""
 	if len(r) > 0 && r[0] != nil {
@@ -653,16 +736,29 @@ func setCORSHeaders(w http.ResponseWriter, r ...*http.Request) {
 	w.Header().Set("Access-Control-Max-Age", "86400")
 }
 
-// isLocalhostOrigin returns true if the origin is a localhost URL.
+// isLocalhostOrigin reports whether origin is an http(s) URL whose host is
+// exactly localhost or a loopback address.
 func isLocalhostOrigin(origin string) bool {
-	for _, prefix := range []string{
-		"http://localhost", "https://localhost",
-		"http://127.0.0.1", "https://127.0.0.1",
-		"http://[::1]", "https://[::1]",
-	} {
-		if strings.HasPrefix(origin, prefix) {
-			return true
-		}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return isLoopbackHostname(u.Hostname())
+}
+
+// isLoopbackHost reports whether a Host header, with or without a port,
+// names localhost or a loopback address.
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return isLoopbackHostname(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+}
+
+func isLoopbackHostname(host string) bool {
+	switch strings.ToLower(host) {
+	case "localhost", "127.0.0.1", "::1":
+		return true
 	}
 	return false
 }
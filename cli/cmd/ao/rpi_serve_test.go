@@ -198,6 +198,35 @@ func TestSetCORSHeaders(t *testing.T) {
 	}
 }
 
+func TestIsLocalhostOrigin(t *testing.T) {
+	for origin, want := range map[string]bool{
+		"http://localhost":                  true,
+		"https://localhost:7799":            true,
+		"http://127.0.0.1:3000":             true,
+		"http://[::1]:7799":                 true,
+		"http://localhost.attacker.example": false,
+		"http://127.0.0.1.nip.io":           false,
+		"http://localhost@evil.example":     false,
+		"file://localhost":                  false,
+		"null":                              false,
+	} {
+		if got := isLocalhostOrigin(origin); got != want {
+			t.Errorf("isLocalhostOrigin(%q) = %v, want %v", origin, got, want)
+		}
+	}
+	for host, want := range map[string]bool{
+		"localhost:7799":    true,
+		"127.0.0.1":         true,
+		"[::1]:7799":        true,
+		"evil.example:7799": false,
+		"localhost.evil":    false,
+	} {
+		if got := isLoopbackHost(host); got != want {
+			t.Errorf("isLoopbackHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
 func TestClassifyServeArg_12HexRunID(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -952,6 +952,9 @@ func classifyRunStatus(state phasedState, isActive bool) string {
 		return state.TerminalStatus
 	}
 	if isActive {
+		if state.PausedAt != "" {
+			return "paused"
+		}
 		return "running"
 	}
 	if state.Phase >= completedPhaseNumber(state) {
@@ -20,6 +20,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/boshu2/agentops/cli/internal/ratchet"
+	"github.com/boshu2/agentops/cli/internal/search"
 	"github.com/boshu2/agentops/cli/internal/storage"
 	"github.com/boshu2/agentops/cli/internal/types"
 	"github.com/boshu2/agentops/cli/pkg/vault"
@@ -55,9 +56,13 @@ By default, ao search brokers across two backends:
 
 Use --cass to require upstream cass only.
 Use --local to force repo-local AgentOps search only.
+Use --hybrid to rank repo-local results by BM25 blended with vector
+similarity from .agents/ao/vectors.jsonl (written by ao store rebuild). The
+default embedder is offline and deterministic, so no network or Obsidian is
+needed; documents can match on related wording, not just exact keywords.
 Use --use-sc to try Smart Connections semantic search first when Obsidian is
 available. If Smart Connections is unavailable or fails, ao search falls back
-to the selected non-Smart-Connections backend chain.
+to the selected non-Smart-Connections backend chain with hybrid ranking.
 
 Use ao lookup when you specifically want curated learnings, patterns, and
 findings by relevance.`,
@@ -65,6 +70,7 @@ findings by relevance.`,
   ao search "authentication" --limit 20
   ao search "database migration" --type decisions
   ao search "config" --use-sc
+  ao search "auth" --local --hybrid
   ao search "auth" --cass
   ao search "auth" --local`,
 	Args: cobra.ExactArgs(1),
@@ -81,6 +87,7 @@ func init() {
 	searchCmd.Flags().BoolVar(&searchUseSC, "use-sc", false, "Try Smart Connections semantic search first (requires Obsidian)")
 	searchCmd.Flags().BoolVar(&searchUseCASS, "cass", false, "Require upstream cass session-history search")
 	searchCmd.Flags().BoolVar(&searchUseLocal, "local", false, "Force repo-local AgentOps search only")
+	searchCmd.Flags().BoolVar(&hybridRetrieval, "hybrid", false, hybridFlagUsage)
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
@@ -187,7 +194,7 @@ func selectAndSearch(query, sessionsDir string, limit int) ([]searchResult, erro
 			VerbosePrintf("Using Smart Connections for semantic search...\n")
 			results, err := searchSmartConnections(query, sessionsDir, limit)
 			if err != nil {
-				VerbosePrintf("Smart Connections failed, falling back to CASS: %v\n", err)
+				VerbosePrintf("Smart Connections failed, falling back to local hybrid search: %v\n", err)
 				return searchCASS(query, sessionsDir, limit)
 			}
 			return results, nil
@@ -777,6 +784,12 @@ func searchRepoLocalKnowledge(query, dir string, limit int) ([]searchResult, err
 		unique = append(unique, result)
 	}
 
+	if hybridSearchEnabled() {
+		unique = rankRepoLocalHybrid(query, dir, unique)
+	} else {
+		rankRepoLocalResults(query, dir, unique)
+	}
+
 	// Limit results
 	if limit > 0 && len(unique) > limit {
 		unique = unique[:limit]
@@ -785,6 +798,55 @@ func searchRepoLocalKnowledge(query, dir string, limit int) ([]searchResult, err
 	return unique, nil
 }
 
+// rankRepoLocalResults re-scores repo-local matches in place with BM25 so a
+// focused document outranks a long one that mentions a query term once.
+// Corpus statistics come from the persisted search index when one exists;
+// candidates it does not cover are indexed in memory. Existing positive
+// scores (learning maturity weights) are kept as a multiplier.
+func rankRepoLocalResults(query, sessionsDir string, results []searchResult) {
+	idx := loadRepoLocalSearchIndex(sessionsDir)
+	for _, r := range results {
+		if _, ok := idx.Lengths[r.Path]; !ok {
+			_ = search.UpdateIndex(idx, r.Path) // non-fatal: keep the grep match unscored
+		}
+	}
+
+	bm25 := make(map[string]float64, len(results))
+	for _, hit := range search.Search(idx, query, 0) {
+		bm25[hit.Path] = hit.Score
+	}
+
+	for i := range results {
+		weight := results[i].Score
+		if weight <= 0 {
+			weight = 1
+		}
+		results[i].Score = bm25[results[i].Path] * weight
+	}
+
+	slices.SortFunc(results, func(a, b searchResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+}
+
+// loadRepoLocalSearchIndex loads the persisted BM25 index that lives next to
+// the sessions directory, or returns an empty index if there is none.
+func loadRepoLocalSearchIndex(sessionsDir string) *search.Index {
+	idxPath := filepath.Join(filepath.Dir(filepath.Clean(sessionsDir)), search.DefaultIndexFile)
+	if _, err := os.Stat(idxPath); err != nil {
+		return search.NewIndex()
+	}
+	idx, err := search.LoadIndex(idxPath)
+	if err != nil {
+		VerbosePrintf("Warning: load search index: %v\n", err)
+		return search.NewIndex()
+	}
+	return idx
+}
+
 func searchCASS(query, dir string, limit int) ([]searchResult, error) {
 	return searchRepoLocalKnowledge(query, dir, limit)
 }
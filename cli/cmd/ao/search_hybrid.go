@@ -0,0 +1,189 @@
+package main
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/boshu2/agentops/cli/internal/search"
+	"github.com/boshu2/agentops/cli/internal/storage"
+)
+
+const (
+	// hybridFlagUsage is the shared help text for --hybrid on search, lookup and inject.
+	hybridFlagUsage = "Blend BM25 with local vector similarity when ranking (offline, no Smart Connections needed)"
+
+	// hybridCandidateLimit caps how many vector-only matches ao search adds
+	// on top of the keyword matches before hybrid ranking.
+	hybridCandidateLimit = 50
+
+	// hybridMinRelevance drops learnings whose hybrid relevance is below this
+	// floor, so skipping the substring filter does not inject noise.
+	hybridMinRelevance = 0.05
+
+	// hybridRelevanceWeight scales hybrid relevance before it is added to a
+	// learning's composite (z-normalized freshness + utility) score.
+	hybridRelevanceWeight = 2.0
+)
+
+// hybridRetrieval is bound to --hybrid on ao search, ao lookup and ao inject.
+var hybridRetrieval bool
+
+// hybridSearchEnabled reports whether repo-local search should use hybrid
+// ranking. --use-sc implies it so that the fallback when Smart Connections
+// is unavailable is still semantic rather than keyword-only.
+func hybridSearchEnabled() bool {
+	return hybridRetrieval || searchUseSC
+}
+
+// rankRepoLocalHybrid re-ranks repo-local matches with BM25 blended with
+// vector similarity from .agents/ao/vectors.jsonl. Documents the vector store
+// ranks close to the query are added even when no keyword matched, so
+// "auth" can surface a note that only says "authentication". Existing
+// positive scores (learning maturity weights) are kept as a multiplier.
+func rankRepoLocalHybrid(query, sessionsDir string, results []searchResult) []searchResult {
+	embedder := search.NewHashEmbedder()
+	vs := loadRepoLocalVectorStore(sessionsDir, embedder)
+
+	seen := make(map[string]bool, len(results))
+	for _, r := range results {
+		seen[r.Path] = true
+	}
+	for _, hit := range search.VectorSearch(vs, embedder, query, hybridCandidateLimit) {
+		if seen[hit.Path] {
+			continue
+		}
+		if _, err := os.Stat(hit.Path); err != nil {
+			continue // stale vector for a deleted file
+		}
+		seen[hit.Path] = true
+		results = append(results, searchResult{
+			Path:    hit.Path,
+			Context: hybridResultContext(hit.Path, query),
+			Type:    classifyResultType(hit.Path),
+		})
+	}
+
+	idx := loadRepoLocalSearchIndex(sessionsDir)
+	for _, r := range results {
+		if _, ok := idx.Lengths[r.Path]; !ok {
+			_ = search.UpdateIndex(idx, r.Path) // non-fatal: vector score still applies
+		}
+		if _, ok := vs.Vectors[r.Path]; !ok {
+			_ = search.EmbedFile(vs, embedder, r.Path) // non-fatal: BM25 score still applies
+		}
+	}
+
+	lexical := search.Search(idx, query, 0)
+	semantic := search.VectorSearch(vs, embedder, query, 0)
+	hybrid := make(map[string]float64, len(results))
+	for _, hit := range search.HybridRank(lexical, semantic, search.DefaultLexicalWeight, 0) {
+		hybrid[hit.Path] = hit.Score
+	}
+
+	for i := range results {
+		weight := results[i].Score
+		if weight <= 0 {
+			weight = 1
+		}
+		results[i].Score = hybrid[results[i].Path] * weight
+	}
+
+	slices.SortFunc(results, func(a, b searchResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	return results
+}
+
+// loadRepoLocalVectorStore loads the vector store that lives next to the
+// sessions directory, or returns an empty store if there is none.
+func loadRepoLocalVectorStore(sessionsDir string, e search.Embedder) *search.VectorStore {
+	path := filepath.Join(filepath.Dir(filepath.Clean(sessionsDir)), search.DefaultVectorFile)
+	vs, err := search.LoadVectorStore(path, e)
+	if err != nil {
+		VerbosePrintf("Warning: load vector store: %v\n", err)
+		return search.NewVectorStore(e.Model())
+	}
+	return vs
+}
+
+// hybridResultContext returns display context for a vector-only match:
+// matching lines when there are any, otherwise the document title.
+func hybridResultContext(path, query string) string {
+	if context := getFileContext(path, query); context != "" {
+		return context
+	}
+	doc, err := search.LoadDocument(path)
+	if err != nil {
+		return ""
+	}
+	return truncateContext(doc.Title)
+}
+
+// rerankLearningsHybrid scores learnings against query with hybrid BM25 +
+// vector relevance over their title and summary. Learnings below
+// hybridMinRelevance are dropped; the rest get their relevance added to the
+// composite score and are re-sorted. Vectors come from the persisted
+// .agents/ao/vectors.jsonl when it matches the embedder's model: a learning
+// file it already holds is ranked on the indexed file content so the stored
+// vector is reused instead of re-embedded.
+func rerankLearningsHybrid(cwd, query string, learnings []learning) []learning {
+	embedder := search.NewHashEmbedder()
+	vs := loadRepoLocalVectorStore(filepath.Join(cwd, storage.DefaultBaseDir, storage.SessionsDir), embedder)
+
+	keys := learningRerankKeys(learnings)
+	docs := make(map[string]search.Document, len(learnings))
+	for i, l := range learnings {
+		doc := search.Document{Title: l.Title, Body: l.Summary}
+		if _, ok := vs.Vectors[keys[i]]; ok {
+			if indexed, err := search.LoadDocument(keys[i]); err == nil {
+				doc = indexed
+			}
+		}
+		docs[keys[i]] = doc
+	}
+
+	relevance := make(map[string]float64, len(docs))
+	for _, hit := range search.RankDocuments(docs, query, embedder, vs, search.DefaultLexicalWeight) {
+		relevance[hit.Path] = hit.Score
+	}
+
+	kept := learnings[:0]
+	for i, l := range learnings {
+		l.Relevance = relevance[keys[i]]
+		if l.Relevance < hybridMinRelevance {
+			continue
+		}
+		l.CompositeScore += hybridRelevanceWeight * l.Relevance
+		kept = append(kept, l)
+	}
+
+	slices.SortFunc(kept, func(a, b learning) int {
+		return cmp.Compare(b.CompositeScore, a.CompositeScore)
+	})
+	return kept
+}
+
+// learningRerankKeys returns a document key per learning: its source path
+// when no other learning shares it, so it lines up with the persisted vector
+// store, and source#position for learnings read from one JSONL file.
+func learningRerankKeys(learnings []learning) []string {
+	sources := make(map[string]int, len(learnings))
+	for _, l := range learnings {
+		sources[l.Source]++
+	}
+	keys := make([]string, len(learnings))
+	for i, l := range learnings {
+		keys[i] = l.Source
+		if l.Source == "" || sources[l.Source] > 1 {
+			keys[i] = l.Source + "#" + strconv.Itoa(i)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/search"
+)
+
+func TestCollectLearnings_HybridMatchesRelatedWording(t *testing.T) {
+	tmpDir := t.TempDir()
+	learningsDir := filepath.Join(tmpDir, ".agents", "learnings")
+	if err := os.MkdirAll(learningsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"auth.md":    "---\nmaturity: provisional\nutility: 0.8\nsource_bead: ag-1\n---\n# Authentication Tokens\n\nRotate authentication tokens before they expire.\n",
+		"migrate.md": "---\nmaturity: provisional\nutility: 0.8\nsource_bead: ag-2\n---\n# Database Migrations\n\nRun migrations inside a transaction.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(learningsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plain, err := collectLearnings(tmpDir, "auth tokens expiring", 10, "", 0)
+	if err != nil {
+		t.Fatalf("collectLearnings: %v", err)
+	}
+	if len(plain) != 0 {
+		t.Fatalf("expected substring filter to miss, got %d learnings", len(plain))
+	}
+
+	hybridRetrieval = true
+	t.Cleanup(func() { hybridRetrieval = false })
+
+	got, err := collectLearnings(tmpDir, "auth tokens expiring", 10, "", 0)
+	if err != nil {
+		t.Fatalf("collectLearnings: %v", err)
+	}
+	if len(got) != 1 || got[0].Title != "Authentication Tokens" {
+		t.Fatalf("expected only the authentication learning, got %+v", got)
+	}
+	if got[0].Relevance <= 0 {
+		t.Errorf("expected positive relevance, got %v", got[0].Relevance)
+	}
+}
+
+func TestRankRepoLocalHybrid_AddsVectorOnlyMatches(t *testing.T) {
+	tmp := t.TempDir()
+	aoDir := filepath.Join(tmp, ".agents", "ao")
+	sessDir := filepath.Join(aoDir, "sessions")
+	learningsDir := filepath.Join(tmp, ".agents", "learnings")
+	for _, dir := range []string{sessDir, learningsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keyword := filepath.Join(learningsDir, "auth.md")
+	related := filepath.Join(learningsDir, "authentication.md")
+	if err := os.WriteFile(keyword, []byte("auth notes: check the auth header"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(related, []byte("---\ntitle: Authentication\n---\nauthentication tokens expire hourly"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := search.NewHashEmbedder()
+	vs := search.NewVectorStore(e.Model())
+	for _, p := range []string{keyword, related} {
+		if err := search.EmbedFile(vs, e, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := search.SaveVectorStore(vs, filepath.Join(aoDir, search.DefaultVectorFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	results := rankRepoLocalHybrid("auth", sessDir, []searchResult{{Path: keyword}})
+	if len(results) != 2 {
+		t.Fatalf("expected keyword match plus vector-only match, got %+v", results)
+	}
+	if results[0].Path != keyword {
+		t.Errorf("expected keyword match first, got %s", results[0].Path)
+	}
+	if results[1].Path != related || results[1].Type != "learning" || results[1].Score <= 0 {
+		t.Errorf("unexpected vector-only result: %+v", results[1])
+	}
+	if results[1].Context == "" {
+		t.Error("expected display context for vector-only match")
+	}
+}
+
+func TestRerankLearningsHybrid_UsesPersistedVectors(t *testing.T) {
+	tmp := t.TempDir()
+	aoDir := filepath.Join(tmp, ".agents", "ao")
+	learningsDir := filepath.Join(tmp, ".agents", "learnings")
+	for _, dir := range []string{aoDir, learningsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(learningsDir, "migrate.md")
+	if err := os.WriteFile(path, []byte("# Database Migrations\n\nRun migrations inside a transaction.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	learnings := []learning{{Source: path, Title: "Database Migrations", Summary: "Run migrations inside a transaction."}}
+	const query = "auth tokens expiring"
+
+	if got := rerankLearningsHybrid(tmp, query, slices.Clone(learnings)); len(got) != 0 {
+		t.Fatalf("unrelated learning should be dropped without a vector store, got %+v", got)
+	}
+
+	// A stored vector whose hash still matches the file is reused as-is, so
+	// planting the query's own embedding makes the learning relevant.
+	e := search.NewHashEmbedder()
+	vs := search.NewVectorStore(e.Model())
+	if err := search.EmbedFile(vs, e, path); err != nil {
+		t.Fatal(err)
+	}
+	vs.Vectors[path] = e.Embed(query)
+	vecPath := filepath.Join(aoDir, search.DefaultVectorFile)
+	if err := search.SaveVectorStore(vs, vecPath); err != nil {
+		t.Fatal(err)
+	}
+	got := rerankLearningsHybrid(tmp, query, slices.Clone(learnings))
+	if len(got) != 1 || got[0].Relevance <= 0 {
+		t.Fatalf("expected persisted vector to rank the learning, got %+v", got)
+	}
+
+	vs.Model = "other-model"
+	if err := search.SaveVectorStore(vs, vecPath); err != nil {
+		t.Fatal(err)
+	}
+	if got := rerankLearningsHybrid(tmp, query, slices.Clone(learnings)); len(got) != 0 {
+		t.Fatalf("store for another model should be ignored, got %+v", got)
+	}
+}
+
+func TestLearningRerankKeys(t *testing.T) {
+	got := learningRerankKeys([]learning{{Source: "a.md"}, {Source: "b.jsonl"}, {Source: "b.jsonl"}, {}})
+	want := []string{"a.md", "b.jsonl#1", "b.jsonl#2", "#3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("keys = %v, want %v", got, want)
+	}
+}
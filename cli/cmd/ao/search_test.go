@@ -1608,3 +1608,30 @@ func TestSelectAndSearch_IncludesResearchDir(t *testing.T) {
 		t.Error("expected research file in search results, but none found")
 	}
 }
+
+func TestRankRepoLocalResults_PrefersFocusedDocuments(t *testing.T) {
+	tmp := t.TempDir()
+	sessDir := filepath.Join(tmp, "sessions")
+	if err := os.MkdirAll(sessDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	long := filepath.Join(tmp, "long.md")
+	focused := filepath.Join(tmp, "focused.md")
+	if err := os.WriteFile(long, []byte("mutex "+strings.Repeat("release checklist filler text ", 50)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(focused, []byte("mutex ordering: always take the mutex before the map"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := []searchResult{{Path: long}, {Path: focused}}
+	rankRepoLocalResults("mutex", sessDir, results)
+
+	if results[0].Path != focused {
+		t.Errorf("expected focused document first, got %s", results[0].Path)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("expected focused score %f > long score %f", results[0].Score, results[1].Score)
+	}
+}
@@ -277,7 +277,10 @@ func forgeTranscriptForClose(transcriptPath, cwd string) (*storage.Session, erro
 	p := parser.NewParser()
 	p.MaxContentLength = 0
 
-	extractor := parser.NewExtractor()
+	extractor, err := loadForgeExtractor(cwd)
+	if err != nil {
+		return nil, err
+	}
 
 	session, err := processTranscript(transcriptPath, p, extractor, true, os.Stdout)
 	if err != nil {
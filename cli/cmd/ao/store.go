@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -16,12 +17,15 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"github.com/boshu2/agentops/cli/internal/search"
+	"github.com/boshu2/agentops/cli/internal/storage"
 	"github.com/boshu2/agentops/cli/internal/types"
 )
 
 var (
-	storeLimit      int
-	storeCategorize bool
+	storeLimit       int
+	storeCategorize  bool
+	storeIncremental bool
 )
 
 const (
@@ -130,7 +134,8 @@ Examples:
 		Short: "Search the index",
 		Long: `Search for artifacts matching a query.
 
-Returns results ranked by relevance with snippets.
+Returns results ranked by BM25 relevance with snippets. Title and
+tag/keyword matches are boosted; scores are blended with MemRL utility.
 
 Examples:
   ao store search "mutex pattern"
@@ -154,12 +159,22 @@ Scans all .agents/ directories and re-indexes:
   - research/
   - retros/
 
+Also rewrites the BM25 index at .agents/ao/index.jsonl (adding findings/
+and ao/sessions/) used by 'ao search --local', together with a manifest
+of indexed files (path, size, mtime, content hash).
+
+With --incremental, only files whose size, mtime or content changed are
+re-tokenized, deleted files are purged, and added/updated/removed counts
+are reported.
+
 Examples:
   ao store rebuild
+  ao store rebuild --incremental
   ao store rebuild --verbose`,
 		RunE: runStoreRebuild,
 	}
 	rebuildCmd.Flags().BoolVar(&storeCategorize, "categorize", false, "Extract and store category/tags for retrieval")
+	rebuildCmd.Flags().BoolVar(&storeIncremental, "incremental", false, "Only re-index files that changed since the last rebuild")
 	storeCmd.AddCommand(rebuildCmd)
 
 	// stats subcommand
@@ -268,6 +283,10 @@ func runStoreRebuild(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if storeIncremental {
+		return runStoreRebuildIncremental(cwd)
+	}
+
 	// Remove existing index
 	indexPath := filepath.Join(cwd, IndexDir, IndexFileName)
 	if err := os.Remove(indexPath); err != nil && !os.IsNotExist(err) {
@@ -278,6 +297,189 @@ func runStoreRebuild(cmd *cobra.Command, args []string) error {
 	indexed := indexFiles(cwd, files, storeCategorize)
 
 	fmt.Printf("Rebuilt index: %d artifacts\n", indexed)
+
+	report, err := updateSearchIndex(cwd, files, false)
+	if err != nil {
+		return fmt.Errorf("rebuild search index: %w", err)
+	}
+	VerbosePrintf("Rebuilt BM25 search index: %d documents\n", len(report.Added))
+	return nil
+}
+
+// runStoreRebuildIncremental re-tokenizes only artifacts whose size, mtime
+// or content changed since the last rebuild and purges deleted ones.
+func runStoreRebuildIncremental(cwd string) error {
+	files := collectArtifactFiles(cwd)
+
+	report, err := updateSearchIndex(cwd, files, true)
+	if err != nil {
+		return fmt.Errorf("update search index: %w", err)
+	}
+	if err := refreshStoreIndex(cwd, files, report, storeCategorize); err != nil {
+		return fmt.Errorf("update store index: %w", err)
+	}
+
+	fmt.Printf("Updated index: %d added, %d updated, %d removed, %d unchanged\n",
+		len(report.Added), len(report.Updated), len(report.Removed), report.Unchanged)
+	return nil
+}
+
+// updateSearchIndex brings the BM25 index used by `ao search --local` (and
+// kept current by forge) in line with the store artifacts plus findings and
+// forged sessions, then saves it with its manifest. When incremental is
+// false the existing index and manifest are discarded first.
+func updateSearchIndex(cwd string, files []string, incremental bool) (search.UpdateReport, error) {
+	all := slices.Clone(files)
+	for _, dir := range []string{
+		filepath.Join(cwd, ".agents", "findings"),
+		filepath.Join(cwd, storage.DefaultBaseDir, storage.SessionsDir),
+	} {
+		found, err := walkIndexableFiles(dir)
+		if err != nil {
+			VerbosePrintf("Warning: scan %s: %v\n", dir, err)
+		}
+		all = append(all, found...)
+	}
+
+	idxPath := filepath.Join(cwd, storage.DefaultBaseDir, search.DefaultIndexFile)
+	manifestPath := search.ManifestPath(idxPath)
+
+	idx, manifest := search.NewIndex(), search.NewManifest()
+	if incremental {
+		idx, manifest = loadSearchIndexState(idxPath, manifestPath)
+	}
+
+	report := search.IncrementalUpdate(idx, manifest, all)
+	if err := search.SaveIndex(idx, idxPath); err != nil {
+		return report, err
+	}
+	if err := search.SaveManifest(manifest, manifestPath); err != nil {
+		return report, err
+	}
+	return report, updateVectorStore(cwd, idx, report, incremental)
+}
+
+// updateVectorStore keeps .agents/ao/vectors.jsonl in step with the search
+// index: changed documents are re-embedded, removed ones dropped, and any
+// indexed document without a vector (e.g. after a model change) embedded.
+func updateVectorStore(cwd string, idx *search.Index, report search.UpdateReport, incremental bool) error {
+	embedder := search.NewHashEmbedder()
+	vecPath := filepath.Join(cwd, storage.DefaultBaseDir, search.DefaultVectorFile)
+
+	vs := search.NewVectorStore(embedder.Model())
+	if incremental {
+		loaded, err := search.LoadVectorStore(vecPath, embedder)
+		if err != nil {
+			VerbosePrintf("Warning: load vector store: %v\n", err)
+		} else {
+			vs = loaded
+		}
+	}
+
+	for _, path := range report.Removed {
+		search.RemoveVector(vs, path)
+	}
+	for path := range vs.Vectors {
+		if _, ok := idx.Lengths[path]; !ok {
+			search.RemoveVector(vs, path)
+		}
+	}
+
+	changed := make(map[string]bool, len(report.Added)+len(report.Updated))
+	for _, path := range slices.Concat(report.Added, report.Updated) {
+		changed[path] = true
+	}
+	for path := range idx.Lengths {
+		if _, ok := vs.Vectors[path]; ok && !changed[path] {
+			continue
+		}
+		if err := search.EmbedFile(vs, embedder, path); err != nil {
+			VerbosePrintf("Warning: embed %s: %v\n", path, err)
+		}
+	}
+
+	return search.SaveVectorStore(vs, vecPath)
+}
+
+// loadSearchIndexState loads the persisted index and manifest, falling back
+// to empty ones (a full re-index) when either cannot be read.
+func loadSearchIndexState(idxPath, manifestPath string) (*search.Index, *search.Manifest) {
+	manifest, err := search.LoadManifest(manifestPath)
+	if err != nil {
+		VerbosePrintf("Warning: load index manifest: %v\n", err)
+		return search.NewIndex(), search.NewManifest()
+	}
+	if _, err := os.Stat(idxPath); os.IsNotExist(err) {
+		return search.NewIndex(), search.NewManifest()
+	}
+	idx, err := search.LoadIndex(idxPath)
+	if err != nil {
+		VerbosePrintf("Warning: load search index: %v\n", err)
+		return search.NewIndex(), search.NewManifest()
+	}
+	return idx, manifest
+}
+
+// refreshStoreIndex rewrites the store index keeping entries for unchanged
+// artifacts and re-creating entries for added, updated or missing ones.
+// Entries for files outside the current artifact set are dropped.
+func refreshStoreIndex(cwd string, files []string, report search.UpdateReport, categorize bool) error {
+	entries, err := loadStoreEntries(cwd)
+	if err != nil {
+		entries = nil // missing or unreadable store index: recreate every entry
+	}
+
+	stale := make(map[string]bool, len(report.Added)+len(report.Updated)+len(report.Removed))
+	for _, list := range [][]string{report.Added, report.Updated, report.Removed} {
+		for _, path := range list {
+			stale[path] = true
+		}
+	}
+	current := make(map[string]bool, len(files))
+	for _, path := range files {
+		current[path] = true
+	}
+
+	kept := make(map[string]bool, len(entries))
+	var buf strings.Builder
+	for _, entry := range entries {
+		if !current[entry.Path] || stale[entry.Path] || kept[entry.Path] {
+			continue
+		}
+		kept[entry.Path] = true
+		if err := writeStoreEntry(&buf, &entry); err != nil {
+			return err
+		}
+	}
+	for _, path := range files {
+		if kept[path] {
+			continue
+		}
+		entry, err := createIndexEntry(path, categorize)
+		if err != nil {
+			VerbosePrintf("Warning: skip %s: %v\n", filepath.Base(path), err)
+			continue
+		}
+		if err := writeStoreEntry(&buf, entry); err != nil {
+			return err
+		}
+	}
+
+	indexDir := filepath.Join(cwd, IndexDir)
+	if err := os.MkdirAll(indexDir, 0750); err != nil {
+		return err
+	}
+	return atomicWriteFile(filepath.Join(indexDir, IndexFileName), []byte(buf.String()), 0600)
+}
+
+// writeStoreEntry appends one JSONL-encoded store index entry to buf.
+func writeStoreEntry(buf *strings.Builder, entry *IndexEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	buf.WriteByte('\n')
 	return nil
 }
 
@@ -463,43 +665,37 @@ func appendToIndex(baseDir string, entry *IndexEntry) error {
 	return err
 }
 
-// searchIndex searches the index for matching entries.
+// searchIndex searches the index for matching entries, ranking them with
+// BM25 over title, tags/keywords and content.
 func searchIndex(baseDir, query string, limit int) ([]SearchResult, error) {
-	indexPath := filepath.Join(baseDir, IndexDir, IndexFileName)
-
-	f, err := os.Open(indexPath)
-	if os.IsNotExist(err) {
-		return nil, fmt.Errorf("index not found - run 'ao store rebuild' first")
-	}
+	entries, err := loadStoreEntries(baseDir)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		_ = f.Close() //nolint:errcheck // read-only index search, close error non-fatal
-	}()
-
-	queryTerms := strings.Fields(strings.ToLower(query))
-	var results []SearchResult
 
-	scanner := bufio.NewScanner(f)
-	// Increase buffer size for large entries
-	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
+	// Entries are keyed by position: paths may repeat or be empty when the
+	// same artifact was indexed more than once.
+	idx := search.NewIndex()
+	for i, entry := range entries {
+		search.AddDocument(idx, strconv.Itoa(i), search.Document{
+			Title: entry.Title,
+			Tags:  append(slices.Clone(entry.Tags), entry.Keywords...),
+			Body:  entry.Content,
+		})
+	}
 
-	for scanner.Scan() {
-		var entry IndexEntry
-		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+	var results []SearchResult
+	for _, hit := range search.Search(idx, query, 0) {
+		i, err := strconv.Atoi(hit.Path)
+		if err != nil {
 			continue
 		}
-
-		score := computeSearchScore(entry, queryTerms)
-		if score > 0 {
-			snippet := createSearchSnippet(entry.Content, query, 150)
-			results = append(results, SearchResult{
-				Entry:   entry,
-				Score:   score,
-				Snippet: snippet,
-			})
-		}
+		entry := entries[i]
+		results = append(results, SearchResult{
+			Entry:   entry,
+			Score:   applyUtilityBoost(hit.Score, entry.Utility),
+			Snippet: createSearchSnippet(entry.Content, query, 150),
+		})
 	}
 
 	// Sort by score (descending) then by utility (descending)
@@ -515,43 +711,48 @@ func searchIndex(baseDir, query string, limit int) ([]SearchResult, error) {
 		results = results[:limit]
 	}
 
-	return results, scanner.Err()
+	return results, nil
 }
 
-// computeSearchScore calculates relevance score for a query.
-func computeSearchScore(entry IndexEntry, queryTerms []string) float64 {
-	var score float64
+// loadStoreEntries reads every well-formed entry from the store index.
+func loadStoreEntries(baseDir string) ([]IndexEntry, error) {
+	indexPath := filepath.Join(baseDir, IndexDir, IndexFileName)
 
-	lowerContent := strings.ToLower(entry.Content)
-	lowerTitle := strings.ToLower(entry.Title)
+	f, err := os.Open(indexPath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("index not found - run 'ao store rebuild' first")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only index search, close error non-fatal
+	}()
 
-	for _, term := range queryTerms {
-		// Title matches are worth more
-		if strings.Contains(lowerTitle, term) {
-			score += 3.0
-		}
+	var entries []IndexEntry
 
-		// Content matches
-		if strings.Contains(lowerContent, term) {
-			score += 1.0
-		}
+	scanner := bufio.NewScanner(f)
+	// Increase buffer size for large entries
+	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
 
-		// Keyword matches
-		for _, kw := range entry.Keywords {
-			if strings.Contains(strings.ToLower(kw), term) {
-				score += 2.0
-				break
-			}
+	for scanner.Scan() {
+		var entry IndexEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
 		}
+		entries = append(entries, entry)
 	}
 
-	// Boost by utility (MemRL integration)
-	// Lambda = 0.5 (balanced weighting)
+	return entries, scanner.Err()
+}
+
+// applyUtilityBoost blends a relevance score with the MemRL utility.
+// Lambda = 0.5 (balanced weighting)
+func applyUtilityBoost(score, utility float64) float64 {
 	lambda := types.DefaultLambda
-	if entry.Utility > 0 {
-		score = (1-lambda)*score + lambda*entry.Utility*score
+	if utility > 0 {
+		score = (1-lambda)*score + lambda*utility*score
 	}
-
 	return score
 }
 
@@ -8,106 +8,60 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/boshu2/agentops/cli/internal/search"
 )
 
-func TestComputeSearchScore(t *testing.T) {
+func TestApplyUtilityBoost(t *testing.T) {
 	tests := []struct {
-		name       string
-		entry      IndexEntry
-		queryTerms []string
-		wantMin    float64
+		name    string
+		score   float64
+		utility float64
+		want    float64
 	}{
-		{
-			name: "title match scores highest",
-			entry: IndexEntry{
-				Title:   "Mutex Pattern",
-				Content: "unrelated content",
-			},
-			queryTerms: []string{"mutex"},
-			wantMin:    3.0, // title match = 3.0
-		},
-		{
-			name: "content match",
-			entry: IndexEntry{
-				Title:   "Some Title",
-				Content: "Use mutex for shared state",
-			},
-			queryTerms: []string{"mutex"},
-			wantMin:    1.0, // content match = 1.0
-		},
-		{
-			name: "keyword match",
-			entry: IndexEntry{
-				Title:    "Title",
-				Content:  "content",
-				Keywords: []string{"mutex", "concurrency"},
-			},
-			queryTerms: []string{"mutex"},
-			wantMin:    2.0, // keyword match = 2.0
-		},
-		{
-			name: "all three match",
-			entry: IndexEntry{
-				Title:    "Mutex Pattern",
-				Content:  "Use mutex for shared state",
-				Keywords: []string{"mutex"},
-			},
-			queryTerms: []string{"mutex"},
-			wantMin:    6.0, // 3 + 1 + 2 = 6.0
-		},
-		{
-			name: "no match",
-			entry: IndexEntry{
-				Title:   "Database Pattern",
-				Content: "pooling connections",
-			},
-			queryTerms: []string{"auth"},
-			wantMin:    0.0,
-		},
-		{
-			name: "utility boost",
-			entry: IndexEntry{
-				Title:   "Mutex Pattern",
-				Content: "content",
-				Utility: 0.9,
-			},
-			queryTerms: []string{"mutex"},
-			// title match (3.0) weighted by utility: (1-0.5)*3 + 0.5*0.9*3 = 1.5 + 1.35 = 2.85
-			wantMin: 2.8,
-		},
-		{
-			name: "multiple query terms",
-			entry: IndexEntry{
-				Title:   "Mutex Pattern for Go",
-				Content: "Use mutex and channels for concurrency",
-			},
-			queryTerms: []string{"mutex", "channels"},
-			wantMin:    4.0, // mutex: title(3) + content(1), channels: content(1) = 5.0
-		},
+		{"no utility leaves score", 3.0, 0, 3.0},
+		// (1-0.5)*3 + 0.5*0.9*3 = 1.5 + 1.35 = 2.85
+		{"utility blends score", 3.0, 0.9, 2.85},
+		{"zero score stays zero", 0, 0.9, 0},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := computeSearchScore(tt.entry, tt.queryTerms)
-			if got < tt.wantMin {
-				t.Errorf("computeSearchScore() = %v, want >= %v", got, tt.wantMin)
+			got := applyUtilityBoost(tt.score, tt.utility)
+			if diff := got - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("applyUtilityBoost(%v, %v) = %v, want %v", tt.score, tt.utility, got, tt.want)
 			}
 		})
 	}
+}
 
-	// Test relative ordering
-	t.Run("title match ranks higher than content match", func(t *testing.T) {
-		titleEntry := IndexEntry{Title: "Mutex Pattern", Content: "something else"}
-		contentEntry := IndexEntry{Title: "Something", Content: "mutex is useful"}
-
-		titleScore := computeSearchScore(titleEntry, []string{"mutex"})
-		contentScore := computeSearchScore(contentEntry, []string{"mutex"})
-
-		if titleScore <= contentScore {
-			t.Errorf("title match (%.2f) should rank higher than content match (%.2f)",
-				titleScore, contentScore)
+func TestSearchIndex_BM25Ranking(t *testing.T) {
+	tmp := t.TempDir()
+	filler := strings.Repeat("deployment pipeline notes and unrelated filler ", 30)
+	entries := []*IndexEntry{
+		{Path: "/long.md", ID: "long", Title: "Deploy Notes", Content: "mutex " + filler},
+		{Path: "/focused.md", ID: "focused", Title: "Locking", Content: "hold the mutex briefly; a mutex held across IO stalls callers"},
+		{Path: "/titled.md", ID: "titled", Title: "Mutex", Content: "guidance on shared state with one mention of mutex"},
+	}
+	for _, e := range entries {
+		if err := appendToIndex(tmp, e); err != nil {
+			t.Fatalf("append: %v", err)
 		}
-	})
+	}
+
+	results, err := searchIndex(tmp, "mutex", 10)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Entry.ID != "titled" {
+		t.Errorf("title boost should rank 'titled' first, got %q", results[0].Entry.ID)
+	}
+	if results[2].Entry.ID != "long" {
+		t.Errorf("long document with a single mention should rank last, got %q", results[2].Entry.ID)
+	}
 }
 
 func TestExtractTitle(t *testing.T) {
@@ -610,6 +564,111 @@ func TestStoreCoverage_IndexFiles(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// updateSearchIndex
+// ---------------------------------------------------------------------------
+
+func TestStoreCoverage_RebuildSearchIndex(t *testing.T) {
+	tmp := t.TempDir()
+
+	learning := filepath.Join(tmp, ".agents", "learnings", "l1.md")
+	finding := filepath.Join(tmp, ".agents", "findings", "f1.md")
+	session := filepath.Join(tmp, ".agents", "ao", "sessions", "s1.md")
+	for _, path := range []string{learning, finding, session} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("mutex guidance"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := updateSearchIndex(tmp, collectArtifactFiles(tmp), false)
+	if err != nil {
+		t.Fatalf("updateSearchIndex: %v", err)
+	}
+	if len(report.Added) != 3 {
+		t.Errorf("indexed %d documents, want 3", len(report.Added))
+	}
+
+	idx, err := search.LoadIndex(filepath.Join(tmp, ".agents", "ao", search.DefaultIndexFile))
+	if err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	if got := len(search.Search(idx, "mutex", 0)); got != 3 {
+		t.Errorf("expected 3 hits from rebuilt index, got %d", got)
+	}
+
+	vs, err := search.LoadVectorStore(filepath.Join(tmp, ".agents", "ao", search.DefaultVectorFile), search.NewHashEmbedder())
+	if err != nil {
+		t.Fatalf("LoadVectorStore: %v", err)
+	}
+	if len(vs.Vectors) != 3 {
+		t.Errorf("expected 3 embedded documents, got %d", len(vs.Vectors))
+	}
+}
+
+func TestStoreCoverage_RebuildIncremental(t *testing.T) {
+	tmp := t.TempDir()
+	chdirTempWorkspace(t, tmp)
+
+	dir := filepath.Join(tmp, ".agents", "learnings")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	keep := filepath.Join(dir, "keep.md")
+	edit := filepath.Join(dir, "edit.md")
+	gone := filepath.Join(dir, "gone.md")
+	for path, content := range map[string]string{keep: "# Keep\nstable", edit: "# Edit\nalpha", gone: "# Gone\nomega"} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldIncremental := storeIncremental
+	t.Cleanup(func() { storeIncremental = oldIncremental })
+
+	storeIncremental = false
+	if err := runStoreRebuild(nil, nil); err != nil {
+		t.Fatalf("full rebuild: %v", err)
+	}
+
+	if err := os.WriteFile(edit, []byte("# Edit\nbeta"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(2 * time.Second)
+	if err := os.Chtimes(edit, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	storeIncremental = true
+	out, err := captureStdout(t, func() error { return runStoreRebuild(nil, nil) })
+	if err != nil {
+		t.Fatalf("incremental rebuild: %v", err)
+	}
+	if !strings.Contains(out, "0 added, 1 updated, 1 removed, 1 unchanged") {
+		t.Errorf("unexpected incremental summary: %q", out)
+	}
+
+	entries, err := loadStoreEntries(tmp)
+	if err != nil {
+		t.Fatalf("loadStoreEntries: %v", err)
+	}
+	byPath := make(map[string]IndexEntry, len(entries))
+	for _, e := range entries {
+		byPath[e.Path] = e
+	}
+	if len(byPath) != 2 || len(entries) != 2 {
+		t.Fatalf("expected 2 store entries, got %d", len(entries))
+	}
+	if !strings.Contains(byPath[edit].Content, "beta") {
+		t.Errorf("expected edited entry to be refreshed, got %q", byPath[edit].Content)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // printSearchResults (smoke test — just ensure no panic)
 // ---------------------------------------------------------------------------
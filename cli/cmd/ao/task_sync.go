@@ -615,7 +615,8 @@ func processSingleTaskFeedback(cwd string, task TaskEvent) bool {
 		return false
 	}
 
-	oldUtility, newUtility, err := updateLearningUtility(learningPath, completionReward, types.DefaultAlpha)
+	oldUtility, newUtility, err := updateLearningUtilityRecorded(cwd, learningPath, completionReward, types.DefaultAlpha,
+		utilityOrigin{Source: "task-sync", SessionID: task.SessionID})
 	if err != nil {
 		VerbosePrintf("Warning: failed to update %s: %v\n", learningPath, err)
 		return false
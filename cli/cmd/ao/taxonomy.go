@@ -0,0 +1,127 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+var taxonomyCmd = &cobra.Command{
+	Use:   "taxonomy",
+	Short: "Inspect knowledge types, tiers and scoring weights",
+	Long: `Inspect the knowledge taxonomy used to score pool candidates.
+
+The built-in taxonomy defines knowledge types with base scores, quality tier
+thresholds with the confidence and human-gate settings of each tier, and the
+rubric weights. A repo can override any of them in .agents/taxonomy.yaml:
+
+  knowledge_types:
+    decision: {base_score: 0.95}
+    gotcha: {base_score: 0.4, description: "Surprising behavior"}
+  tiers:
+    gold: {min_score: 0.8}
+    bronze: {human_gate: false}
+  weights:
+    specificity: 0.35
+    actionability: 0.20
+
+A tier without max_score ends where the tier above it starts. Weights must
+sum to 1.0 and tiers must cover 0 to 1 without gaps. ao pool ingest and the
+pending learnings ao forge writes use the repo taxonomy.
+
+Examples:
+  ao taxonomy show
+  ao taxonomy show -o json`,
+}
+
+var taxonomyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective taxonomy",
+	Long: `Print the effective taxonomy with .agents/taxonomy.yaml overrides applied.
+
+Without a taxonomy file the built-in defaults are shown.
+
+Examples:
+  ao taxonomy show
+  ao taxonomy show -o json`,
+	Args: cobra.NoArgs,
+	RunE: runTaxonomyShow,
+}
+
+func init() {
+	taxonomyCmd.GroupID = "config"
+	rootCmd.AddCommand(taxonomyCmd)
+	taxonomyCmd.AddCommand(taxonomyShowCmd)
+}
+
+func runTaxonomyShow(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+	tax, err := taxonomy.Load(cwd)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if GetOutput() == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tax)
+	}
+	return printTaxonomy(w, tax)
+}
+
+// printTaxonomy prints knowledge types by base score, tiers from gold down
+// and the rubric weights.
+func printTaxonomy(w io.Writer, tax *taxonomy.Taxonomy) error {
+	fmt.Fprintf(w, "Source: %s\n\n", cmp.Or(tax.Source, "built-in defaults")) //nolint:errcheck // CLI output
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tBASE SCORE\tDESCRIPTION") //nolint:errcheck // CLI tabwriter output
+	kts := slices.SortedFunc(maps.Keys(tax.KnowledgeTypes), func(a, b types.KnowledgeType) int {
+		return cmp.Or(
+			cmp.Compare(tax.KnowledgeTypes[b].BaseScore, tax.KnowledgeTypes[a].BaseScore),
+			cmp.Compare(a, b),
+		)
+	})
+	for _, kt := range kts {
+		info := tax.KnowledgeTypes[kt]
+		fmt.Fprintf(tw, "%s\t%.2f\t%s\n", kt, info.BaseScore, displayOrDash(info.Description)) //nolint:errcheck // CLI tabwriter output
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w) //nolint:errcheck // CLI output
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIER\tSCORE\tCONFIDENCE\tHUMAN GATE") //nolint:errcheck // CLI tabwriter output
+	for _, tier := range taxonomy.TierOrder {
+		cfg := tax.Tiers[tier]
+		gate := "no"
+		if cfg.HumanGateRequired {
+			gate = fmt.Sprintf("yes (sample %.0f%%)", cfg.HumanGateSampleRate*100)
+		}
+		fmt.Fprintf(tw, "%s\t%.2f-%.2f\t%.2f\t%s\n", tier, cfg.MinScore, math.Min(cfg.MaxScore, 1.0), cfg.Confidence, gate) //nolint:errcheck // CLI tabwriter output
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	wt := tax.Weights
+	fmt.Fprintf(w, "\nWeights: specificity %.2f, actionability %.2f, novelty %.2f, context %.2f, confidence %.2f\n", //nolint:errcheck // CLI output
+		wt.Specificity, wt.Actionability, wt.Novelty, wt.Context, wt.Confidence)
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boshu2/agentops/cli/internal/pool"
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+func writeTestTaxonomy(t *testing.T, dir, content string) *taxonomy.Taxonomy {
+	t.Helper()
+	path := filepath.Join(dir, taxonomy.TaxonomyFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tax, err := taxonomy.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return tax
+}
+
+func TestTaxonomyShow_Defaults(t *testing.T) {
+	chdirTemp(t)
+
+	out, err := executeCommand("taxonomy", "show")
+	if err != nil {
+		t.Fatalf("taxonomy show: %v", err)
+	}
+	for _, want := range []string{"Source: built-in defaults", "solution", "gold", "yes (sample 5%)", "specificity 0.30"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTaxonomyShow_JSONWithOverrides(t *testing.T) {
+	dir := chdirTemp(t)
+	writeTestTaxonomy(t, dir, "knowledge_types:\n  gotcha: {base_score: 0.4}\n")
+
+	out, err := executeCommand("taxonomy", "show", "-o", "json")
+	if err != nil {
+		t.Fatalf("taxonomy show: %v", err)
+	}
+	var got taxonomy.Taxonomy
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if !strings.HasSuffix(got.Source, taxonomy.TaxonomyFile) {
+		t.Errorf("Source = %q", got.Source)
+	}
+	if got.KnowledgeTypes["gotcha"].BaseScore != 0.4 {
+		t.Errorf("gotcha = %+v", got.KnowledgeTypes["gotcha"])
+	}
+}
+
+func TestTaxonomyShow_InvalidFile(t *testing.T) {
+	dir := chdirTemp(t)
+	path := filepath.Join(dir, taxonomy.TaxonomyFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("weights: {specificity: 0.9}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executeCommand("taxonomy", "show"); err == nil || !strings.Contains(err.Error(), "must sum to 1.0") {
+		t.Errorf("err = %v, want weight sum error", err)
+	}
+}
+
+func TestBuildCandidate_UsesRepoTaxonomy(t *testing.T) {
+	tax := writeTestTaxonomy(t, t.TempDir(), `knowledge_types:
+  gotcha: {base_score: 0.3}
+tiers:
+  bronze: {human_gate: false}
+`)
+	b := learningBlock{
+		Title:      "Surprising flag default",
+		ID:         "L1",
+		Category:   "gotcha",
+		Confidence: "high",
+		Body:       "## What We Learned\n\nThe `--force` flag defaults to true in `cmd/ao/pool.go`.\n",
+	}
+	cand, scoring, ok := buildCandidateFromLearningBlock(b, "/test/file.md", time.Now(), "ag-xyz", tax)
+	if !ok {
+		t.Fatal("expected ok=true")
+	}
+	if cand.Type != "gotcha" {
+		t.Errorf("Type = %q, want gotcha", cand.Type)
+	}
+	if cand.Tier != tax.AssignTier(cand.RawScore) {
+		t.Errorf("Tier = %s, want %s", cand.Tier, tax.AssignTier(cand.RawScore))
+	}
+	if cand.Tier == types.TierBronze && scoring.GateRequired {
+		t.Error("bronze human gate is disabled by the taxonomy")
+	}
+}
+
+func TestPendingCategory_CustomType(t *testing.T) {
+	tax := writeTestTaxonomy(t, t.TempDir(), "knowledge_types:\n  gotcha: {base_score: 0.3}\n")
+
+	if got := pendingCategory("Gotcha: the fix only applies on linux", tax); got != "gotcha" {
+		t.Errorf("pendingCategory = %q, want gotcha", got)
+	}
+	if got := pendingCategory("Gotcha: the fix only applies on linux", taxonomy.Default()); got != "solution" {
+		t.Errorf("pendingCategory without taxonomy = %q, want solution", got)
+	}
+}
+
+func TestFindTaxonomyDrift(t *testing.T) {
+	entries := []pool.PoolEntry{
+		{PoolEntry: types.PoolEntry{
+			Candidate:     types.Candidate{ID: "moved"},
+			ScoringResult: types.Scoring{RawScore: 0.82, TierAssignment: types.TierSilver},
+		}},
+		{PoolEntry: types.PoolEntry{
+			Candidate:     types.Candidate{ID: "same"},
+			ScoringResult: types.Scoring{RawScore: 0.6, TierAssignment: types.TierBronze},
+		}},
+	}
+
+	if drift := findTaxonomyDrift(entries, taxonomy.Default()); drift != nil {
+		t.Errorf("defaults should report no drift, got %+v", drift)
+	}
+
+	tax := writeTestTaxonomy(t, t.TempDir(), "tiers:\n  gold: {min_score: 0.8}\n")
+	drift := findTaxonomyDrift(entries, tax)
+	if len(drift) != 1 || drift[0].ID != "moved" || drift[0].NowTier != types.TierGold {
+		t.Errorf("drift = %+v, want moved silver -> gold", drift)
+	}
+}
+
+func TestPoolList_TaxonomyFilters(t *testing.T) {
+	dir := t.TempDir()
+	writeTestTaxonomy(t, dir, "knowledge_types:\n  gotcha: {base_score: 0.4}\n")
+	p := pool.NewPool(dir)
+	for id, kt := range map[string]types.KnowledgeType{"cand-gotcha": "gotcha", "cand-decision": types.KnowledgeTypeDecision} {
+		candidate := types.Candidate{ID: id, Tier: types.TierBronze, Type: kt, Content: "Content of " + id}
+		if err := p.Add(candidate, types.Scoring{RawScore: 0.6}); err != nil {
+			t.Fatalf("Add %s: %v", id, err)
+		}
+	}
+	t.Chdir(dir)
+
+	oldOutput, oldTier, oldType, oldWide := output, poolTier, poolType, poolWide
+	t.Cleanup(func() { output, poolTier, poolType, poolWide = oldOutput, oldTier, oldType, oldWide })
+	output, poolWide = "table", true
+
+	poolType = "gotcha"
+	out, err := captureStdout(t, func() error { return poolListCmd.RunE(poolListCmd, nil) })
+	if err != nil {
+		t.Fatalf("pool list --type=gotcha: %v", err)
+	}
+	if !strings.Contains(out, "TYPE") || !strings.Contains(out, "cand-gotcha") || strings.Contains(out, "cand-decision") {
+		t.Errorf("expected only the custom-type entry with a TYPE column, got:\n%s", out)
+	}
+
+	for _, tc := range []struct{ tier, kt, want string }{
+		{"", "rumor", `unknown knowledge type "rumor"`},
+		{"platinum", "", `unknown tier "platinum"`},
+	} {
+		poolTier, poolType = tc.tier, tc.kt
+		if err := poolListCmd.RunE(poolListCmd, nil); err == nil || !strings.Contains(err.Error(), tc.want) || !strings.Contains(err.Error(), "valid:") {
+			t.Errorf("tier=%q type=%q: err = %v, want %q", tc.tier, tc.kt, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// UtilityHistoryFilePath is the relative path to the utility history log.
+// Learning files only keep their current utility; every update is also
+// appended here so the trajectory survives.
+const UtilityHistoryFilePath = ".agents/ao/utility-history.jsonl"
+
+// UtilityHistoryEvent records one utility update of one learning.
+type UtilityHistoryEvent struct {
+	LearningID   string    `json:"learning_id"`
+	ArtifactPath string    `json:"artifact_path"`
+	OldUtility   float64   `json:"old_utility"`
+	NewUtility   float64   `json:"new_utility"`
+	Alpha        float64   `json:"alpha"`
+	Reward       float64   `json:"reward"`
+	SessionID    string    `json:"session_id,omitempty"`
+	CitationType string    `json:"citation_type,omitempty"`
+	Source       string    `json:"source"` // feedback, feedback-loop, flywheel, task-sync, recalibrate
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// utilityOrigin says which command updated a utility and on whose behalf.
+type utilityOrigin struct {
+	Source       string
+	SessionID    string
+	CitationType string
+}
+
+// updateLearningUtilityRecorded applies the EMA update like
+// updateLearningUtility and appends the change to the utility history.
+// A history write failure is logged, not returned: the utility update
+// itself already happened.
+func updateLearningUtilityRecorded(baseDir, path string, reward, alpha float64, origin utilityOrigin) (oldUtility, newUtility float64, err error) {
+	oldUtility, newUtility, err = updateLearningUtility(path, reward, alpha)
+	if err != nil {
+		return oldUtility, newUtility, err
+	}
+	event := UtilityHistoryEvent{
+		LearningID:   extractLearningID(path),
+		ArtifactPath: canonicalArtifactPath(baseDir, path),
+		OldUtility:   oldUtility,
+		NewUtility:   newUtility,
+		Alpha:        alpha,
+		Reward:       reward,
+		SessionID:    origin.SessionID,
+		CitationType: origin.CitationType,
+		Source:       origin.Source,
+		RecordedAt:   time.Now(),
+	}
+	if err := appendUtilityHistory(baseDir, event); err != nil {
+		VerbosePrintf("Warning: failed to record utility history for %s: %v\n", filepath.Base(path), err)
+	}
+	return oldUtility, newUtility, nil
+}
+
+// appendUtilityHistory appends events to the utility history log.
+func appendUtilityHistory(baseDir string, events ...UtilityHistoryEvent) error {
+	historyPath := filepath.Join(baseDir, UtilityHistoryFilePath)
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0750); err != nil {
+		return fmt.Errorf("create utility history directory: %w", err)
+	}
+	f, err := os.OpenFile(historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("open utility history: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // write-only file, Close error non-actionable
+
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("marshal utility history event: %w", err)
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("write utility history: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadUtilityHistory reads the utility history log in recorded order.
+// A missing log is an empty history; malformed lines are skipped.
+func loadUtilityHistory(baseDir string) ([]UtilityHistoryEvent, error) {
+	f, err := os.Open(filepath.Join(baseDir, UtilityHistoryFilePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open utility history: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // read-only file
+
+	var events []UtilityHistoryEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event UtilityHistoryEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read utility history: %w", err)
+	}
+	return events, nil
+}
+
+// utilityHistoryFor returns the events of one learning, matched by its
+// resolved path when the file still exists and by ID otherwise.
+func utilityHistoryFor(baseDir string, events []UtilityHistoryEvent, learningID, learningPath string) []UtilityHistoryEvent {
+	pathKey := ""
+	if learningPath != "" {
+		pathKey = canonicalArtifactKey(baseDir, learningPath)
+	}
+	var matched []UtilityHistoryEvent
+	for _, e := range events {
+		switch {
+		case pathKey != "" && canonicalArtifactKey(baseDir, e.ArtifactPath) == pathKey,
+			e.LearningID == learningID,
+			strings.TrimSuffix(e.LearningID, filepath.Ext(e.LearningID)) == learningID:
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// sparklineBlocks renders utility on a fixed 0..1 scale, so sparklines of
+// different learnings are comparable.
+var sparklineBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// utilitySparkline draws the starting utility followed by every update.
+func utilitySparkline(events []UtilityHistoryEvent) string {
+	if len(events) == 0 {
+		return ""
+	}
+	values := make([]float64, 0, len(events)+1)
+	values = append(values, events[0].OldUtility)
+	for _, e := range events {
+		values = append(values, e.NewUtility)
+	}
+	var sb strings.Builder
+	top := len(sparklineBlocks) - 1
+	for _, v := range values {
+		idx := int(math.Round(math.Max(0, math.Min(1, v)) * float64(top)))
+		sb.WriteRune(sparklineBlocks[idx])
+	}
+	return sb.String()
+}
+
+// maxUtilityMovers caps each list in the metrics report.
+const maxUtilityMovers = 5
+
+// populateUtilityMovers fills metrics.UtilityMovers from the utility history
+// of the last days days. A missing or unreadable history leaves it nil.
+func populateUtilityMovers(baseDir string, days int, metrics *types.FlywheelMetrics) {
+	if metrics == nil {
+		return
+	}
+	events, err := loadUtilityHistory(baseDir)
+	if err != nil {
+		VerbosePrintf("Warning: utility history: %v\n", err)
+		return
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	metrics.UtilityMovers = computeUtilityMovers(baseDir, events, since, maxUtilityMovers)
+}
+
+// computeUtilityMovers finds the learnings whose utility rose or fell the
+// most between since and now, keeping at most limit of each.
+func computeUtilityMovers(baseDir string, events []UtilityHistoryEvent, since time.Time, limit int) *types.UtilityMovers {
+	byLearning := make(map[string]*types.UtilityMover)
+	var order []string
+	for _, e := range events {
+		if e.RecordedAt.Before(since) {
+			continue
+		}
+		key := canonicalArtifactKey(baseDir, e.ArtifactPath)
+		m, ok := byLearning[key]
+		if !ok {
+			m = &types.UtilityMover{LearningID: e.LearningID, From: e.OldUtility}
+			byLearning[key] = m
+			order = append(order, key)
+		}
+		m.To = e.NewUtility
+		m.Updates++
+	}
+	if len(byLearning) == 0 {
+		return nil
+	}
+
+	movers := &types.UtilityMovers{}
+	for _, key := range order {
+		m := byLearning[key]
+		m.Delta = m.To - m.From
+		switch {
+		case m.Delta > 0:
+			movers.MostImproved = append(movers.MostImproved, *m)
+		case m.Delta < 0:
+			movers.MostDegraded = append(movers.MostDegraded, *m)
+		}
+	}
+	slices.SortStableFunc(movers.MostImproved, func(a, b types.UtilityMover) int { return cmp.Compare(b.Delta, a.Delta) })
+	slices.SortStableFunc(movers.MostDegraded, func(a, b types.UtilityMover) int { return cmp.Compare(a.Delta, b.Delta) })
+	movers.MostImproved = movers.MostImproved[:min(limit, len(movers.MostImproved))]
+	movers.MostDegraded = movers.MostDegraded[:min(limit, len(movers.MostDegraded))]
+	return movers
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+func writeUtilityLearning(t *testing.T, dir, name string) string {
+	t.Helper()
+	learningsDir := filepath.Join(dir, ".agents", "learnings")
+	if err := os.MkdirAll(learningsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(learningsDir, name)
+	if err := os.WriteFile(path, []byte("---\nutility: 0.5\nmaturity: provisional\n---\n# Learning\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFeedbackLoop_RecordsUtilityHistory(t *testing.T) {
+	dir := t.TempDir()
+	path := writeUtilityLearning(t, dir, "L001.md")
+	citations := []types.CitationEvent{{ArtifactPath: path, SessionID: "s1", CitationType: "applied", CitedAt: time.Now()}}
+
+	processUniqueCitations(dir, "s1", "", citations, 1.0, 0.2)
+	processUniqueCitations(dir, "s2", "", citations, 0.0, 0.2)
+
+	all, err := loadUtilityHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := utilityHistoryFor(dir, all, "L001", "")
+	if len(events) != 2 {
+		t.Fatalf("got %d history events, want 2: %+v", len(events), all)
+	}
+	first, second := events[0], events[1]
+	if first.Source != "feedback-loop" || first.SessionID != canonicalSessionID("s1") || first.CitationType != "applied" {
+		t.Errorf("first event = %+v", first)
+	}
+	if first.OldUtility != 0.5 || first.NewUtility <= 0.5 || first.Alpha != 0.2 || first.Reward != 1.0 {
+		t.Errorf("first event values = %+v", first)
+	}
+	if math.Abs(second.OldUtility-first.NewUtility) > 1e-4 || second.NewUtility >= second.OldUtility {
+		t.Errorf("second event should continue the trajectory downwards: %+v", second)
+	}
+	if got := utilitySparkline(events); len([]rune(got)) != 3 {
+		t.Errorf("sparkline %q should have one point per value", got)
+	}
+}
+
+func TestUtilitySparkline(t *testing.T) {
+	events := []UtilityHistoryEvent{
+		{OldUtility: 0, NewUtility: 0.5},
+		{OldUtility: 0.5, NewUtility: 1},
+		{OldUtility: 1, NewUtility: 1.4}, // clamped
+	}
+	if got := utilitySparkline(events); got != "▁▅██" {
+		t.Errorf("sparkline = %q, want ▁▅██", got)
+	}
+}
+
+func TestComputeUtilityMovers(t *testing.T) {
+	now := time.Now()
+	since := now.AddDate(0, 0, -7)
+	event := func(id string, old, new float64, age time.Duration) UtilityHistoryEvent {
+		return UtilityHistoryEvent{LearningID: id, ArtifactPath: "/repo/.agents/learnings/" + id,
+			OldUtility: old, NewUtility: new, RecordedAt: now.Add(-age)}
+	}
+	events := []UtilityHistoryEvent{
+		event("up.md", 0.1, 0.9, 30*24*time.Hour), // before the period
+		event("up.md", 0.5, 0.6, time.Hour),
+		event("up.md", 0.6, 0.8, time.Minute),
+		event("small.md", 0.5, 0.55, time.Hour),
+		event("down.md", 0.5, 0.2, time.Hour),
+		event("flat.md", 0.5, 0.5, time.Hour),
+	}
+
+	movers := computeUtilityMovers("/repo", events, since, 1)
+	if len(movers.MostImproved) != 1 || movers.MostImproved[0].LearningID != "up.md" {
+		t.Fatalf("most improved = %+v", movers.MostImproved)
+	}
+	up := movers.MostImproved[0]
+	if up.From != 0.5 || up.To != 0.8 || up.Updates != 2 {
+		t.Errorf("up mover = %+v, want 0.5 → 0.8 over 2 updates", up)
+	}
+	if len(movers.MostDegraded) != 1 || movers.MostDegraded[0].LearningID != "down.md" {
+		t.Errorf("most degraded = %+v", movers.MostDegraded)
+	}
+
+	if computeUtilityMovers("/repo", events[:1], since, 5) != nil {
+		t.Error("no updates in the period should give no movers")
+	}
+}
+
+func TestMaturityHistory_Command(t *testing.T) {
+	dir := chdirTemp(t)
+	path := writeUtilityLearning(t, dir, "L002.md")
+	for _, reward := range []float64{1, 1} {
+		if _, _, err := updateLearningUtilityRecorded(dir, path, reward, 0.1, utilityOrigin{Source: "feedback"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := executeCommand("maturity", "history", "L002", "-o", "json")
+	if err != nil {
+		t.Fatalf("maturity history: %v\n%s", err, out)
+	}
+	var report maturityHistoryReport
+	if err := json.Unmarshal([]byte(out[strings.Index(out, "{"):]), &report); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	if len(report.Events) != 2 || report.Events[1].Source != "feedback" {
+		t.Errorf("report = %+v", report)
+	}
+
+	out, err = executeCommand("maturity", "history", "L002", "--sparkline", "-o", "table")
+	if err != nil {
+		t.Fatalf("maturity history --sparkline: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "0.500 → 0.595 (2 updates)") {
+		t.Errorf("sparkline output = %q", out)
+	}
+}
+
+func TestPrintMetricsTable_UtilityMovers(t *testing.T) {
+	m := &types.FlywheelMetrics{UtilityMovers: &types.UtilityMovers{
+		MostImproved: []types.UtilityMover{{LearningID: "up.md", From: 0.5, To: 0.8, Delta: 0.3, Updates: 2}},
+	}}
+	out, err := captureStdout(t, func() error { printMetricsTable(m); return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "UTILITY MOVERS:") || !strings.Contains(out, "0.500 → 0.800 (+0.300, 2 updates)") || strings.Contains(out, "Most degraded") {
+		t.Errorf("output:\n%s", out)
+	}
+}
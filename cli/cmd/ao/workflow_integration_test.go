@@ -47,7 +47,7 @@ func TestWorkflow_RPIRatchetProgression(t *testing.T) {
 	if err != nil {
 		t.Fatalf("load empty chain: %v", err)
 	}
-	result := computeNextStep(loaded)
+	result := computeNextStep(loaded, ratchet.DefaultWorkflow())
 	if result.Next != "research" {
 		t.Fatalf("empty chain: Next = %q, want %q", result.Next, "research")
 	}
@@ -119,7 +119,7 @@ func TestWorkflow_RPIRatchetProgression(t *testing.T) {
 			t.Fatalf("reload after %s: %v", p.step, err)
 		}
 
-		result := computeNextStep(loaded)
+		result := computeNextStep(loaded, ratchet.DefaultWorkflow())
 
 		if result.Next != p.wantNext {
 			t.Errorf("after %s: Next = %q, want %q", p.step, result.Next, p.wantNext)
@@ -519,7 +519,7 @@ func TestWorkflow_RatchetToMemoryCrossCommand(t *testing.T) {
 	if err != nil {
 		t.Fatalf("load chain: %v", err)
 	}
-	nextResult := computeNextStep(loaded)
+	nextResult := computeNextStep(loaded, ratchet.DefaultWorkflow())
 	if nextResult.Next != "plan" {
 		t.Fatalf("after research+pre-mortem: next = %q, want plan", nextResult.Next)
 	}
@@ -585,7 +585,7 @@ func TestWorkflow_RatchetToMemoryCrossCommand(t *testing.T) {
 	// ---- Step 5: Verify ratchet state is independent of memory ----
 	// Ratchet should still say "plan" regardless of memory sync
 	loaded2, _ := ratchet.LoadChain(tmpDir)
-	nextResult2 := computeNextStep(loaded2)
+	nextResult2 := computeNextStep(loaded2, ratchet.DefaultWorkflow())
 	if nextResult2.Next != "plan" {
 		t.Errorf("ratchet state changed after memory sync: next = %q, want plan", nextResult2.Next)
 	}
@@ -0,0 +1,314 @@
+// Package graph is a typed knowledge graph over AgentOps artifacts:
+// sessions, learnings, findings, constraints and the markdown notes that
+// link them. It answers neighborhood, shortest-path and dependency queries
+// and renders results as JSON, Graphviz DOT or Mermaid.
+//
+// Edges that express derivation always point downstream, from the artifact
+// that was used to the artifact produced from it, so "what depends on X" is
+// a forward walk over those kinds.
+package graph
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Node types with special meaning; other artifact types pass through as-is.
+const (
+	TypeSession    = "session"
+	TypeLearning   = "learning"
+	TypeFinding    = "finding"
+	TypeConstraint = "constraint"
+)
+
+// Edge kinds.
+const (
+	// KindProduced links a session to a learning extracted from it.
+	KindProduced = "produced"
+	// KindCompiledInto links a learning to a finding compiled from it.
+	KindCompiledInto = "compiled_into"
+	// KindEnforcedBy links a finding to the constraint that enforces it.
+	KindEnforcedBy = "enforced_by"
+	// KindDerives links any other source artifact to one derived from it.
+	KindDerives = "derives"
+	// KindSupersedes links a replacement to the artifact it replaces.
+	KindSupersedes = "supersedes"
+	// KindLinks is an undirected wikilink between two artifacts.
+	KindLinks = "links"
+	// KindReferences is any other frontmatter reference.
+	KindReferences = "references"
+)
+
+// dependencyKinds are the edge kinds followed by Dependents.
+var dependencyKinds = map[string]bool{
+	KindProduced:     true,
+	KindCompiledInto: true,
+	KindEnforcedBy:   true,
+	KindDerives:      true,
+}
+
+// Node is one artifact in the graph.
+type Node struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label,omitempty"`
+	Path  string `json:"path,omitempty"`
+}
+
+// Edge is a typed, directed relation between two node IDs.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// Graph holds nodes by ID with outgoing and incoming adjacency.
+type Graph struct {
+	nodes   map[string]*Node
+	out     map[string][]Edge
+	in      map[string][]Edge
+	edges   map[Edge]bool
+	aliases map[string]string
+}
+
+// New creates an empty graph.
+func New() *Graph {
+	return &Graph{
+		nodes:   make(map[string]*Node),
+		out:     make(map[string][]Edge),
+		in:      make(map[string][]Edge),
+		edges:   make(map[Edge]bool),
+		aliases: make(map[string]string),
+	}
+}
+
+// AddNode inserts n, or fills empty fields of an existing node with the
+// same ID. The node's path is registered as an alias for Resolve.
+func (g *Graph) AddNode(n Node) {
+	if existing, ok := g.nodes[n.ID]; ok {
+		existing.Type = cmp.Or(existing.Type, n.Type)
+		existing.Label = cmp.Or(existing.Label, n.Label)
+		existing.Path = cmp.Or(existing.Path, n.Path)
+	} else {
+		node := n
+		g.nodes[n.ID] = &node
+	}
+	if n.Path != "" {
+		g.Alias(n.Path, n.ID)
+	}
+}
+
+// Alias makes ref resolve to id unless ref is already taken.
+func (g *Graph) Alias(ref, id string) {
+	if _, taken := g.aliases[ref]; !taken && ref != "" {
+		g.aliases[ref] = id
+	}
+}
+
+// AddEdge inserts e once. Both endpoints must already be nodes.
+func (g *Graph) AddEdge(e Edge) error {
+	if _, ok := g.nodes[e.From]; !ok {
+		return fmt.Errorf("edge %s: unknown node %q", e.Kind, e.From)
+	}
+	if _, ok := g.nodes[e.To]; !ok {
+		return fmt.Errorf("edge %s: unknown node %q", e.Kind, e.To)
+	}
+	if e.From == e.To || g.edges[e] {
+		return nil
+	}
+	g.edges[e] = true
+	g.out[e.From] = append(g.out[e.From], e)
+	g.in[e.To] = append(g.in[e.To], e)
+	return nil
+}
+
+// Node returns the node with the given ID.
+func (g *Graph) Node(id string) (Node, bool) {
+	n, ok := g.nodes[id]
+	if !ok {
+		return Node{}, false
+	}
+	return *n, true
+}
+
+// Resolve maps a node ID, path or alias to a node ID.
+func (g *Graph) Resolve(ref string) (string, bool) {
+	if _, ok := g.nodes[ref]; ok {
+		return ref, true
+	}
+	id, ok := g.aliases[ref]
+	return id, ok
+}
+
+// Nodes returns all nodes sorted by type then ID.
+func (g *Graph) Nodes() []Node {
+	nodes := make([]Node, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes = append(nodes, *n)
+	}
+	slices.SortFunc(nodes, compareNodes)
+	return nodes
+}
+
+// Edges returns all edges sorted by endpoints then kind.
+func (g *Graph) Edges() []Edge {
+	edges := make([]Edge, 0, len(g.edges))
+	for e := range g.edges {
+		edges = append(edges, e)
+	}
+	slices.SortFunc(edges, compareEdges)
+	return edges
+}
+
+// Neighborhood returns the subgraph within depth hops of id, following
+// edges in either direction.
+func (g *Graph) Neighborhood(id string, depth int) *Graph {
+	seen := map[string]bool{id: true}
+	frontier := []string{id}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []string
+		for _, cur := range frontier {
+			for _, nb := range g.neighbors(cur) {
+				if !seen[nb] {
+					seen[nb] = true
+					next = append(next, nb)
+				}
+			}
+		}
+		frontier = next
+	}
+	return g.subgraph(seen)
+}
+
+// ShortestPath returns the edges of a shortest path between from and to,
+// ignoring edge direction. Ties are broken by node ID so results are
+// stable. It returns an error when no path exists.
+func (g *Graph) ShortestPath(from, to string) ([]Edge, error) {
+	if from == to {
+		return nil, nil
+	}
+	via := map[string]Edge{}
+	seen := map[string]bool{from: true}
+	frontier := []string{from}
+	for len(frontier) > 0 && !seen[to] {
+		var next []string
+		for _, cur := range frontier {
+			for _, e := range g.incident(cur) {
+				nb := e.To
+				if nb == cur {
+					nb = e.From
+				}
+				if seen[nb] {
+					continue
+				}
+				seen[nb] = true
+				via[nb] = e
+				next = append(next, nb)
+			}
+		}
+		frontier = next
+	}
+	if !seen[to] {
+		return nil, fmt.Errorf("no path between %s and %s", from, to)
+	}
+
+	var path []Edge
+	for cur := to; cur != from; {
+		e := via[cur]
+		path = append(path, e)
+		if e.To == cur {
+			cur = e.From
+		} else {
+			cur = e.To
+		}
+	}
+	slices.Reverse(path)
+	return path, nil
+}
+
+// PathGraph returns the subgraph made of the given edges.
+func (g *Graph) PathGraph(edges []Edge) *Graph {
+	sub := New()
+	for _, e := range edges {
+		for _, id := range []string{e.From, e.To} {
+			if n, ok := g.nodes[id]; ok {
+				sub.AddNode(*n)
+			}
+		}
+		_ = sub.AddEdge(e)
+	}
+	return sub
+}
+
+// Dependents returns the subgraph of everything derived, directly or
+// transitively, from id: learnings produced by a session, findings compiled
+// from a learning, constraints enforcing a finding, and so on.
+func (g *Graph) Dependents(id string) *Graph {
+	seen := map[string]bool{id: true}
+	stack := []string{id}
+	sub := New()
+	sub.AddNode(*g.nodes[id])
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range g.out[cur] {
+			if !dependencyKinds[e.Kind] {
+				continue
+			}
+			sub.AddNode(*g.nodes[e.To])
+			_ = sub.AddEdge(e)
+			if !seen[e.To] {
+				seen[e.To] = true
+				stack = append(stack, e.To)
+			}
+		}
+	}
+	return sub
+}
+
+// neighbors returns adjacent node IDs in either direction, sorted.
+func (g *Graph) neighbors(id string) []string {
+	var ids []string
+	for _, e := range g.incident(id) {
+		if e.From == id {
+			ids = append(ids, e.To)
+		} else {
+			ids = append(ids, e.From)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// incident returns outgoing then incoming edges of id, sorted.
+func (g *Graph) incident(id string) []Edge {
+	edges := slices.Concat(g.out[id], g.in[id])
+	slices.SortFunc(edges, compareEdges)
+	return edges
+}
+
+// subgraph returns the nodes in keep and every edge between them.
+func (g *Graph) subgraph(keep map[string]bool) *Graph {
+	sub := New()
+	for id := range keep {
+		if n, ok := g.nodes[id]; ok {
+			sub.AddNode(*n)
+		}
+	}
+	for e := range g.edges {
+		if keep[e.From] && keep[e.To] {
+			_ = sub.AddEdge(e)
+		}
+	}
+	return sub
+}
+
+func compareNodes(a, b Node) int {
+	return cmp.Or(strings.Compare(a.Type, b.Type), strings.Compare(a.ID, b.ID))
+}
+
+func compareEdges(a, b Edge) int {
+	return cmp.Or(strings.Compare(a.From, b.From), strings.Compare(a.To, b.To), strings.Compare(a.Kind, b.Kind))
+}
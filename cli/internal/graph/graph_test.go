@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// chain builds session → learning → finding → constraint, plus a linked
+// learning and a superseded one.
+func chain(t *testing.T) *Graph {
+	t.Helper()
+	g := New()
+	for _, n := range []Node{
+		{ID: "s1", Type: TypeSession},
+		{ID: "l1", Type: TypeLearning, Label: "Retry", Path: ".agents/learnings/retry.md"},
+		{ID: "l2", Type: TypeLearning},
+		{ID: "l0", Type: TypeLearning},
+		{ID: "f1", Type: TypeFinding},
+		{ID: "c1", Type: TypeConstraint},
+	} {
+		g.AddNode(n)
+	}
+	for _, e := range []Edge{
+		{From: "s1", To: "l1", Kind: KindProduced},
+		{From: "l1", To: "f1", Kind: KindCompiledInto},
+		{From: "f1", To: "c1", Kind: KindEnforcedBy},
+		{From: "l2", To: "l1", Kind: KindLinks},
+		{From: "l1", To: "l0", Kind: KindSupersedes},
+	} {
+		if err := g.AddEdge(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func nodeIDs(g *Graph) []string {
+	var ids []string
+	for _, n := range g.Nodes() {
+		ids = append(ids, n.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestAddEdgeRejectsUnknownNodes(t *testing.T) {
+	g := New()
+	g.AddNode(Node{ID: "a", Type: TypeLearning})
+	if err := g.AddEdge(Edge{From: "a", To: "b", Kind: KindLinks}); err == nil {
+		t.Error("expected error for unknown node")
+	}
+	if err := g.AddEdge(Edge{From: "a", To: "a", Kind: KindLinks}); err != nil || len(g.Edges()) != 0 {
+		t.Errorf("self-edge should be dropped silently, got err=%v edges=%v", err, g.Edges())
+	}
+}
+
+func TestResolve(t *testing.T) {
+	g := chain(t)
+	if id, ok := g.Resolve(".agents/learnings/retry.md"); !ok || id != "l1" {
+		t.Errorf("Resolve(path) = %q, %v", id, ok)
+	}
+	g.Alias("short", "s1")
+	if id, ok := g.Resolve("short"); !ok || id != "s1" {
+		t.Errorf("Resolve(alias) = %q, %v", id, ok)
+	}
+	if _, ok := g.Resolve("missing"); ok {
+		t.Error("Resolve(missing) should fail")
+	}
+}
+
+func TestNeighborhood(t *testing.T) {
+	g := chain(t)
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, []string{"l1"}},
+		{1, []string{"f1", "l0", "l1", "l2", "s1"}},
+		{2, []string{"c1", "f1", "l0", "l1", "l2", "s1"}},
+	}
+	for _, tt := range tests {
+		if got := nodeIDs(g.Neighborhood("l1", tt.depth)); !slices.Equal(got, tt.want) {
+			t.Errorf("Neighborhood(l1, %d) = %v, want %v", tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	g := chain(t)
+	path, err := g.ShortestPath("l2", "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Edge{
+		{From: "l2", To: "l1", Kind: KindLinks},
+		{From: "l1", To: "f1", Kind: KindCompiledInto},
+		{From: "f1", To: "c1", Kind: KindEnforcedBy},
+	}
+	if !slices.Equal(path, want) {
+		t.Errorf("ShortestPath = %v, want %v", path, want)
+	}
+
+	g.AddNode(Node{ID: "island", Type: TypeLearning})
+	if _, err := g.ShortestPath("l1", "island"); err == nil {
+		t.Error("expected error for disconnected nodes")
+	}
+}
+
+func TestDependents(t *testing.T) {
+	g := chain(t)
+	if got, want := nodeIDs(g.Dependents("l1")), []string{"c1", "f1", "l1"}; !slices.Equal(got, want) {
+		t.Errorf("Dependents(l1) = %v, want %v", got, want)
+	}
+	if got, want := nodeIDs(g.Dependents("s1")), []string{"c1", "f1", "l1", "s1"}; !slices.Equal(got, want) {
+		t.Errorf("Dependents(s1) = %v, want %v", got, want)
+	}
+	if got := nodeIDs(g.Dependents("l2")); !slices.Equal(got, []string{"l2"}) {
+		t.Errorf("links are not dependencies, got %v", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := chain(t).Dependents("l1")
+
+	var buf bytes.Buffer
+	if err := Render(&buf, g, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var doc document
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.Nodes) != 3 || len(doc.Edges) != 2 {
+		t.Errorf("JSON has %d nodes, %d edges", len(doc.Nodes), len(doc.Edges))
+	}
+
+	buf.Reset()
+	if err := Render(&buf, g, FormatDOT); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"digraph knowledge {", `"l1" -> "f1" [label="compiled_into"];`, `"c1" [label="constraint: c1", shape=octagon];`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("DOT missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := Render(&buf, g, FormatMermaid); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"flowchart LR", `n2["learning: Retry"]`, "n2 -->|compiled_into| n1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	if err := Render(&buf, g, "svg"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Output formats accepted by Render.
+const (
+	FormatJSON    = "json"
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{FormatJSON, FormatDOT, FormatMermaid}
+
+// document is the JSON form of a graph.
+type document struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Render writes g to w in the given format.
+func Render(w io.Writer, g *Graph, format string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(document{Nodes: g.Nodes(), Edges: g.Edges()})
+	case FormatDOT:
+		return renderDOT(w, g)
+	case FormatMermaid:
+		return renderMermaid(w, g)
+	default:
+		return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+func renderDOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph knowledge {\n  rankdir=LR;\n")
+	for _, n := range g.Nodes() {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", strconv.Quote(n.ID), strconv.Quote(nodeLabel(n)), dotShape(n.Type))
+	}
+	for _, e := range g.Edges() {
+		attrs := "label=" + strconv.Quote(e.Kind)
+		if e.Kind == KindLinks {
+			attrs += ", dir=none, style=dashed"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), attrs)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotShape(nodeType string) string {
+	switch nodeType {
+	case TypeSession:
+		return "ellipse"
+	case TypeFinding:
+		return "diamond"
+	case TypeConstraint:
+		return "octagon"
+	default:
+		return "box"
+	}
+}
+
+// renderMermaid writes a flowchart. Mermaid node IDs must be plain
+// identifiers, so nodes are numbered in sorted order.
+func renderMermaid(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	ids := make(map[string]string)
+	for i, n := range g.Nodes() {
+		ids[n.ID] = "n" + strconv.Itoa(i)
+		open, close := mermaidShape(n.Type)
+		fmt.Fprintf(&b, "  %s%s\"%s\"%s\n", ids[n.ID], open, mermaidEscape(nodeLabel(n)), close)
+	}
+	for _, e := range g.Edges() {
+		arrow := "-->"
+		if e.Kind == KindLinks {
+			arrow = "-.-"
+		}
+		fmt.Fprintf(&b, "  %s %s|%s| %s\n", ids[e.From], arrow, e.Kind, ids[e.To])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidShape(nodeType string) (string, string) {
+	switch nodeType {
+	case TypeSession:
+		return "([", "])"
+	case TypeFinding:
+		return "{", "}"
+	case TypeConstraint:
+		return "{{", "}}"
+	default:
+		return "[", "]"
+	}
+}
+
+// mermaidEscape replaces characters that end a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
+
+// nodeLabel is "type: label", falling back to the ID.
+func nodeLabel(n Node) string {
+	label := n.Label
+	if label == "" {
+		label = n.ID
+	}
+	return n.Type + ": " + label
+}
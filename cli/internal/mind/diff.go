@@ -0,0 +1,146 @@
+package mind
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each hunk.
+const diffContext = 3
+
+// diffOp is one line of an edit script.
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// UnifiedDiff renders the line difference between old and new in unified
+// diff format, or "" when they are equal.
+func UnifiedDiff(oldName, newName, old, new string) string {
+	if old == new {
+		return ""
+	}
+	ops := diffLines(splitLines(old), splitLines(new))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks(ops) {
+		writeHunk(&b, ops, h)
+	}
+	return b.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes an edit script with a longest-common-subsequence table
+// over the lines between the common prefix and suffix. Pipeline edits are
+// local (frontmatter at the top, links at the bottom), so the table stays
+// small even for long artifacts.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			ops = append(ops, diffOp{' ', midA[i]})
+			i++
+			j++
+		case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', midA[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', midB[j]})
+			j++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// hunk is a half-open range of ops to print.
+type hunk struct{ start, end int }
+
+// hunks groups changed ops with diffContext lines of context, merging
+// hunks whose context would overlap.
+func hunks(ops []diffOp) []hunk {
+	var out []hunk
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start, end := max(i-diffContext, 0), min(i+diffContext+1, len(ops))
+		if n := len(out); n > 0 && start <= out[n-1].end {
+			out[n-1].end = end
+			continue
+		}
+		out = append(out, hunk{start, end})
+	}
+	return out
+}
+
+func writeHunk(b *strings.Builder, ops []diffOp, h hunk) {
+	oldStart, newStart := 1, 1
+	for _, op := range ops[:h.start] {
+		if op.kind != '+' {
+			oldStart++
+		}
+		if op.kind != '-' {
+			newStart++
+		}
+	}
+	oldLen, newLen := 0, 0
+	for _, op := range ops[h.start:h.end] {
+		if op.kind != '+' {
+			oldLen++
+		}
+		if op.kind != '-' {
+			newLen++
+		}
+	}
+	if oldLen == 0 {
+		oldStart--
+	}
+	if newLen == 0 {
+		newStart--
+	}
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldLen, newStart, newLen)
+	for _, op := range ops[h.start:h.end] {
+		b.WriteByte(op.kind)
+		b.WriteString(op.text)
+		b.WriteByte('\n')
+	}
+}
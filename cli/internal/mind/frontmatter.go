@@ -0,0 +1,161 @@
+package mind
+
+import (
+	"strings"
+)
+
+// frontmatterDelim opens and closes a YAML frontmatter block.
+const frontmatterDelim = "---"
+
+// field is one top-level frontmatter key together with its raw lines,
+// including any indented or list continuation lines that follow it.
+type field struct {
+	Key   string
+	Lines []string
+}
+
+// Frontmatter is a line-preserving view of a YAML frontmatter block.
+// Unknown keys, comments and formatting are kept verbatim so normalization
+// only touches the fields it changes.
+type Frontmatter struct {
+	fields []field
+}
+
+// splitFrontmatter separates content into frontmatter lines and body.
+// ok is false when content has no frontmatter; unterminated reports an
+// opening delimiter with no closing one.
+func splitFrontmatter(content string) (fm []string, body string, ok, unterminated bool) {
+	if !strings.HasPrefix(content, frontmatterDelim+"\n") && !strings.HasPrefix(content, frontmatterDelim+"\r\n") {
+		return nil, content, false, false
+	}
+	lines := strings.SplitAfter(content, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], "\r\n") == frontmatterDelim {
+			for _, l := range lines[1:i] {
+				fm = append(fm, strings.TrimRight(l, "\r\n"))
+			}
+			return fm, strings.Join(lines[i+1:], ""), true, false
+		}
+	}
+	return nil, content, false, true
+}
+
+// parseFrontmatter groups frontmatter lines into top-level fields.
+func parseFrontmatter(lines []string) *Frontmatter {
+	f := &Frontmatter{}
+	for _, line := range lines {
+		key, isKey := topLevelKey(line)
+		if isKey || len(f.fields) == 0 {
+			f.fields = append(f.fields, field{Key: key, Lines: []string{line}})
+			continue
+		}
+		last := &f.fields[len(f.fields)-1]
+		last.Lines = append(last.Lines, line)
+	}
+	return f
+}
+
+// topLevelKey returns the key of an unindented "key: value" line.
+func topLevelKey(line string) (string, bool) {
+	if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' || line[0] == '-' {
+		return "", false
+	}
+	key, _, ok := strings.Cut(line, ":")
+	if !ok || strings.ContainsAny(key, " \t") {
+		return "", false
+	}
+	return key, true
+}
+
+// Has reports whether key is present.
+func (f *Frontmatter) Has(key string) bool {
+	return f.index(key) >= 0
+}
+
+// Get returns the unquoted scalar value of key, or "" if absent.
+func (f *Frontmatter) Get(key string) string {
+	i := f.index(key)
+	if i < 0 {
+		return ""
+	}
+	_, value, _ := strings.Cut(f.fields[i].Lines[0], ":")
+	return unquote(strings.TrimSpace(value))
+}
+
+// List returns the values of key written either inline ([a, b]), as a
+// comma-separated scalar, or as a block of "- item" lines.
+func (f *Frontmatter) List(key string) []string {
+	i := f.index(key)
+	if i < 0 {
+		return nil
+	}
+	var items []string
+	for _, line := range f.fields[i].Lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+			items = append(items, unquote(strings.TrimSpace(item)))
+		}
+	}
+	if len(items) > 0 {
+		return items
+	}
+	return splitList(f.Get(key))
+}
+
+// Set replaces key with a single "key: value" line, appending it if absent.
+func (f *Frontmatter) Set(key, value string) {
+	line := key + ": " + value
+	if i := f.index(key); i >= 0 {
+		f.fields[i].Lines = []string{line}
+		return
+	}
+	f.fields = append(f.fields, field{Key: key, Lines: []string{line}})
+}
+
+// Render returns the frontmatter block including both delimiters.
+func (f *Frontmatter) Render() string {
+	var b strings.Builder
+	b.WriteString(frontmatterDelim + "\n")
+	for _, fd := range f.fields {
+		for _, line := range fd.Lines {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+	b.WriteString(frontmatterDelim + "\n")
+	return b.String()
+}
+
+func (f *Frontmatter) index(key string) int {
+	for i, fd := range f.fields {
+		if fd.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitList parses "[a, b]" or "a, b" into trimmed, unquoted items.
+func splitList(value string) []string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// formatList renders items as an inline YAML list.
+func formatList(items []string) string {
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
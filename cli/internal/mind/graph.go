@@ -0,0 +1,268 @@
+package mind
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// GraphVersion is the on-disk format version of the graph index.
+const GraphVersion = 1
+
+// GraphIndexFile is the graph index file name under .agents/ao/.
+const GraphIndexFile = "graph.json"
+
+// Edge kinds not named after a frontmatter key.
+const (
+	// EdgeLink is a wikilink written by hand in the body.
+	EdgeLink = "link"
+	// EdgeRelated is a wikilink in the mind-managed related block.
+	EdgeRelated = "related"
+)
+
+// idRefFields are frontmatter keys whose plain (non-wikilink) values name
+// another artifact by id or path.
+var idRefFields = []string{"supersedes", "superseded_by"}
+
+// Node is one artifact in the knowledge graph.
+type Node struct {
+	ID    string   `json:"id"`
+	Path  string   `json:"path"`
+	Type  string   `json:"type"`
+	Title string   `json:"title"`
+	Date  string   `json:"date,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// Edge is a directed reference between two artifacts, keyed by path.
+// Kind is EdgeLink, EdgeRelated, or the frontmatter key holding the
+// reference (for example "source" or "supersedes").
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// UnresolvedLink is a reference whose target is not a scanned artifact.
+type UnresolvedLink struct {
+	From   string `json:"from"`
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+}
+
+// Graph is the graph index written by ao mind index.
+type Graph struct {
+	Version     int              `json:"version"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Nodes       []Node           `json:"nodes"`
+	Edges       []Edge           `json:"edges"`
+	Unresolved  []UnresolvedLink `json:"unresolved,omitempty"`
+}
+
+// NodeDegree pairs a node path with its total edge count.
+type NodeDegree struct {
+	Path   string `json:"path"`
+	Degree int    `json:"degree"`
+}
+
+// GraphStats summarises a Graph for ao mind graph.
+type GraphStats struct {
+	Nodes       int            `json:"nodes"`
+	Edges       int            `json:"edges"`
+	NodesByType map[string]int `json:"nodes_by_type"`
+	EdgesByKind map[string]int `json:"edges_by_kind"`
+	Orphans     int            `json:"orphans"`
+	Unresolved  int            `json:"unresolved_links"`
+	AvgDegree   float64        `json:"avg_degree"`
+	MostLinked  []NodeDegree   `json:"most_linked"`
+}
+
+// GraphIndexPath returns the graph index location for a vault root.
+func GraphIndexPath(root string) string {
+	return filepath.Join(root, AgentsDir, "ao", GraphIndexFile)
+}
+
+// BuildGraph derives nodes from artifacts and edges from their wikilinks
+// and id references. Duplicate edges and self-links are dropped.
+func BuildGraph(artifacts []Artifact) *Graph {
+	g := &Graph{Version: GraphVersion, GeneratedAt: time.Now().UTC()}
+	resolve := newResolver()
+
+	for _, a := range artifacts {
+		fmLines, _, _, _ := splitFrontmatter(a.Content)
+		fm := parseFrontmatter(fmLines)
+		n := Node{
+			ID:    fm.Get("id"),
+			Path:  a.Path,
+			Type:  fm.Get("type"),
+			Title: a.Title(),
+			Date:  fm.Get("date"),
+			Tags:  fm.List("tags"),
+		}
+		if n.Type == "" {
+			n.Type = a.Type()
+		}
+		if n.ID == "" {
+			n.ID = artifactID(a.Stem(), n.Type)
+		}
+		if n.Date == "" {
+			if m := dateRe.FindStringSubmatch(a.Stem()); m != nil {
+				n.Date = m[1]
+			}
+		}
+		g.Nodes = append(g.Nodes, n)
+		resolve.add(n)
+	}
+
+	seen := make(map[Edge]bool)
+	addRef := func(from, target, kind string) {
+		to, ok := resolve.lookup(target)
+		if !ok {
+			g.Unresolved = append(g.Unresolved, UnresolvedLink{From: from, Target: target, Kind: kind})
+			return
+		}
+		e := Edge{From: from, To: to, Kind: kind}
+		if to == from || seen[e] {
+			return
+		}
+		seen[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+
+	for _, a := range artifacts {
+		fmLines, body, _, _ := splitFrontmatter(a.Content)
+		fm := parseFrontmatter(fmLines)
+		for _, fd := range fm.fields {
+			for _, target := range wikilinkTargets(strings.Join(fd.Lines, "\n")) {
+				addRef(a.Path, target, cmp.Or(fd.Key, EdgeLink))
+			}
+		}
+		for _, key := range idRefFields {
+			for _, value := range fm.List(key) {
+				if !strings.Contains(value, "[[") {
+					addRef(a.Path, value, key)
+				}
+			}
+		}
+		for _, block := range relatedBlockRe.FindAllString(body, -1) {
+			for _, target := range wikilinkTargets(block) {
+				addRef(a.Path, target, EdgeRelated)
+			}
+		}
+		for _, target := range wikilinkTargets(relatedBlockRe.ReplaceAllString(body, "\n")) {
+			addRef(a.Path, target, EdgeLink)
+		}
+	}
+
+	slices.SortFunc(g.Edges, func(x, y Edge) int {
+		return cmp.Or(strings.Compare(x.From, y.From), strings.Compare(x.To, y.To), strings.Compare(x.Kind, y.Kind))
+	})
+	return g
+}
+
+// resolver maps the spellings a wikilink may use for an artifact (vault
+// path with or without .md, path below .agents/, file stem, id) to its path.
+type resolver map[string]string
+
+func newResolver() resolver { return make(resolver) }
+
+func (r resolver) add(n Node) {
+	noExt := strings.TrimSuffix(n.Path, ".md")
+	keys := []string{
+		n.Path, noExt,
+		strings.TrimPrefix(n.Path, AgentsDir+"/"), strings.TrimPrefix(noExt, AgentsDir+"/"),
+		filepath.Base(noExt), n.ID,
+	}
+	for _, k := range keys {
+		if _, taken := r[k]; !taken && k != "" {
+			r[k] = n.Path
+		}
+	}
+}
+
+func (r resolver) lookup(target string) (string, bool) {
+	target = strings.TrimPrefix(strings.TrimSpace(target), "./")
+	if path, ok := r[target]; ok {
+		return path, true
+	}
+	path, ok := r[strings.TrimSuffix(target, ".md")]
+	return path, ok
+}
+
+// Stats computes node/edge statistics for the graph.
+func (g *Graph) Stats() GraphStats {
+	s := GraphStats{
+		Nodes:       len(g.Nodes),
+		Edges:       len(g.Edges),
+		NodesByType: make(map[string]int),
+		EdgesByKind: make(map[string]int),
+		Unresolved:  len(g.Unresolved),
+		MostLinked:  []NodeDegree{},
+	}
+
+	degree := make(map[string]int, len(g.Nodes))
+	for _, e := range g.Edges {
+		s.EdgesByKind[e.Kind]++
+		degree[e.From]++
+		degree[e.To]++
+	}
+
+	var ranked []NodeDegree
+	for _, n := range g.Nodes {
+		s.NodesByType[n.Type]++
+		if degree[n.Path] == 0 {
+			s.Orphans++
+			continue
+		}
+		ranked = append(ranked, NodeDegree{Path: n.Path, Degree: degree[n.Path]})
+	}
+	if s.Nodes > 0 {
+		s.AvgDegree = float64(2*s.Edges) / float64(s.Nodes)
+	}
+
+	slices.SortFunc(ranked, func(x, y NodeDegree) int {
+		return cmp.Or(cmp.Compare(y.Degree, x.Degree), strings.Compare(x.Path, y.Path))
+	})
+	if len(ranked) > 5 {
+		ranked = ranked[:5]
+	}
+	s.MostLinked = append(s.MostLinked, ranked...)
+	return s
+}
+
+// SaveGraph writes the graph index as indented JSON.
+func SaveGraph(g *Graph, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("create graph dir: %w", err)
+	}
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal graph: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("write graph: %w", err)
+	}
+	return nil
+}
+
+// LoadGraph reads a graph index. A file written by a different format
+// version is reported as an error so callers can rebuild it.
+func LoadGraph(path string) (*Graph, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var g Graph
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("parse graph: %w", err)
+	}
+	if g.Version != GraphVersion {
+		return nil, fmt.Errorf("graph index version %d, want %d", g.Version, GraphVersion)
+	}
+	return &g, nil
+}
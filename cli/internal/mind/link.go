@@ -0,0 +1,220 @@
+package mind
+
+import (
+	"cmp"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/boshu2/agentops/cli/internal/search"
+)
+
+// Markers delimit the mind-managed related-links block so reruns replace
+// it instead of appending a second one. Links written by hand elsewhere in
+// the body are never touched.
+const (
+	relatedStart   = "<!-- mind:related -->"
+	relatedEnd     = "<!-- /mind:related -->"
+	relatedHeading = "## Related"
+)
+
+var (
+	// aliasReplacer keeps titles from breaking out of a [[path|alias]] link.
+	aliasReplacer = strings.NewReplacer("|", "-", "[", "(", "]", ")", "\n", " ")
+
+	wikilinkRe     = regexp.MustCompile(`\[\[([^\]\n]+)\]\]`)
+	relatedBlockRe = regexp.MustCompile(`(?s)\n*(?:` + regexp.QuoteMeta(relatedHeading) + `\n+)?` +
+		regexp.QuoteMeta(relatedStart) + `.*?` + regexp.QuoteMeta(relatedEnd) + `\n?`)
+)
+
+// LinkOptions tunes which artifacts count as related.
+type LinkOptions struct {
+	// MinSimilarity is the TF-IDF cosine similarity an artifact pair needs
+	// to be linked.
+	MinSimilarity float64
+
+	// MaxLinks caps the related links written into one artifact.
+	MaxLinks int
+}
+
+// DefaultLinkOptions returns the link thresholds used by ao mind link.
+func DefaultLinkOptions() LinkOptions {
+	return LinkOptions{MinSimilarity: 0.25, MaxLinks: 5}
+}
+
+// Relation is a scored link suggestion from one artifact to another.
+type Relation struct {
+	Path  string
+	Title string
+	Score float64
+}
+
+// Related scores every artifact pair by TF-IDF cosine similarity over
+// title, tags and body (title and tag terms boosted as in search) and
+// returns, per artifact path, the best matches above opts.MinSimilarity.
+func Related(artifacts []Artifact, opts LinkOptions) map[string][]Relation {
+	idx := search.NewIndex()
+	titles := make(map[string]string, len(artifacts))
+	for _, a := range artifacts {
+		addToIndex(idx, a)
+		titles[a.Path] = a.Title()
+	}
+	vectors := tfidfVectors(idx)
+
+	related := make(map[string][]Relation, len(artifacts))
+	for i, a := range artifacts {
+		for _, b := range artifacts[i+1:] {
+			score := cosineSparse(vectors[a.Path], vectors[b.Path])
+			if score < opts.MinSimilarity {
+				continue
+			}
+			related[a.Path] = append(related[a.Path], Relation{Path: b.Path, Title: titles[b.Path], Score: score})
+			related[b.Path] = append(related[b.Path], Relation{Path: a.Path, Title: titles[a.Path], Score: score})
+		}
+	}
+	for path, rels := range related {
+		slices.SortFunc(rels, func(x, y Relation) int {
+			if c := cmp.Compare(y.Score, x.Score); c != 0 {
+				return c
+			}
+			return strings.Compare(x.Path, y.Path)
+		})
+		if opts.MaxLinks > 0 && len(rels) > opts.MaxLinks {
+			rels = rels[:opts.MaxLinks]
+		}
+		related[path] = rels
+	}
+	return related
+}
+
+// addToIndex indexes the artifact's title, tags and body under its path.
+// The frontmatter and the mind-managed related block are excluded so that
+// links written by a previous run do not feed back into similarity.
+func addToIndex(idx *search.Index, a Artifact) {
+	fmLines, body, _, _ := splitFrontmatter(a.Content)
+	fm := parseFrontmatter(fmLines)
+	search.AddDocument(idx, a.Path, search.Document{
+		Title: a.Title(),
+		Tags:  fm.List("tags"),
+		Body:  relatedBlockRe.ReplaceAllString(body, "\n"),
+	})
+}
+
+// tfidfVectors builds a sparse, L2-normalised TF-IDF vector per document.
+// Terms found in only one document, or in all of them, cannot distinguish
+// pairs and are skipped.
+func tfidfVectors(idx *search.Index) map[string]map[string]float64 {
+	n := float64(len(idx.Lengths))
+	vectors := make(map[string]map[string]float64, len(idx.Lengths))
+	for term, postings := range idx.Terms {
+		df := float64(len(postings))
+		if df < 2 || df >= n {
+			continue
+		}
+		idf := math.Log(n / df)
+		for path, p := range postings {
+			tf := float64(p.TF) + search.TitleBoost*float64(p.Title) + search.TagBoost*float64(p.Tags)
+			if vectors[path] == nil {
+				vectors[path] = make(map[string]float64)
+			}
+			vectors[path][term] = (1 + math.Log(tf)) * idf
+		}
+	}
+	for _, vec := range vectors {
+		var sq float64
+		for _, w := range vec {
+			sq += w * w
+		}
+		norm := math.Sqrt(sq)
+		for term := range vec {
+			vec[term] /= norm
+		}
+	}
+	return vectors
+}
+
+// cosineSparse returns the dot product of two unit sparse vectors.
+func cosineSparse(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var dot float64
+	for term, w := range a {
+		dot += w * b[term]
+	}
+	return dot
+}
+
+// Link rewrites the mind-managed related block of a to list rels. Targets
+// the artifact already links to by hand are left out. ok is false when the
+// block is already current.
+func Link(a Artifact, rels []Relation) (Change, bool) {
+	fmLines, body, hasFM, _ := splitFrontmatter(a.Content)
+
+	stripped := relatedBlockRe.ReplaceAllString(body, "\n")
+	existing := make(map[string]bool)
+	for _, target := range wikilinkTargets(stripped) {
+		existing[target] = true
+	}
+
+	var lines []string
+	for _, r := range rels {
+		if existing[r.Path] || existing[strings.TrimSuffix(r.Path, ".md")] {
+			continue
+		}
+		lines = append(lines, "- [["+r.Path+"|"+aliasReplacer.Replace(r.Title)+"]]")
+	}
+
+	newBody := strings.TrimRight(stripped, "\n") + "\n"
+	if len(lines) > 0 {
+		newBody += "\n" + relatedHeading + "\n\n" + relatedStart + "\n" + strings.Join(lines, "\n") + "\n" + relatedEnd + "\n"
+	}
+	if strings.TrimRight(newBody, "\n") == strings.TrimRight(body, "\n") {
+		return Change{}, false
+	}
+
+	content := newBody
+	if hasFM {
+		content = parseFrontmatter(fmLines).Render() + newBody
+	}
+	reason := "remove stale related links"
+	if len(lines) > 0 {
+		reason = "link " + pluralize(len(lines), "related artifact")
+	}
+	return Change{Path: a.Path, Reasons: []string{reason}, Old: a.Content, New: content}, true
+}
+
+// LinkAll computes relations across artifacts and returns the link changes.
+func LinkAll(artifacts []Artifact, opts LinkOptions) []Change {
+	related := Related(artifacts, opts)
+	var changes []Change
+	for _, a := range artifacts {
+		if c, ok := Link(a, related[a.Path]); ok {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// wikilinkTargets returns the targets of [[target|alias]] and
+// [[target#heading]] links in text.
+func wikilinkTargets(text string) []string {
+	var targets []string
+	for _, m := range wikilinkRe.FindAllStringSubmatch(text, -1) {
+		target, _, _ := strings.Cut(m[1], "|")
+		target, _, _ = strings.Cut(target, "#")
+		if target = strings.TrimSpace(target); target != "" {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
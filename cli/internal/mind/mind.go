@@ -0,0 +1,190 @@
+// Package mind turns .agents/ markdown into an Obsidian-style knowledge
+// graph. It normalizes artifact frontmatter, inserts wikilinks between
+// related artifacts, and writes a graph index that other commands can query.
+//
+// Every step is a pure transformation over in-memory Artifacts that yields
+// Changes; callers decide whether to write them or show them as a diff.
+package mind
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AgentsDir is the knowledge root scanned relative to the vault.
+const AgentsDir = ".agents"
+
+// skipDirs are .agents/ subdirectories holding generated state, not notes.
+var skipDirs = map[string]bool{
+	"ao": true,
+}
+
+// dirTypes maps a top-level .agents/ directory to an artifact type.
+var dirTypes = map[string]string{
+	"learnings": "learning",
+	"patterns":  "pattern",
+	"findings":  "finding",
+	"research":  "research",
+	"plans":     "plan",
+	"retros":    "retro",
+	"retro":     "retro",
+	"decisions": "decision",
+	"council":   "council",
+	"releases":  "release",
+	"rpi":       "rpi",
+}
+
+var (
+	dateRe    = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})`)
+	headingRe = regexp.MustCompile(`(?m)^#\s+(.+?)\s*$`)
+)
+
+// Artifact is one markdown file under .agents/.
+type Artifact struct {
+	// Path is slash-separated and relative to the vault root,
+	// e.g. ".agents/learnings/2026-01-02-retry.md".
+	Path string
+
+	// Content is the full file content, updated in place by the pipeline.
+	Content string
+
+	// ModTime is used to date artifacts that carry no date of their own.
+	ModTime time.Time
+}
+
+// Type returns the artifact type implied by its directory.
+func (a Artifact) Type() string {
+	parts := strings.Split(a.Path, "/")
+	if len(parts) > 2 {
+		if t, ok := dirTypes[parts[1]]; ok {
+			return t
+		}
+	}
+	return "note"
+}
+
+// Stem returns the file name without the .md extension.
+func (a Artifact) Stem() string {
+	return strings.TrimSuffix(filepath.Base(a.Path), ".md")
+}
+
+// Title returns the frontmatter title, else the first H1, else the stem.
+func (a Artifact) Title() string {
+	fmLines, body, ok, _ := splitFrontmatter(a.Content)
+	if ok {
+		if title := parseFrontmatter(fmLines).Get("title"); title != "" {
+			return title
+		}
+	}
+	if m := headingRe.FindStringSubmatch(body); m != nil {
+		return m[1]
+	}
+	return a.Stem()
+}
+
+// Frontmatter returns the parsed frontmatter, empty when there is none.
+func (a Artifact) Frontmatter() *Frontmatter {
+	fmLines, _, _, _ := splitFrontmatter(a.Content)
+	return parseFrontmatter(fmLines)
+}
+
+// Change is a proposed rewrite of one artifact.
+type Change struct {
+	Path    string   `json:"path"`
+	Reasons []string `json:"reasons"`
+	Old     string   `json:"-"`
+	New     string   `json:"-"`
+}
+
+// Diff returns the change as a unified diff against the vault root.
+func (c Change) Diff() string {
+	return UnifiedDiff("a/"+c.Path, "b/"+c.Path, c.Old, c.New)
+}
+
+// Scan loads every markdown artifact under root/.agents, sorted by path.
+// Generated INDEX.md files and .agents/ao state are skipped.
+func Scan(root string) ([]Artifact, error) {
+	base := filepath.Join(root, AgentsDir)
+	if _, err := os.Stat(base); err != nil {
+		return nil, fmt.Errorf("knowledge root %s: %w", base, err)
+	}
+
+	var artifacts []Artifact
+	err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip unreadable entries
+		}
+		if d.IsDir() {
+			if path != base && (strings.HasPrefix(d.Name(), ".") || skipDirs[d.Name()] && filepath.Dir(path) == base) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".md" || d.Name() == "INDEX.md" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		artifacts = append(artifacts, Artifact{
+			Path:    filepath.ToSlash(rel),
+			Content: string(data),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(artifacts, func(a, b Artifact) int { return strings.Compare(a.Path, b.Path) })
+	return artifacts, nil
+}
+
+// Apply records changes in artifacts so later pipeline steps see them.
+func Apply(artifacts []Artifact, changes []Change) {
+	byPath := make(map[string]int, len(artifacts))
+	for i, a := range artifacts {
+		byPath[a.Path] = i
+	}
+	for _, c := range changes {
+		if i, ok := byPath[c.Path]; ok {
+			artifacts[i].Content = c.New
+		}
+	}
+}
+
+// Merge collapses successive change sets into one change per path, from
+// the original content to the final content, with reasons concatenated.
+func Merge(sets ...[]Change) []Change {
+	var merged []Change
+	byPath := make(map[string]int)
+	for _, set := range sets {
+		for _, c := range set {
+			if i, ok := byPath[c.Path]; ok {
+				merged[i].New = c.New
+				merged[i].Reasons = append(merged[i].Reasons, c.Reasons...)
+				continue
+			}
+			byPath[c.Path] = len(merged)
+			merged = append(merged, Change{Path: c.Path, Old: c.Old, New: c.New, Reasons: slices.Clone(c.Reasons)})
+		}
+	}
+	slices.SortFunc(merged, func(a, b Change) int { return strings.Compare(a.Path, b.Path) })
+	return merged
+}
@@ -0,0 +1,206 @@
+package mind
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeArtifact(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan_SkipsGeneratedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeArtifact(t, root, ".agents/learnings/2026-01-02-retry.md", "# Retry\n")
+	writeArtifact(t, root, ".agents/learnings/INDEX.md", "# Index\n")
+	writeArtifact(t, root, ".agents/ao/sessions/s1.md", "# Session\n")
+	writeArtifact(t, root, ".agents/learnings/notes.txt", "not markdown")
+
+	artifacts, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(artifacts) != 1 || artifacts[0].Path != ".agents/learnings/2026-01-02-retry.md" {
+		t.Fatalf("unexpected artifacts: %+v", artifacts)
+	}
+	if artifacts[0].Type() != "learning" || artifacts[0].Title() != "Retry" {
+		t.Errorf("type=%q title=%q", artifacts[0].Type(), artifacts[0].Title())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	mtime := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+		reasons []string
+	}{
+		{
+			name:    "adds frontmatter from filename and body",
+			path:    ".agents/learnings/2026-01-02-retry.md",
+			content: "# Retry\n\n**Tags:** network, backoff\n",
+			want:    "---\nid: learning-2026-01-02-retry\ntype: learning\ndate: 2026-01-02\ntags: [network, backoff]\n---\n# Retry\n\n**Tags:** network, backoff\n",
+			reasons: []string{"add id", "add type", "add date", "add tags from body"},
+		},
+		{
+			name:    "keeps existing fields and order",
+			path:    ".agents/research/topic.md",
+			content: "---\nutility: 0.7\ntype: research\ntags: go, concurrency\n---\nbody\n",
+			want:    "---\nutility: 0.7\ntype: research\ntags: [go, concurrency]\nid: research-topic\ndate: 2026-03-04\n---\nbody\n",
+			reasons: []string{"add id", "add date", "rewrite tags as a list"},
+		},
+		{
+			name:    "body date beats mtime",
+			path:    ".agents/council/sweep.md",
+			content: "# Sweep\n\nDate: 2026-02-09\n",
+			want:    "---\nid: council-sweep\ntype: council\ndate: 2026-02-09\n---\n# Sweep\n\nDate: 2026-02-09\n",
+			reasons: []string{"add id", "add type", "add date"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ok := Normalize(Artifact{Path: tt.path, Content: tt.content, ModTime: mtime})
+			if !ok {
+				t.Fatal("expected a change")
+			}
+			if c.New != tt.want {
+				t.Errorf("content =\n%s\nwant\n%s", c.New, tt.want)
+			}
+			if !slices.Equal(c.Reasons, tt.reasons) {
+				t.Errorf("reasons = %v, want %v", c.Reasons, tt.reasons)
+			}
+			if _, again := Normalize(Artifact{Path: tt.path, Content: c.New, ModTime: mtime}); again {
+				t.Error("normalize should be idempotent")
+			}
+		})
+	}
+}
+
+func TestNormalize_SkipsUnterminatedFrontmatter(t *testing.T) {
+	a := Artifact{Path: ".agents/learnings/x.md", Content: "---\ntitle: broken\n"}
+	if _, ok := Normalize(a); ok {
+		t.Error("expected no change for unterminated frontmatter")
+	}
+	if got := Check(a); !slices.Equal(got, []string{"unterminated frontmatter"}) {
+		t.Errorf("Check = %v", got)
+	}
+}
+
+func relatedFixture() []Artifact {
+	return []Artifact{
+		{Path: ".agents/learnings/mutex.md", Content: "# Mutex ordering\n\nAlways lock the registry mutex before the cache mutex to avoid deadlock.\n"},
+		{Path: ".agents/patterns/lock-order.md", Content: "# Lock order pattern\n\nDeadlock happens when mutex acquisition order differs; document registry then cache.\n"},
+		{Path: ".agents/research/release.md", Content: "# Release notes\n\nChangelog entries for the release train and version bump.\n"},
+		{Path: ".agents/plans/ship.md", Content: "# Ship plan\n\nCut the release, bump the version, publish the changelog.\n"},
+	}
+}
+
+func TestLinkAll_InsertsRelatedBlockIdempotently(t *testing.T) {
+	artifacts := relatedFixture()
+	changes := LinkAll(artifacts, DefaultLinkOptions())
+	if len(changes) != 4 {
+		t.Fatalf("expected every artifact to gain a link, got %d changes", len(changes))
+	}
+
+	var mutex Change
+	for _, c := range changes {
+		if c.Path == ".agents/learnings/mutex.md" {
+			mutex = c
+		}
+	}
+	if !strings.Contains(mutex.New, "- [[.agents/patterns/lock-order.md|Lock order pattern]]") {
+		t.Errorf("expected link to lock-order pattern:\n%s", mutex.New)
+	}
+	if strings.Contains(mutex.New, "release.md") {
+		t.Errorf("unrelated artifact linked:\n%s", mutex.New)
+	}
+
+	Apply(artifacts, changes)
+	if again := LinkAll(artifacts, DefaultLinkOptions()); len(again) != 0 {
+		t.Errorf("expected rerun to be a no-op, got %d changes", len(again))
+	}
+}
+
+func TestLink_SkipsHandWrittenLinksAndClearsStaleBlock(t *testing.T) {
+	a := Artifact{
+		Path:    ".agents/learnings/a.md",
+		Content: "# A\n\nSee [[.agents/learnings/b]].\n\n## Related\n\n<!-- mind:related -->\n- [[.agents/learnings/c.md|C]]\n<!-- /mind:related -->\n",
+	}
+	c, ok := Link(a, []Relation{{Path: ".agents/learnings/b.md", Title: "B"}})
+	if !ok {
+		t.Fatal("expected stale block to be removed")
+	}
+	if c.New != "# A\n\nSee [[.agents/learnings/b]].\n" {
+		t.Errorf("unexpected content:\n%q", c.New)
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	artifacts := []Artifact{
+		{Path: ".agents/learnings/old.md", Content: "---\nid: L1\n---\n# Old\n"},
+		{Path: ".agents/learnings/new.md", Content: "---\nid: L2\nsupersedes: L1\n---\n# New\n\nSee [[learnings/old]] and [[missing]].\n\n## Related\n\n<!-- mind:related -->\n- [[.agents/plans/p.md|P]]\n<!-- /mind:related -->\n"},
+		{Path: ".agents/plans/p.md", Content: "---\nsource: \"[[.agents/learnings/new.md]]\"\n---\n# P\n"},
+		{Path: ".agents/research/lonely.md", Content: "# Lonely\n"},
+	}
+	g := BuildGraph(artifacts)
+
+	want := []Edge{
+		{From: ".agents/learnings/new.md", To: ".agents/learnings/old.md", Kind: EdgeLink},
+		{From: ".agents/learnings/new.md", To: ".agents/learnings/old.md", Kind: "supersedes"},
+		{From: ".agents/learnings/new.md", To: ".agents/plans/p.md", Kind: EdgeRelated},
+		{From: ".agents/plans/p.md", To: ".agents/learnings/new.md", Kind: "source"},
+	}
+	if !slices.Equal(g.Edges, want) {
+		t.Errorf("edges = %+v\nwant %+v", g.Edges, want)
+	}
+
+	s := g.Stats()
+	if s.Nodes != 4 || s.Edges != 4 || s.Orphans != 1 || s.Unresolved != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+	if s.NodesByType["learning"] != 2 || s.EdgesByKind[EdgeLink] != 1 {
+		t.Errorf("breakdown = %+v / %+v", s.NodesByType, s.EdgesByKind)
+	}
+	if s.MostLinked[0].Path != ".agents/learnings/new.md" || s.MostLinked[0].Degree != 4 {
+		t.Errorf("most linked = %+v", s.MostLinked)
+	}
+
+	path := filepath.Join(t.TempDir(), GraphIndexFile)
+	if err := SaveGraph(g, path); err != nil {
+		t.Fatalf("SaveGraph: %v", err)
+	}
+	loaded, err := LoadGraph(path)
+	if err != nil {
+		t.Fatalf("LoadGraph: %v", err)
+	}
+	if !slices.Equal(loaded.Edges, g.Edges) || len(loaded.Nodes) != len(g.Nodes) {
+		t.Error("graph did not round-trip")
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	want := "--- a/x\n+++ b/x\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -8,3 +8,4 @@\n h\n i\n j\n+k\n"
+	if got := UnifiedDiff("a/x", "b/x", old, new); got != want {
+		t.Errorf("diff =\n%s\nwant\n%s", got, want)
+	}
+	if got := UnifiedDiff("a/x", "b/x", old, old); got != "" {
+		t.Errorf("expected empty diff for equal input, got %q", got)
+	}
+}
@@ -0,0 +1,126 @@
+package mind
+
+import (
+	"regexp"
+	"strings"
+)
+
+// bodyFieldRe matches "Field: value" lines, optionally in bold, that older
+// artifacts use in place of frontmatter.
+var bodyFieldRe = regexp.MustCompile(`(?m)^(?:\*\*)?(Date|Discovered|Tags):(?:\*\*)?[ \t]*(.+?)[ \t]*$`)
+
+// Check lists the frontmatter problems Normalize would fix in a, plus any
+// it cannot fix (such as an unterminated block).
+func Check(a Artifact) []string {
+	if _, _, _, unterminated := splitFrontmatter(a.Content); unterminated {
+		return []string{"unterminated frontmatter"}
+	}
+	_, reasons := normalizeContent(a)
+	return reasons
+}
+
+// Normalize fills in the frontmatter every artifact should carry: id, type
+// and date, plus tags recovered from a "Tags:" body line. Tags written
+// as a plain comma-separated string are rewritten as a YAML list. Existing
+// values are never overwritten. ok is false when nothing needs to change.
+func Normalize(a Artifact) (Change, bool) {
+	if _, _, _, unterminated := splitFrontmatter(a.Content); unterminated {
+		return Change{}, false
+	}
+	content, reasons := normalizeContent(a)
+	if len(reasons) == 0 {
+		return Change{}, false
+	}
+	return Change{Path: a.Path, Reasons: reasons, Old: a.Content, New: content}, true
+}
+
+// NormalizeAll runs Normalize over artifacts and returns the changes.
+func NormalizeAll(artifacts []Artifact) []Change {
+	var changes []Change
+	for _, a := range artifacts {
+		if c, ok := Normalize(a); ok {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+func normalizeContent(a Artifact) (string, []string) {
+	fmLines, body, _, _ := splitFrontmatter(a.Content)
+	fm := parseFrontmatter(fmLines)
+	bodyFields := extractBodyFields(body)
+
+	var reasons []string
+	artifactType := fm.Get("type")
+	if artifactType == "" {
+		artifactType = a.Type()
+	}
+	if !fm.Has("id") {
+		fm.Set("id", artifactID(a.Stem(), artifactType))
+		reasons = append(reasons, "add id")
+	}
+	if !fm.Has("type") {
+		fm.Set("type", artifactType)
+		reasons = append(reasons, "add type")
+	}
+	if !fm.Has("date") {
+		fm.Set("date", artifactDate(a, bodyFields))
+		reasons = append(reasons, "add date")
+	}
+	switch {
+	case fm.Has("tags") && isScalarList(fm, "tags"):
+		fm.Set("tags", formatList(fm.List("tags")))
+		reasons = append(reasons, "rewrite tags as a list")
+	case !fm.Has("tags") && bodyFields["Tags"] != "":
+		fm.Set("tags", formatList(splitList(bodyFields["Tags"])))
+		reasons = append(reasons, "add tags from body")
+	}
+
+	if len(reasons) == 0 {
+		return a.Content, nil
+	}
+	return fm.Render() + body, reasons
+}
+
+// artifactID derives an id from the file stem, prefixed with the type
+// unless the stem already carries it.
+func artifactID(stem, artifactType string) string {
+	if strings.HasPrefix(stem, artifactType+"-") {
+		return stem
+	}
+	return artifactType + "-" + stem
+}
+
+// artifactDate picks the file-name date, then a bold body date, then mtime.
+func artifactDate(a Artifact, bodyFields map[string]string) string {
+	if m := dateRe.FindStringSubmatch(a.Stem()); m != nil {
+		return m[1]
+	}
+	for _, key := range []string{"Date", "Discovered"} {
+		if m := dateRe.FindStringSubmatch(bodyFields[key]); m != nil {
+			return m[1]
+		}
+	}
+	return a.ModTime.Format("2006-01-02")
+}
+
+// extractBodyFields returns the first value of each bold body field.
+func extractBodyFields(body string) map[string]string {
+	fields := make(map[string]string)
+	for _, m := range bodyFieldRe.FindAllStringSubmatch(body, -1) {
+		if _, seen := fields[m[1]]; !seen {
+			fields[m[1]] = m[2]
+		}
+	}
+	return fields
+}
+
+// isScalarList reports whether key holds a plain string rather than an
+// inline or block YAML list.
+func isScalarList(fm *Frontmatter, key string) bool {
+	value := strings.TrimSpace(strings.TrimPrefix(fm.fields[fm.index(key)].Lines[0], key+":"))
+	if value == "" || strings.HasPrefix(value, "[") {
+		return false
+	}
+	return len(fm.fields[fm.index(key)].Lines) == 1
+}
@@ -0,0 +1,232 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// sniffSize is how much of a transcript is buffered for dialect detection.
+const sniffSize = 64 * 1024
+
+// sniffLines is how many non-empty lines a JSONL dialect inspects.
+const sniffLines = 5
+
+// TranscriptDialect normalizes one agent runtime's session log into
+// TranscriptMessages.
+//
+// A dialect either reads one JSON record per line (Document returns false)
+// or the whole transcript as a single JSON document (Document returns true,
+// e.g. an exported session). ParseRecord receives a line or the whole
+// document accordingly.
+type TranscriptDialect interface {
+	// Name identifies the dialect, e.g. "claude" or "opencode".
+	Name() string
+
+	// Sniff reports whether head, the first bytes of a transcript, looks
+	// like this dialect. head may end mid-record.
+	Sniff(head []byte) bool
+
+	// Document reports whether the transcript is one JSON document rather
+	// than JSONL.
+	Document() bool
+
+	// ParseRecord converts one record into zero or more messages. lineNum
+	// is the 1-based line of the record (1 for documents).
+	ParseRecord(p *Parser, record []byte, lineNum int) ([]types.TranscriptMessage, error)
+}
+
+// dialects is the registry. User-registered dialects are sniffed before the
+// built-ins; the built-ins are ordered most specific first.
+var dialects = struct {
+	sync.RWMutex
+	list []TranscriptDialect
+}{
+	list: []TranscriptDialect{openCodeDialect{}, cursorDialect{}, claudeDialect, codexDialect},
+}
+
+// RegisterDialect adds a dialect to the registry. It panics if d is nil or
+// its name is already registered, like database/sql.Register.
+func RegisterDialect(d TranscriptDialect) {
+	if d == nil {
+		panic("parser: RegisterDialect dialect is nil")
+	}
+	dialects.Lock()
+	defer dialects.Unlock()
+	for _, existing := range dialects.list {
+		if existing.Name() == d.Name() {
+			panic("parser: RegisterDialect called twice for dialect " + d.Name())
+		}
+	}
+	dialects.list = slices.Insert(dialects.list, 0, d)
+}
+
+// Dialects returns the registered dialects in sniffing order.
+func Dialects() []TranscriptDialect {
+	dialects.RLock()
+	defer dialects.RUnlock()
+	return slices.Clone(dialects.list)
+}
+
+// DialectNames returns the registered dialect names, sorted.
+func DialectNames() []string {
+	var names []string
+	for _, d := range Dialects() {
+		names = append(names, d.Name())
+	}
+	slices.Sort(names)
+	return names
+}
+
+// LookupDialect returns the dialect registered under name.
+func LookupDialect(name string) (TranscriptDialect, error) {
+	for _, d := range Dialects() {
+		if d.Name() == name {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown transcript dialect %q (known: %s)", name, strings.Join(DialectNames(), ", "))
+}
+
+// DetectDialect returns the first dialect whose Sniff accepts head. When
+// none does it falls back to the Claude dialect, the historical default.
+func DetectDialect(head []byte) TranscriptDialect {
+	for _, d := range Dialects() {
+		if d.Sniff(head) {
+			return d
+		}
+	}
+	return claudeDialect
+}
+
+// dialectFor returns the parser's forced dialect, or sniffs one from br
+// without consuming it.
+func (p *Parser) dialectFor(br *bufio.Reader) TranscriptDialect {
+	if p.Dialect != nil {
+		return p.Dialect
+	}
+	head, _ := br.Peek(sniffSize) // a short read still has everything there is
+	return DetectDialect(head)
+}
+
+// recordFunc parses one type-tagged JSONL record.
+type recordFunc func(p *Parser, raw rawMessage, lineNum int) (*types.TranscriptMessage, error)
+
+// recordDialect is a JSONL dialect whose records carry a top-level "type"
+// tag. Claude and Codex are both record dialects.
+type recordDialect struct {
+	name    string
+	records map[string]recordFunc
+}
+
+// claudeDialect reads Claude Code session JSONL.
+var claudeDialect = &recordDialect{
+	name: "claude",
+	records: map[string]recordFunc{
+		msgTypeUser: func(p *Parser, raw rawMessage, lineNum int) (*types.TranscriptMessage, error) {
+			return p.parseClaudeMessage(raw, lineNum), nil
+		},
+		msgTypeAssistant: func(p *Parser, raw rawMessage, lineNum int) (*types.TranscriptMessage, error) {
+			return p.parseClaudeMessage(raw, lineNum), nil
+		},
+		msgTypeToolUse: func(p *Parser, raw rawMessage, lineNum int) (*types.TranscriptMessage, error) {
+			return p.parseClaudeToolUse(raw, lineNum), nil
+		},
+		msgTypeToolResult: func(p *Parser, raw rawMessage, lineNum int) (*types.TranscriptMessage, error) {
+			return p.parseClaudeToolResult(raw, lineNum), nil
+		},
+	},
+}
+
+// codexDialect reads Codex CLI session JSONL.
+var codexDialect = &recordDialect{
+	name: "codex",
+	records: map[string]recordFunc{
+		"session_meta":  (*Parser).parseCodexSessionMeta,
+		"event_msg":     (*Parser).parseCodexEvent,
+		"response_item": (*Parser).parseCodexResponseItem,
+	},
+}
+
+// recordDialects are consulted together when parsing a record line. Their
+// type tags never collide, so a stream mixing both still parses.
+var recordDialects = []*recordDialect{claudeDialect, codexDialect}
+
+func (d *recordDialect) Name() string   { return d.name }
+func (d *recordDialect) Document() bool { return false }
+
+// Sniff accepts head when one of its first lines carries a type tag this
+// dialect handles.
+func (d *recordDialect) Sniff(head []byte) bool {
+	seen := 0
+	for line := range bytes.Lines(head) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var tag struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(line, &tag) == nil {
+			if _, ok := d.records[tag.Type]; ok {
+				return true
+			}
+		}
+		if seen++; seen == sniffLines {
+			break
+		}
+	}
+	return false
+}
+
+func (d *recordDialect) ParseRecord(p *Parser, record []byte, lineNum int) ([]types.TranscriptMessage, error) {
+	msg, err := p.parseLine(record, lineNum)
+	if err != nil || msg == nil {
+		return nil, err
+	}
+	return []types.TranscriptMessage{*msg}, nil
+}
+
+// hasJSONKey reports whether head contains key as an object key. Quotes
+// inside string values are escaped, so a match preceded by a backslash is
+// text, not a key.
+func hasJSONKey(head []byte, key string) bool {
+	needle := []byte(`"` + key + `"`)
+	for i := 0; ; {
+		j := bytes.Index(head[i:], needle)
+		if j < 0 {
+			return false
+		}
+		start := i + j
+		end := start + len(needle)
+		i = end
+		if start > 0 && head[start-1] == '\\' {
+			continue
+		}
+		rest := bytes.TrimLeft(head[end:], " \t\r\n")
+		if len(rest) > 0 && rest[0] == ':' {
+			return true
+		}
+	}
+}
+
+// startsJSON reports whether head opens with a JSON object or array.
+func startsJSON(head []byte) bool {
+	trimmed := bytes.TrimLeft(head, " \t\r\n")
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// unixMillis converts epoch milliseconds to UTC time; zero stays zero.
+func unixMillis(ms float64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(ms)).UTC()
+}
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// cursorDialect reads Cursor chat exports: composer sessions
+// ({"composerId", "conversation": [bubbles]}) or the older chat tabs
+// ({"tabs": [{"bubbles": [...]}]}). A document may also be an array of
+// composer sessions.
+type cursorDialect struct{}
+
+type cursorBubble struct {
+	// Type is 1 (user) or 2 (assistant) in composer data, "user" or "ai"
+	// in chat tabs.
+	Type       any     `json:"type"`
+	Text       string  `json:"text"`
+	RawText    string  `json:"rawText"`
+	CreatedAt  float64 `json:"createdAt"`
+	TimingInfo struct {
+		ClientStartTime float64 `json:"clientStartTime"`
+	} `json:"timingInfo"`
+	ToolFormerData *struct {
+		Name    string `json:"name"`
+		RawArgs string `json:"rawArgs"`
+		Result  string `json:"result"`
+		Status  string `json:"status"`
+	} `json:"toolFormerData"`
+}
+
+type cursorComposer struct {
+	ComposerID   string         `json:"composerId"`
+	CreatedAt    float64        `json:"createdAt"`
+	Conversation []cursorBubble `json:"conversation"`
+	Tabs         []struct {
+		TabID        string         `json:"tabId"`
+		LastSendTime float64        `json:"lastSendTime"`
+		Bubbles      []cursorBubble `json:"bubbles"`
+	} `json:"tabs"`
+}
+
+func (cursorDialect) Name() string   { return "cursor" }
+func (cursorDialect) Document() bool { return true }
+
+func (cursorDialect) Sniff(head []byte) bool {
+	if !startsJSON(head) {
+		return false
+	}
+	return (hasJSONKey(head, "composerId") && hasJSONKey(head, "conversation")) ||
+		(hasJSONKey(head, "tabs") && hasJSONKey(head, "bubbles"))
+}
+
+func (cursorDialect) ParseRecord(p *Parser, record []byte, _ int) ([]types.TranscriptMessage, error) {
+	var composers []cursorComposer
+	if trimmed := bytes.TrimSpace(record); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &composers); err != nil {
+			return nil, fmt.Errorf("invalid cursor export: %w", err)
+		}
+	} else {
+		var c cursorComposer
+		if err := json.Unmarshal(trimmed, &c); err != nil {
+			return nil, fmt.Errorf("invalid cursor export: %w", err)
+		}
+		composers = []cursorComposer{c}
+	}
+
+	var msgs []types.TranscriptMessage
+	for _, c := range composers {
+		msgs = p.appendCursorBubbles(msgs, c.Conversation, c.ComposerID, c.CreatedAt)
+		for _, tab := range c.Tabs {
+			msgs = p.appendCursorBubbles(msgs, tab.Bubbles, tab.TabID, tab.LastSendTime)
+		}
+	}
+	return msgs, nil
+}
+
+// appendCursorBubbles converts one conversation's bubbles to messages.
+// Bubbles without their own timestamp inherit fallbackMillis.
+func (p *Parser) appendCursorBubbles(msgs []types.TranscriptMessage, bubbles []cursorBubble, sessionID string, fallbackMillis float64) []types.TranscriptMessage {
+	for _, b := range bubbles {
+		role := cursorRole(b.Type)
+		if role == "" {
+			continue
+		}
+		msg := types.TranscriptMessage{
+			Type:         role,
+			Role:         role,
+			Content:      p.truncate(coalesce(b.Text, b.RawText)),
+			Timestamp:    unixMillis(coalesceMillis(b.TimingInfo.ClientStartTime, b.CreatedAt, fallbackMillis)),
+			SessionID:    sessionID,
+			MessageIndex: len(msgs) + 1,
+		}
+		if t := b.ToolFormerData; t != nil && t.Name != "" {
+			call := types.ToolCall{
+				Name:   t.Name,
+				Input:  parseCodexToolInput(t.RawArgs),
+				Output: p.truncate(t.Result),
+			}
+			if t.Status == "error" {
+				call.Error = "tool error"
+			}
+			msg.Tools = []types.ToolCall{call}
+		}
+		if msg.Content == "" && len(msg.Tools) == 0 {
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// cursorRole maps a bubble type to a transcript role.
+func cursorRole(t any) string {
+	switch t {
+	case float64(1), "user":
+		return msgTypeUser
+	case float64(2), "ai", "assistant":
+		return msgTypeAssistant
+	default:
+		return ""
+	}
+}
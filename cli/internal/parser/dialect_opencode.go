@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// openCodeDialect reads OpenCode session exports (opencode export <id>):
+// one JSON document holding the session info and its messages, each split
+// into typed parts.
+type openCodeDialect struct{}
+
+type openCodeTime struct {
+	Created float64 `json:"created"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+}
+
+type openCodeExport struct {
+	Info struct {
+		ID   string       `json:"id"`
+		Time openCodeTime `json:"time"`
+	} `json:"info"`
+	Messages []struct {
+		Info struct {
+			ID        string       `json:"id"`
+			SessionID string       `json:"sessionID"`
+			Role      string       `json:"role"`
+			Time      openCodeTime `json:"time"`
+		} `json:"info"`
+		Parts []openCodePart `json:"parts"`
+	} `json:"messages"`
+}
+
+type openCodePart struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Tool  string `json:"tool"`
+	State struct {
+		Status string         `json:"status"`
+		Input  map[string]any `json:"input"`
+		Output string         `json:"output"`
+		Error  string         `json:"error"`
+		Time   openCodeTime   `json:"time"`
+	} `json:"state"`
+}
+
+func (openCodeDialect) Name() string   { return "opencode" }
+func (openCodeDialect) Document() bool { return true }
+
+func (openCodeDialect) Sniff(head []byte) bool {
+	return startsJSON(head) && hasJSONKey(head, "info") && hasJSONKey(head, "messages") && hasJSONKey(head, "parts")
+}
+
+func (openCodeDialect) ParseRecord(p *Parser, record []byte, _ int) ([]types.TranscriptMessage, error) {
+	var export openCodeExport
+	if err := json.Unmarshal(record, &export); err != nil {
+		return nil, fmt.Errorf("invalid opencode export: %w", err)
+	}
+
+	var msgs []types.TranscriptMessage
+	for _, m := range export.Messages {
+		if m.Info.Role != msgTypeUser && m.Info.Role != msgTypeAssistant {
+			continue
+		}
+		var content strings.Builder
+		var tools []types.ToolCall
+		for _, part := range m.Parts {
+			switch part.Type {
+			case "text":
+				content.WriteString(part.Text)
+			case "tool":
+				tools = append(tools, p.openCodeToolCall(part))
+			}
+		}
+		if content.Len() == 0 && len(tools) == 0 {
+			continue
+		}
+		msgs = append(msgs, types.TranscriptMessage{
+			Type:         m.Info.Role,
+			Role:         m.Info.Role,
+			Content:      p.truncate(content.String()),
+			Tools:        tools,
+			Timestamp:    unixMillis(coalesceMillis(m.Info.Time.Created, export.Info.Time.Created)),
+			SessionID:    coalesce(m.Info.SessionID, export.Info.ID),
+			MessageIndex: len(msgs) + 1,
+		})
+	}
+	return msgs, nil
+}
+
+func (p *Parser) openCodeToolCall(part openCodePart) types.ToolCall {
+	call := types.ToolCall{
+		Name:   part.Tool,
+		Input:  part.State.Input,
+		Output: p.truncate(part.State.Output),
+		Error:  part.State.Error,
+	}
+	if part.State.Status == "error" && call.Error == "" {
+		call.Error = "tool error"
+	}
+	if start, end := part.State.Time.Start, part.State.Time.End; start > 0 && end > start {
+		call.Duration = time.Duration(end-start) * time.Millisecond
+	}
+	return call
+}
+
+// coalesceMillis returns the first positive timestamp.
+func coalesceMillis(values ...float64) float64 {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "update golden files")
+
+// TestDialects_Golden parses testdata/dialects/<name>/transcript.* with
+// auto-detection and compares the normalized messages against
+// expected.golden.json. Refresh with:
+//
+//	go test ./internal/parser -run TestDialects_Golden -update-golden
+func TestDialects_Golden(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("testdata", "dialects", "*"))
+	if err != nil || len(dirs) == 0 {
+		t.Fatalf("no dialect fixtures: %v", err)
+	}
+	for _, dir := range dirs {
+		name := filepath.Base(dir)
+		t.Run(name, func(t *testing.T) {
+			inputs, _ := filepath.Glob(filepath.Join(dir, "transcript.*"))
+			if len(inputs) != 1 {
+				t.Fatalf("want one transcript fixture in %s, got %v", dir, inputs)
+			}
+
+			result, err := NewParser().ParseFile(inputs[0])
+			if err != nil {
+				t.Fatalf("ParseFile: %v", err)
+			}
+			if result.Dialect != name {
+				t.Fatalf("detected dialect %q, want %q", result.Dialect, name)
+			}
+			got, err := json.MarshalIndent(result.Messages, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join(dir, "expected.golden.json")
+			if *updateGolden {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatalf("update golden: %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden %s: %v (run with -update-golden to create)", golden, err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("messages mismatch for %s\n--- want\n%s\n--- got\n%s", name, want, got)
+			}
+		})
+	}
+}
+
+func TestParseChannel_DocumentDialect(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "dialects", "opencode", "transcript.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgCh, errCh := NewParser().ParseChannel(strings.NewReader(string(data)))
+	var msgs []types.TranscriptMessage
+	for msg := range msgCh {
+		msgs = append(msgs, msg)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("ParseChannel: %v", err)
+	}
+	if len(msgs) != 3 {
+		t.Fatalf("got %d messages, want 3", len(msgs))
+	}
+	if msgs[1].Tools[0].Name != "read" || msgs[1].Tools[1].Error != "exit status 2" {
+		t.Errorf("unexpected tool calls: %+v", msgs[1].Tools)
+	}
+}
+
+func TestDetectDialect(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want string
+	}{
+		{"claude", `{"type":"user","message":{"role":"user","content":"hi"}}`, "claude"},
+		{"codex", `{"type":"session_meta","payload":{"id":"x"}}`, "codex"},
+		{"codex after blank line", "\n" + `{"type":"event_msg","payload":{"type":"user_message"}}`, "codex"},
+		{"opencode", `{"info":{"id":"ses_1"},"messages":[{"info":{},"parts":[]}]}`, "opencode"},
+		{"cursor composer", `{"composerId":"c1","conversation":[]}`, "cursor"},
+		{"cursor tabs", `{"tabs":[{"bubbles":[]}]}`, "cursor"},
+		{"cursor array", `[{"composerId":"c1","conversation":[]}]`, "cursor"},
+		{"keys inside claude text", `{"type":"user","message":{"content":"{\"info\": 1, \"messages\": 2, \"parts\": 3}"}}`, "claude"},
+		{"unknown falls back to claude", `{"kind":"other"}`, "claude"},
+		{"empty", ``, "claude"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectDialect([]byte(tt.head)).Name(); got != tt.want {
+				t.Errorf("DetectDialect = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_ForcedDialect(t *testing.T) {
+	d, err := LookupDialect("codex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewParser()
+	p.Dialect = d
+	result, err := p.Parse(strings.NewReader(`{"type":"event_msg","payload":{"type":"agent_message","message":"done"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Dialect != "codex" || len(result.Messages) != 1 || result.Messages[0].Content != "done" {
+		t.Fatalf("unexpected result: dialect=%q messages=%+v", result.Dialect, result.Messages)
+	}
+}
+
+func TestParser_MalformedDocument(t *testing.T) {
+	p := NewParser()
+	p.SkipMalformed = false
+	p.Dialect = cursorDialect{}
+	result, err := p.Parse(strings.NewReader("{\n\"composerId\": \"c1\",\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MalformedLines != 1 || len(result.Errors) != 1 {
+		t.Fatalf("MalformedLines=%d Errors=%v, want one malformed document", result.MalformedLines, result.Errors)
+	}
+	if result.TotalLines != 2 {
+		t.Errorf("TotalLines = %d, want 2", result.TotalLines)
+	}
+}
+
+func TestCursorDialect_ChatTabs(t *testing.T) {
+	doc := `{"tabs":[{"tabId":"tab-1","lastSendTime":1767348000000,"bubbles":[
+		{"type":"user","text":"What does ao forge do?"},
+		{"type":"ai","rawText":"It extracts knowledge from transcripts."},
+		{"type":"user","text":""}]}]}`
+	result, err := NewParser().Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Messages) != 2 {
+		t.Fatalf("got %d messages, want 2: %+v", len(result.Messages), result.Messages)
+	}
+	ai := result.Messages[1]
+	if ai.Role != "assistant" || ai.Content != "It extracts knowledge from transcripts." || ai.SessionID != "tab-1" {
+		t.Errorf("unexpected assistant message: %+v", ai)
+	}
+	if ai.Timestamp.IsZero() {
+		t.Error("expected tab lastSendTime as fallback timestamp")
+	}
+}
+
+type stubDialect struct{ name string }
+
+func (d stubDialect) Name() string         { return d.name }
+func (stubDialect) Document() bool         { return false }
+func (stubDialect) Sniff(head []byte) bool { return strings.Contains(string(head), `"gemini"`) }
+func (stubDialect) ParseRecord(_ *Parser, record []byte, lineNum int) ([]types.TranscriptMessage, error) {
+	return []types.TranscriptMessage{{Type: "user", Role: "user", Content: string(record), MessageIndex: lineNum}}, nil
+}
+
+func TestRegisterDialect(t *testing.T) {
+	orig := Dialects()
+	t.Cleanup(func() {
+		dialects.Lock()
+		dialects.list = orig
+		dialects.Unlock()
+	})
+
+	RegisterDialect(stubDialect{name: "stub"})
+	if d, err := LookupDialect("stub"); err != nil || d.Name() != "stub" {
+		t.Fatalf("LookupDialect(stub) = %v, %v", d, err)
+	}
+	if got := DetectDialect([]byte(`{"type":"user","gemini":true}`)).Name(); got != "stub" {
+		t.Errorf("registered dialects should sniff first, got %q", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate registration")
+		}
+	}()
+	RegisterDialect(stubDialect{name: "claude"})
+}
+
+func TestLookupDialect_Unknown(t *testing.T) {
+	_, err := LookupDialect("gemini")
+	if err == nil || !strings.Contains(err.Error(), "claude, codex, cursor, opencode") {
+		t.Fatalf("err = %v, want unknown dialect listing known names", err)
+	}
+}
@@ -3,6 +3,7 @@ package parser
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/boshu2/agentops/cli/internal/types"
@@ -10,9 +11,16 @@ import (
 
 // ExtractionPattern defines a pattern for identifying knowledge types.
 type ExtractionPattern struct {
+	// Name identifies the rule in reports and overrides (defaults to Type).
+	Name string
+
 	// Type is the knowledge type this pattern identifies.
 	Type types.KnowledgeType
 
+	// Roles limits the pattern to messages from these roles: "user",
+	// "assistant" or "tool" (tool_use/tool_result messages). Empty means all.
+	Roles []string
+
 	// Keywords are phrases that suggest this knowledge type.
 	Keywords []string
 
@@ -26,6 +34,7 @@ type ExtractionPattern struct {
 // DefaultPatterns provides the standard extraction patterns.
 var DefaultPatterns = []ExtractionPattern{
 	{
+		Name: "decision",
 		Type: types.KnowledgeTypeDecision,
 		Keywords: []string{
 			// Explicit markers
@@ -68,6 +77,7 @@ var DefaultPatterns = []ExtractionPattern{
 		MinScore: 0.6,
 	},
 	{
+		Name: "solution",
 		Type: types.KnowledgeTypeSolution,
 		Keywords: []string{
 			"**Solution:**",
@@ -87,6 +97,7 @@ var DefaultPatterns = []ExtractionPattern{
 		MinScore: 0.7,
 	},
 	{
+		Name: "learning",
 		Type: types.KnowledgeTypeLearning,
 		Keywords: []string{
 			"**Learning:**",
@@ -106,6 +117,7 @@ var DefaultPatterns = []ExtractionPattern{
 		MinScore: 0.5,
 	},
 	{
+		Name: "failure",
 		Type: types.KnowledgeTypeFailure,
 		Keywords: []string{
 			"**Failure:**",
@@ -125,6 +137,7 @@ var DefaultPatterns = []ExtractionPattern{
 		MinScore: 0.6,
 	},
 	{
+		Name: "reference",
 		Type: types.KnowledgeTypeReference,
 		Keywords: []string{
 			"**Reference:**",
@@ -145,6 +158,9 @@ var DefaultPatterns = []ExtractionPattern{
 
 // ExtractionResult represents a potential knowledge extraction.
 type ExtractionResult struct {
+	// Rule is the name of the pattern that matched.
+	Rule string
+
 	// Type is the identified knowledge type.
 	Type types.KnowledgeType
 
@@ -182,13 +198,34 @@ func (e *Extractor) Extract(msg types.TranscriptMessage) []ExtractionResult {
 	if msg.Content == "" {
 		return nil
 	}
+	return deduplicateByType(e.ExtractAll(msg))
+}
+
+// ExtractAll returns every rule match in a message, before Extract keeps
+// the best one per knowledge type. It is what ao forge rules test reports.
+func (e *Extractor) ExtractAll(msg types.TranscriptMessage) []ExtractionResult {
+	if msg.Content == "" {
+		return nil
+	}
 
+	role := MessageRole(msg)
 	var results []ExtractionResult
 	for _, pattern := range e.Patterns {
+		if len(pattern.Roles) > 0 && !slices.Contains(pattern.Roles, role) {
+			continue
+		}
 		results = append(results, matchPattern(msg.Content, pattern)...)
 	}
+	return results
+}
 
-	return deduplicateByType(results)
+// MessageRole classifies a message for rule role filters: "tool" for tool
+// calls and results, otherwise the message role.
+func MessageRole(msg types.TranscriptMessage) string {
+	if msg.Type == msgTypeToolUse || msg.Type == msgTypeToolResult {
+		return "tool"
+	}
+	return coalesce(msg.Role, msg.Type)
 }
 
 // matchPattern returns extraction results from keyword and regex matching for a single pattern.
@@ -197,6 +234,7 @@ func matchPattern(content string, pattern ExtractionPattern) []ExtractionResult
 	for _, keyword := range pattern.Keywords {
 		if idx := strings.Index(strings.ToLower(content), strings.ToLower(keyword)); idx >= 0 {
 			results = append(results, ExtractionResult{
+				Rule:           pattern.RuleName(),
 				Type:           pattern.Type,
 				Score:          pattern.MinScore + 0.1,
 				MatchedKeyword: keyword,
@@ -209,6 +247,7 @@ func matchPattern(content string, pattern ExtractionPattern) []ExtractionResult
 	for _, re := range pattern.Patterns {
 		if loc := re.FindStringIndex(content); loc != nil {
 			results = append(results, ExtractionResult{
+				Rule:           pattern.RuleName(),
 				Type:           pattern.Type,
 				Score:          pattern.MinScore + 0.2,
 				MatchedPattern: re.String(),
@@ -221,6 +260,11 @@ func matchPattern(content string, pattern ExtractionPattern) []ExtractionResult
 	return results
 }
 
+// RuleName is the pattern's name, or its type when it has none.
+func (p ExtractionPattern) RuleName() string {
+	return coalesce(p.Name, string(p.Type))
+}
+
 // deduplicateByType keeps only the highest-scoring result per knowledge type.
 func deduplicateByType(results []ExtractionResult) []ExtractionResult {
 	seen := make(map[types.KnowledgeType]ExtractionResult)
@@ -1,4 +1,7 @@
-// Package parser provides streaming JSONL parsing for Claude and Codex transcripts.
+// Package parser provides streaming parsing for agent session transcripts.
+// Each runtime's log format is a TranscriptDialect (Claude, Codex, OpenCode
+// and Cursor are built in); the parser sniffs the dialect from the first
+// lines unless one is set.
 package parser
 
 import (
@@ -45,6 +48,9 @@ type Parser struct {
 
 	// OnProgress is called with progress updates for large files.
 	OnProgress func(linesProcessed, totalLines int)
+
+	// Dialect forces a transcript dialect. Nil sniffs it from the input.
+	Dialect TranscriptDialect
 }
 
 // NewParser creates a parser with default settings.
@@ -120,6 +126,9 @@ type ParseResult struct {
 
 	// ParsedAt is when parsing completed.
 	ParsedAt time.Time
+
+	// Dialect is the name of the dialect the transcript was parsed as.
+	Dialect string
 }
 
 // ParseError provides structured error information for transcript parsing failures.
@@ -138,31 +147,35 @@ func (e *ParseError) Error() string {
 	return fmt.Sprintf("line %d: %s (%s)", e.Line, e.Message, e.ErrorType)
 }
 
-// Parse reads JSONL from the reader and returns parsed messages.
+// Parse reads a transcript from the reader and returns parsed messages.
 func (p *Parser) Parse(r io.Reader) (*ParseResult, error) {
 	result := &ParseResult{
 		Messages: make([]types.TranscriptMessage, 0),
 	}
 
+	br := bufio.NewReaderSize(r, sniffSize)
+	dialect := p.dialectFor(br)
+	result.Dialect = dialect.Name()
+
 	hasher := sha256.New()
-	if err := readJSONLLines(r, func(line []byte, lineNum int) error {
+	if err := readRecords(br, dialect, func(record []byte, lineNum int) error {
 		result.TotalLines = lineNum
 
-		if len(line) == 0 {
+		if len(record) == 0 {
 			return nil
 		}
 
-		_, _ = hasher.Write(line)
+		_, _ = hasher.Write(record)
 		_, _ = hasher.Write([]byte("\n"))
 
-		p.processLine(line, lineNum, result)
+		p.processLine(dialect, record, lineNum, result)
 
 		if p.OnProgress != nil && lineNum%100 == 0 {
 			p.OnProgress(lineNum, 0)
 		}
 		return nil
 	}); err != nil {
-		return result, fmt.Errorf("read jsonl: %w", err)
+		return result, fmt.Errorf("read %s: %w", recordSource(dialect), err)
 	}
 
 	hash := hasher.Sum(nil)
@@ -172,9 +185,39 @@ func (p *Parser) Parse(r io.Reader) (*ParseResult, error) {
 	return result, nil
 }
 
-// processLine parses a single JSONL line and appends the result or error.
-func (p *Parser) processLine(line []byte, lineNum int, result *ParseResult) {
-	msg, err := p.parseLine(line, lineNum)
+// recordSource names the input kind for read errors.
+func recordSource(dialect TranscriptDialect) string {
+	if dialect.Document() {
+		return dialect.Name() + " export"
+	}
+	return "jsonl"
+}
+
+// readRecords calls fn for each record of a transcript: each line for JSONL
+// dialects, or the whole input once for document dialects. For documents
+// lineNum is the document's line count.
+func readRecords(r io.Reader, dialect TranscriptDialect, fn func([]byte, int) error) error {
+	if !dialect.Document() {
+		return readJSONLLines(r, fn)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	lines := bytes.Count(data, []byte("\n"))
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		lines++
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+	return fn(data, lines)
+}
+
+// processLine parses a single record and appends the results or error.
+func (p *Parser) processLine(dialect TranscriptDialect, line []byte, lineNum int, result *ParseResult) {
+	msgs, err := dialect.ParseRecord(p, line, lineNum)
 	if err != nil {
 		result.MalformedLines++
 		if !p.SkipMalformed {
@@ -187,9 +230,7 @@ func (p *Parser) processLine(line []byte, lineNum int, result *ParseResult) {
 		}
 		return
 	}
-	if msg != nil {
-		result.Messages = append(result.Messages, *msg)
-	}
+	result.Messages = append(result.Messages, msgs...)
 }
 
 // classifyError determines the error type for structured reporting.
@@ -224,7 +265,7 @@ var openFileFunc = func(path string) (io.ReadCloser, error) {
 	return os.Open(path)
 }
 
-// ParseFile parses a JSONL file by path.
+// ParseFile parses a transcript file by path.
 func (p *Parser) ParseFile(path string) (result *ParseResult, err error) {
 	f, err := openFileFunc(path)
 	if err != nil {
@@ -306,29 +347,19 @@ func (p *Parser) classifyBlock(blockMap map[string]any) (string, *types.ToolCall
 	return "", nil
 }
 
-// parseLine parses a single JSON line.
+// parseLine parses a single Claude or Codex JSONL record.
 func (p *Parser) parseLine(line []byte, lineNum int) (*types.TranscriptMessage, error) {
 	var raw rawMessage
 	if err := json.Unmarshal(line, &raw); err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w", err)
 	}
 
-	switch raw.Type {
-	case msgTypeUser, msgTypeAssistant:
-		return p.parseClaudeMessage(raw, lineNum), nil
-	case msgTypeToolUse:
-		return p.parseClaudeToolUse(raw, lineNum), nil
-	case msgTypeToolResult:
-		return p.parseClaudeToolResult(raw, lineNum), nil
-	case "session_meta":
-		return p.parseCodexSessionMeta(raw, lineNum)
-	case "event_msg":
-		return p.parseCodexEvent(raw, lineNum)
-	case "response_item":
-		return p.parseCodexResponseItem(raw, lineNum)
-	default:
-		return nil, nil
+	for _, d := range recordDialects {
+		if parse, ok := d.records[raw.Type]; ok {
+			return parse(p, raw, lineNum)
+		}
 	}
+	return nil, nil
 }
 
 func (p *Parser) parseClaudeMessage(raw rawMessage, lineNum int) *types.TranscriptMessage {
@@ -627,7 +658,8 @@ func (p *Parser) ParseChannel(r io.Reader) (<-chan types.TranscriptMessage, <-ch
 	go func() {
 		defer close(msgCh)
 		defer close(errCh)
-		p.channelScanner(r, msgCh, errCh)
+		br := bufio.NewReaderSize(r, sniffSize)
+		p.channelScanner(br, p.dialectFor(br), msgCh, errCh)
 	}()
 
 	return msgCh, errCh
@@ -635,11 +667,11 @@ func (p *Parser) ParseChannel(r io.Reader) (<-chan types.TranscriptMessage, <-ch
 
 // processChannelLine parses one scanner line and forwards the result to msgCh/errCh.
 // Returns false if scanning should stop (fatal parse error).
-func (p *Parser) processChannelLine(line []byte, lineNum int, msgCh chan<- types.TranscriptMessage, errCh chan<- error) bool {
+func (p *Parser) processChannelLine(dialect TranscriptDialect, line []byte, lineNum int, msgCh chan<- types.TranscriptMessage, errCh chan<- error) bool {
 	if len(line) == 0 {
 		return true
 	}
-	msg, err := p.parseLine(line, lineNum)
+	msgs, err := dialect.ParseRecord(p, line, lineNum)
 	if err != nil {
 		if !p.SkipMalformed {
 			errCh <- fmt.Errorf("line %d: %w", lineNum, err)
@@ -647,21 +679,21 @@ func (p *Parser) processChannelLine(line []byte, lineNum int, msgCh chan<- types
 		}
 		return true
 	}
-	if msg != nil {
-		msgCh <- *msg
+	for _, msg := range msgs {
+		msgCh <- msg
 	}
 	return true
 }
 
 // channelScanner scans r line by line, sending parsed messages to msgCh and errors to errCh.
-func (p *Parser) channelScanner(r io.Reader, msgCh chan<- types.TranscriptMessage, errCh chan<- error) {
-	if err := readJSONLLines(r, func(line []byte, lineNum int) error {
-		if !p.processChannelLine(line, lineNum, msgCh, errCh) {
+func (p *Parser) channelScanner(r io.Reader, dialect TranscriptDialect, msgCh chan<- types.TranscriptMessage, errCh chan<- error) {
+	if err := readRecords(r, dialect, func(line []byte, lineNum int) error {
+		if !p.processChannelLine(dialect, line, lineNum, msgCh, errCh) {
 			return errStopChannelScan
 		}
 		return nil
 	}); err != nil && !errors.Is(err, errStopChannelScan) {
-		errCh <- fmt.Errorf("read jsonl: %w", err)
+		errCh <- fmt.Errorf("read %s: %w", recordSource(dialect), err)
 	}
 }
 
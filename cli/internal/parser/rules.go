@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// ExtractionRulesFile is the per-repo extraction rules path, relative to the
+// repo root.
+const ExtractionRulesFile = ".agents/extraction-rules.yaml"
+
+// ExtractionRule is one rule in an extraction rules file.
+type ExtractionRule struct {
+	Name     string   `yaml:"name"`
+	Type     string   `yaml:"type"`
+	Roles    []string `yaml:"roles,omitempty"`
+	Keywords []string `yaml:"keywords,omitempty"`
+	Patterns []string `yaml:"patterns,omitempty"`
+	Score    float64  `yaml:"score"`
+	Disabled bool     `yaml:"disabled,omitempty"`
+}
+
+// ExtractionRules is the top-level structure of an extraction rules file.
+//
+// Rules extend DefaultPatterns: a rule whose name matches a default rule
+// (decision, solution, learning, failure, reference) replaces it, and
+// disabled: true removes it. Replace: true drops the defaults entirely.
+type ExtractionRules struct {
+	Replace bool             `yaml:"replace,omitempty"`
+	Rules   []ExtractionRule `yaml:"rules"`
+}
+
+// validRuleRoles are the roles a rule may filter on.
+var validRuleRoles = []string{msgTypeUser, msgTypeAssistant, "tool"}
+
+// LoadExtractor returns an extractor using the rules file at path. A
+// missing file yields the default patterns. Rule types must be knowledge
+// types defined by tax.
+func LoadExtractor(path string, tax *taxonomy.Taxonomy) (*Extractor, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewExtractor(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read extraction rules: %w", err)
+	}
+	var rules ExtractionRules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	patterns, err := rules.Compile(tax)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &Extractor{Patterns: patterns}, nil
+}
+
+// Compile validates the rules against tax and merges them with
+// DefaultPatterns.
+func (r ExtractionRules) Compile(tax *taxonomy.Taxonomy) ([]ExtractionPattern, error) {
+	var patterns []ExtractionPattern
+	if !r.Replace {
+		patterns = slices.Clone(DefaultPatterns)
+	}
+	seen := make(map[string]bool, len(r.Rules))
+	for i, rule := range r.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("rule %q: defined twice", rule.Name)
+		}
+		seen[rule.Name] = true
+
+		existing := slices.IndexFunc(patterns, func(p ExtractionPattern) bool { return p.RuleName() == rule.Name })
+		if rule.Disabled {
+			if existing >= 0 {
+				patterns = slices.Delete(patterns, existing, existing+1)
+			}
+			continue
+		}
+		pattern, err := rule.compile(tax)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		if existing >= 0 {
+			patterns[existing] = pattern
+		} else {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		return nil, errors.New("no extraction rules left")
+	}
+	return patterns, nil
+}
+
+func (r ExtractionRule) compile(tax *taxonomy.Taxonomy) (ExtractionPattern, error) {
+	if r.Type == "" {
+		return ExtractionPattern{}, errors.New("type is required")
+	}
+	if _, ok := tax.KnowledgeTypes[types.KnowledgeType(r.Type)]; !ok {
+		return ExtractionPattern{}, fmt.Errorf("unknown knowledge type %q (define it in %s)", r.Type, taxonomy.TaxonomyFile)
+	}
+	if len(r.Keywords) == 0 && len(r.Patterns) == 0 {
+		return ExtractionPattern{}, errors.New("needs at least one keyword or pattern")
+	}
+	if r.Score < 0 || r.Score > 1 {
+		return ExtractionPattern{}, fmt.Errorf("score %.2f outside [0, 1]", r.Score)
+	}
+	for _, role := range r.Roles {
+		if !slices.Contains(validRuleRoles, role) {
+			return ExtractionPattern{}, fmt.Errorf("unknown role %q (want user, assistant or tool)", role)
+		}
+	}
+	pattern := ExtractionPattern{
+		Name:     r.Name,
+		Type:     types.KnowledgeType(r.Type),
+		Roles:    r.Roles,
+		Keywords: r.Keywords,
+		MinScore: r.Score,
+	}
+	for _, expr := range r.Patterns {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return ExtractionPattern{}, fmt.Errorf("pattern %q: %w", expr, err)
+		}
+		pattern.Patterns = append(pattern.Patterns, re)
+	}
+	return pattern, nil
+}
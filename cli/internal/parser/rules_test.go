@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+func writeRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "extraction-rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// rulesTaxonomy defines the custom types the test rules use.
+func rulesTaxonomy(t *testing.T) *taxonomy.Taxonomy {
+	t.Helper()
+	tax, err := taxonomy.Parse([]byte("knowledge_types:\n  gotcha: {base_score: 0.4}\n  todo: {base_score: 0.3}\n  x: {base_score: 0.5}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tax
+}
+
+func ruleNames(patterns []ExtractionPattern) string {
+	names := make([]string, len(patterns))
+	for i, p := range patterns {
+		names[i] = p.RuleName()
+	}
+	return strings.Join(names, ",")
+}
+
+func TestLoadExtractor_MissingFileUsesDefaults(t *testing.T) {
+	e, err := LoadExtractor(filepath.Join(t.TempDir(), "nope.yaml"), taxonomy.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ruleNames(e.Patterns), ruleNames(DefaultPatterns); got != want {
+		t.Errorf("rules = %s, want defaults %s", got, want)
+	}
+}
+
+func TestLoadExtractor_ExtendOverrideDisable(t *testing.T) {
+	path := writeRules(t, `
+rules:
+  - name: gotcha
+    type: gotcha
+    roles: [assistant]
+    keywords: ["watch out"]
+    patterns: ['(?i)turns out .+ silently']
+    score: 0.7
+  - name: decision
+    type: decision
+    keywords: ["we're going with"]
+    score: 0.9
+  - name: reference
+    disabled: true
+`)
+	e, err := LoadExtractor(path, rulesTaxonomy(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ruleNames(e.Patterns); got != "decision,solution,learning,failure,gotcha" {
+		t.Fatalf("rules = %s", got)
+	}
+
+	assistant := types.TranscriptMessage{Type: "assistant", Role: "assistant",
+		Content: "It turns out the cache drops writes silently under load."}
+	results := e.ExtractAll(assistant)
+	if len(results) != 1 || results[0].Rule != "gotcha" || results[0].Type != "gotcha" {
+		t.Fatalf("assistant results = %+v, want the gotcha rule", results)
+	}
+	if results[0].Score <= 0.7 || results[0].MatchedPattern == "" {
+		t.Errorf("regex hit should score above the base and record the pattern: %+v", results[0])
+	}
+
+	// The role filter keeps the same words in a user message from firing.
+	user := assistant
+	user.Type, user.Role = "user", "user"
+	if results := e.ExtractAll(user); len(results) != 0 {
+		t.Errorf("user results = %+v, want none", results)
+	}
+
+	// The overridden decision rule no longer knows "decided to".
+	decided := types.TranscriptMessage{Type: "assistant", Content: "We decided to use Postgres."}
+	if results := e.ExtractAll(decided); len(results) != 0 {
+		t.Errorf("old decision keywords still fire: %+v", results)
+	}
+}
+
+func TestLoadExtractor_Replace(t *testing.T) {
+	path := writeRules(t, `
+replace: true
+rules:
+  - name: todo
+    type: todo
+    roles: [user]
+    keywords: ["todo"]
+    score: 0.5
+`)
+	e, err := LoadExtractor(path, rulesTaxonomy(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ruleNames(e.Patterns); got != "todo" {
+		t.Errorf("rules = %s, want only todo", got)
+	}
+}
+
+func TestLoadExtractor_Invalid(t *testing.T) {
+	tests := []struct {
+		name, yaml, wantErr string
+	}{
+		{"bad regex", "rules:\n  - {name: x, type: x, patterns: ['(']}", `rule "x": pattern "("`},
+		{"no name", "rules:\n  - {type: x, keywords: [a]}", "rule 1: name is required"},
+		{"no type", "rules:\n  - {name: x, keywords: [a]}", "type is required"},
+		{"unknown type", "rules:\n  - {name: rumors, type: rumor, keywords: [a]}", `rule "rumors": unknown knowledge type "rumor"`},
+		{"no matchers", "rules:\n  - {name: x, type: x}", "at least one keyword or pattern"},
+		{"bad role", "rules:\n  - {name: x, type: x, keywords: [a], roles: [system]}", `unknown role "system"`},
+		{"bad score", "rules:\n  - {name: x, type: x, keywords: [a], score: 2}", "outside [0, 1]"},
+		{"duplicate", "rules:\n  - {name: x, type: x, keywords: [a]}\n  - {name: x, type: x, keywords: [b]}", "defined twice"},
+		{"nothing left", "replace: true\nrules: []", "no extraction rules left"},
+		{"not yaml", "rules: [", "parse "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadExtractor(writeRules(t, tt.yaml), rulesTaxonomy(t))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMessageRole(t *testing.T) {
+	tests := []struct {
+		msg  types.TranscriptMessage
+		want string
+	}{
+		{types.TranscriptMessage{Type: "user", Role: "user"}, "user"},
+		{types.TranscriptMessage{Type: "assistant"}, "assistant"},
+		{types.TranscriptMessage{Type: "tool_result", Role: "user"}, "tool"},
+		{types.TranscriptMessage{Type: "tool_use", Role: "assistant"}, "tool"},
+	}
+	for _, tt := range tests {
+		if got := MessageRole(tt.msg); got != tt.want {
+			t.Errorf("MessageRole(%s/%s) = %q, want %q", tt.msg.Type, tt.msg.Role, got, tt.want)
+		}
+	}
+}
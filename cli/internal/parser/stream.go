@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// Position is a resumable point in a transcript: the byte offset just past
+// a record and the number of lines read up to it.
+type Position struct {
+	Offset int64 `json:"offset"`
+	Line   int   `json:"line"`
+}
+
+// Stream parses r record by record, calling fn with each message and the
+// position just past the record that produced it. Memory stays bounded by
+// the largest record, and a caller can checkpoint any position it was handed
+// and later resume by seeking to Offset and passing that position as start.
+//
+// r must already be positioned at start.Offset. A final line without a
+// newline is consumed only when it is complete JSON, so a transcript that is
+// still being written resumes at the partial line instead of skipping it.
+// Document dialects have no inner positions: the whole input is one record
+// and its end is the only position.
+//
+// Stream returns the position after the last consumed record and a
+// ParseResult carrying the dialect and line counts; its Messages stay empty.
+// If fn returns an error, Stream stops and returns that error with the
+// position before the record whose message failed.
+func (p *Parser) Stream(r io.Reader, start Position, fn func(types.TranscriptMessage, Position) error) (Position, *ParseResult, error) {
+	br := bufio.NewReaderSize(r, sniffSize)
+	dialect := p.dialectFor(br)
+	result := &ParseResult{Dialect: dialect.Name()}
+	pos := start
+
+	emit := func(record []byte, lineNum int, next Position) error {
+		msgs, err := dialect.ParseRecord(p, record, lineNum)
+		if err != nil {
+			result.MalformedLines++
+			if !p.SkipMalformed {
+				return &ParseError{
+					Line:       lineNum,
+					Message:    err.Error(),
+					ErrorType:  classifyError(err),
+					RawContent: truncateForError(string(record), 100),
+				}
+			}
+		}
+		for _, msg := range msgs {
+			if err := fn(msg, next); err != nil {
+				return err
+			}
+		}
+		pos = next
+		return nil
+	}
+
+	if dialect.Document() {
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return pos, result, fmt.Errorf("read %s: %w", recordSource(dialect), err)
+		}
+		next := Position{Offset: start.Offset + int64(len(data)), Line: start.Line + bytes.Count(data, []byte("\n"))}
+		if record := bytes.TrimSpace(data); len(record) > 0 {
+			result.TotalLines = next.Line
+			err = emit(record, start.Line+1, next)
+		}
+		pos = next
+		return pos, result, err
+	}
+
+	for {
+		raw, readErr := br.ReadBytes('\n')
+		if len(raw) > 0 {
+			terminated := raw[len(raw)-1] == '\n'
+			line := bytes.TrimSuffix(bytes.TrimSuffix(raw, []byte("\n")), []byte("\r"))
+			if !terminated && !json.Valid(line) {
+				// Partial record still being written; resume here next time.
+				return pos, result, nil
+			}
+			next := Position{Offset: pos.Offset + int64(len(raw)), Line: pos.Line + 1}
+			result.TotalLines = next.Line
+			if len(bytes.TrimSpace(line)) == 0 {
+				pos = next
+			} else if err := emit(line, next.Line, next); err != nil {
+				return pos, result, err
+			}
+			if p.OnProgress != nil && next.Line%100 == 0 {
+				p.OnProgress(next.Line, 0)
+			}
+		}
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				return pos, result, nil
+			}
+			return pos, result, fmt.Errorf("read %s: %w", recordSource(dialect), readErr)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+const streamTranscript = `{"type":"user","sessionId":"s1","message":{"role":"user","content":"first"}}
+{"type":"progress","sessionId":"s1"}
+{"type":"assistant","sessionId":"s1","message":{"role":"assistant","content":"second"}}
+`
+
+func collectStream(t *testing.T, p *Parser, input string, start Position) ([]string, []Position, Position) {
+	t.Helper()
+	var contents []string
+	var positions []Position
+	end, _, err := p.Stream(strings.NewReader(input[start.Offset:]), start, func(msg types.TranscriptMessage, pos Position) error {
+		contents = append(contents, msg.Content)
+		positions = append(positions, pos)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	return contents, positions, end
+}
+
+func TestStream_Positions(t *testing.T) {
+	contents, positions, end := collectStream(t, NewParser(), streamTranscript, Position{})
+	if strings.Join(contents, ",") != "first,second" {
+		t.Fatalf("contents = %v", contents)
+	}
+	firstLen := int64(strings.Index(streamTranscript, "\n") + 1)
+	if positions[0] != (Position{Offset: firstLen, Line: 1}) {
+		t.Errorf("first position = %+v, want offset %d line 1", positions[0], firstLen)
+	}
+	if want := (Position{Offset: int64(len(streamTranscript)), Line: 3}); end != want || positions[1] != want {
+		t.Errorf("end = %+v, last = %+v, want %+v", end, positions[1], want)
+	}
+}
+
+func TestStream_ResumeFromPosition(t *testing.T) {
+	_, positions, _ := collectStream(t, NewParser(), streamTranscript, Position{})
+
+	p := NewParser()
+	p.Dialect = claudeDialect
+	contents, _, end := collectStream(t, p, streamTranscript, positions[0])
+	if strings.Join(contents, ",") != "second" {
+		t.Fatalf("resumed contents = %v, want only the second message", contents)
+	}
+	if end.Line != 3 {
+		t.Errorf("end line = %d, want 3", end.Line)
+	}
+}
+
+func TestStream_PartialTrailingLine(t *testing.T) {
+	partial := streamTranscript + `{"type":"user","sessionId":"s1","mess`
+	contents, _, end := collectStream(t, NewParser(), partial, Position{})
+	if len(contents) != 2 {
+		t.Fatalf("contents = %v, want the two complete messages", contents)
+	}
+	if end.Offset != int64(len(streamTranscript)) {
+		t.Fatalf("end offset = %d, want %d (before the partial line)", end.Offset, len(streamTranscript))
+	}
+
+	// The writer finishes the line; a resume picks it up.
+	complete := partial + `age":{"role":"user","content":"third"}}`
+	p := NewParser()
+	p.Dialect = claudeDialect
+	contents, _, end = collectStream(t, p, complete, end)
+	if strings.Join(contents, ",") != "third" || end.Offset != int64(len(complete)) {
+		t.Fatalf("after completion: contents=%v end=%+v", contents, end)
+	}
+}
+
+func TestStream_CallbackErrorStops(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	end, _, err := NewParser().Stream(strings.NewReader(streamTranscript), Position{}, func(types.TranscriptMessage, Position) error {
+		calls++
+		if calls == 2 {
+			return boom
+		}
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if end.Line != 2 {
+		t.Errorf("end line = %d, want 2 (before the failing record)", end.Line)
+	}
+}
+
+func TestStream_Malformed(t *testing.T) {
+	input := "{bad json}\n" + streamTranscript
+
+	contents, _, _ := collectStream(t, NewParser(), input, Position{})
+	if len(contents) != 2 {
+		t.Errorf("skip mode: contents = %v", contents)
+	}
+
+	p := NewParser()
+	p.SkipMalformed = false
+	p.Dialect = claudeDialect
+	_, _, err := p.Stream(strings.NewReader(input), Position{}, func(types.TranscriptMessage, Position) error { return nil })
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 1 {
+		t.Fatalf("err = %v, want ParseError on line 1", err)
+	}
+}
+
+func TestStream_Document(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "dialects", "cursor", "transcript.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, positions, end := collectStream(t, NewParser(), string(data), Position{})
+	if len(contents) != 4 {
+		t.Fatalf("got %d messages, want 4", len(contents))
+	}
+	if end.Offset != int64(len(data)) || positions[0] != end {
+		t.Errorf("document positions should all be the end: first=%+v end=%+v", positions[0], end)
+	}
+}
@@ -0,0 +1,291 @@
+package pool
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ChainVerification is the result of checking the hash links of the pool chain.
+type ChainVerification struct {
+	// Events is the number of chain lines checked.
+	Events int `json:"events"`
+
+	// Hashed is the number of hash-linked events.
+	Hashed int `json:"hashed"`
+
+	// Legacy counts unhashed events written before the chain was hash-linked.
+	// They are not tamper-evident until MigrateChain back-fills them.
+	Legacy int `json:"legacy"`
+
+	// Head is the hash of the newest event.
+	Head string `json:"head,omitempty"`
+
+	// Problems lists every broken link, edited event or malformed line.
+	Problems []ChainProblem `json:"problems,omitempty"`
+}
+
+// ChainProblem describes one integrity failure in the chain.
+type ChainProblem struct {
+	// Line is the 1-based line number in chain.jsonl.
+	Line int `json:"line"`
+
+	// CandidateID is the candidate the event refers to, when parseable.
+	CandidateID string `json:"candidate_id,omitempty"`
+
+	// Message explains the failure.
+	Message string `json:"message"`
+}
+
+// Intact reports whether the chain verified without problems.
+func (v *ChainVerification) Intact() bool {
+	return len(v.Problems) == 0
+}
+
+// chainEventHash hashes an event's JSON encoding with Hash cleared, so the
+// hash covers every field including PrevHash.
+func chainEventHash(event ChainEvent) (string, error) {
+	event.Hash = ""
+	data, err := jsonMarshalFunc(event)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// linkChainEvent sets PrevHash and Hash on event so it follows prevHash.
+func linkChainEvent(event *ChainEvent, prevHash string) error {
+	event.PrevHash = prevHash
+	hash, err := chainEventHash(*event)
+	if err != nil {
+		return err
+	}
+	event.Hash = hash
+	return nil
+}
+
+// chainTailChunk is how much of the chain file chainHead reads per step
+// while walking back from the end.
+const chainTailChunk = 4096
+
+// chainHead returns the hash of the last event in the chain file, or ""
+// when the file is missing, empty, or ends in an unhashed legacy event.
+// It reads back from the end of the file until it finds a parseable event,
+// so appending stays cheap as the chain grows.
+func chainHead(path string) (string, error) {
+	f, err := openIfExists(path)
+	if err != nil || f == nil {
+		return "", err
+	}
+	defer f.Close() //nolint:errcheck // read-only file
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	// tail holds the bytes after offset; its first line may be partial
+	// until offset reaches the start of the file.
+	var tail []byte
+	for offset := info.Size(); ; {
+		for {
+			i := bytes.LastIndexByte(tail, '\n')
+			if i < 0 && offset > 0 {
+				break // the remaining line may start before offset
+			}
+			line := bytes.TrimSpace(tail[i+1:])
+			tail = tail[:max(i, 0)]
+			if len(line) > 0 {
+				var event ChainEvent
+				if json.Unmarshal(line, &event) == nil {
+					return event.Hash, nil
+				}
+			}
+			if i < 0 {
+				return "", nil // reached the first line
+			}
+		}
+
+		n := min(offset, chainTailChunk)
+		offset -= n
+		chunk := make([]byte, n, n+int64(len(tail)))
+		if _, err := f.ReadAt(chunk, offset); err != nil {
+			return "", err
+		}
+		tail = append(chunk, tail...)
+	}
+}
+
+// withChainLock runs fn while holding an exclusive lock on the pool's chain
+// lock file, so reading the chain head and appending after it (or relinking
+// the whole chain) is atomic across processes. The lock lives in a separate
+// file because MigrateChain replaces chain.jsonl by rename.
+func (p *Pool) withChainLock(fn func() error) error {
+	if err := os.MkdirAll(p.PoolPath, 0700); err != nil {
+		return fmt.Errorf("create pool directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(p.PoolPath, ChainFile+".lock"), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("open chain lock: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // lock file holds no data
+
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("lock chain: %w", err)
+	}
+	defer func() {
+		_ = unlockFile(f) //nolint:errcheck // unlock best-effort
+	}()
+	return fn()
+}
+
+// chainLine is one line of chain.jsonl with its parse result.
+type chainLine struct {
+	number int
+	event  ChainEvent
+	err    error
+}
+
+// readChainLines reads every non-blank line of the chain file. Unlike
+// GetChain it keeps malformed lines, which verification must report.
+func (p *Pool) readChainLines() ([]chainLine, error) {
+	f, err := openIfExists(filepath.Join(p.PoolPath, ChainFile))
+	if err != nil || f == nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck // read-only file
+
+	var lines []chainLine
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	n := 0
+	for scanner.Scan() {
+		n++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		line := chainLine{number: n}
+		line.err = json.Unmarshal(raw, &line.event)
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// VerifyChain checks that every event's hash matches its content and links
+// to the event before it. Editing an event breaks its hash; deleting or
+// reordering events breaks the next prev_hash. Unhashed legacy events are
+// allowed only before the first hashed event. Truncating the newest events
+// is not detectable from the chain alone; compare Head with a recorded value.
+func (p *Pool) VerifyChain() (*ChainVerification, error) {
+	lines, err := p.readChainLines()
+	if err != nil {
+		return nil, fmt.Errorf("read chain: %w", err)
+	}
+
+	v := &ChainVerification{}
+	prevHash := ""
+	for _, line := range lines {
+		v.Events++
+		problem := func(format string, args ...any) {
+			v.Problems = append(v.Problems, ChainProblem{
+				Line:        line.number,
+				CandidateID: line.event.CandidateID,
+				Message:     fmt.Sprintf(format, args...),
+			})
+		}
+		if line.err != nil {
+			problem("malformed event: %v", line.err)
+			continue
+		}
+
+		event := line.event
+		if event.Hash == "" {
+			if v.Hashed > 0 {
+				problem("unhashed event after hash-linked events")
+				continue
+			}
+			v.Legacy++
+			continue
+		}
+
+		v.Hashed++
+		if event.PrevHash != prevHash {
+			problem("prev_hash does not match the previous event (deleted or reordered event)")
+		}
+		hash, err := chainEventHash(event)
+		if err != nil {
+			return nil, fmt.Errorf("hash chain line %d: %w", line.number, err)
+		}
+		if hash != event.Hash {
+			problem("hash does not match event content (edited event)")
+		}
+		// Follow the recorded link so one break is reported once.
+		prevHash = event.Hash
+	}
+	v.Head = prevHash
+	return v, nil
+}
+
+// MigrateChain back-fills prev_hash and hash for legacy events and relinks
+// the chain from the first event. The hash-linked part must verify first:
+// relinking a tampered chain would launder the tampering. Returns the number
+// of legacy events that gained hashes. It holds the chain lock, so no event
+// is appended between reading and replacing the chain.
+func (p *Pool) MigrateChain() (migrated int, err error) {
+	err = p.withChainLock(func() error {
+		migrated, err = p.migrateChain()
+		return err
+	})
+	return migrated, err
+}
+
+// migrateChain relinks the chain for MigrateChain. Callers hold the chain lock.
+func (p *Pool) migrateChain() (int, error) {
+	v, err := p.VerifyChain()
+	if err != nil {
+		return 0, err
+	}
+	if !v.Intact() {
+		return 0, fmt.Errorf("chain has %d integrity problem(s); refusing to relink (first: line %d: %s)",
+			len(v.Problems), v.Problems[0].Line, v.Problems[0].Message)
+	}
+	if v.Legacy == 0 {
+		return 0, nil
+	}
+
+	lines, err := p.readChainLines()
+	if err != nil {
+		return 0, fmt.Errorf("read chain: %w", err)
+	}
+	var buf bytes.Buffer
+	prevHash := ""
+	for _, line := range lines {
+		event := line.event
+		if err := linkChainEvent(&event, prevHash); err != nil {
+			return 0, fmt.Errorf("hash chain line %d: %w", line.number, err)
+		}
+		data, err := jsonMarshalFunc(event)
+		if err != nil {
+			return 0, fmt.Errorf("marshal chain line %d: %w", line.number, err)
+		}
+		buf.Write(append(data, '\n'))
+		prevHash = event.Hash
+	}
+
+	chainPath := filepath.Join(p.PoolPath, ChainFile)
+	tmpPath := chainPath + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0600); err != nil {
+		return 0, fmt.Errorf("write migrated chain: %w", err)
+	}
+	if err := os.Rename(tmpPath, chainPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return 0, fmt.Errorf("replace chain: %w", err)
+	}
+	return v.Legacy, nil
+}
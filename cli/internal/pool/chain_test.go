@@ -0,0 +1,236 @@
+package pool
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+func newChainPool(t *testing.T, ids ...string) *Pool {
+	t.Helper()
+	p := NewPool(t.TempDir())
+	if err := p.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids {
+		if err := p.recordEvent(ChainEvent{Timestamp: time.Now(), Operation: "add", CandidateID: id, ToStatus: types.PoolStatusPending}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p
+}
+
+func chainFileLines(t *testing.T, p *Pool) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(p.PoolPath, ChainFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func writeChainFileLines(t *testing.T, p *Pool, lines []string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(p.PoolPath, ChainFile), []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func verifyChain(t *testing.T, p *Pool) *ChainVerification {
+	t.Helper()
+	v, err := p.VerifyChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestVerifyChain_Intact(t *testing.T) {
+	p := newChainPool(t, "a", "b", "c")
+	v := verifyChain(t, p)
+	if !v.Intact() || v.Events != 3 || v.Hashed != 3 || v.Legacy != 0 {
+		t.Fatalf("verification = %+v", v)
+	}
+
+	events, err := p.GetChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events[0].PrevHash != "" || events[1].PrevHash != events[0].Hash || v.Head != events[2].Hash {
+		t.Errorf("events are not linked: %+v", events)
+	}
+}
+
+func TestVerifyChain_DetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func([]string) []string
+		line   int
+		want   string
+	}{
+		{"edited", func(l []string) []string {
+			l[1] = strings.Replace(l[1], `"candidate_id":"b"`, `"candidate_id":"z"`, 1)
+			return l
+		}, 2, "edited event"},
+		{"deleted", func(l []string) []string { return append(l[:1], l[2:]...) }, 2, "deleted or reordered"},
+		{"reordered", func(l []string) []string { l[1], l[2] = l[2], l[1]; return l }, 2, "deleted or reordered"},
+		{"hash stripped", func(l []string) []string {
+			var e ChainEvent
+			_ = json.Unmarshal([]byte(l[2]), &e)
+			e.Hash, e.PrevHash = "", ""
+			data, _ := json.Marshal(e)
+			l[2] = string(data)
+			return l
+		}, 3, "unhashed event after"},
+		{"malformed", func(l []string) []string { l[1] = "{not json"; return l }, 2, "malformed event"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newChainPool(t, "a", "b", "c")
+			writeChainFileLines(t, p, tt.tamper(chainFileLines(t, p)))
+
+			v := verifyChain(t, p)
+			if v.Intact() {
+				t.Fatal("tampered chain verified as intact")
+			}
+			first := v.Problems[0]
+			if first.Line != tt.line || !strings.Contains(first.Message, tt.want) {
+				t.Errorf("first problem = %+v, want line %d containing %q", first, tt.line, tt.want)
+			}
+			if _, err := p.MigrateChain(); err == nil {
+				t.Error("MigrateChain should refuse a tampered chain")
+			}
+		})
+	}
+}
+
+func TestMigrateChain_BackfillsLegacyEvents(t *testing.T) {
+	p := NewPool(t.TempDir())
+	if err := p.Init(); err != nil {
+		t.Fatal(err)
+	}
+	var legacy []string
+	for _, id := range []string{"old-1", "old-2"} {
+		data, _ := json.Marshal(ChainEvent{Timestamp: time.Now(), Operation: "add", CandidateID: id})
+		legacy = append(legacy, string(data))
+	}
+	writeChainFileLines(t, p, legacy)
+	if err := p.recordEvent(ChainEvent{Timestamp: time.Now(), Operation: "stage", CandidateID: "old-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	v := verifyChain(t, p)
+	if !v.Intact() || v.Legacy != 2 || v.Hashed != 1 {
+		t.Fatalf("before migration = %+v", v)
+	}
+
+	migrated, err := p.MigrateChain()
+	if err != nil || migrated != 2 {
+		t.Fatalf("MigrateChain = %d, %v; want 2", migrated, err)
+	}
+	v = verifyChain(t, p)
+	if !v.Intact() || v.Legacy != 0 || v.Hashed != 3 {
+		t.Errorf("after migration = %+v", v)
+	}
+	if migrated, err := p.MigrateChain(); err != nil || migrated != 0 {
+		t.Errorf("second MigrateChain = %d, %v; want a no-op", migrated, err)
+	}
+}
+
+func TestVerifyChain_MissingChain(t *testing.T) {
+	v := verifyChain(t, NewPool(t.TempDir()))
+	if !v.Intact() || v.Events != 0 {
+		t.Errorf("verification = %+v", v)
+	}
+}
+
+func TestChainHead_ReadsTail(t *testing.T) {
+	// Enough events to span several tail chunks.
+	ids := make([]string, 60)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("cand-%02d-%s", i, strings.Repeat("x", 80))
+	}
+	p := newChainPool(t, ids...)
+	chainPath := filepath.Join(p.PoolPath, ChainFile)
+	v, err := p.VerifyChain()
+	if err != nil || !v.Intact() {
+		t.Fatalf("VerifyChain = %+v, %v", v, err)
+	}
+	if info, _ := os.Stat(chainPath); info.Size() <= 2*chainTailChunk {
+		t.Fatalf("chain is %d bytes, want several chunks", info.Size())
+	}
+
+	lines := chainFileLines(t, p)
+	for _, tc := range []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{"full chain", lines, v.Head},
+		{"trailing junk", append(slices.Clone(lines), "", "{not json", "   "), v.Head},
+		{"single event", lines[:1], chainLineHash(t, lines[0])},
+		{"legacy tail", append(slices.Clone(lines), `{"operation":"add","candidate_id":"legacy"}`), ""},
+	} {
+		writeChainFileLines(t, p, tc.lines)
+		if got, err := chainHead(chainPath); err != nil || got != tc.want {
+			t.Errorf("%s: chainHead = %q, %v; want %q", tc.name, got, err, tc.want)
+		}
+	}
+
+	if err := os.WriteFile(chainPath, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := chainHead(chainPath); err != nil || got != v.Head {
+		t.Errorf("no trailing newline: chainHead = %q, %v; want %q", got, err, v.Head)
+	}
+	if err := os.WriteFile(chainPath, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := chainHead(chainPath); err != nil || got != "" {
+		t.Errorf("empty chain: chainHead = %q, %v", got, err)
+	}
+}
+
+func chainLineHash(t *testing.T, line string) string {
+	t.Helper()
+	var event ChainEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		t.Fatal(err)
+	}
+	return event.Hash
+}
+
+func TestRecordEvent_ConcurrentWritersStayLinked(t *testing.T) {
+	p := newChainPool(t)
+	const writers, each = 8, 10
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Go(func() {
+			// A pool per writer, as separate ao processes would have.
+			wp := NewPool(p.BaseDir)
+			for i := range each {
+				event := ChainEvent{Timestamp: time.Now(), Operation: "add", CandidateID: fmt.Sprintf("w%d-%d", w, i)}
+				if err := wp.recordEvent(event); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	v, err := p.VerifyChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Events != writers*each || !v.Intact() {
+		t.Errorf("VerifyChain = %d events, problems %+v; want %d linked events", v.Events, v.Problems, writers*each)
+	}
+}
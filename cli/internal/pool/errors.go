@@ -23,6 +23,12 @@ var (
 	ErrNotStaged = errors.New("candidate must be staged before promotion")
 	// ErrThresholdTooLow is returned when bulk approval threshold is below minimum.
 	ErrThresholdTooLow = errors.New("threshold must be >= 1h")
+	// ErrQuorumNotMet is returned when promoting a candidate with fewer approvals than its tier quorum.
+	ErrQuorumNotMet = errors.New("review quorum not met")
+	// ErrReviewerNotAssigned is returned when the gate policy does not list the reviewer for the candidate's type.
+	ErrReviewerNotAssigned = errors.New("reviewer not assigned")
+	// ErrDuplicateReview is returned when a reviewer reviews the same candidate twice.
+	ErrDuplicateReview = errors.New("candidate already reviewed by")
 	// ErrReasonTooLong is returned when reason/note exceeds MaxReasonLength.
 	ErrReasonTooLong = fmt.Errorf("reason/note exceeds maximum length of %d characters", MaxReasonLength)
 )
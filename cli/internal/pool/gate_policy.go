@@ -0,0 +1,177 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// GatePolicyFile is the per-repo review gate policy path, relative to the
+// repo root.
+const GatePolicyFile = ".agents/gate-policy.yaml"
+
+// GatePolicy configures multi-reviewer gates. A repo without a policy file
+// gets the zero policy: one review decides a candidate and promotion does
+// not check approvals.
+//
+//	quorum:
+//	  gold: 2
+//	reviewers:
+//	  decision: [alice, bob, carol]
+type GatePolicy struct {
+	// Quorum is the number of distinct approvals a tier needs before its
+	// candidates can be promoted. The same number of rejections rejects a
+	// candidate; fewer are kept as dissent.
+	Quorum map[types.Tier]int `yaml:"quorum,omitempty" json:"quorum,omitempty"`
+
+	// Reviewers lists who may review each knowledge type. Types not listed
+	// may be reviewed by anyone.
+	Reviewers map[types.KnowledgeType][]string `yaml:"reviewers,omitempty" json:"reviewers,omitempty"`
+}
+
+// LoadGatePolicy reads the gate policy of the repo at baseDir. A missing
+// file yields the zero policy. Reviewer lists are checked against the
+// knowledge types of the repo's taxonomy, so custom types can be gated.
+func LoadGatePolicy(baseDir string) (*GatePolicy, error) {
+	path := filepath.Join(baseDir, GatePolicyFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &GatePolicy{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read gate policy: %w", err)
+	}
+	var policy GatePolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	tax, err := taxonomy.Load(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("load taxonomy for gate policy: %w", err)
+	}
+	if err := policy.Validate(tax); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// Validate checks tiers, reviewer lists and that every reviewed knowledge
+// type is defined by tax.
+func (g *GatePolicy) Validate(tax *taxonomy.Taxonomy) error {
+	for tier, n := range g.Quorum {
+		switch tier {
+		case types.TierGold, types.TierSilver, types.TierBronze:
+		default:
+			return fmt.Errorf("quorum: unknown tier %q", tier)
+		}
+		if n < 0 {
+			return fmt.Errorf("quorum: %s must be >= 0, got %d", tier, n)
+		}
+	}
+	for kt, reviewers := range g.Reviewers {
+		if _, ok := tax.KnowledgeTypes[kt]; !ok {
+			return fmt.Errorf("reviewers: unknown knowledge type %q", kt)
+		}
+		for i, r := range reviewers {
+			if r == "" {
+				return fmt.Errorf("reviewers: %s has an empty name", kt)
+			}
+			if slices.Contains(reviewers[:i], r) {
+				return fmt.Errorf("reviewers: %s lists %s twice", kt, r)
+			}
+		}
+	}
+	return nil
+}
+
+// RequiredApprovals is the number of approvals promotion of tier requires.
+func (g *GatePolicy) RequiredApprovals(tier types.Tier) int {
+	return g.Quorum[tier]
+}
+
+// EntryQuorum is the number of approvals promotion of entry requires: the
+// largest quorum of its current tier, the tier scoring assigned and the
+// highest tier it held before a retier. Moving a candidate to a lower tier
+// never lowers the bar for approving it.
+func (g *GatePolicy) EntryQuorum(entry *PoolEntry) int {
+	return max(g.Quorum[entry.Candidate.Tier], g.Quorum[entry.ScoringResult.TierAssignment], g.Quorum[entry.PeakTier])
+}
+
+// decisionThreshold is the number of matching reviews that decide entry:
+// its quorum, or a single review when none is set.
+func (g *GatePolicy) decisionThreshold(entry *PoolEntry) int {
+	return max(1, g.EntryQuorum(entry))
+}
+
+// CanReview reports whether reviewer may review candidates of kt.
+func (g *GatePolicy) CanReview(reviewer string, kt types.KnowledgeType) bool {
+	reviewers := g.Reviewers[kt]
+	return len(reviewers) == 0 || slices.Contains(reviewers, reviewer)
+}
+
+// AssignedTo reports whether entry is waiting on reviewer: reviewer may
+// review it and has not done so yet.
+func (g *GatePolicy) AssignedTo(reviewer string, entry PoolEntry) bool {
+	return g.CanReview(reviewer, entry.Candidate.Type) && !entry.HumanReview.HasReviewed(reviewer)
+}
+
+// GatePolicy loads the gate policy of the pool's repo.
+func (p *Pool) GatePolicy() (*GatePolicy, error) {
+	return LoadGatePolicy(p.BaseDir)
+}
+
+// checkReviewer rejects reviews from unassigned or repeat reviewers.
+func (g *GatePolicy) checkReviewer(entry *PoolEntry, reviewer string) error {
+	if !g.CanReview(reviewer, entry.Candidate.Type) {
+		return fmt.Errorf("%w: %s may not review %s candidates", ErrReviewerNotAssigned, reviewer, entry.Candidate.Type)
+	}
+	if entry.HumanReview.HasReviewed(reviewer) {
+		return fmt.Errorf("%w: %s", ErrDuplicateReview, reviewer)
+	}
+	return nil
+}
+
+// checkQuorum blocks promotion until the entry's quorum is met.
+func (g *GatePolicy) checkQuorum(entry *PoolEntry) error {
+	need := g.EntryQuorum(entry)
+	if got := entry.HumanReview.Approvals(); got < need {
+		return fmt.Errorf("%w: %d/%d approvals for %s tier", ErrQuorumNotMet, got, need, entry.Candidate.Tier)
+	}
+	return nil
+}
+
+// addReview appends review to entry, carrying over a review recorded before
+// individual reviews were kept.
+func addReview(entry *PoolEntry, review types.Review) *types.HumanReview {
+	hr := entry.HumanReview
+	if hr == nil {
+		hr = &types.HumanReview{}
+		entry.HumanReview = hr
+	}
+	if len(hr.Reviews) == 0 && hr.Reviewed && hr.Reviewer != "" {
+		hr.Reviews = append(hr.Reviews, types.Review{
+			Reviewer:   hr.Reviewer,
+			Approved:   hr.Approved,
+			Notes:      hr.Notes,
+			ReviewedAt: hr.ReviewedAt,
+		})
+	}
+	hr.Reviews = append(hr.Reviews, review)
+	return hr
+}
+
+// decide records review as the deciding review of hr.
+func decide(hr *types.HumanReview, review types.Review) {
+	hr.Reviewed = true
+	hr.Approved = review.Approved
+	hr.Reviewer = review.Reviewer
+	hr.Notes = review.Notes
+	hr.ReviewedAt = review.ReviewedAt
+}
@@ -0,0 +1,358 @@
+package pool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+func writeGatePolicy(t *testing.T, baseDir, content string) {
+	t.Helper()
+	path := filepath.Join(baseDir, GatePolicyFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func addGoldDecision(t *testing.T, p *Pool, id string) {
+	t.Helper()
+	candidate := types.Candidate{
+		ID:      id,
+		Tier:    types.TierGold,
+		Type:    types.KnowledgeTypeDecision,
+		Content: "Gold decision content",
+	}
+	if err := p.Add(candidate, types.Scoring{}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+}
+
+func TestLoadGatePolicy_MissingFileIsZeroPolicy(t *testing.T) {
+	policy, err := LoadGatePolicy(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadGatePolicy: %v", err)
+	}
+	if got := policy.RequiredApprovals(types.TierGold); got != 0 {
+		t.Errorf("RequiredApprovals(gold) = %d, want 0", got)
+	}
+	if !policy.CanReview("anyone", types.KnowledgeTypeDecision) {
+		t.Error("zero policy should let anyone review")
+	}
+}
+
+func TestLoadGatePolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown tier", "quorum:\n  platinum: 2\n", "unknown tier"},
+		{"negative quorum", "quorum:\n  gold: -1\n", "must be >= 0"},
+		{"unknown type", "reviewers:\n  rumor: [alice]\n", "unknown knowledge type"},
+		{"duplicate reviewer", "reviewers:\n  decision: [alice, alice]\n", "twice"},
+		{"bad yaml", "quorum: [", "parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeGatePolicy(t, dir, tt.content)
+			_, err := LoadGatePolicy(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadGatePolicy error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadGatePolicy_TaxonomyTypes(t *testing.T) {
+	dir := t.TempDir()
+	writeGatePolicy(t, dir, "reviewers:\n  gotcha: [alice]\n")
+	if _, err := LoadGatePolicy(dir); err == nil || !strings.Contains(err.Error(), `unknown knowledge type "gotcha"`) {
+		t.Fatalf("type outside the taxonomy: err = %v", err)
+	}
+
+	taxPath := filepath.Join(dir, taxonomy.TaxonomyFile)
+	if err := os.WriteFile(taxPath, []byte("knowledge_types:\n  gotcha: {base_score: 0.4}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadGatePolicy(dir)
+	if err != nil {
+		t.Fatalf("custom taxonomy type should be accepted: %v", err)
+	}
+	if policy.CanReview("bob", types.KnowledgeType("gotcha")) || !policy.CanReview("alice", types.KnowledgeType("gotcha")) {
+		t.Error("reviewers of a custom type should be enforced")
+	}
+
+	if err := os.WriteFile(taxPath, []byte("knowledge_types: ["), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGatePolicy(dir); err == nil || !strings.Contains(err.Error(), "taxonomy") {
+		t.Errorf("broken taxonomy: err = %v", err)
+	}
+}
+
+func TestQuorum_PromotionBlockedUntilMet(t *testing.T) {
+	dir := t.TempDir()
+	writeGatePolicy(t, dir, "quorum:\n  gold: 2\n")
+	p := NewPool(dir)
+	addGoldDecision(t, p, "gold-quorum")
+	if err := p.Stage("gold-quorum", types.TierBronze); err != nil {
+		t.Fatalf("Stage: %v", err)
+	}
+
+	if _, err := p.Promote("gold-quorum"); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("Promote with no approvals: err = %v, want ErrQuorumNotMet", err)
+	}
+
+	if err := p.Approve("gold-quorum", "lgtm", "alice"); err != nil {
+		t.Fatalf("Approve alice: %v", err)
+	}
+	entry, _ := p.Get("gold-quorum")
+	if entry.HumanReview.Reviewed {
+		t.Error("one approval of two should leave the candidate undecided")
+	}
+	if err := p.Approve("gold-quorum", "again", "alice"); !errors.Is(err, ErrDuplicateReview) {
+		t.Errorf("second approval by alice: err = %v, want ErrDuplicateReview", err)
+	}
+	if _, err := p.Promote("gold-quorum"); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("Promote with 1/2 approvals: err = %v, want ErrQuorumNotMet", err)
+	}
+
+	if err := p.Approve("gold-quorum", "agreed", "bob"); err != nil {
+		t.Fatalf("Approve bob: %v", err)
+	}
+	entry, _ = p.Get("gold-quorum")
+	hr := entry.HumanReview
+	if !hr.Reviewed || !hr.Approved || hr.Reviewer != "bob" || len(hr.Reviews) != 2 {
+		t.Errorf("after quorum: %+v", hr)
+	}
+	if _, err := p.Promote("gold-quorum"); err != nil {
+		t.Fatalf("Promote after quorum: %v", err)
+	}
+}
+
+func TestQuorum_DissentRecordedWithoutRejecting(t *testing.T) {
+	dir := t.TempDir()
+	writeGatePolicy(t, dir, "quorum:\n  gold: 2\n")
+	p := NewPool(dir)
+	addGoldDecision(t, p, "gold-dissent")
+
+	if err := p.Reject("gold-dissent", "too narrow", "carol"); err != nil {
+		t.Fatalf("Reject carol: %v", err)
+	}
+	entry, err := p.Get("gold-dissent")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if entry.Status != types.PoolStatusPending {
+		t.Fatalf("status after one dissent = %s, want pending", entry.Status)
+	}
+	if got := entry.HumanReview.Dissents(); got != 1 {
+		t.Errorf("Dissents() = %d, want 1", got)
+	}
+
+	for _, r := range []string{"alice", "bob"} {
+		if err := p.Approve("gold-dissent", "ok", r); err != nil {
+			t.Fatalf("Approve %s: %v", r, err)
+		}
+	}
+	entry, _ = p.Get("gold-dissent")
+	if !entry.HumanReview.Approved || entry.HumanReview.Dissents() != 1 {
+		t.Errorf("approved entry should keep the dissent: %+v", entry.HumanReview)
+	}
+
+	events, err := p.GetChain()
+	if err != nil {
+		t.Fatalf("GetChain: %v", err)
+	}
+	found := false
+	for _, e := range events {
+		if e.Operation == "dissent" && e.Reviewer == "carol" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a dissent chain event from carol")
+	}
+}
+
+func TestQuorum_RejectionsReachingQuorumReject(t *testing.T) {
+	dir := t.TempDir()
+	writeGatePolicy(t, dir, "quorum:\n  gold: 2\n")
+	p := NewPool(dir)
+	addGoldDecision(t, p, "gold-reject")
+
+	for _, r := range []string{"alice", "bob"} {
+		if err := p.Reject("gold-reject", "no", r); err != nil {
+			t.Fatalf("Reject %s: %v", r, err)
+		}
+	}
+	entry, _ := p.Get("gold-reject")
+	if entry.Status != types.PoolStatusRejected {
+		t.Errorf("status = %s, want rejected", entry.Status)
+	}
+	if hr := entry.HumanReview; !hr.Reviewed || hr.Approved || len(hr.Reviews) != 2 {
+		t.Errorf("HumanReview = %+v", hr)
+	}
+}
+
+func TestGatePolicy_ReviewerAssignments(t *testing.T) {
+	dir := t.TempDir()
+	writeGatePolicy(t, dir, "reviewers:\n  decision: [alice, bob]\n")
+	p := NewPool(dir)
+	addGoldDecision(t, p, "assigned")
+
+	if err := p.Approve("assigned", "", "mallory"); !errors.Is(err, ErrReviewerNotAssigned) {
+		t.Errorf("Approve by unlisted reviewer: err = %v, want ErrReviewerNotAssigned", err)
+	}
+	if err := p.Reject("assigned", "no", "mallory"); !errors.Is(err, ErrReviewerNotAssigned) {
+		t.Errorf("Reject by unlisted reviewer: err = %v, want ErrReviewerNotAssigned", err)
+	}
+
+	policy, err := p.GatePolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := p.Get("assigned")
+	if !policy.AssignedTo("alice", *entry) || policy.AssignedTo("mallory", *entry) {
+		t.Error("AssignedTo should follow the decision reviewer list")
+	}
+	if !policy.CanReview("mallory", types.KnowledgeTypeLearning) {
+		t.Error("unlisted knowledge types should be open to anyone")
+	}
+}
+
+func TestQuorum_RetierDownKeepsQuorum(t *testing.T) {
+	dir := t.TempDir()
+	writeGatePolicy(t, dir, "quorum:\n  gold: 2\nreviewers:\n  decision: [alice, bob]\n")
+	p := NewPool(dir)
+	addGoldDecision(t, p, "gold-retier")
+
+	if err := p.Retier("gold-retier", types.TierBronze, "mallory"); !errors.Is(err, ErrReviewerNotAssigned) {
+		t.Fatalf("Retier by unassigned reviewer = %v, want ErrReviewerNotAssigned", err)
+	}
+	if err := p.Retier("gold-retier", types.TierBronze, "alice"); err != nil {
+		t.Fatalf("Retier: %v", err)
+	}
+	if err := p.Approve("gold-retier", "", "alice"); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+
+	entry, err := p.Get("gold-retier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.PeakTier != types.TierGold || entry.HumanReview.Reviewed {
+		t.Errorf("peak tier = %s, decided = %v; a bronze retier must not drop the gold quorum", entry.PeakTier, entry.HumanReview.Reviewed)
+	}
+	if err := p.Stage("gold-retier", types.TierBronze); err != nil {
+		t.Fatalf("Stage: %v", err)
+	}
+	if _, err := p.Promote("gold-retier"); !errors.Is(err, ErrQuorumNotMet) {
+		t.Errorf("Promote after one approval = %v, want ErrQuorumNotMet", err)
+	}
+
+	if err := p.Retier("gold-retier", types.TierSilver, "bob"); err != nil {
+		t.Fatalf("Retier: %v", err)
+	}
+	if entry, _ := p.Get("gold-retier"); entry.PeakTier != types.TierGold {
+		t.Errorf("peak tier = %s after a further retier, want gold", entry.PeakTier)
+	}
+}
+
+func TestListPendingReview_IncludesQuorumTiers(t *testing.T) {
+	dir := t.TempDir()
+	writeGatePolicy(t, dir, "quorum:\n  gold: 2\n")
+	p := NewPool(dir)
+	addGoldDecision(t, p, "gold-pending")
+
+	pending, err := p.ListPendingReview()
+	if err != nil {
+		t.Fatalf("ListPendingReview: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Candidate.ID != "gold-pending" || pending[0].Quorum != 2 {
+		t.Fatalf("pending = %+v, want gold-pending with quorum 2", pending)
+	}
+
+	for _, r := range []string{"alice", "bob"} {
+		if err := p.Approve("gold-pending", "", r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pending, err = p.ListPendingReview()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("quorum met: pending = %d, want 0", len(pending))
+	}
+}
+
+func TestBulkApprove_RefusesMultiReviewerSilverQuorum(t *testing.T) {
+	dir := t.TempDir()
+	writeGatePolicy(t, dir, "quorum:\n  silver: 2\n")
+	p := NewPool(dir)
+	if _, err := p.BulkApprove(MinBulkApproveThreshold, "bulk", true); !errors.Is(err, ErrQuorumNotMet) {
+		t.Errorf("BulkApprove: err = %v, want ErrQuorumNotMet", err)
+	}
+}
+
+func TestAddReview_CarriesLegacyReview(t *testing.T) {
+	entry := &PoolEntry{PoolEntry: types.PoolEntry{
+		HumanReview: &types.HumanReview{Reviewed: true, Approved: true, Reviewer: "old"},
+	}}
+	hr := addReview(entry, types.Review{Reviewer: "new", Approved: false})
+	if len(hr.Reviews) != 2 || hr.Reviews[0].Reviewer != "old" {
+		t.Fatalf("Reviews = %+v, want legacy review first", hr.Reviews)
+	}
+	if hr.Approvals() != 1 || hr.Dissents() != 1 {
+		t.Errorf("Approvals/Dissents = %d/%d, want 1/1", hr.Approvals(), hr.Dissents())
+	}
+}
+
+func TestListPendingReview_FollowsTaxonomyHumanGate(t *testing.T) {
+	dir := t.TempDir()
+	taxPath := filepath.Join(dir, taxonomy.TaxonomyFile)
+	if err := os.MkdirAll(filepath.Dir(taxPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(taxPath, []byte("tiers:\n  silver: {human_gate: true}\n  bronze: {human_gate: false}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tax, err := taxonomy.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPool(dir)
+	for id, tier := range map[string]types.Tier{"gated-silver": types.TierSilver, "open-bronze": types.TierBronze} {
+		candidate := types.Candidate{ID: id, Tier: tier, Type: types.KnowledgeTypeLearning, Content: "Content of " + id}
+		if err := p.Add(candidate, types.Scoring{TierAssignment: tier, GateRequired: tax.RequiresHumanGate(tier)}); err != nil {
+			t.Fatalf("Add %s: %v", id, err)
+		}
+	}
+
+	pending, err := p.ListPendingReview()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Candidate.ID != "gated-silver" {
+		t.Fatalf("pending = %+v, want only the gated silver candidate", pending)
+	}
+
+	// Retiering follows the taxonomy too: bronze has no gate here.
+	if err := p.Retier("gated-silver", types.TierBronze, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := p.ListPendingReview(); err != nil || len(pending) != 0 {
+		t.Errorf("after retier to ungated bronze: pending = %+v, %v", pending, err)
+	}
+}
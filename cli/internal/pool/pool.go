@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/boshu2/agentops/cli/internal/taxonomy"
 	"github.com/boshu2/agentops/cli/internal/types"
 )
 
@@ -84,6 +85,14 @@ type PoolEntry struct {
 
 	// ApproachingAutoPromote indicates if nearing 24h threshold.
 	ApproachingAutoPromote bool `json:"approaching_auto_promote,omitempty"`
+
+	// Approvals and Quorum report review progress in ListPendingReview.
+	Approvals int `json:"approvals,omitempty"`
+	Quorum    int `json:"quorum,omitempty"`
+
+	// PeakTier is the highest tier the candidate held before a retier
+	// moved it down; its quorum still applies.
+	PeakTier types.Tier `json:"peak_tier,omitempty"`
 }
 
 // ChainEvent records a pool operation.
@@ -91,7 +100,8 @@ type ChainEvent struct {
 	// Timestamp is when the event occurred.
 	Timestamp time.Time `json:"timestamp"`
 
-	// Operation is the action taken (add, stage, promote, reject).
+	// Operation is the action taken (add, stage, promote, reject, approve,
+	// dissent, retier).
 	Operation string `json:"operation"`
 
 	// CandidateID is the affected candidate.
@@ -111,6 +121,13 @@ type ChainEvent struct {
 
 	// ArtifactPath is the destination path for promotions.
 	ArtifactPath string `json:"artifact_path,omitempty"`
+
+	// PrevHash is the Hash of the previous event ("" for the first).
+	PrevHash string `json:"prev_hash,omitempty"`
+
+	// Hash is the SHA-256 of this event with Hash cleared. Empty on legacy
+	// events written before the chain was hash-linked.
+	Hash string `json:"hash,omitempty"`
 }
 
 // Pool manages the candidate pool.
@@ -156,6 +173,9 @@ type ListOptions struct {
 	// Status filters by pool status.
 	Status types.PoolStatus
 
+	// Type filters by knowledge type.
+	Type types.KnowledgeType
+
 	// Offset skips the first N results (for pagination).
 	Offset int
 
@@ -189,6 +209,7 @@ func (p *Pool) ListPaginated(opts ListOptions) (*ListResult, error) {
 	}
 
 	entries = filterByTier(entries, opts.Tier)
+	entries = filterByType(entries, opts.Type)
 
 	// Sort by added time (newest first)
 	slices.SortFunc(entries, func(a, b PoolEntry) int {
@@ -244,6 +265,20 @@ func filterByTier(entries []PoolEntry, tier types.Tier) []PoolEntry {
 	return filtered
 }
 
+// filterByType returns only entries matching the given knowledge type; returns all if kt is empty.
+func filterByType(entries []PoolEntry, kt types.KnowledgeType) []PoolEntry {
+	if kt == "" {
+		return entries
+	}
+	filtered := make([]PoolEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.Candidate.Type == kt {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
 // paginate applies offset and limit to a slice of entries.
 func paginate(entries []PoolEntry, offset, limit int) []PoolEntry {
 	if offset > 0 {
@@ -431,6 +466,66 @@ func (p *Pool) Stage(candidateID string, minTier types.Tier) error {
 	return nil
 }
 
+// Retier changes a pending or staged candidate's tier on a reviewer's
+// judgment. Only reviewers the gate policy assigns to the candidate's type
+// may retier it. The scoring result keeps the tier the rubric assigned, and
+// PeakTier keeps the highest tier the candidate left, so it still needs the
+// quorum of the highest tier it held. Whether the candidate needs the human
+// gate follows the repo taxonomy's human_gate setting for the new tier.
+func (p *Pool) Retier(candidateID string, tier types.Tier, reviewer string) error {
+	switch tier {
+	case types.TierGold, types.TierSilver, types.TierBronze:
+	default:
+		return fmt.Errorf("invalid tier %q (want gold, silver or bronze)", tier)
+	}
+
+	entry, err := p.Get(candidateID)
+	if err != nil {
+		return err
+	}
+	if entry.Status != types.PoolStatusPending && entry.Status != types.PoolStatusStaged {
+		return fmt.Errorf("cannot retier %s candidate", entry.Status)
+	}
+	policy, err := p.GatePolicy()
+	if err != nil {
+		return err
+	}
+	if !policy.CanReview(reviewer, entry.Candidate.Type) {
+		return fmt.Errorf("%w: %s may not review %s candidates", ErrReviewerNotAssigned, reviewer, entry.Candidate.Type)
+	}
+	priorTier := entry.Candidate.Tier
+	if priorTier == tier {
+		return nil
+	}
+	tax, err := taxonomy.Load(p.BaseDir)
+	if err != nil {
+		return err
+	}
+
+	if !isAboveThreshold(entry.PeakTier, priorTier) {
+		entry.PeakTier = priorTier
+	}
+	entry.Candidate.Tier = tier
+	entry.ScoringResult.GateRequired = tax.RequiresHumanGate(tier)
+	if entry.ScoringResult.GateRequired && entry.HumanReview == nil {
+		entry.HumanReview = &types.HumanReview{}
+	}
+	entry.UpdatedAt = time.Now()
+	if err := p.writeEntry(entry.FilePath, entry); err != nil {
+		return fmt.Errorf("write retiered entry: %w", err)
+	}
+
+	p.recordEventOrWarn(ChainEvent{
+		Timestamp:   time.Now(),
+		Operation:   "retier",
+		CandidateID: candidateID,
+		Reason:      fmt.Sprintf("tier %s -> %s", priorTier, tier),
+		Reviewer:    reviewer,
+	})
+
+	return nil
+}
+
 // Promote moves a staged candidate to learnings/patterns.
 func (p *Pool) Promote(candidateID string) (string, error) {
 	entry, err := p.Get(candidateID)
@@ -440,6 +535,13 @@ func (p *Pool) Promote(candidateID string) (string, error) {
 	if err := validatePromotable(entry); err != nil {
 		return "", err
 	}
+	policy, err := p.GatePolicy()
+	if err != nil {
+		return "", err
+	}
+	if err := policy.checkQuorum(entry); err != nil {
+		return "", err
+	}
 
 	destDir := promotionDir(p.BaseDir, entry.Candidate.Type)
 	if err := os.MkdirAll(destDir, 0700); err != nil {
@@ -507,7 +609,9 @@ func resolveArtifactPath(destDir, candidateID string) string {
 	return artifactPath
 }
 
-// Reject marks a candidate as rejected.
+// Reject records a rejecting review. Once rejections reach the tier quorum
+// (one when unset) the candidate is rejected; until then the review is kept
+// as dissent and the candidate stays where it is.
 func (p *Pool) Reject(candidateID, reason, reviewer string) error {
 	// Validate reason length
 	if len(reason) > MaxReasonLength {
@@ -519,6 +623,20 @@ func (p *Pool) Reject(candidateID, reason, reviewer string) error {
 		return err
 	}
 
+	policy, err := p.GatePolicy()
+	if err != nil {
+		return err
+	}
+	if err := policy.checkReviewer(entry, reviewer); err != nil {
+		return err
+	}
+	review := types.Review{Reviewer: reviewer, Approved: false, Notes: reason, ReviewedAt: time.Now()}
+	hr := addReview(entry, review)
+	if hr.Dissents() < policy.decisionThreshold(entry) {
+		return p.recordDissent(entry, review)
+	}
+	decide(hr, review)
+
 	// Capture status before mutation for chain event
 	priorStatus := entry.Status
 
@@ -531,13 +649,6 @@ func (p *Pool) Reject(candidateID, reason, reviewer string) error {
 	// Update entry
 	entry.Status = types.PoolStatusRejected
 	entry.UpdatedAt = time.Now()
-	entry.HumanReview = &types.HumanReview{
-		Reviewed:   true,
-		Approved:   false,
-		Reviewer:   reviewer,
-		Notes:      reason,
-		ReviewedAt: time.Now(),
-	}
 
 	if err := p.writeEntry(newPath, entry); err != nil {
 		return fmt.Errorf("write rejected entry: %w", err)
@@ -559,7 +670,24 @@ func (p *Pool) Reject(candidateID, reason, reviewer string) error {
 	return nil
 }
 
-// Approve records human approval for a bronze candidate.
+// recordDissent saves a rejecting review that did not reach quorum.
+func (p *Pool) recordDissent(entry *PoolEntry, review types.Review) error {
+	entry.UpdatedAt = time.Now()
+	if err := p.writeEntry(entry.FilePath, entry); err != nil {
+		return fmt.Errorf("write dissent: %w", err)
+	}
+	p.recordEventOrWarn(ChainEvent{
+		Timestamp:   time.Now(),
+		Operation:   "dissent",
+		CandidateID: entry.Candidate.ID,
+		Reason:      review.Notes,
+		Reviewer:    review.Reviewer,
+	})
+	return nil
+}
+
+// Approve records a reviewer's approval. The candidate counts as approved
+// once distinct approvals reach its tier quorum (one when unset).
 func (p *Pool) Approve(candidateID, note, reviewer string) error {
 	// Validate note length
 	if len(note) > MaxReasonLength {
@@ -576,13 +704,19 @@ func (p *Pool) Approve(candidateID, note, reviewer string) error {
 		return fmt.Errorf("already reviewed by %s", entry.HumanReview.Reviewer)
 	}
 
+	policy, err := p.GatePolicy()
+	if err != nil {
+		return err
+	}
+	if err := policy.checkReviewer(entry, reviewer); err != nil {
+		return err
+	}
+
 	// Update entry with review
-	entry.HumanReview = &types.HumanReview{
-		Reviewed:   true,
-		Approved:   true,
-		Reviewer:   reviewer,
-		Notes:      note,
-		ReviewedAt: time.Now(),
+	review := types.Review{Reviewer: reviewer, Approved: true, Notes: note, ReviewedAt: time.Now()}
+	hr := addReview(entry, review)
+	if hr.Approvals() >= policy.decisionThreshold(entry) {
+		decide(hr, review)
 	}
 	entry.UpdatedAt = time.Now()
 
@@ -602,22 +736,26 @@ func (p *Pool) Approve(candidateID, note, reviewer string) error {
 	return nil
 }
 
-// ListPendingReview returns bronze candidates awaiting human review.
+// ListPendingReview returns candidates awaiting the human gate their tier's
+// taxonomy requires and candidates whose gate policy quorum is not met yet.
 func (p *Pool) ListPendingReview() ([]PoolEntry, error) {
-	entries, err := p.List(ListOptions{
-		Tier:   types.TierBronze,
-		Status: types.PoolStatusPending,
-	})
+	policy, err := p.GatePolicy()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := p.List(ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter to only those without review
 	var pending []PoolEntry
 	for _, e := range entries {
-		if e.HumanReview == nil || !e.HumanReview.Reviewed {
-			pending = append(pending, e)
+		if !awaitingReview(policy, e) {
+			continue
 		}
+		e.Approvals = e.HumanReview.Approvals()
+		e.Quorum = policy.decisionThreshold(&e)
+		pending = append(pending, e)
 	}
 
 	// Sort by age (oldest first for urgency)
@@ -628,6 +766,21 @@ func (p *Pool) ListPendingReview() ([]PoolEntry, error) {
 	return pending, nil
 }
 
+// awaitingReview reports whether e still needs reviews: an undecided
+// pending candidate scored into a tier with a human gate, or a pending or
+// staged candidate short of its tier quorum.
+func awaitingReview(policy *GatePolicy, e PoolEntry) bool {
+	if e.Status != types.PoolStatusPending && e.Status != types.PoolStatusStaged {
+		return false
+	}
+	if e.ScoringResult.GateRequired && e.Status == types.PoolStatusPending &&
+		(e.HumanReview == nil || !e.HumanReview.Reviewed) {
+		return true
+	}
+	need := policy.EntryQuorum(&e)
+	return need > 0 && e.HumanReview.Approvals() < need
+}
+
 // MinBulkApproveThreshold is the minimum duration for bulk approval.
 // Prevents accidental approval of very recent candidates.
 const MinBulkApproveThreshold = time.Hour
@@ -638,10 +791,20 @@ const MaxReasonLength = 1000
 
 // BulkApprove approves all silver candidates older than threshold.
 // Returns ErrThresholdTooLow if olderThan < 1h to prevent accidental mass approval.
+// Fails with ErrQuorumNotMet when the gate policy requires more than one
+// silver approval: approval by age must not count toward a multi-reviewer
+// quorum.
 func (p *Pool) BulkApprove(olderThan time.Duration, reviewer string, dryRun bool) ([]string, error) {
 	if olderThan < MinBulkApproveThreshold {
 		return nil, ErrThresholdTooLow
 	}
+	policy, err := p.GatePolicy()
+	if err != nil {
+		return nil, err
+	}
+	if need := policy.RequiredApprovals(types.TierSilver); need > 1 {
+		return nil, fmt.Errorf("%w: silver tier requires %d approvals; review candidates individually", ErrQuorumNotMet, need)
+	}
 
 	entries, err := p.List(ListOptions{
 		Tier:   types.TierSilver,
@@ -653,6 +816,10 @@ func (p *Pool) BulkApprove(olderThan time.Duration, reviewer string, dryRun bool
 
 	var approved []string
 	for _, entry := range entries {
+		if policy.EntryQuorum(&entry) > 1 {
+			// Retiered down from a tier that needs several reviewers.
+			continue
+		}
 		if entry.Age >= olderThan {
 			if id, ok := p.bulkApproveEntry(entry, reviewer, dryRun); ok {
 				approved = append(approved, id)
@@ -818,10 +985,28 @@ func defaultOpenChainFile(path string) (fileWriter, error) {
 	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
 }
 
-// recordEvent appends an event to the chain file.
-func (p *Pool) recordEvent(event ChainEvent) (err error) {
+// recordEvent links an event to the chain head and appends it to the chain
+// file. The head read and the append hold the chain lock, so concurrent
+// writers cannot link two events to the same predecessor.
+func (p *Pool) recordEvent(event ChainEvent) error {
+	return p.withChainLock(func() error {
+		return p.appendChainEvent(event)
+	})
+}
+
+// appendChainEvent links event to the current head and appends it. Callers
+// hold the chain lock.
+func (p *Pool) appendChainEvent(event ChainEvent) (err error) {
 	chainPath := filepath.Join(p.PoolPath, ChainFile)
 
+	prevHash, err := chainHead(chainPath)
+	if err != nil {
+		return fmt.Errorf("read chain head: %w", err)
+	}
+	if err := linkChainEvent(&event, prevHash); err != nil {
+		return err
+	}
+
 	data, err := jsonMarshalFunc(event)
 	if err != nil {
 		return err
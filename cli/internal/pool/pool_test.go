@@ -4586,3 +4586,42 @@ func TestPoolNaNScore(t *testing.T) {
 		t.Errorf("expected NaN-related error, got: %v", err)
 	}
 }
+
+func TestPoolRetier(t *testing.T) {
+	p := NewPool(t.TempDir())
+	candidate := types.Candidate{ID: "retier-test", Tier: types.TierBronze, Content: "content"}
+	if err := p.Add(candidate, types.Scoring{TierAssignment: types.TierBronze}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	if err := p.Retier("retier-test", types.TierDiscard, "tester"); err == nil {
+		t.Error("expected error retiering to discard")
+	}
+	if err := p.Retier("retier-test", types.TierSilver, "tester"); err != nil {
+		t.Fatalf("Retier: %v", err)
+	}
+
+	entry, err := p.Get("retier-test")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if entry.Candidate.Tier != types.TierSilver || entry.ScoringResult.TierAssignment != types.TierBronze {
+		t.Errorf("tier = %s, scored tier = %s; want silver, bronze", entry.Candidate.Tier, entry.ScoringResult.TierAssignment)
+	}
+
+	events, err := p.GetChain()
+	if err != nil {
+		t.Fatalf("GetChain: %v", err)
+	}
+	last := events[len(events)-1]
+	if last.Operation != "retier" || last.Reviewer != "tester" || last.Reason != "tier bronze -> silver" {
+		t.Errorf("last event = %+v", last)
+	}
+
+	if err := p.Reject("retier-test", "no", "tester"); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if err := p.Retier("retier-test", types.TierGold, "tester"); err == nil {
+		t.Error("expected error retiering a rejected candidate")
+	}
+}
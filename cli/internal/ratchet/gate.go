@@ -76,6 +76,93 @@ func (g *GateChecker) Check(step Step) (*GateResult, error) {
 	}, nil
 }
 
+// CheckWorkflowStep validates the gate for a step of workflow w. Steps the
+// chain must have completed first are checked before the gate itself. A
+// declarative gate takes precedence over the built-in check of a step.
+func (g *GateChecker) CheckWorkflowStep(w *Workflow, chain *Chain, step Step) (*GateResult, error) {
+	ws, ok := w.Step(step)
+	if !ok {
+		return &GateResult{
+			Step:    step,
+			Passed:  false,
+			Message: fmt.Sprintf("Unknown step: %s", step),
+		}, nil
+	}
+
+	if unmet := w.UnmetNeeds(chain, step); len(unmet) > 0 {
+		names := make([]string, len(unmet))
+		for i, s := range unmet {
+			names[i] = string(s)
+		}
+		return &GateResult{
+			Step:    step,
+			Passed:  false,
+			Message: fmt.Sprintf("Requires %s to be recorded first", strings.Join(names, ", ")),
+		}, nil
+	}
+
+	if ws.Gate != nil {
+		return g.checkDeclaredGate(step, ws.Gate), nil
+	}
+	if _, builtin := gateCheckerFuncs[step]; builtin {
+		return g.Check(step)
+	}
+	return &GateResult{
+		Step:    step,
+		Passed:  true,
+		Message: "No gate defined",
+	}, nil
+}
+
+// checkDeclaredGate evaluates a workflow-defined gate.
+func (g *GateChecker) checkDeclaredGate(step Step, gate *StepGate) *GateResult {
+	for _, pattern := range gate.Artifacts {
+		if path, loc, err := g.locator.FindFirst(pattern); err == nil {
+			return &GateResult{
+				Step:     step,
+				Passed:   true,
+				Message:  fmt.Sprintf("Artifact found: %s", path),
+				Input:    path,
+				Location: string(loc),
+			}
+		}
+	}
+	if gate.Epic != "" {
+		if epicID, err := g.findEpic(gate.Epic); err == nil && epicID != "" {
+			return &GateResult{
+				Step:     step,
+				Passed:   true,
+				Message:  fmt.Sprintf("Epic %s is %s", epicID, gate.Epic),
+				Input:    epicID,
+				Location: "beads",
+			}
+		}
+	}
+
+	var missing []string
+	if len(gate.Artifacts) > 0 {
+		missing = append(missing, "artifact matching "+strings.Join(gate.Artifacts, " or "))
+	}
+	if gate.Epic != "" {
+		missing = append(missing, gate.Epic+" epic")
+	}
+	if len(missing) == 0 {
+		return &GateResult{Step: step, Passed: true, Message: "Gate has no requirements"}
+	}
+	if gate.Soft {
+		return &GateResult{
+			Step:    step,
+			Passed:  true,
+			Message: fmt.Sprintf("Soft gate: always passes (no %s found)", strings.Join(missing, " or ")),
+		}
+	}
+	return &GateResult{
+		Step:    step,
+		Passed:  false,
+		Message: fmt.Sprintf("No %s found.", strings.Join(missing, " or ")),
+	}
+}
+
 // checkResearchGate - No gate (chaos phase, always passes).
 func (g *GateChecker) checkResearchGate() (*GateResult, error) {
 	return &GateResult{
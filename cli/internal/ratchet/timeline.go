@@ -0,0 +1,271 @@
+package ratchet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timeline export formats accepted by RenderTimeline.
+const (
+	TimelineFormatJSON    = "json"
+	TimelineFormatDOT     = "dot"
+	TimelineFormatMermaid = "mermaid"
+)
+
+// TimelineFormats lists the supported timeline export formats.
+var TimelineFormats = []string{TimelineFormatJSON, TimelineFormatDOT, TimelineFormatMermaid}
+
+// Timeline is the full history of a chain: every recorded entry in order,
+// with the time spent reaching it and the cycle it belongs to.
+type Timeline struct {
+	ChainID string          `json:"chain_id"`
+	EpicID  string          `json:"epic_id,omitempty"`
+	Started time.Time       `json:"started"`
+	Ended   time.Time       `json:"ended,omitzero"`
+	Cycles  []TimelineCycle `json:"cycles"`
+}
+
+// TimelineCycle groups the events of one RPI cycle.
+type TimelineCycle struct {
+	Cycle      int             `json:"cycle"`
+	ParentEpic string          `json:"parent_epic,omitempty"`
+	Events     []TimelineEvent `json:"events"`
+}
+
+// TimelineEvent is one chain entry placed on the timeline. Start is when
+// the previous entry was recorded (or the chain started), so the event spans
+// the time taken to reach this step.
+type TimelineEvent struct {
+	Step     Step       `json:"step"`
+	Status   StepStatus `json:"status"`
+	Start    time.Time  `json:"start"`
+	End      time.Time  `json:"end"`
+	Seconds  float64    `json:"duration_seconds"`
+	Reopened bool       `json:"reopened,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+	Input    string     `json:"input,omitempty"`
+	Output   string     `json:"output,omitempty"`
+}
+
+// Duration is the time between the previous entry and this one.
+func (e TimelineEvent) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// BuildTimeline lays out the entries of chain in recorded order. Entries
+// without a cycle number belong to the cycle before them (the first cycle
+// is 1). A step recorded again after it was locked or skipped in the same
+// cycle is marked reopened.
+func BuildTimeline(chain *Chain) *Timeline {
+	t := &Timeline{
+		ChainID: chain.ID,
+		EpicID:  chain.EpicID,
+		Started: chain.Started,
+	}
+
+	prev := chain.Started
+	if len(chain.Entries) > 0 && (prev.IsZero() || prev.After(chain.Entries[0].Timestamp)) {
+		prev = chain.Entries[0].Timestamp
+		t.Started = prev
+	}
+
+	var current *TimelineCycle
+	done := make(map[Step]bool)
+	for _, entry := range chain.Entries {
+		cycle := entry.Cycle
+		if cycle == 0 {
+			cycle = 1
+			if current != nil {
+				cycle = current.Cycle
+			}
+		}
+		if current == nil || cycle != current.Cycle {
+			t.Cycles = append(t.Cycles, TimelineCycle{Cycle: cycle})
+			current = &t.Cycles[len(t.Cycles)-1]
+			done = make(map[Step]bool)
+		}
+		if entry.ParentEpic != "" {
+			current.ParentEpic = entry.ParentEpic
+		}
+
+		start := prev
+		if entry.Timestamp.Before(start) {
+			start = entry.Timestamp
+		}
+		event := TimelineEvent{
+			Step:     entry.Step,
+			Status:   entryStatus(entry),
+			Start:    start,
+			End:      entry.Timestamp,
+			Reopened: done[entry.Step],
+			Reason:   entry.Reason,
+			Input:    entry.Input,
+			Output:   entry.Output,
+		}
+		event.Seconds = event.Duration().Seconds()
+		current.Events = append(current.Events, event)
+
+		done[entry.Step] = entry.Locked || entry.Skipped
+		prev = entry.Timestamp
+		t.Ended = entry.Timestamp
+	}
+	return t
+}
+
+// entryStatus is the status a single entry gives its step.
+func entryStatus(e ChainEntry) StepStatus {
+	switch {
+	case e.Skipped:
+		return StatusSkipped
+	case e.Locked:
+		return StatusLocked
+	default:
+		return StatusInProgress
+	}
+}
+
+// Cycle returns the timeline restricted to one cycle, or nil if the chain
+// has no such cycle.
+func (t *Timeline) Cycle(n int) *Timeline {
+	for _, c := range t.Cycles {
+		if c.Cycle == n && len(c.Events) > 0 {
+			out := *t
+			out.Cycles = []TimelineCycle{c}
+			out.Started = c.Events[0].Start
+			out.Ended = c.Events[len(c.Events)-1].End
+			return &out
+		}
+	}
+	return nil
+}
+
+// LongestWait returns the event that took longest to reach, with its cycle.
+// ok is false for an empty timeline.
+func (t *Timeline) LongestWait() (event TimelineEvent, cycle int, ok bool) {
+	for _, c := range t.Cycles {
+		for _, e := range c.Events {
+			if !ok || e.Duration() > event.Duration() {
+				event, cycle, ok = e, c.Cycle, true
+			}
+		}
+	}
+	return event, cycle, ok
+}
+
+// RenderTimeline writes t to w in the given export format.
+func RenderTimeline(w io.Writer, t *Timeline, format string) error {
+	switch format {
+	case TimelineFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t)
+	case TimelineFormatDOT:
+		return renderTimelineDOT(w, t)
+	case TimelineFormatMermaid:
+		return renderTimelineMermaid(w, t)
+	default:
+		return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(TimelineFormats, ", "))
+	}
+}
+
+// eventLabel is the step name annotated with how the entry ended.
+func eventLabel(e TimelineEvent) string {
+	label := string(e.Step)
+	switch {
+	case e.Status == StatusSkipped && e.Reason != "":
+		label += " (skipped: " + e.Reason + ")"
+	case e.Status == StatusSkipped:
+		label += " (skipped)"
+	case e.Status == StatusInProgress:
+		label += " (in progress)"
+	}
+	if e.Reopened {
+		label += " [reopened]"
+	}
+	return label
+}
+
+// renderTimelineDOT writes the events as a left-to-right chain, one cluster
+// per cycle, with the wait before each step on the edge leading into it.
+func renderTimelineDOT(w io.Writer, t *Timeline) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph ratchet {\n  rankdir=LR;\n  label=%s;\n  node [shape=box];\n", strconv.Quote("Ratchet chain "+t.ChainID))
+	n := 0
+	prevID := ""
+	for _, c := range t.Cycles {
+		fmt.Fprintf(&b, "  subgraph cluster_cycle_%d {\n    label=%s;\n", c.Cycle, strconv.Quote(fmt.Sprintf("Cycle %d", c.Cycle)))
+		var edges []string
+		for _, e := range c.Events {
+			id := "e" + strconv.Itoa(n)
+			n++
+			attrs := "label=" + strconv.Quote(eventLabel(e)+"\n"+e.End.UTC().Format(time.RFC3339))
+			switch {
+			case e.Status == StatusSkipped:
+				attrs += ", style=dashed"
+			case e.Status == StatusInProgress:
+				attrs += ", style=dotted"
+			}
+			if e.Reopened {
+				attrs += ", color=red"
+			}
+			fmt.Fprintf(&b, "    %s [%s];\n", id, attrs)
+			if prevID != "" {
+				edges = append(edges, fmt.Sprintf("  %s -> %s [label=%s];\n", prevID, id, strconv.Quote(e.Duration().Round(time.Second).String())))
+			}
+			prevID = id
+		}
+		b.WriteString("  }\n")
+		for _, edge := range edges {
+			b.WriteString(edge)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidTimeFormat is the Go layout matching the gantt dateFormat below.
+const mermaidTimeFormat = "2006-01-02 15:04:05"
+
+// renderTimelineMermaid writes a gantt chart with one section per cycle.
+// Skipped steps are milestones; reopened steps are marked critical.
+func renderTimelineMermaid(w io.Writer, t *Timeline) error {
+	var b strings.Builder
+	b.WriteString("gantt\n")
+	fmt.Fprintf(&b, "  title Ratchet chain %s\n", ganttEscape(t.ChainID))
+	b.WriteString("  dateFormat YYYY-MM-DD HH:mm:ss\n  axisFormat %m-%d %H:%M\n")
+	n := 0
+	for _, c := range t.Cycles {
+		fmt.Fprintf(&b, "  section Cycle %d\n", c.Cycle)
+		for _, e := range c.Events {
+			id := "e" + strconv.Itoa(n)
+			n++
+			end := e.End.UTC().Format(mermaidTimeFormat)
+			if e.Status == StatusSkipped {
+				fmt.Fprintf(&b, "  %s :milestone, %s, %s, 0d\n", ganttEscape(eventLabel(e)), id, end)
+				continue
+			}
+			var tags []string
+			if e.Reopened {
+				tags = append(tags, "crit")
+			}
+			if e.Status == StatusInProgress {
+				tags = append(tags, "active")
+			} else {
+				tags = append(tags, "done")
+			}
+			fmt.Fprintf(&b, "  %s :%s, %s, %s, %s\n", ganttEscape(eventLabel(e)), strings.Join(tags, ", "), id, e.Start.UTC().Format(mermaidTimeFormat), end)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ganttEscape removes characters that end a Mermaid gantt task name.
+func ganttEscape(s string) string {
+	return strings.NewReplacer(":", " -", ";", ",", "#", "", "\n", " ").Replace(s)
+}
@@ -0,0 +1,131 @@
+package ratchet
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func timelineChain() *Chain {
+	base := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return base.Add(d) }
+	return &Chain{
+		ID:      "chain-demo",
+		Started: base,
+		Entries: []ChainEntry{
+			{Step: StepResearch, Timestamp: at(time.Hour), Output: "research.md", Locked: true, Cycle: 1},
+			{Step: StepPreMortem, Timestamp: at(90 * time.Minute), Locked: true, Skipped: true, Reason: "small fix"},
+			{Step: StepPlan, Timestamp: at(3 * time.Hour), Output: "epic:ol-1", Locked: true},
+			{Step: StepPlan, Timestamp: at(7 * time.Hour), Output: "epic:ol-1b", Locked: true},
+			{Step: StepImplement, Timestamp: at(24 * time.Hour), Output: "issue:ol-2"},
+			{Step: StepResearch, Timestamp: at(48 * time.Hour), Output: "research-2.md", Locked: true, Cycle: 2, ParentEpic: "ol-1"},
+		},
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	tl := BuildTimeline(timelineChain())
+
+	if len(tl.Cycles) != 2 {
+		t.Fatalf("cycles = %d, want 2", len(tl.Cycles))
+	}
+	first := tl.Cycles[0].Events
+	if len(first) != 5 {
+		t.Fatalf("cycle 1 events = %d, want 5", len(first))
+	}
+	if first[0].Duration() != time.Hour {
+		t.Errorf("research duration = %s, want 1h from chain start", first[0].Duration())
+	}
+	if first[1].Status != StatusSkipped || first[1].Reason != "small fix" {
+		t.Errorf("pre-mortem event = %+v", first[1])
+	}
+	if first[2].Reopened || !first[3].Reopened {
+		t.Errorf("second plan entry should be reopened: %+v / %+v", first[2], first[3])
+	}
+	if first[4].Status != StatusInProgress || first[4].Seconds != (17*time.Hour).Seconds() {
+		t.Errorf("implement event = %+v", first[4])
+	}
+	second := tl.Cycles[1]
+	if second.Cycle != 2 || second.ParentEpic != "ol-1" || second.Events[0].Reopened {
+		t.Errorf("cycle 2 = %+v (research should not count as reopened in a new cycle)", second)
+	}
+
+	e, cycle, ok := tl.LongestWait()
+	if !ok || e.Step != StepResearch || cycle != 2 {
+		t.Errorf("LongestWait = %s in cycle %d", e.Step, cycle)
+	}
+	if got := tl.Ended.Sub(tl.Started); got != 48*time.Hour {
+		t.Errorf("span = %s, want 48h", got)
+	}
+}
+
+func TestTimeline_Cycle(t *testing.T) {
+	tl := BuildTimeline(timelineChain())
+	c2 := tl.Cycle(2)
+	if c2 == nil || len(c2.Cycles) != 1 || c2.Started != tl.Cycles[1].Events[0].Start {
+		t.Fatalf("Cycle(2) = %+v", c2)
+	}
+	if tl.Cycle(3) != nil {
+		t.Error("Cycle(3) should be nil")
+	}
+}
+
+func TestBuildTimeline_Empty(t *testing.T) {
+	tl := BuildTimeline(&Chain{ID: "empty"})
+	if len(tl.Cycles) != 0 {
+		t.Errorf("cycles = %d, want 0", len(tl.Cycles))
+	}
+	if _, _, ok := tl.LongestWait(); ok {
+		t.Error("empty timeline has no longest wait")
+	}
+}
+
+func TestRenderTimeline(t *testing.T) {
+	tl := BuildTimeline(timelineChain())
+
+	var buf bytes.Buffer
+	if err := RenderTimeline(&buf, tl, TimelineFormatMermaid); err != nil {
+		t.Fatal(err)
+	}
+	mermaid := buf.String()
+	for _, want := range []string{
+		"gantt\n",
+		"section Cycle 2",
+		"pre-mortem (skipped - small fix) :milestone, e1, 2026-01-10 10:30:00, 0d",
+		"plan [reopened] :crit, done, e3, 2026-01-10 12:00:00, 2026-01-10 16:00:00",
+		"implement (in progress) :active",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+
+	buf.Reset()
+	if err := RenderTimeline(&buf, tl, TimelineFormatDOT); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	for _, want := range []string{"digraph ratchet {", "subgraph cluster_cycle_1", "style=dashed", "color=red", `e3 -> e4 [label="17h0m0s"]`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("dot missing %q:\n%s", want, dot)
+		}
+	}
+
+	buf.Reset()
+	if err := RenderTimeline(&buf, tl, TimelineFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Timeline
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(decoded.Cycles) != 2 || !decoded.Cycles[0].Events[3].Reopened {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	if err := RenderTimeline(&buf, tl, "svg"); err == nil {
+		t.Error("unknown format should error")
+	}
+}
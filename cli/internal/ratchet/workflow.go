@@ -0,0 +1,320 @@
+package ratchet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowFile is the workflow definition path, relative to the .agents directory.
+const WorkflowFile = "ratchet/workflow.yaml"
+
+// validStepName matches step names a workflow may define.
+var validStepName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// epicStatuses are the bd epic statuses a declarative gate may require.
+var epicStatuses = []string{"open", "in_progress", "closed"}
+
+// builtinSkills maps the built-in steps to the skills that perform them.
+var builtinSkills = map[Step]string{
+	StepResearch:   "/research",
+	StepPreMortem:  "/pre-mortem",
+	StepPlan:       "/plan",
+	StepImplement:  "/implement or /crank",
+	StepCrank:      "/implement or /crank",
+	StepVibe:       "/vibe",
+	StepPostMortem: "/post-mortem",
+}
+
+// Workflow is an ordered set of ratchet steps. The default workflow is the
+// seven-step RPI flow; a repo can replace it with .agents/ratchet/workflow.yaml.
+type Workflow struct {
+	// Source is the file the workflow was loaded from, or "" for the default.
+	Source string `json:"source,omitempty" yaml:"-"`
+
+	// Steps are the workflow steps in order.
+	Steps []WorkflowStep `json:"steps" yaml:"steps"`
+
+	// aliases maps every accepted name to its step, built by index.
+	aliases map[string]Step
+}
+
+// WorkflowStep defines one step of a workflow.
+type WorkflowStep struct {
+	// Name is the canonical step name recorded in the chain.
+	Name Step `json:"name" yaml:"name"`
+
+	// Aliases are alternative names accepted on the command line.
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+
+	// Needs lists steps that must be locked or skipped before this step's
+	// gate passes. They must appear earlier in the workflow.
+	Needs []Step `json:"needs,omitempty" yaml:"needs,omitempty"`
+
+	// Optional steps are never suggested by next; the flow moves past them.
+	Optional bool `json:"optional,omitempty" yaml:"optional,omitempty"`
+
+	// Satisfies names another step that this step stands in for, the way
+	// crank stands in for implement.
+	Satisfies Step `json:"satisfies,omitempty" yaml:"satisfies,omitempty"`
+
+	// Gate declares what must exist before the step can run. Built-in steps
+	// without a gate keep their built-in checks; other steps always pass.
+	Gate *StepGate `json:"gate,omitempty" yaml:"gate,omitempty"`
+
+	// Skill is the command suggested by next.
+	Skill string `json:"skill,omitempty" yaml:"skill,omitempty"`
+
+	// Input describes the artifact the step consumes.
+	Input string `json:"input,omitempty" yaml:"input,omitempty"`
+
+	// Output describes the artifact the step produces.
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// StepGate is a declarative gate. Artifacts are glob patterns relative to
+// .agents, searched across crew, rig and town locations; any match passes.
+// Epic requires a bd epic with the given status. A soft gate always passes
+// and only reports what it found.
+type StepGate struct {
+	Artifacts []string `json:"artifacts,omitempty" yaml:"artifacts,omitempty"`
+	Epic      string   `json:"epic,omitempty" yaml:"epic,omitempty"`
+	Soft      bool     `json:"soft,omitempty" yaml:"soft,omitempty"`
+}
+
+// DefaultWorkflow returns the built-in seven-step RPI workflow.
+func DefaultWorkflow() *Workflow {
+	w := &Workflow{}
+	for _, step := range AllSteps() {
+		ws := WorkflowStep{Name: step}
+		switch step {
+		case StepCrank:
+			ws.Optional = true
+			ws.Satisfies = StepImplement
+		}
+		w.Steps = append(w.Steps, w.withBuiltinDefaults(ws))
+	}
+	w.index()
+	return w
+}
+
+// LoadWorkflow returns the workflow of the nearest .agents directory, or the
+// default workflow when none is defined.
+func LoadWorkflow(startDir string) (*Workflow, error) {
+	agentsDir, err := findAgentsDir(startDir)
+	if err != nil {
+		return DefaultWorkflow(), nil
+	}
+	path := filepath.Join(agentsDir, WorkflowFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultWorkflow(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read workflow: %w", err)
+	}
+	w, err := ParseWorkflow(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	w.Source = path
+	return w, nil
+}
+
+// ParseWorkflow parses and validates a workflow definition. Built-in step
+// names keep their built-in aliases, skill and artifact descriptions unless
+// the definition overrides them.
+func ParseWorkflow(data []byte) (*Workflow, error) {
+	var w Workflow
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&w); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse workflow: %w", err)
+	}
+	for i, ws := range w.Steps {
+		ws.Name = Step(strings.ToLower(strings.TrimSpace(string(ws.Name))))
+		w.Steps[i] = w.withBuiltinDefaults(ws)
+	}
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	w.index()
+	return &w, nil
+}
+
+// withBuiltinDefaults fills unset fields of a built-in step.
+func (w *Workflow) withBuiltinDefaults(ws WorkflowStep) WorkflowStep {
+	if !slices.Contains(AllSteps(), ws.Name) {
+		return ws
+	}
+	for alias, step := range stepAliases {
+		if step == ws.Name && alias != string(ws.Name) && !w.claimsName(alias) {
+			ws.Aliases = append(ws.Aliases, alias)
+		}
+	}
+	slices.Sort(ws.Aliases)
+	if ws.Skill == "" {
+		ws.Skill = builtinSkills[ws.Name]
+	}
+	if ws.Input == "" {
+		ws.Input = requiredInputs[ws.Name]
+	}
+	if ws.Output == "" {
+		ws.Output = expectedOutputs[ws.Name]
+	}
+	return ws
+}
+
+// claimsName reports whether a defined step is named name or lists it as
+// an alias, so built-in aliases never shadow names the workflow chose.
+func (w *Workflow) claimsName(name string) bool {
+	return slices.ContainsFunc(w.Steps, func(ws WorkflowStep) bool {
+		return string(ws.Name) == name || slices.Contains(ws.Aliases, name)
+	})
+}
+
+// Validate checks that step names and aliases are unique, that needs refer
+// to earlier steps and that gates are well formed.
+func (w *Workflow) Validate() error {
+	if len(w.Steps) == 0 {
+		return errors.New("workflow: no steps defined")
+	}
+	seen := make(map[string]Step)
+	claim := func(name string, step Step) error {
+		if other, ok := seen[name]; ok && other != step {
+			return fmt.Errorf("workflow: name %q used by both %s and %s", name, other, step)
+		}
+		seen[name] = step
+		return nil
+	}
+	for _, ws := range w.Steps {
+		if !validStepName.MatchString(string(ws.Name)) {
+			return fmt.Errorf("workflow: step name %q must be lowercase letters, digits and hyphens", ws.Name)
+		}
+		if _, dup := seen[string(ws.Name)]; dup {
+			return fmt.Errorf("workflow: step %s defined twice", ws.Name)
+		}
+		if err := claim(string(ws.Name), ws.Name); err != nil {
+			return err
+		}
+	}
+	for i, ws := range w.Steps {
+		for _, alias := range ws.Aliases {
+			if err := claim(strings.ToLower(alias), ws.Name); err != nil {
+				return err
+			}
+		}
+		for _, need := range ws.Needs {
+			if !slices.ContainsFunc(w.Steps[:i], func(s WorkflowStep) bool { return s.Name == need }) {
+				return fmt.Errorf("workflow: step %s needs %s, which is not an earlier step", ws.Name, need)
+			}
+		}
+		if ws.Satisfies != "" && (ws.Satisfies == ws.Name || w.indexOf(ws.Satisfies) < 0) {
+			return fmt.Errorf("workflow: step %s satisfies unknown step %s", ws.Name, ws.Satisfies)
+		}
+		if ws.Gate != nil && ws.Gate.Epic != "" && !slices.Contains(epicStatuses, ws.Gate.Epic) {
+			return fmt.Errorf("workflow: step %s gate epic must be one of %s", ws.Name, strings.Join(epicStatuses, ", "))
+		}
+	}
+	if w.Steps[len(w.Steps)-1].Optional {
+		return fmt.Errorf("workflow: final step %s cannot be optional", w.Steps[len(w.Steps)-1].Name)
+	}
+	return nil
+}
+
+func (w *Workflow) index() {
+	w.aliases = make(map[string]Step)
+	for _, ws := range w.Steps {
+		w.aliases[string(ws.Name)] = ws.Name
+		for _, alias := range ws.Aliases {
+			w.aliases[strings.ToLower(alias)] = ws.Name
+		}
+	}
+}
+
+func (w *Workflow) indexOf(step Step) int {
+	return slices.IndexFunc(w.Steps, func(ws WorkflowStep) bool { return ws.Name == step })
+}
+
+// ParseStep resolves a step name or alias to the workflow's canonical step.
+// Returns empty string if the workflow has no such step.
+func (w *Workflow) ParseStep(name string) Step {
+	if w.aliases == nil {
+		w.index()
+	}
+	return w.aliases[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// Step returns the definition of a step.
+func (w *Workflow) Step(step Step) (WorkflowStep, bool) {
+	if i := w.indexOf(step); i >= 0 {
+		return w.Steps[i], true
+	}
+	return WorkflowStep{}, false
+}
+
+// StepNames returns the canonical step names in order.
+func (w *Workflow) StepNames() []Step {
+	names := make([]Step, len(w.Steps))
+	for i, ws := range w.Steps {
+		names[i] = ws.Name
+	}
+	return names
+}
+
+// Last returns the final step of the workflow.
+func (w *Workflow) Last() Step {
+	return w.Steps[len(w.Steps)-1].Name
+}
+
+// Successor returns the first non-optional step after step, or "" when step
+// is the last step or not part of the workflow.
+func (w *Workflow) Successor(step Step) Step {
+	i := w.indexOf(step)
+	if i < 0 {
+		return ""
+	}
+	for _, ws := range w.Steps[i+1:] {
+		if !ws.Optional {
+			return ws.Name
+		}
+	}
+	return ""
+}
+
+// Done reports whether step is locked or skipped in chain, directly or
+// through a step that satisfies it.
+func (w *Workflow) Done(chain *Chain, step Step) bool {
+	for _, ws := range w.Steps {
+		if ws.Name != step && ws.Satisfies != step {
+			continue
+		}
+		if status := chain.GetStatus(ws.Name); status == StatusLocked || status == StatusSkipped {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmetNeeds returns the steps step needs that chain has not completed.
+func (w *Workflow) UnmetNeeds(chain *Chain, step Step) []Step {
+	ws, ok := w.Step(step)
+	if !ok {
+		return nil
+	}
+	var unmet []Step
+	for _, need := range ws.Needs {
+		if !w.Done(chain, need) {
+			unmet = append(unmet, need)
+		}
+	}
+	return unmet
+}
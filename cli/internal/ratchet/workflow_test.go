@@ -0,0 +1,207 @@
+package ratchet
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeWorkflow(t *testing.T, dir, content string) {
+	t.Helper()
+	path := filepath.Join(dir, ".agents", WorkflowFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultWorkflow_MatchesBuiltinSteps(t *testing.T) {
+	w := DefaultWorkflow()
+	if !slices.Equal(w.StepNames(), AllSteps()) {
+		t.Fatalf("StepNames = %v, want %v", w.StepNames(), AllSteps())
+	}
+	for alias, want := range stepAliases {
+		if got := w.ParseStep(alias); got != want {
+			t.Errorf("ParseStep(%q) = %q, want %q", alias, got, want)
+		}
+	}
+	if got := w.Successor(StepPlan); got != StepImplement {
+		t.Errorf("Successor(plan) = %q, want implement", got)
+	}
+	if got := w.Successor(StepCrank); got != StepVibe {
+		t.Errorf("Successor(crank) = %q, want vibe", got)
+	}
+	if got := w.Successor(StepPostMortem); got != "" {
+		t.Errorf("Successor(post-mortem) = %q, want empty", got)
+	}
+	if ws, _ := w.Step(StepVibe); ws.Skill != "/vibe" || ws.Output != expectedOutputs[StepVibe] {
+		t.Errorf("vibe step = %+v", ws)
+	}
+}
+
+func TestLoadWorkflow_MissingFileIsDefault(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".agents"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	w, err := LoadWorkflow(dir)
+	if err != nil {
+		t.Fatalf("LoadWorkflow: %v", err)
+	}
+	if w.Source != "" || len(w.Steps) != len(AllSteps()) {
+		t.Errorf("expected default workflow, got source %q with %d steps", w.Source, len(w.Steps))
+	}
+}
+
+func TestLoadWorkflow_Custom(t *testing.T) {
+	dir := t.TempDir()
+	writeWorkflow(t, dir, `steps:
+  - name: research
+  - name: plan
+  - name: implement
+    needs: [plan]
+  - name: security-review
+    aliases: [secrev]
+    needs: [implement]
+    gate: {artifacts: ["security/*.md"]}
+    skill: /security-review
+  - name: post-mortem
+    aliases: [review]
+`)
+	w, err := LoadWorkflow(dir)
+	if err != nil {
+		t.Fatalf("LoadWorkflow: %v", err)
+	}
+	if !strings.HasSuffix(w.Source, WorkflowFile) {
+		t.Errorf("Source = %q", w.Source)
+	}
+	if got := w.ParseStep("SecRev"); got != "security-review" {
+		t.Errorf("ParseStep(SecRev) = %q", got)
+	}
+	if got := w.ParseStep("postmortem"); got != StepPostMortem {
+		t.Errorf("built-in alias postmortem = %q, want post-mortem", got)
+	}
+	if got := w.ParseStep("crank"); got != "" {
+		t.Errorf("crank is not in this workflow, ParseStep = %q", got)
+	}
+	if got := w.Successor(StepImplement); got != "security-review" {
+		t.Errorf("Successor(implement) = %q", got)
+	}
+	if ws, _ := w.Step(StepPlan); ws.Skill != "/plan" {
+		t.Errorf("plan should keep its built-in skill, got %q", ws.Skill)
+	}
+}
+
+func TestParseWorkflow_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"empty", "steps: []", "no steps"},
+		{"bad name", "steps: [{name: \"Security Review\"}]", "lowercase"},
+		{"duplicate", "steps: [{name: plan}, {name: plan}]", "defined twice"},
+		{"alias collides", "steps: [{name: plan}, {name: build, aliases: [plan]}]", "used by both"},
+		{"forward need", "steps: [{name: plan, needs: [build]}, {name: build}]", "not an earlier step"},
+		{"bad satisfies", "steps: [{name: plan, satisfies: ship}]", "satisfies unknown step"},
+		{"bad epic", "steps: [{name: plan, gate: {epic: done}}]", "gate epic"},
+		{"optional last", "steps: [{name: plan}, {name: ship, optional: true}]", "cannot be optional"},
+		{"typo", "steps: [{name: plan, need: [x]}]", "parse workflow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWorkflow([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseWorkflow error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkflow_UnmetNeedsHonorsSatisfies(t *testing.T) {
+	w := DefaultWorkflow()
+	w.Steps[w.indexOf(StepVibe)].Needs = []Step{StepImplement}
+	chain := &Chain{}
+	if got := w.UnmetNeeds(chain, StepVibe); !slices.Equal(got, []Step{StepImplement}) {
+		t.Errorf("UnmetNeeds = %v, want [implement]", got)
+	}
+	chain.Entries = append(chain.Entries, ChainEntry{Step: StepCrank, Timestamp: time.Now(), Locked: true})
+	if got := w.UnmetNeeds(chain, StepVibe); len(got) != 0 {
+		t.Errorf("crank satisfies implement, UnmetNeeds = %v", got)
+	}
+}
+
+func TestCheckWorkflowStep_DeclaredGate(t *testing.T) {
+	dir := t.TempDir()
+	writeWorkflow(t, dir, `steps:
+  - name: implement
+  - name: security-review
+    needs: [implement]
+    gate: {artifacts: ["security/*.md"]}
+`)
+	w, err := LoadWorkflow(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker, err := NewGateChecker(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := &Chain{}
+
+	result, err := checker.CheckWorkflowStep(w, chain, "security-review")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed || !strings.Contains(result.Message, "implement") {
+		t.Errorf("unmet need should fail the gate: %+v", result)
+	}
+
+	chain.Entries = append(chain.Entries, ChainEntry{Step: StepImplement, Timestamp: time.Now(), Locked: true})
+	result, _ = checker.CheckWorkflowStep(w, chain, "security-review")
+	if result.Passed || !strings.Contains(result.Message, "security/*.md") {
+		t.Errorf("missing artifact should fail the gate: %+v", result)
+	}
+
+	report := filepath.Join(dir, ".agents", "security", "audit.md")
+	if err := os.MkdirAll(filepath.Dir(report), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(report, []byte("# Audit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, _ = checker.CheckWorkflowStep(w, chain, "security-review")
+	if !result.Passed || !strings.HasSuffix(result.Input, "audit.md") {
+		t.Errorf("artifact present should pass: %+v", result)
+	}
+}
+
+func TestCheckWorkflowStep_BuiltinAndUngated(t *testing.T) {
+	dir := t.TempDir()
+	writeWorkflow(t, dir, "steps: [{name: research}, {name: triage}]\n")
+	w, err := LoadWorkflow(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker, err := NewGateChecker(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ := checker.CheckWorkflowStep(w, &Chain{}, StepResearch)
+	if !result.Passed || !strings.Contains(result.Message, "chaos phase") {
+		t.Errorf("research should use its built-in gate: %+v", result)
+	}
+	result, _ = checker.CheckWorkflowStep(w, &Chain{}, "triage")
+	if !result.Passed {
+		t.Errorf("ungated custom step should pass: %+v", result)
+	}
+	result, _ = checker.CheckWorkflowStep(w, &Chain{}, StepPlan)
+	if result.Passed {
+		t.Errorf("step outside the workflow should fail: %+v", result)
+	}
+}
@@ -159,7 +159,7 @@ func TestNormalizeRuntimeMode(t *testing.T) {
 }
 
 func TestValidateRuntimeMode(t *testing.T) {
-	validModes := []string{"auto", "direct", "stream", "tmux", " Auto ", "DIRECT", "TMUX"}
+	validModes := []string{"auto", "direct", "stream", "tmux", "sandbox", " Auto ", "DIRECT", "TMUX"}
 	for _, m := range validModes {
 		if err := ValidateRuntimeMode(m); err != nil {
 			t.Errorf("ValidateRuntimeMode(%q) unexpected error: %v", m, err)
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+const (
+	// BM25K1 controls term-frequency saturation.
+	BM25K1 = 1.2
+
+	// BM25B controls how strongly scores are normalised by document length.
+	BM25B = 0.75
+
+	// TitleBoost weights occurrences in the frontmatter title.
+	TitleBoost = 3.0
+
+	// TagBoost weights occurrences in the frontmatter tags.
+	TagBoost = 2.0
+)
+
+// Search finds documents matching the query and returns up to limit results
+// sorted by descending BM25 score, ties broken by path.
+func Search(idx *Index, query string, limit int) []IndexResult {
+	queryTerms := tokenize(query)
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	scores := scoreDocuments(idx, queryTerms)
+	if len(scores) == 0 {
+		return nil
+	}
+
+	return rankResults(scores, limit)
+}
+
+// scoreDocuments sums the BM25 contribution of each query term per document.
+func scoreDocuments(idx *Index, queryTerms []string) map[string]float64 {
+	avgLen := averageLength(idx)
+	scores := make(map[string]float64)
+	for _, term := range queryTerms {
+		docs, ok := idx.Terms[term]
+		if !ok || len(docs) == 0 {
+			continue
+		}
+		idf := inverseDocFrequency(len(idx.Lengths), len(docs))
+		for doc, p := range docs {
+			scores[doc] += idf * saturate(weightedFrequency(p), idx.Lengths[doc], avgLen)
+		}
+	}
+	return scores
+}
+
+// averageLength returns the mean document length, or 1 for an empty corpus.
+func averageLength(idx *Index) float64 {
+	if len(idx.Lengths) == 0 || idx.TotalLength <= 0 {
+		return 1
+	}
+	return float64(idx.TotalLength) / float64(len(idx.Lengths))
+}
+
+// inverseDocFrequency is the BM25 IDF with the +1 smoothing that keeps it
+// positive for terms that appear in most documents.
+func inverseDocFrequency(docCount, docFreq int) float64 {
+	n := float64(max(docCount, docFreq))
+	df := float64(docFreq)
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// weightedFrequency combines per-field counts using the field boosts.
+func weightedFrequency(p Posting) float64 {
+	return float64(p.TF) + TitleBoost*float64(p.Title) + TagBoost*float64(p.Tags)
+}
+
+// saturate applies BM25 term-frequency saturation and length normalisation.
+func saturate(tf float64, docLen int, avgLen float64) float64 {
+	if tf <= 0 {
+		return 0
+	}
+	norm := 1 - BM25B + BM25B*float64(docLen)/avgLen
+	return tf * (BM25K1 + 1) / (tf + BM25K1*norm)
+}
+
+// rankResults converts scores to sorted results, applying a limit.
+func rankResults(scores map[string]float64, limit int) []IndexResult {
+	results := make([]IndexResult, 0, len(scores))
+	for path, score := range scores {
+		results = append(results, IndexResult{Path: path, Score: score})
+	}
+
+	slices.SortFunc(results, func(a, b IndexResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Path, b.Path)
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
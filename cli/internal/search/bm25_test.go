@@ -0,0 +1,153 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSearch_BM25FocusedDocOutranksLongDoc(t *testing.T) {
+	dir := t.TempDir()
+	long := "mutex " + strings.Repeat("unrelated filler words about deployment pipelines ", 40)
+	writeFile(t, filepath.Join(dir, "long.md"), long)
+	writeFile(t, filepath.Join(dir, "focused.md"), "mutex contention: hold the mutex briefly, never hold a mutex across IO")
+
+	idx, err := BuildIndex(dir)
+	if err != nil {
+		t.Fatalf("BuildIndex: %v", err)
+	}
+
+	results := Search(idx, "mutex", 10)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if filepath.Base(results[0].Path) != "focused.md" {
+		t.Errorf("expected focused.md first, got %s", results[0].Path)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("expected strictly higher score for focused doc: %v vs %v", results[0].Score, results[1].Score)
+	}
+}
+
+func TestSearch_FrontmatterFieldBoosts(t *testing.T) {
+	idx := NewIndex()
+	AddDocument(idx, "/body.md", Document{Body: "notes that mention retry once among other words"})
+	AddDocument(idx, "/title.md", Document{Title: "Retry", Body: "notes that mention retry once among other words"})
+	AddDocument(idx, "/tags.md", Document{Tags: []string{"retry"}, Body: "notes that mention retry once among other words"})
+
+	results := Search(idx, "retry", 0)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	order := []string{results[0].Path, results[1].Path, results[2].Path}
+	want := []string{"/title.md", "/tags.md", "/body.md"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("ranking = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestAddDocument_ReplacesStatistics(t *testing.T) {
+	idx := NewIndex()
+	AddDocument(idx, "/a.md", Document{Body: "alpha beta gamma"})
+	AddDocument(idx, "/a.md", Document{Body: "delta"})
+
+	if idx.TotalLength != 1 || idx.Lengths["/a.md"] != 1 {
+		t.Errorf("expected length 1 after replace, got total=%d doc=%d", idx.TotalLength, idx.Lengths["/a.md"])
+	}
+	if _, ok := idx.Terms["alpha"]; ok {
+		t.Error("expected stale term 'alpha' to be purged")
+	}
+}
+
+func TestParseDocument_Frontmatter(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantTitle string
+		wantTags  []string
+	}{
+		{"inline list", "---\ntitle: \"Mutex Usage\"\ntags: [go, concurrency]\n---\nbody", "Mutex Usage", []string{"go", "concurrency"}},
+		{"block list", "---\ntitle: Retry\ntags:\n  - network\n  - 'backoff'\nutility: 0.5\n---\nbody", "Retry", []string{"network", "backoff"}},
+		{"no frontmatter", "# Heading\ntitle: not frontmatter", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseDocument(tt.content)
+			if doc.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", doc.Title, tt.wantTitle)
+			}
+			if strings.Join(doc.Tags, ",") != strings.Join(tt.wantTags, ",") {
+				t.Errorf("tags = %v, want %v", doc.Tags, tt.wantTags)
+			}
+			if doc.Body != tt.content {
+				t.Error("body should retain the full content")
+			}
+		})
+	}
+}
+
+func TestSaveIndex_WritesVersionHeader(t *testing.T) {
+	dir := t.TempDir()
+	idx := NewIndex()
+	AddDocument(idx, "/a.md", Document{Body: "alpha beta"})
+
+	indexPath := filepath.Join(dir, "index.jsonl")
+	if err := SaveIndex(idx, indexPath); err != nil {
+		t.Fatalf("SaveIndex: %v", err)
+	}
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _, _ := strings.Cut(string(data), "\n")
+	if first != `{"version":2,"docs":1,"total_length":2}` {
+		t.Errorf("unexpected header line: %s", first)
+	}
+
+	loaded, err := LoadIndex(indexPath)
+	if err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	if loaded.TotalLength != 2 || loaded.Lengths["/a.md"] != 2 {
+		t.Errorf("corpus stats not restored: total=%d lengths=%v", loaded.TotalLength, loaded.Lengths)
+	}
+}
+
+func TestLoadIndex_RebuildsLegacyFormat(t *testing.T) {
+	dir := t.TempDir()
+	live := filepath.Join(dir, "live.md")
+	writeFile(t, live, "current content mutex mutex")
+
+	// Unversioned index: presence-only postings, one of which is stale.
+	legacy := `{"term":"obsolete","paths":["` + live + `"]}
+{"term":"mutex","paths":["` + live + `","` + filepath.Join(dir, "deleted.md") + `"]}
+`
+	indexPath := filepath.Join(dir, "index.jsonl")
+	writeFile(t, indexPath, legacy)
+
+	idx, err := LoadIndex(indexPath)
+	if err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	if _, ok := idx.Terms["obsolete"]; ok {
+		t.Error("legacy term should not survive a rebuild")
+	}
+	if got := idx.Terms["mutex"][live].TF; got != 2 {
+		t.Errorf("expected rebuilt tf=2 for mutex, got %d", got)
+	}
+	if len(idx.Lengths) != 1 {
+		t.Errorf("expected only the surviving file to be indexed, got %v", idx.Lengths)
+	}
+}
+
+func TestInverseDocFrequency_PositiveForCommonTerms(t *testing.T) {
+	if idf := inverseDocFrequency(3, 3); idf <= 0 {
+		t.Errorf("expected positive idf for term in every doc, got %v", idf)
+	}
+	if idf := inverseDocFrequency(0, 2); idf <= 0 {
+		t.Errorf("expected positive idf when stats are missing, got %v", idf)
+	}
+}
@@ -4,7 +4,6 @@ package search
 
 import (
 	"bufio"
-	"cmp"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,32 +14,90 @@ import (
 	"unicode"
 )
 
+// IndexVersion is the on-disk format version written by SaveIndex.
+// LoadIndex rebuilds indexes written with any other version from their
+// source files instead of trying to interpret them.
+const IndexVersion = 2
+
+// DefaultIndexFile is the index file name used under .agents/ao/.
+const DefaultIndexFile = "index.jsonl"
+
 var createIndexOutput = func(path string) (io.WriteCloser, error) {
 	return os.Create(path)
 }
 
+// Posting records how often a term occurs in one document, split by field
+// so that field boosts can be applied at query time.
+type Posting struct {
+	// TF is the number of occurrences in the document body.
+	TF int `json:"tf,omitempty"`
+	// Title is the number of occurrences in the frontmatter title.
+	Title int `json:"title,omitempty"`
+	// Tags is the number of occurrences in the frontmatter tags.
+	Tags int `json:"tags,omitempty"`
+}
+
 // Index is an in-memory inverted index mapping lowercase terms to the
-// set of document paths that contain them.
+// documents that contain them, with the statistics needed for BM25.
 type Index struct {
-	// Terms maps each lowercase term to a set of document paths.
-	Terms map[string]map[string]bool `json:"-"`
+	// Terms maps each lowercase term to its per-document postings.
+	Terms map[string]map[string]Posting `json:"-"`
+
+	// Lengths maps each document path to its body length in tokens.
+	Lengths map[string]int `json:"-"`
+
+	// TotalLength is the sum of all document lengths.
+	TotalLength int `json:"-"`
+}
+
+// Document is the field-split content of a single indexed file.
+type Document struct {
+	Title string
+	Tags  []string
+	Body  string
+}
+
+// indexHeader is the first line of a versioned index file.
+type indexHeader struct {
+	Version     int `json:"version"`
+	Docs        int `json:"docs"`
+	TotalLength int `json:"total_length"`
+}
+
+// docEntry is the JSONL-serialised form of one document's statistics.
+type docEntry struct {
+	Doc    string `json:"doc"`
+	Length int    `json:"length"`
 }
 
 // IndexEntry is the JSONL-serialised form: one line per term.
 type IndexEntry struct {
-	Term  string   `json:"term"`
-	Paths []string `json:"paths"`
+	Term     string             `json:"term"`
+	Postings map[string]Posting `json:"postings,omitempty"`
+
+	// Paths is only present in unversioned (v1) indexes.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// indexRecord is the union of every line shape found in an index file.
+type indexRecord struct {
+	indexHeader
+	docEntry
+	IndexEntry
 }
 
 // IndexResult is returned by Search.
 type IndexResult struct {
 	Path  string
-	Score int // number of query terms matched
+	Score float64 // BM25 relevance
 }
 
 // NewIndex creates an empty index.
 func NewIndex() *Index {
-	return &Index{Terms: make(map[string]map[string]bool)}
+	return &Index{
+		Terms:   make(map[string]map[string]Posting),
+		Lengths: make(map[string]int),
+	}
 }
 
 // isIndexableFile returns true for file extensions we include in the search index.
@@ -79,63 +136,63 @@ func BuildIndex(dir string) (*Index, error) {
 // UpdateIndex adds or re-indexes a single file in the index.
 // It first removes any existing entries for the path, then re-scans.
 func UpdateIndex(idx *Index, path string) error {
-	// Remove old entries for this path
-	for _, docs := range idx.Terms {
-		delete(docs, path)
-	}
+	removeDocument(idx, path)
 	return indexFile(idx, path)
 }
 
-// Search finds documents matching the query and returns up to limit results
-// sorted by descending score (number of matching query terms).
-func Search(idx *Index, query string, limit int) []IndexResult {
-	queryTerms := tokenize(query)
-	if len(queryTerms) == 0 {
-		return nil
-	}
+// AddDocument indexes already-loaded content under path, replacing any
+// previous entry for that path.
+func AddDocument(idx *Index, path string, doc Document) {
+	removeDocument(idx, path)
 
-	scores := scoreDocuments(idx, queryTerms)
-	if len(scores) == 0 {
-		return nil
-	}
+	body, length := termCounts(doc.Body)
+	title, _ := termCounts(doc.Title)
+	tags, _ := termCounts(strings.Join(doc.Tags, " "))
+
+	addField(idx, path, body, func(p *Posting, n int) { p.TF += n })
+	addField(idx, path, title, func(p *Posting, n int) { p.Title += n })
+	addField(idx, path, tags, func(p *Posting, n int) { p.Tags += n })
 
-	return rankResults(scores, limit)
+	idx.Lengths[path] = length
+	idx.TotalLength += length
 }
 
-// scoreDocuments counts how many query terms each document matches.
-func scoreDocuments(idx *Index, queryTerms []string) map[string]int {
-	scores := make(map[string]int)
-	for _, term := range queryTerms {
-		if docs, ok := idx.Terms[term]; ok {
-			for doc := range docs {
-				scores[doc]++
-			}
+// addField merges one field's term counts into the postings for path.
+func addField(idx *Index, path string, counts map[string]int, apply func(*Posting, int)) {
+	for term, n := range counts {
+		docs := idx.Terms[term]
+		if docs == nil {
+			docs = make(map[string]Posting)
+			idx.Terms[term] = docs
 		}
+		p := docs[path]
+		apply(&p, n)
+		docs[path] = p
 	}
-	return scores
 }
 
-// rankResults converts scores to sorted results, applying a limit.
-func rankResults(scores map[string]int, limit int) []IndexResult {
-	results := make([]IndexResult, 0, len(scores))
-	for path, score := range scores {
-		results = append(results, IndexResult{Path: path, Score: score})
+// removeDocument drops every posting and statistic recorded for path.
+// Every indexed document has a length entry, so unknown paths are skipped
+// without scanning the postings.
+func removeDocument(idx *Index, path string) {
+	if _, ok := idx.Lengths[path]; !ok {
+		return
 	}
-
-	slices.SortFunc(results, func(a, b IndexResult) int {
-		if c := cmp.Compare(b.Score, a.Score); c != 0 {
-			return c
+	for term, docs := range idx.Terms {
+		if _, ok := docs[path]; !ok {
+			continue
+		}
+		delete(docs, path)
+		if len(docs) == 0 {
+			delete(idx.Terms, term)
 		}
-		return cmp.Compare(a.Path, b.Path)
-	})
-
-	if limit > 0 && len(results) > limit {
-		results = results[:limit]
 	}
-	return results
+	idx.TotalLength -= idx.Lengths[path]
+	delete(idx.Lengths, path)
 }
 
-// SaveIndex writes the index to a JSONL file (one line per term).
+// SaveIndex writes the index to a versioned JSONL file: a header line,
+// one line per document, then one line per term.
 func SaveIndex(idx *Index, path string) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
 		return fmt.Errorf("create index dir: %w", err)
@@ -150,12 +207,43 @@ func SaveIndex(idx *Index, path string) error {
 	}()
 
 	w := bufio.NewWriter(f)
+	if err := writeIndexHeader(w, idx); err != nil {
+		return err
+	}
+	if err := writeIndexDocs(w, idx); err != nil {
+		return err
+	}
 	if err := writeIndexTerms(w, idx); err != nil {
 		return err
 	}
 	return w.Flush()
 }
 
+// writeIndexHeader writes the version and corpus statistics line.
+func writeIndexHeader(w *bufio.Writer, idx *Index) error {
+	return writeJSONLine(w, indexHeader{
+		Version:     IndexVersion,
+		Docs:        len(idx.Lengths),
+		TotalLength: idx.TotalLength,
+	})
+}
+
+// writeIndexDocs serializes per-document lengths in sorted path order.
+func writeIndexDocs(w *bufio.Writer, idx *Index) error {
+	paths := make([]string, 0, len(idx.Lengths))
+	for p := range idx.Lengths {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	for _, p := range paths {
+		if err := writeJSONLine(w, docEntry{Doc: p, Length: idx.Lengths[p]}); err != nil {
+			return fmt.Errorf("write doc %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
 // writeIndexTerms serializes all index terms to the writer in sorted order.
 func writeIndexTerms(w *bufio.Writer, idx *Index) error {
 	terms := make([]string, 0, len(idx.Terms))
@@ -176,17 +264,19 @@ func writeIndexTerms(w *bufio.Writer, idx *Index) error {
 	return nil
 }
 
-// writeTermEntry serializes a single term and its document set.
-func writeTermEntry(w *bufio.Writer, term string, docs map[string]bool) error {
-	paths := make([]string, 0, len(docs))
-	for p := range docs {
-		paths = append(paths, p)
+// writeTermEntry serializes a single term and its postings.
+func writeTermEntry(w *bufio.Writer, term string, docs map[string]Posting) error {
+	if err := writeJSONLine(w, IndexEntry{Term: term, Postings: docs}); err != nil {
+		return fmt.Errorf("write term %q: %w", term, err)
 	}
-	slices.Sort(paths)
+	return nil
+}
 
-	data, err := json.Marshal(IndexEntry{Term: term, Paths: paths})
+// writeJSONLine marshals v and writes it followed by a newline.
+func writeJSONLine(w *bufio.Writer, v any) error {
+	data, err := json.Marshal(v)
 	if err != nil {
-		return fmt.Errorf("marshal term %q: %w", term, err)
+		return err
 	}
 	if _, err := w.Write(data); err != nil {
 		return err
@@ -195,24 +285,45 @@ func writeTermEntry(w *bufio.Writer, term string, docs map[string]bool) error {
 	return err
 }
 
-// parseIndexEntry unmarshals a single JSONL line into the index.
+// parseIndexRecord unmarshals a single JSONL line.
 // Returns false if the line is empty or malformed (skip, no error).
-func parseIndexEntry(idx *Index, line string) {
+func parseIndexRecord(line string) (indexRecord, bool) {
+	var rec indexRecord
 	if line == "" {
-		return
+		return rec, false
 	}
-	var entry IndexEntry
-	if err := json.Unmarshal([]byte(line), &entry); err != nil {
-		return // skip malformed lines
+	if err := json.Unmarshal([]byte(line), &rec); err != nil {
+		return rec, false // skip malformed lines
 	}
-	docs := make(map[string]bool, len(entry.Paths))
-	for _, p := range entry.Paths {
-		docs[p] = true
+	return rec, true
+}
+
+// applyIndexRecord merges a parsed current-version record into the index.
+func applyIndexRecord(idx *Index, rec indexRecord) {
+	switch {
+	case rec.Doc != "":
+		idx.Lengths[rec.Doc] = rec.Length
+		idx.TotalLength += rec.Length
+	case rec.Term != "" && len(rec.Postings) > 0:
+		idx.Terms[rec.Term] = rec.Postings
+	}
+}
+
+// recordPaths returns every document path referenced by a record.
+func recordPaths(rec indexRecord) []string {
+	paths := slices.Clone(rec.Paths)
+	if rec.Doc != "" {
+		paths = append(paths, rec.Doc)
+	}
+	for p := range rec.Postings {
+		paths = append(paths, p)
 	}
-	idx.Terms[entry.Term] = docs
+	return paths
 }
 
-// LoadIndex reads an index from a JSONL file.
+// LoadIndex reads an index from a JSONL file. Indexes written with an older
+// or unknown format version are rebuilt by re-indexing the files they
+// reference; files that no longer exist are dropped.
 func LoadIndex(path string) (*Index, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -227,49 +338,130 @@ func LoadIndex(path string) (*Index, error) {
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, 1024*1024)
 
+	versionChecked := false
+	current := false
+	stale := make(map[string]bool)
+
 	for scanner.Scan() {
-		parseIndexEntry(idx, scanner.Text())
+		rec, ok := parseIndexRecord(scanner.Text())
+		if !ok {
+			continue
+		}
+		if !versionChecked {
+			versionChecked = true
+			current = rec.Version == IndexVersion
+			if current {
+				continue
+			}
+		}
+		if current {
+			applyIndexRecord(idx, rec)
+			continue
+		}
+		for _, p := range recordPaths(rec) {
+			stale[p] = true
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("read index: %w", err)
 	}
 
+	if !current {
+		rebuildFromPaths(idx, stale)
+	}
 	return idx, nil
 }
 
+// rebuildFromPaths re-indexes each referenced file, skipping unreadable ones.
+func rebuildFromPaths(idx *Index, paths map[string]bool) {
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	slices.Sort(sorted)
+
+	for _, p := range sorted {
+		_ = indexFile(idx, p) // non-fatal: source may have been deleted
+	}
+}
+
 // indexFile reads a file and adds its terms to the index.
 func indexFile(idx *Index, path string) error {
-	f, err := os.Open(path)
+	doc, err := LoadDocument(path)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		_ = f.Close() //nolint:errcheck // read-only, close best-effort
-	}()
+	AddDocument(idx, path, doc)
+	return nil
+}
 
-	scanner := bufio.NewScanner(f)
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
+// LoadDocument reads a file and splits it into document fields.
+func LoadDocument(path string) (Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Document{}, err
+	}
+	return parseDocument(string(data)), nil
+}
 
-	seen := make(map[string]bool) // dedupe terms per file
+// parseDocument splits file content into title, tags and body fields.
+// Title and tags come from YAML frontmatter when present; the body is the
+// full text so frontmatter values remain searchable as ordinary terms.
+func parseDocument(content string) Document {
+	doc := Document{Body: content}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		terms := tokenize(line)
-		for _, term := range terms {
-			if seen[term] {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return doc
+	}
+
+	inTags := false
+	for _, raw := range lines[1:] {
+		line := strings.TrimSpace(raw)
+		if line == "---" {
+			break
+		}
+		if inTags && strings.HasPrefix(line, "- ") {
+			doc.Tags = append(doc.Tags, trimQuotes(strings.TrimPrefix(line, "- ")))
+			continue
+		}
+		inTags = false
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "title":
+			doc.Title = trimQuotes(value)
+		case "tags":
+			if value == "" {
+				inTags = true
 				continue
 			}
-			seen[term] = true
-			if idx.Terms[term] == nil {
-				idx.Terms[term] = make(map[string]bool)
-			}
-			idx.Terms[term][path] = true
+			doc.Tags = append(doc.Tags, parseInlineList(value)...)
 		}
 	}
+	return doc
+}
 
-	return scanner.Err()
+// parseInlineList parses "[a, b]" or "a, b" into trimmed values.
+func parseInlineList(s string) []string {
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if v := trimQuotes(strings.TrimSpace(item)); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// trimQuotes strips surrounding single or double quotes.
+func trimQuotes(s string) string {
+	return strings.Trim(s, "\"'")
 }
 
 // isTokenSeparator returns true for characters that split words during tokenization.
@@ -297,3 +489,18 @@ func tokenize(text string) []string {
 	words := strings.FieldsFunc(strings.ToLower(text), isTokenSeparator)
 	return dedupeTokens(words)
 }
+
+// termCounts tokenizes text like tokenize but keeps repeats, returning the
+// occurrence count of each term and the total number of counted tokens.
+func termCounts(text string) (map[string]int, int) {
+	counts := make(map[string]int)
+	total := 0
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), isTokenSeparator) {
+		if len(w) < 2 {
+			continue
+		}
+		counts[w]++
+		total++
+	}
+	return counts, total
+}
@@ -173,12 +173,12 @@ func TestUpdateIndex(t *testing.T) {
 	}
 
 	// "alpha" should be gone (or at least not point to docPath)
-	if docs, ok := idx.Terms["alpha"]; ok && docs[docPath] {
+	if docs, ok := idx.Terms["alpha"]; ok && docs[docPath].TF > 0 {
 		t.Error("expected 'alpha' to be removed for docPath after update")
 	}
 
 	// "beta" should be present
-	if docs, ok := idx.Terms["beta"]; !ok || !docs[docPath] {
+	if docs, ok := idx.Terms["beta"]; !ok || docs[docPath].TF == 0 {
 		t.Error("expected 'beta' to be present after update")
 	}
 }
@@ -273,8 +273,8 @@ func TestSaveIndex_EmptyDocsSkipped(t *testing.T) {
 	idx := NewIndex()
 
 	// Manually add a term with empty docs map
-	idx.Terms["orphan"] = make(map[string]bool)
-	idx.Terms["real"] = map[string]bool{"/some/path.md": true}
+	idx.Terms["orphan"] = make(map[string]Posting)
+	idx.Terms["real"] = map[string]Posting{"/some/path.md": {TF: 1}}
 
 	indexPath := filepath.Join(dir, "index.jsonl")
 	if err := SaveIndex(idx, indexPath); err != nil {
@@ -306,7 +306,7 @@ func TestSaveIndex_ReadOnlyDir(t *testing.T) {
 	})
 
 	idx := NewIndex()
-	idx.Terms["test"] = map[string]bool{"/a.md": true}
+	idx.Terms["test"] = map[string]Posting{"/a.md": {TF: 1}}
 
 	err := SaveIndex(idx, filepath.Join(readOnlyDir, "subdir", "index.jsonl"))
 	if err == nil {
@@ -319,10 +319,11 @@ func TestLoadIndex_EmptyAndMalformedLines(t *testing.T) {
 	indexPath := filepath.Join(dir, "index.jsonl")
 
 	// Write index with empty lines and malformed JSON mixed in
-	content := `{"term":"good","paths":["/a.md"]}
+	content := `{"version":2,"docs":2,"total_length":2}
+{"term":"good","postings":{"/a.md":{"tf":1}}}
 
 {"not valid json
-{"term":"also_good","paths":["/b.md"]}
+{"term":"also_good","postings":{"/b.md":{"tf":1}}}
 `
 	writeFile(t, indexPath, content)
 
@@ -343,7 +344,7 @@ func TestLoadIndex_EmptyAndMalformedLines(t *testing.T) {
 func TestSaveIndex_NestedDirCreation(t *testing.T) {
 	dir := t.TempDir()
 	idx := NewIndex()
-	idx.Terms["hello"] = map[string]bool{"/doc.md": true}
+	idx.Terms["hello"] = map[string]Posting{"/doc.md": {TF: 1}}
 
 	// Save to a deeply nested path that doesn't exist yet
 	deepPath := filepath.Join(dir, "a", "b", "c", "index.jsonl")
@@ -382,7 +383,7 @@ func TestSearch_LimitZeroReturnsAll(t *testing.T) {
 func TestSearch_TieBreakByPath(t *testing.T) {
 	idx := NewIndex()
 	// Two docs with same score
-	idx.Terms["shared"] = map[string]bool{"/b.md": true, "/a.md": true}
+	idx.Terms["shared"] = map[string]Posting{"/b.md": {TF: 1}, "/a.md": {TF: 1}}
 
 	results := Search(idx, "shared", 10)
 	if len(results) != 2 {
@@ -397,7 +398,7 @@ func TestSearch_TieBreakByPath(t *testing.T) {
 func TestSaveIndex_CreateFileError(t *testing.T) {
 	dir := t.TempDir()
 	idx := NewIndex()
-	idx.Terms["test"] = map[string]bool{"/a.md": true}
+	idx.Terms["test"] = map[string]Posting{"/a.md": {TF: 1}}
 
 	// Create a directory where the file should go, but make it read-only
 	targetDir := filepath.Join(dir, "indexdir")
@@ -485,7 +486,7 @@ func TestSaveIndex_ReadOnlySubdir(t *testing.T) {
 	t.Cleanup(func() { _ = os.Chmod(readOnly, 0700) })
 
 	idx := NewIndex()
-	idx.Terms["test"] = map[string]bool{"file.md": true}
+	idx.Terms["test"] = map[string]Posting{"file.md": {TF: 1}}
 
 	// Try to save into a read-only directory's subdirectory
 	err := SaveIndex(idx, filepath.Join(readOnly, "subdir", "index.jsonl"))
@@ -505,7 +506,7 @@ func TestSaveIndex_FilePermissionError(t *testing.T) {
 	}
 
 	idx := NewIndex()
-	idx.Terms["test"] = map[string]bool{"file.md": true}
+	idx.Terms["test"] = map[string]Posting{"file.md": {TF: 1}}
 
 	err := SaveIndex(idx, indexPath)
 	if err == nil {
@@ -525,9 +526,10 @@ func TestLoadIndex_MalformedLines(t *testing.T) {
 	indexPath := filepath.Join(tmpDir, "index.jsonl")
 
 	// Write a file with valid, malformed, and empty lines
-	content := `{"term":"valid","paths":["a.md","b.md"]}
+	content := `{"version":2,"docs":3,"total_length":3}
+{"term":"valid","postings":{"a.md":{"tf":1},"b.md":{"tf":1}}}
 {invalid json line
-` + "\n" + `{"term":"also-valid","paths":["c.md"]}
+` + "\n" + `{"term":"also-valid","postings":{"c.md":{"tf":1}}}
 `
 	if err := os.WriteFile(indexPath, []byte(content), 0o600); err != nil {
 		t.Fatal(err)
@@ -561,9 +563,9 @@ func TestSaveIndex_EmptyTermsSkipped(t *testing.T) {
 
 	idx := NewIndex()
 	// Add a term with empty docs (should be skipped)
-	idx.Terms["empty"] = map[string]bool{}
+	idx.Terms["empty"] = map[string]Posting{}
 	// Add a term with docs
-	idx.Terms["valid"] = map[string]bool{"file.md": true}
+	idx.Terms["valid"] = map[string]Posting{"file.md": {TF: 1}}
 
 	if err := SaveIndex(idx, indexPath); err != nil {
 		t.Fatalf("SaveIndex: %v", err)
@@ -613,7 +615,7 @@ func TestSaveIndex_ReadOnlySubDir(t *testing.T) {
 	t.Cleanup(func() { _ = os.Chmod(readOnly, 0700) })
 
 	idx := NewIndex()
-	idx.Terms["hello"] = map[string]bool{"file.md": true}
+	idx.Terms["hello"] = map[string]Posting{"file.md": {TF: 1}}
 
 	err := SaveIndex(idx, filepath.Join(readOnly, "sub", "index.jsonl"))
 	if err == nil {
@@ -631,7 +633,7 @@ func TestSaveIndex_TargetIsDirectory(t *testing.T) {
 	}
 
 	idx := NewIndex()
-	idx.Terms["hello"] = map[string]bool{"file.md": true}
+	idx.Terms["hello"] = map[string]Posting{"file.md": {TF: 1}}
 
 	err := SaveIndex(idx, filePath)
 	if err == nil {
@@ -796,7 +798,7 @@ func TestExtra_writeTermEntry_MarshalSuccess(t *testing.T) {
 	var buf bytes.Buffer
 	w := bufio.NewWriter(&buf)
 
-	docs := map[string]bool{"a.md": true, "b.md": true}
+	docs := map[string]Posting{"a.md": {TF: 1}, "b.md": {TF: 1}}
 	if err := writeTermEntry(w, "hello", docs); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -849,7 +851,7 @@ func TestWriteTermEntry_WriteError(t *testing.T) {
 	ew := &errWriter{limit: 5}
 	w := bufio.NewWriterSize(ew, 8) // small buffer forces flush
 
-	docs := map[string]bool{"a.md": true}
+	docs := map[string]Posting{"a.md": {TF: 1}}
 	err := writeTermEntry(w, "hello", docs)
 	// The error may come from Write or Flush
 	flushErr := w.Flush()
@@ -864,7 +866,7 @@ func TestWriteIndexTerms_WriteError(t *testing.T) {
 	w := bufio.NewWriterSize(ew, 8)
 
 	idx := NewIndex()
-	idx.Terms["fail"] = map[string]bool{"a.md": true}
+	idx.Terms["fail"] = map[string]Posting{"a.md": {TF: 1}}
 
 	err := writeIndexTerms(w, idx)
 	flushErr := w.Flush()
@@ -887,8 +889,8 @@ func TestSaveIndex_WriteIndexTermsError(t *testing.T) {
 	idx := NewIndex()
 	for i := 0; i < 200; i++ {
 		term := fmt.Sprintf("term_%04d", i)
-		idx.Terms[term] = map[string]bool{
-			fmt.Sprintf("/path/to/doc_%04d.md", i): true,
+		idx.Terms[term] = map[string]Posting{
+			fmt.Sprintf("/path/to/doc_%04d.md", i): {TF: 1},
 		}
 	}
 
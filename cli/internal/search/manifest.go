@@ -0,0 +1,203 @@
+package search
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ManifestEntry records the state of one indexed file when it was last
+// tokenized, so unchanged files can be skipped on the next update.
+type ManifestEntry struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime"`
+	Hash    string    `json:"sha256"`
+}
+
+// Manifest is the set of files covered by an index, keyed by path.
+type Manifest struct {
+	Files map[string]ManifestEntry
+}
+
+// UpdateReport lists the paths touched by an incremental update.
+type UpdateReport struct {
+	Added     []string `json:"added"`
+	Updated   []string `json:"updated"`
+	Removed   []string `json:"removed"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// NewManifest creates an empty manifest.
+func NewManifest() *Manifest {
+	return &Manifest{Files: make(map[string]ManifestEntry)}
+}
+
+// ManifestPath returns the manifest location for an index file: the same
+// directory and base name with a .manifest.jsonl suffix.
+func ManifestPath(indexPath string) string {
+	return strings.TrimSuffix(indexPath, filepath.Ext(indexPath)) + ".manifest.jsonl"
+}
+
+// LoadManifest reads a manifest from a JSONL file. A missing file yields an
+// empty manifest so the first incremental update indexes everything.
+func LoadManifest(path string) (*Manifest, error) {
+	m := NewManifest()
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open manifest: %w", err)
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only, close best-effort
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry ManifestEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Path == "" {
+			continue // skip malformed lines
+		}
+		m.Files[entry.Path] = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	return m, nil
+}
+
+// SaveManifest writes the manifest to a JSONL file, one line per file in
+// sorted path order.
+func SaveManifest(m *Manifest, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("create manifest dir: %w", err)
+	}
+
+	f, err := createIndexOutput(path)
+	if err != nil {
+		return fmt.Errorf("create manifest file: %w", err)
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // best-effort close
+	}()
+
+	paths := make([]string, 0, len(m.Files))
+	for p := range m.Files {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	w := bufio.NewWriter(f)
+	for _, p := range paths {
+		if err := writeJSONLine(w, m.Files[p]); err != nil {
+			return fmt.Errorf("write manifest entry %q: %w", p, err)
+		}
+	}
+	return w.Flush()
+}
+
+// IncrementalUpdate brings idx and m in line with the given file set.
+// Files whose size and mtime match the manifest are skipped without being
+// read; files whose content hash still matches only have their manifest
+// entry refreshed. Changed files are re-tokenized, and files that are in
+// the manifest but no longer in paths (or no longer readable) are purged.
+func IncrementalUpdate(idx *Index, m *Manifest, paths []string) UpdateReport {
+	var report UpdateReport
+	current := make(map[string]bool, len(paths))
+
+	for _, path := range paths {
+		if current[path] {
+			continue
+		}
+		current[path] = true
+
+		switch refreshFile(idx, m, path) {
+		case fileAdded:
+			report.Added = append(report.Added, path)
+		case fileUpdated:
+			report.Updated = append(report.Updated, path)
+		case fileUnchanged:
+			report.Unchanged++
+		case fileGone:
+			current[path] = false
+		}
+	}
+
+	for path := range m.Files {
+		if !current[path] {
+			report.Removed = append(report.Removed, path)
+		}
+	}
+	// Documents indexed without a manifest entry (e.g. by forge) are
+	// purged too when they are outside the file set.
+	for path := range idx.Lengths {
+		if _, tracked := m.Files[path]; !tracked && !current[path] {
+			report.Removed = append(report.Removed, path)
+		}
+	}
+	slices.Sort(report.Removed)
+	report.Removed = slices.Compact(report.Removed)
+	for _, path := range report.Removed {
+		removeDocument(idx, path)
+		delete(m.Files, path)
+	}
+
+	return report
+}
+
+// fileChange classifies the outcome of refreshing one file.
+type fileChange int
+
+const (
+	fileUnchanged fileChange = iota
+	fileAdded
+	fileUpdated
+	fileGone
+)
+
+// refreshFile re-indexes path if it changed since its manifest entry.
+func refreshFile(idx *Index, m *Manifest, path string) fileChange {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return fileGone
+	}
+
+	prev, tracked := m.Files[path]
+	_, indexed := idx.Lengths[path]
+	if tracked && indexed && prev.Size == info.Size() && prev.ModTime.Equal(info.ModTime()) {
+		return fileUnchanged
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fileGone
+	}
+	sum := sha256.Sum256(data)
+	entry := ManifestEntry{
+		Path:    path,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		Hash:    hex.EncodeToString(sum[:]),
+	}
+	m.Files[path] = entry
+
+	if tracked && indexed && prev.Hash == entry.Hash {
+		return fileUnchanged // touched but not edited
+	}
+
+	AddDocument(idx, path, parseDocument(string(data)))
+	if tracked {
+		return fileUpdated
+	}
+	return fileAdded
+}
@@ -0,0 +1,134 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManifestPath(t *testing.T) {
+	got := ManifestPath(filepath.Join("a", "index.jsonl"))
+	if want := filepath.Join("a", "index.manifest.jsonl"); got != want {
+		t.Errorf("ManifestPath = %q, want %q", got, want)
+	}
+}
+
+func TestIncrementalUpdate_AddUpdateRemove(t *testing.T) {
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "keep.md")
+	edit := filepath.Join(dir, "edit.md")
+	gone := filepath.Join(dir, "gone.md")
+	writeFile(t, keep, "stable content")
+	writeFile(t, edit, "original alpha")
+	writeFile(t, gone, "doomed omega")
+
+	idx, m := NewIndex(), NewManifest()
+	first := IncrementalUpdate(idx, m, []string{keep, edit, gone})
+	if len(first.Added) != 3 || len(first.Updated) != 0 || len(first.Removed) != 0 {
+		t.Fatalf("first pass = %+v, want 3 added", first)
+	}
+
+	writeFile(t, edit, "rewritten beta")
+	bumpModTime(t, edit)
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	second := IncrementalUpdate(idx, m, []string{keep, edit})
+	if len(second.Added) != 0 || len(second.Updated) != 1 || len(second.Removed) != 1 || second.Unchanged != 1 {
+		t.Fatalf("second pass = %+v, want 1 updated, 1 removed, 1 unchanged", second)
+	}
+	if _, ok := idx.Terms["alpha"]; ok {
+		t.Error("expected postings of the edited file's old content to be purged")
+	}
+	if _, ok := idx.Terms["omega"]; ok {
+		t.Error("expected postings of the deleted file to be purged")
+	}
+	if _, ok := m.Files[gone]; ok {
+		t.Error("expected deleted file to leave the manifest")
+	}
+	if idx.Terms["beta"][edit].TF != 1 {
+		t.Error("expected new content of the edited file to be indexed")
+	}
+}
+
+func TestIncrementalUpdate_TouchedFileIsUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.md")
+	writeFile(t, path, "same content")
+
+	idx, m := NewIndex(), NewManifest()
+	IncrementalUpdate(idx, m, []string{path})
+	bumpModTime(t, path)
+
+	report := IncrementalUpdate(idx, m, []string{path})
+	if report.Unchanged != 1 || len(report.Updated) != 0 {
+		t.Fatalf("report = %+v, want touched file reported unchanged", report)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Files[path].ModTime.Equal(info.ModTime()) {
+		t.Error("expected manifest mtime to be refreshed for touched file")
+	}
+}
+
+func TestIncrementalUpdate_ReindexesTrackedFileMissingFromIndex(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.md")
+	writeFile(t, path, "content")
+
+	m := NewManifest()
+	IncrementalUpdate(NewIndex(), m, []string{path})
+
+	idx := NewIndex()
+	report := IncrementalUpdate(idx, m, []string{path})
+	if len(report.Updated) != 1 {
+		t.Fatalf("report = %+v, want tracked-but-unindexed file re-indexed", report)
+	}
+	if _, ok := idx.Lengths[path]; !ok {
+		t.Error("expected file to be indexed")
+	}
+}
+
+func TestSaveAndLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.md")
+	writeFile(t, path, "content")
+
+	m := NewManifest()
+	IncrementalUpdate(NewIndex(), m, []string{path})
+
+	manifestPath := filepath.Join(dir, "ao", "index.manifest.jsonl")
+	if err := SaveManifest(m, manifestPath); err != nil {
+		t.Fatalf("SaveManifest: %v", err)
+	}
+	loaded, err := LoadManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	got, want := loaded.Files[path], m.Files[path]
+	if got.Hash != want.Hash || got.Size != want.Size || !got.ModTime.Equal(want.ModTime) {
+		t.Errorf("round-trip mismatch: got %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadManifest_MissingFileIsEmpty(t *testing.T) {
+	m, err := LoadManifest(filepath.Join(t.TempDir(), "none.jsonl"))
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if len(m.Files) != 0 {
+		t.Errorf("expected empty manifest, got %d entries", len(m.Files))
+	}
+}
+
+func bumpModTime(t *testing.T, path string) {
+	t.Helper()
+	later := time.Now().Add(2 * time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,232 @@
+package search
+
+import (
+	"hash/fnv"
+	"math"
+	"slices"
+	"strings"
+)
+
+// MinHash defaults tuned for learning-sized documents (a few hundred words).
+const (
+	// DefaultMinHashes is the signature length.
+	DefaultMinHashes = 128
+	// DefaultShingleSize is the number of words per shingle.
+	DefaultShingleSize = 3
+	// DefaultNearThreshold is the estimated Jaccard similarity above which
+	// two documents are near-duplicates.
+	DefaultNearThreshold = 0.8
+)
+
+// MinHasher computes MinHash signatures over word shingles.
+type MinHasher struct {
+	seeds       []uint64
+	shingleSize int
+}
+
+// Signature is a MinHash signature. The fraction of positions where two
+// signatures agree estimates the Jaccard similarity of their shingle sets.
+type Signature []uint64
+
+// NearCluster is a group of documents that are pairwise connected by
+// near-duplicate links.
+type NearCluster struct {
+	// Keys are sorted document keys.
+	Keys []string
+	// Similarity is the lowest estimated similarity on any link that
+	// joined the cluster.
+	Similarity float64
+}
+
+// NewMinHasher creates a hasher with numHashes permutations over
+// shingleSize-word shingles. The permutations are fixed, so signatures are
+// comparable across runs.
+func NewMinHasher(numHashes, shingleSize int) *MinHasher {
+	m := &MinHasher{seeds: make([]uint64, numHashes), shingleSize: max(shingleSize, 1)}
+	state := uint64(0x9e3779b97f4a7c15)
+	for i := range m.seeds {
+		state = splitmix64(state)
+		m.seeds[i] = state
+	}
+	return m
+}
+
+// Signature returns the MinHash signature of text, or nil when text has no
+// tokens. Texts shorter than one shingle are hashed as a single shingle.
+func (m *MinHasher) Signature(text string) Signature {
+	words := strings.FieldsFunc(strings.ToLower(text), isTokenSeparator)
+	if len(words) == 0 {
+		return nil
+	}
+
+	sig := make(Signature, len(m.seeds))
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+	n := max(len(words)-m.shingleSize+1, 1)
+	for i := 0; i < n; i++ {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(strings.Join(words[i:min(i+m.shingleSize, len(words))], " ")))
+		base := h.Sum64()
+		for j, seed := range m.seeds {
+			if v := splitmix64(base ^ seed); v < sig[j] {
+				sig[j] = v
+			}
+		}
+	}
+	return sig
+}
+
+// Similarity estimates the Jaccard similarity of the shingle sets behind
+// two signatures of equal length.
+func (s Signature) Similarity(other Signature) float64 {
+	if len(s) == 0 || len(s) != len(other) {
+		return 0
+	}
+	agree := 0
+	for i := range s {
+		if s[i] == other[i] {
+			agree++
+		}
+	}
+	return float64(agree) / float64(len(s))
+}
+
+// NearDuplicates clusters texts whose estimated similarity is at least
+// threshold. Candidate pairs come from locality-sensitive hashing over
+// signature bands, so the cost stays near linear in the number of texts;
+// every candidate is then checked against the full signature. Only clusters
+// with two or more members are returned, sorted by first key.
+func NearDuplicates(texts map[string]string, threshold float64) []NearCluster {
+	m := NewMinHasher(DefaultMinHashes, DefaultShingleSize)
+	keys := make([]string, 0, len(texts))
+	sigs := make(map[string]Signature, len(texts))
+	for key, text := range texts {
+		if sig := m.Signature(text); sig != nil {
+			keys = append(keys, key)
+			sigs[key] = sig
+		}
+	}
+	slices.Sort(keys)
+
+	rows := lshRows(DefaultMinHashes, threshold)
+	uf := newUnionFind(keys)
+	checked := make(map[[2]string]bool)
+	for lo := 0; lo < DefaultMinHashes; lo += rows {
+		hi := min(lo+rows, DefaultMinHashes)
+		buckets := make(map[string][]string)
+		for _, key := range keys {
+			band := bandKey(sigs[key][lo:hi])
+			buckets[band] = append(buckets[band], key)
+		}
+		for _, bucket := range buckets {
+			for i := 0; i < len(bucket); i++ {
+				for j := i + 1; j < len(bucket); j++ {
+					pair := [2]string{bucket[i], bucket[j]}
+					if checked[pair] {
+						continue
+					}
+					checked[pair] = true
+					if sim := sigs[pair[0]].Similarity(sigs[pair[1]]); sim >= threshold {
+						uf.union(pair[0], pair[1], sim)
+					}
+				}
+			}
+		}
+	}
+
+	members := make(map[string][]string)
+	for _, key := range keys {
+		root := uf.find(key)
+		members[root] = append(members[root], key)
+	}
+	var clusters []NearCluster
+	for root, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		clusters = append(clusters, NearCluster{Keys: group, Similarity: uf.minSim[root]})
+	}
+	slices.SortFunc(clusters, func(a, b NearCluster) int { return strings.Compare(a.Keys[0], b.Keys[0]) })
+	return clusters
+}
+
+// unionFind tracks clusters and the weakest link that joined each one.
+type unionFind struct {
+	parent map[string]string
+	minSim map[string]float64
+}
+
+func newUnionFind(keys []string) *unionFind {
+	uf := &unionFind{parent: make(map[string]string, len(keys)), minSim: make(map[string]float64)}
+	for _, k := range keys {
+		uf.parent[k] = k
+	}
+	return uf
+}
+
+func (uf *unionFind) find(k string) string {
+	for uf.parent[k] != k {
+		uf.parent[k] = uf.parent[uf.parent[k]]
+		k = uf.parent[k]
+	}
+	return k
+}
+
+func (uf *unionFind) union(a, b string, sim float64) {
+	ra, rb := uf.find(a), uf.find(b)
+	low := sim
+	for _, r := range []string{ra, rb} {
+		if s, ok := uf.minSim[r]; ok {
+			low = min(low, s)
+		}
+	}
+	if ra != rb {
+		// Keep the lexically smaller root so results are stable.
+		if rb < ra {
+			ra, rb = rb, ra
+		}
+		uf.parent[rb] = ra
+		delete(uf.minSim, rb)
+	}
+	uf.minSim[ra] = low
+}
+
+// lshRows picks the rows per band so that the LSH threshold (1/b)^(1/r)
+// sits at or below the similarity threshold, keeping recall high while
+// using as many rows as possible to cut false candidates.
+func lshRows(numHashes int, threshold float64) int {
+	best := 1
+	for r := 1; r <= numHashes; r++ {
+		bands := float64(numHashes / r)
+		if bands < 1 {
+			break
+		}
+		if lshThreshold(bands, float64(r)) <= threshold*0.9 {
+			best = r
+		}
+	}
+	return best
+}
+
+func lshThreshold(bands, rows float64) float64 {
+	return math.Pow(1/bands, 1/rows)
+}
+
+func bandKey(sig Signature) string {
+	var b strings.Builder
+	for _, v := range sig {
+		for shift := 0; shift < 64; shift += 8 {
+			b.WriteByte(byte(v >> shift))
+		}
+	}
+	return b.String()
+}
+
+// splitmix64 is a fast, well-mixed 64-bit permutation.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,81 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+const (
+	retryText    = "When a remote call fails with a transient error, retry it with exponential backoff and jitter, cap the number of attempts, and log the final failure so the operator can see which dependency was unhealthy during the incident window."
+	retryVariant = "When a remote call fails with a transient error, retry it with exponential backoff plus jitter, cap the number of attempts, and log the final failure so the operator can see which dependency was unhealthy during the incident window."
+	mutexText    = "Guard shared maps with a mutex or use sync.Map; concurrent writes to a plain map crash the runtime with a fatal error that recover cannot catch."
+)
+
+func TestMinHasher_Deterministic(t *testing.T) {
+	a := NewMinHasher(DefaultMinHashes, DefaultShingleSize).Signature(retryText)
+	b := NewMinHasher(DefaultMinHashes, DefaultShingleSize).Signature(retryText)
+	if !slices.Equal(a, b) {
+		t.Error("signatures differ across hashers")
+	}
+	if a.Similarity(b) != 1 {
+		t.Errorf("self similarity = %v, want 1", a.Similarity(b))
+	}
+	if sig := NewMinHasher(8, 3).Signature("  ... "); sig != nil {
+		t.Errorf("expected nil signature for text without tokens, got %v", sig)
+	}
+	if sig := NewMinHasher(8, 3).Signature("two words"); len(sig) != 8 {
+		t.Errorf("short text should still get a signature, got %v", sig)
+	}
+}
+
+func TestSignature_Similarity(t *testing.T) {
+	m := NewMinHasher(DefaultMinHashes, DefaultShingleSize)
+	base := m.Signature(retryText)
+	near := base.Similarity(m.Signature(retryVariant))
+	far := base.Similarity(m.Signature(mutexText))
+	if near < 0.7 {
+		t.Errorf("near-duplicate similarity = %v, want >= 0.7", near)
+	}
+	if far > 0.1 {
+		t.Errorf("unrelated similarity = %v, want <= 0.1", far)
+	}
+	if base.Similarity(Signature{1}) != 0 {
+		t.Error("mismatched signature lengths should score 0")
+	}
+}
+
+func TestNearDuplicates(t *testing.T) {
+	texts := map[string]string{
+		"b-retry.md": retryVariant,
+		"a-retry.md": retryText,
+		"c-retry.md": retryText,
+		"mutex.md":   mutexText,
+		"empty.md":   "",
+	}
+	clusters := NearDuplicates(texts, 0.7)
+	if len(clusters) != 1 {
+		t.Fatalf("got %d clusters, want 1: %+v", len(clusters), clusters)
+	}
+	if want := []string{"a-retry.md", "b-retry.md", "c-retry.md"}; !slices.Equal(clusters[0].Keys, want) {
+		t.Errorf("cluster keys = %v, want %v", clusters[0].Keys, want)
+	}
+	if s := clusters[0].Similarity; s < 0.7 || s >= 1 {
+		t.Errorf("cluster similarity = %v, want the weakest link in [0.7, 1)", s)
+	}
+
+	if got := NearDuplicates(texts, 0.99); len(got) != 1 || len(got[0].Keys) != 2 {
+		t.Errorf("at 0.99 only the identical pair should cluster, got %+v", got)
+	}
+}
+
+func TestLSHRows(t *testing.T) {
+	for _, threshold := range []float64{0.5, 0.8, 0.95} {
+		r := lshRows(DefaultMinHashes, threshold)
+		if got := lshThreshold(float64(DefaultMinHashes/r), float64(r)); got > threshold {
+			t.Errorf("lshRows(%v) = %d gives LSH threshold %v above target", threshold, r, got)
+		}
+	}
+	if lshRows(DefaultMinHashes, 0.9) <= lshRows(DefaultMinHashes, 0.5) {
+		t.Error("a higher threshold should use more rows per band")
+	}
+}
@@ -0,0 +1,336 @@
+package search
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultVectorFile is the vector store file name used under .agents/ao/.
+const DefaultVectorFile = "vectors.jsonl"
+
+// DefaultLexicalWeight is the share of a hybrid score taken from BM25; the
+// remainder comes from vector similarity.
+const DefaultLexicalWeight = 0.5
+
+// vectorStoreVersion is the on-disk format version of the vector store.
+const vectorStoreVersion = 1
+
+// Embedder turns text into a dense vector. Implementations must be
+// deterministic for a given Model so stored vectors stay comparable.
+type Embedder interface {
+	// Model identifies the embedding scheme. Stored vectors produced by a
+	// different model are discarded on load.
+	Model() string
+
+	// Embed returns an L2-normalised vector for text.
+	Embed(text string) []float32
+}
+
+// HashEmbedder is the offline default Embedder. It hashes word unigrams and
+// character n-grams into a fixed number of buckets (the "hashing trick"),
+// so related spellings such as "auth" and "authentication" share features
+// without any model download or network access.
+type HashEmbedder struct {
+	Dim     int
+	MinGram int
+	MaxGram int
+}
+
+// NewHashEmbedder returns a HashEmbedder with the default dimensions.
+func NewHashEmbedder() *HashEmbedder {
+	return &HashEmbedder{Dim: 256, MinGram: 3, MaxGram: 4}
+}
+
+// Model implements Embedder.
+func (h *HashEmbedder) Model() string {
+	return fmt.Sprintf("hash-ngram-v1/d%d/n%d-%d", h.Dim, h.MinGram, h.MaxGram)
+}
+
+// Embed implements Embedder.
+func (h *HashEmbedder) Embed(text string) []float32 {
+	vec := make([]float32, h.Dim)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), isTokenSeparator) {
+		if len(word) < 2 {
+			continue
+		}
+		h.addFeature(vec, "w:"+word, 1)
+		padded := []rune(" " + word + " ")
+		for n := h.MinGram; n <= h.MaxGram; n++ {
+			for i := 0; i+n <= len(padded); i++ {
+				h.addFeature(vec, string(padded[i:i+n]), 0.5)
+			}
+		}
+	}
+	normalize(vec)
+	return vec
+}
+
+// addFeature adds a signed weight to the bucket the feature hashes to. The
+// sign bit keeps collisions from systematically inflating similarity.
+func (h *HashEmbedder) addFeature(vec []float32, feature string, weight float32) {
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(feature))
+	sum := hasher.Sum32()
+	if sum&(1<<31) != 0 {
+		weight = -weight
+	}
+	vec[int(sum%uint32(h.Dim))] += weight
+}
+
+// normalize scales vec to unit length in place; zero vectors are left as-is.
+func normalize(vec []float32) {
+	var sq float64
+	for _, v := range vec {
+		sq += float64(v) * float64(v)
+	}
+	if sq == 0 {
+		return
+	}
+	inv := float32(1 / math.Sqrt(sq))
+	for i := range vec {
+		vec[i] *= inv
+	}
+}
+
+// cosine returns the dot product of two unit vectors, or 0 on size mismatch.
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+// VectorStore holds document embeddings keyed by path, together with the
+// content hash each vector was computed from.
+type VectorStore struct {
+	Model   string
+	Vectors map[string][]float32
+	Hashes  map[string]string
+}
+
+// vectorHeader is the first line of a vector store file.
+type vectorHeader struct {
+	Version int    `json:"version"`
+	Model   string `json:"model"`
+}
+
+// vectorEntry is the JSONL-serialised form of one document's embedding.
+type vectorEntry struct {
+	Path   string    `json:"path"`
+	Hash   string    `json:"sha256"`
+	Vector []float32 `json:"vector"`
+}
+
+// vectorRecord is the union of the line shapes found in a vector store file.
+type vectorRecord struct {
+	vectorHeader
+	vectorEntry
+}
+
+// NewVectorStore creates an empty store for the given embedding model.
+func NewVectorStore(model string) *VectorStore {
+	return &VectorStore{
+		Model:   model,
+		Vectors: make(map[string][]float32),
+		Hashes:  make(map[string]string),
+	}
+}
+
+// LoadVectorStore reads a vector store for embedder e. A missing file, or a
+// file written by a different model or format version, yields an empty
+// store so documents are re-embedded rather than compared across models.
+func LoadVectorStore(path string, e Embedder) (*VectorStore, error) {
+	vs := NewVectorStore(e.Model())
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return vs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open vector store: %w", err)
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only, close best-effort
+	}()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	headerSeen := false
+	for scanner.Scan() {
+		var rec vectorRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue // skip malformed lines
+		}
+		if !headerSeen {
+			headerSeen = true
+			if rec.Version != vectorStoreVersion || rec.Model != vs.Model {
+				return vs, nil
+			}
+			continue
+		}
+		if rec.Path == "" || len(rec.Vector) == 0 {
+			continue
+		}
+		vs.Vectors[rec.Path] = rec.Vector
+		vs.Hashes[rec.Path] = rec.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read vector store: %w", err)
+	}
+	return vs, nil
+}
+
+// SaveVectorStore writes the store as JSONL: a header line naming the model,
+// then one line per document in sorted path order.
+func SaveVectorStore(vs *VectorStore, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("create vector store dir: %w", err)
+	}
+
+	f, err := createIndexOutput(path)
+	if err != nil {
+		return fmt.Errorf("create vector store file: %w", err)
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // best-effort close
+	}()
+
+	w := bufio.NewWriter(f)
+	if err := writeJSONLine(w, vectorHeader{Version: vectorStoreVersion, Model: vs.Model}); err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(vs.Vectors))
+	for p := range vs.Vectors {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	for _, p := range paths {
+		entry := vectorEntry{Path: p, Hash: vs.Hashes[p], Vector: vs.Vectors[p]}
+		if err := writeJSONLine(w, entry); err != nil {
+			return fmt.Errorf("write vector %q: %w", p, err)
+		}
+	}
+	return w.Flush()
+}
+
+// EmbedDocument stores the embedding of text under path. Documents whose
+// content hash is unchanged keep their existing vector.
+func EmbedDocument(vs *VectorStore, e Embedder, path, text string) {
+	sum := sha256.Sum256([]byte(text))
+	hash := hex.EncodeToString(sum[:])
+	if _, ok := vs.Vectors[path]; ok && vs.Hashes[path] == hash {
+		return
+	}
+	vs.Vectors[path] = e.Embed(text)
+	vs.Hashes[path] = hash
+}
+
+// EmbedFile reads path and stores the embedding of its content.
+func EmbedFile(vs *VectorStore, e Embedder, path string) error {
+	doc, err := LoadDocument(path)
+	if err != nil {
+		return err
+	}
+	EmbedDocument(vs, e, path, documentText(doc))
+	return nil
+}
+
+// RemoveVector drops the embedding stored for path.
+func RemoveVector(vs *VectorStore, path string) {
+	delete(vs.Vectors, path)
+	delete(vs.Hashes, path)
+}
+
+// VectorSearch returns up to limit documents by descending cosine similarity
+// to the query. Documents with non-positive similarity are omitted.
+func VectorSearch(vs *VectorStore, e Embedder, query string, limit int) []IndexResult {
+	q := e.Embed(query)
+	scores := make(map[string]float64)
+	for path, vec := range vs.Vectors {
+		if sim := cosine(q, vec); sim > 0 {
+			scores[path] = sim
+		}
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+	return rankResults(scores, limit)
+}
+
+// HybridRank blends lexical (BM25) and vector results into one ranking.
+// Lexical scores are divided by the best lexical score so both signals lie
+// in [0, 1]; lexicalWeight sets the BM25 share and the rest goes to vector
+// similarity. Documents found by only one signal score zero on the other.
+func HybridRank(lexical, semantic []IndexResult, lexicalWeight float64, limit int) []IndexResult {
+	lexicalWeight = min(max(lexicalWeight, 0), 1)
+
+	var best float64
+	for _, r := range lexical {
+		best = max(best, r.Score)
+	}
+
+	scores := make(map[string]float64, len(lexical)+len(semantic))
+	if best > 0 {
+		for _, r := range lexical {
+			scores[r.Path] += lexicalWeight * r.Score / best
+		}
+	}
+	for _, r := range semantic {
+		scores[r.Path] += (1 - lexicalWeight) * r.Score
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+	return rankResults(scores, limit)
+}
+
+// RankDocuments ranks an ad-hoc document set against query with hybrid
+// BM25 + vector scoring. Vectors already in vs are reused when the content
+// is unchanged; new embeddings are added to vs in memory.
+func RankDocuments(docs map[string]Document, query string, e Embedder, vs *VectorStore, lexicalWeight float64) []IndexResult {
+	idx := NewIndex()
+	for path, doc := range docs {
+		AddDocument(idx, path, doc)
+	}
+	lexical := Search(idx, query, 0)
+
+	q := e.Embed(query)
+	semantic := make([]IndexResult, 0, len(docs))
+	for path, doc := range docs {
+		EmbedDocument(vs, e, path, documentText(doc))
+		if sim := cosine(q, vs.Vectors[path]); sim > 0 {
+			semantic = append(semantic, IndexResult{Path: path, Score: sim})
+		}
+	}
+
+	return HybridRank(lexical, semantic, lexicalWeight, 0)
+}
+
+// documentText flattens a Document into the text that gets embedded.
+func documentText(doc Document) string {
+	parts := make([]string, 0, 3)
+	if doc.Title != "" {
+		parts = append(parts, doc.Title)
+	}
+	if len(doc.Tags) > 0 {
+		parts = append(parts, strings.Join(doc.Tags, " "))
+	}
+	parts = append(parts, doc.Body)
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestHashEmbedder_Deterministic(t *testing.T) {
+	e := NewHashEmbedder()
+	a, b := e.Embed("Token refresh in the auth middleware"), e.Embed("Token refresh in the auth middleware")
+	if !slices.Equal(a, b) {
+		t.Fatal("expected identical vectors for identical text")
+	}
+	if len(a) != e.Dim {
+		t.Fatalf("len = %d, want %d", len(a), e.Dim)
+	}
+	if sim := cosine(a, b); sim < 0.999 || sim > 1.001 {
+		t.Errorf("expected unit-length vectors, self-similarity = %v", sim)
+	}
+}
+
+func TestHashEmbedder_RelatedWordingScoresHigher(t *testing.T) {
+	e := NewHashEmbedder()
+	q := e.Embed("auth")
+	related := cosine(q, e.Embed("authentication tokens expire after an hour"))
+	unrelated := cosine(q, e.Embed("database migration rollback steps"))
+	if related <= unrelated {
+		t.Errorf("expected related text to score higher: %v <= %v", related, unrelated)
+	}
+}
+
+func TestVectorStore_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "doc.md")
+	writeFile(t, doc, "---\ntitle: Retry\n---\nexponential backoff")
+
+	e := NewHashEmbedder()
+	vs := NewVectorStore(e.Model())
+	if err := EmbedFile(vs, e, doc); err != nil {
+		t.Fatalf("EmbedFile: %v", err)
+	}
+
+	path := filepath.Join(dir, "ao", DefaultVectorFile)
+	if err := SaveVectorStore(vs, path); err != nil {
+		t.Fatalf("SaveVectorStore: %v", err)
+	}
+	loaded, err := LoadVectorStore(path, e)
+	if err != nil {
+		t.Fatalf("LoadVectorStore: %v", err)
+	}
+	if !slices.Equal(loaded.Vectors[doc], vs.Vectors[doc]) || loaded.Hashes[doc] != vs.Hashes[doc] {
+		t.Error("vector store did not round-trip")
+	}
+
+	other := &HashEmbedder{Dim: 64, MinGram: 3, MaxGram: 4}
+	reset, err := LoadVectorStore(path, other)
+	if err != nil {
+		t.Fatalf("LoadVectorStore: %v", err)
+	}
+	if len(reset.Vectors) != 0 {
+		t.Error("expected vectors from a different model to be discarded")
+	}
+}
+
+func TestHybridRank_BlendsSignals(t *testing.T) {
+	lexical := []IndexResult{{Path: "/a", Score: 8}, {Path: "/b", Score: 4}}
+	semantic := []IndexResult{{Path: "/b", Score: 0.9}, {Path: "/c", Score: 0.8}}
+
+	results := HybridRank(lexical, semantic, 0.5, 0)
+	got := make([]string, len(results))
+	for i, r := range results {
+		got[i] = r.Path
+	}
+	// /a: 0.5*1 = 0.5, /b: 0.5*0.5+0.5*0.9 = 0.7, /c: 0.5*0.8 = 0.4
+	if want := []string{"/b", "/a", "/c"}; !slices.Equal(got, want) {
+		t.Errorf("ranking = %v, want %v", got, want)
+	}
+}
+
+func TestRankDocuments_FindsDocsWithoutKeywordOverlap(t *testing.T) {
+	docs := map[string]Document{
+		"auth":  {Title: "Authentication", Body: "rotate authentication tokens before expiry"},
+		"other": {Title: "Migrations", Body: "run database migrations in a transaction"},
+	}
+	e := NewHashEmbedder()
+	results := RankDocuments(docs, "auth", e, NewVectorStore(e.Model()), DefaultLexicalWeight)
+	if len(results) == 0 || results[0].Path != "auth" {
+		t.Fatalf("expected the authentication doc first, got %v", results)
+	}
+}
@@ -0,0 +1,304 @@
+package taxonomy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+// TaxonomyFile is the per-repo taxonomy path, relative to the repo root.
+const TaxonomyFile = ".agents/taxonomy.yaml"
+
+// defaultBaseScore is the base score of knowledge types the taxonomy does
+// not define.
+const defaultBaseScore = 0.5
+
+// boundaryEpsilon absorbs float noise when comparing tier boundaries.
+const boundaryEpsilon = 1e-9
+
+// validTypeName matches knowledge type names a taxonomy may add.
+var validTypeName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Taxonomy is an effective classification and scoring configuration: the
+// built-in defaults with any repo overrides applied.
+type Taxonomy struct {
+	// Source is the file the overrides came from, or "" for the defaults.
+	Source string `json:"source,omitempty"`
+
+	KnowledgeTypes map[types.KnowledgeType]KnowledgeTypeInfo `json:"knowledge_types"`
+	Tiers          map[types.Tier]TierConfig                 `json:"tiers"`
+	Weights        RubricWeights                             `json:"weights"`
+}
+
+// Default returns a copy of the built-in taxonomy.
+func Default() *Taxonomy {
+	return &Taxonomy{
+		KnowledgeTypes: maps.Clone(KnowledgeTypes),
+		Tiers:          maps.Clone(DefaultTierConfigs),
+		Weights:        DefaultRubricWeights,
+	}
+}
+
+// taxonomyFile is the on-disk form of .agents/taxonomy.yaml. Every field is
+// optional and overrides the built-in value it names.
+//
+//	knowledge_types:
+//	  decision: {base_score: 0.95}
+//	  gotcha: {base_score: 0.4, description: "Surprising behavior"}
+//	tiers:
+//	  gold: {min_score: 0.8}
+//	  bronze: {human_gate: false}
+//	weights:
+//	  specificity: 0.25
+//	  novelty: 0.25
+type taxonomyFile struct {
+	KnowledgeTypes map[types.KnowledgeType]knowledgeTypeOverride `yaml:"knowledge_types"`
+	Tiers          map[types.Tier]tierOverride                   `yaml:"tiers"`
+	Weights        weightsOverride                               `yaml:"weights"`
+}
+
+type knowledgeTypeOverride struct {
+	Description *string  `yaml:"description"`
+	BaseScore   *float64 `yaml:"base_score"`
+}
+
+// tierOverride sets tier thresholds. A tier whose max_score is not given
+// ends where the tier above it starts, so moving one min_score keeps the
+// tiers contiguous.
+type tierOverride struct {
+	MinScore   *float64 `yaml:"min_score"`
+	MaxScore   *float64 `yaml:"max_score"`
+	Confidence *float64 `yaml:"confidence"`
+	HumanGate  *bool    `yaml:"human_gate"`
+	SampleRate *float64 `yaml:"sample_rate"`
+}
+
+type weightsOverride struct {
+	Specificity   *float64 `yaml:"specificity"`
+	Actionability *float64 `yaml:"actionability"`
+	Novelty       *float64 `yaml:"novelty"`
+	Context       *float64 `yaml:"context"`
+	Confidence    *float64 `yaml:"confidence"`
+}
+
+// Load returns the taxonomy of the repo at baseDir: the defaults overridden
+// by .agents/taxonomy.yaml. A missing file yields the defaults.
+func Load(baseDir string) (*Taxonomy, error) {
+	path := filepath.Join(baseDir, TaxonomyFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read taxonomy: %w", err)
+	}
+	t, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	t.Source = path
+	return t, nil
+}
+
+// Parse applies taxonomy YAML to the defaults and validates the result.
+// Unknown keys are errors, so a misspelled threshold does not silently
+// fall back to its default.
+func Parse(data []byte) (*Taxonomy, error) {
+	var file taxonomyFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse taxonomy: %w", err)
+	}
+
+	t := Default()
+	if err := t.applyKnowledgeTypes(file.KnowledgeTypes); err != nil {
+		return nil, err
+	}
+	if err := t.applyTiers(file.Tiers); err != nil {
+		return nil, err
+	}
+	t.applyWeights(file.Weights)
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Taxonomy) applyKnowledgeTypes(overrides map[types.KnowledgeType]knowledgeTypeOverride) error {
+	for kt, o := range overrides {
+		info, known := t.KnowledgeTypes[kt]
+		if !known {
+			if !validTypeName.MatchString(string(kt)) {
+				return fmt.Errorf("knowledge type %q: name must be lowercase letters, digits and hyphens", kt)
+			}
+			if o.BaseScore == nil {
+				return fmt.Errorf("knowledge type %q: base_score is required for a new type", kt)
+			}
+			info = KnowledgeTypeInfo{Type: kt}
+		}
+		if o.Description != nil {
+			info.Description = *o.Description
+		}
+		if o.BaseScore != nil {
+			info.BaseScore = *o.BaseScore
+		}
+		t.KnowledgeTypes[kt] = info
+	}
+	return nil
+}
+
+func (t *Taxonomy) applyTiers(overrides map[types.Tier]tierOverride) error {
+	explicitMax := make(map[types.Tier]bool)
+	for tier, o := range overrides {
+		cfg, ok := t.Tiers[tier]
+		if !ok {
+			return fmt.Errorf("tiers: unknown tier %q", tier)
+		}
+		setFloat(&cfg.MinScore, o.MinScore)
+		setFloat(&cfg.Confidence, o.Confidence)
+		setFloat(&cfg.HumanGateSampleRate, o.SampleRate)
+		if o.HumanGate != nil {
+			cfg.HumanGateRequired = *o.HumanGate
+		}
+		if o.MaxScore != nil {
+			cfg.MaxScore = *o.MaxScore
+			explicitMax[tier] = true
+		}
+		t.Tiers[tier] = cfg
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+	// Each tier below gold ends where the tier above starts unless told otherwise.
+	for i := 1; i < len(TierOrder); i++ {
+		tier := TierOrder[i]
+		if explicitMax[tier] {
+			continue
+		}
+		cfg := t.Tiers[tier]
+		cfg.MaxScore = t.Tiers[TierOrder[i-1]].MinScore
+		t.Tiers[tier] = cfg
+	}
+	return nil
+}
+
+func (t *Taxonomy) applyWeights(o weightsOverride) {
+	setFloat(&t.Weights.Specificity, o.Specificity)
+	setFloat(&t.Weights.Actionability, o.Actionability)
+	setFloat(&t.Weights.Novelty, o.Novelty)
+	setFloat(&t.Weights.Context, o.Context)
+	setFloat(&t.Weights.Confidence, o.Confidence)
+}
+
+func setFloat(dst *float64, v *float64) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+// Validate checks base scores, rubric weights and tier boundaries.
+func (t *Taxonomy) Validate() error {
+	for kt, info := range t.KnowledgeTypes {
+		if !inUnitRange(info.BaseScore) {
+			return fmt.Errorf("knowledge type %q: base_score %.2f outside 0-1", kt, info.BaseScore)
+		}
+	}
+	w := t.Weights
+	for name, v := range map[string]float64{
+		"specificity": w.Specificity, "actionability": w.Actionability,
+		"novelty": w.Novelty, "context": w.Context, "confidence": w.Confidence,
+	} {
+		if !inUnitRange(v) {
+			return fmt.Errorf("weights: %s %.2f outside 0-1", name, v)
+		}
+	}
+	if !w.ValidateWeights() {
+		sum := w.Specificity + w.Actionability + w.Novelty + w.Context + w.Confidence
+		return fmt.Errorf("weights: sum to %.2f, must sum to 1.0", sum)
+	}
+	return ValidateTiers(t.Tiers)
+}
+
+// ValidateTiers checks that every tier is configured with sane values and
+// that the tiers cover 0 to 1 without gaps or overlaps: discard starts at 0,
+// each tier ends where the one above starts, and gold reaches past 1.0.
+func ValidateTiers(configs map[types.Tier]TierConfig) error {
+	for _, tier := range TierOrder {
+		cfg, ok := configs[tier]
+		if !ok {
+			return fmt.Errorf("tiers: %s is not configured", tier)
+		}
+		if cfg.MinScore >= cfg.MaxScore {
+			return fmt.Errorf("tiers: %s min_score %.2f must be below max_score %.2f", tier, cfg.MinScore, cfg.MaxScore)
+		}
+		if !inUnitRange(cfg.Confidence) {
+			return fmt.Errorf("tiers: %s confidence %.2f outside 0-1", tier, cfg.Confidence)
+		}
+		if !inUnitRange(cfg.HumanGateSampleRate) {
+			return fmt.Errorf("tiers: %s sample_rate %.2f outside 0-1", tier, cfg.HumanGateSampleRate)
+		}
+	}
+	if top := configs[TierOrder[0]]; top.MaxScore <= 1.0 {
+		return fmt.Errorf("tiers: %s max_score %.2f must exceed 1.0 so a perfect score has a tier", top.Tier, top.MaxScore)
+	}
+	if bottom := configs[TierOrder[len(TierOrder)-1]]; bottom.MinScore > boundaryEpsilon {
+		return fmt.Errorf("tiers: %s min_score %.2f must be 0", bottom.Tier, bottom.MinScore)
+	}
+	for i := 1; i < len(TierOrder); i++ {
+		upper, lower := configs[TierOrder[i-1]], configs[TierOrder[i]]
+		if math.Abs(lower.MaxScore-upper.MinScore) > boundaryEpsilon {
+			return fmt.Errorf("tiers: %s max_score %.2f must equal %s min_score %.2f",
+				TierOrder[i], lower.MaxScore, TierOrder[i-1], upper.MinScore)
+		}
+	}
+	return nil
+}
+
+func inUnitRange(v float64) bool {
+	return v >= 0 && v <= 1
+}
+
+// BaseScore returns the base score for a knowledge type.
+func (t *Taxonomy) BaseScore(kt types.KnowledgeType) float64 {
+	if info, ok := t.KnowledgeTypes[kt]; ok {
+		return info.BaseScore
+	}
+	return defaultBaseScore
+}
+
+// AssignTier returns the tier for a composite score.
+func (t *Taxonomy) AssignTier(score float64) types.Tier {
+	return AssignTier(score, t.Tiers)
+}
+
+// Confidence returns the confidence stored with candidates of tier.
+func (t *Taxonomy) Confidence(tier types.Tier) float64 {
+	return GetConfidence(tier, t.Tiers)
+}
+
+// RequiresHumanGate reports whether candidates of tier need human review.
+func (t *Taxonomy) RequiresHumanGate(tier types.Tier) bool {
+	return RequiresHumanGate(tier, t.Tiers)
+}
+
+// CustomType returns the repo-defined knowledge type named name, if the
+// taxonomy adds one by that name.
+func (t *Taxonomy) CustomType(name string) (types.KnowledgeType, bool) {
+	kt := types.KnowledgeType(name)
+	if _, builtin := KnowledgeTypes[kt]; builtin {
+		return "", false
+	}
+	_, ok := t.KnowledgeTypes[kt]
+	return kt, ok
+}
@@ -0,0 +1,131 @@
+package taxonomy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boshu2/agentops/cli/internal/types"
+)
+
+func TestLoad_MissingFileIsDefault(t *testing.T) {
+	tax, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if tax.Source != "" {
+		t.Errorf("Source = %q, want empty for defaults", tax.Source)
+	}
+	if tax.Weights != DefaultRubricWeights {
+		t.Errorf("Weights = %+v, want defaults", tax.Weights)
+	}
+	if got := tax.AssignTier(0.85); got != types.TierGold {
+		t.Errorf("AssignTier(0.85) = %s, want gold", got)
+	}
+}
+
+func TestDefault_DoesNotShareMaps(t *testing.T) {
+	tax := Default()
+	tax.KnowledgeTypes[types.KnowledgeTypeDecision] = KnowledgeTypeInfo{BaseScore: 0.1}
+	if KnowledgeTypes[types.KnowledgeTypeDecision].BaseScore != 0.8 {
+		t.Fatal("Default() must copy the built-in knowledge types")
+	}
+}
+
+func TestValidateTiers_Defaults(t *testing.T) {
+	if err := ValidateTiers(DefaultTierConfigs); err != nil {
+		t.Errorf("default tiers invalid: %v", err)
+	}
+}
+
+func TestLoad_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, TaxonomyFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `knowledge_types:
+  decision: {base_score: 0.95}
+  gotcha: {base_score: 0.3, description: "Surprising behavior"}
+tiers:
+  gold: {min_score: 0.8, confidence: 0.9}
+  bronze: {human_gate: false}
+weights:
+  specificity: 0.4
+  actionability: 0.15
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tax, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if tax.Source != path {
+		t.Errorf("Source = %q, want %q", tax.Source, path)
+	}
+	if got := tax.BaseScore(types.KnowledgeTypeDecision); got != 0.95 {
+		t.Errorf("BaseScore(decision) = %v, want 0.95", got)
+	}
+	if got, ok := tax.CustomType("gotcha"); !ok || tax.BaseScore(got) != 0.3 {
+		t.Errorf("CustomType(gotcha) = %q, %v", got, ok)
+	}
+	if _, ok := tax.CustomType("decision"); ok {
+		t.Error("built-in types are not custom")
+	}
+	if got := tax.AssignTier(0.82); got != types.TierGold {
+		t.Errorf("AssignTier(0.82) = %s, want gold", got)
+	}
+	if silver := tax.Tiers[types.TierSilver]; silver.MaxScore != 0.8 {
+		t.Errorf("silver max_score = %v, want 0.8 (follows gold min_score)", silver.MaxScore)
+	}
+	if tax.RequiresHumanGate(types.TierBronze) {
+		t.Error("bronze human gate should be disabled")
+	}
+	if got := tax.Confidence(types.TierGold); got != 0.9 {
+		t.Errorf("Confidence(gold) = %v, want 0.9", got)
+	}
+	if tax.Weights.Specificity != 0.4 || tax.Weights.Novelty != DefaultRubricWeights.Novelty {
+		t.Errorf("Weights = %+v", tax.Weights)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"weights do not sum", "weights: {specificity: 0.9}", "must sum to 1.0"},
+		{"negative weight", "weights: {specificity: 0.6, actionability: -0.05}", "outside 0-1"},
+		{"base score range", "knowledge_types: {decision: {base_score: 1.5}}", "outside 0-1"},
+		{"new type without score", "knowledge_types: {gotcha: {description: x}}", "base_score is required"},
+		{"bad type name", "knowledge_types: {\"Got Cha\": {base_score: 0.3}}", "lowercase"},
+		{"unknown tier", "tiers: {platinum: {min_score: 0.9}}", "unknown tier"},
+		{"gap between tiers", "tiers: {silver: {max_score: 0.8}}", "must equal gold min_score"},
+		{"inverted tier", "tiers: {silver: {min_score: 0.9}}", "must be below max_score"},
+		{"gold capped", "tiers: {gold: {max_score: 1.0}}", "must exceed 1.0"},
+		{"discard above zero", "tiers: {discard: {min_score: 0.1}}", "must be 0"},
+		{"typo", "tiers: {gold: {min_scor: 0.8}}", "parse taxonomy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	tax, err := Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse(nil): %v", err)
+	}
+	if len(tax.KnowledgeTypes) != len(KnowledgeTypes) {
+		t.Errorf("empty taxonomy should equal defaults")
+	}
+}
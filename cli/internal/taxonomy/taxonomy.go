@@ -25,6 +25,10 @@
 //   - Novelty (20%): Uniqueness vs. common knowledge
 //   - Context (15%): Quality of surrounding context
 //   - Confidence (10%): Assertion strength
+//
+// # Repository Overrides
+//
+// A repo can override all of the above in .agents/taxonomy.yaml; see Load.
 package taxonomy
 
 import "github.com/boshu2/agentops/cli/internal/types"
@@ -32,13 +36,13 @@ import "github.com/boshu2/agentops/cli/internal/types"
 // KnowledgeTypeInfo describes a knowledge type and its base scoring.
 type KnowledgeTypeInfo struct {
 	// Type is the knowledge type constant.
-	Type types.KnowledgeType
+	Type types.KnowledgeType `json:"type"`
 
 	// Description explains what this type represents.
-	Description string
+	Description string `json:"description"`
 
 	// BaseScore is the starting score for this type (0.0-1.0).
-	BaseScore float64
+	BaseScore float64 `json:"base_score"`
 }
 
 // KnowledgeTypes maps type identifiers to their info.
@@ -73,22 +77,22 @@ var KnowledgeTypes = map[types.KnowledgeType]KnowledgeTypeInfo{
 // TierConfig defines the configuration for a quality tier.
 type TierConfig struct {
 	// Tier is the tier constant.
-	Tier types.Tier
+	Tier types.Tier `json:"tier"`
 
 	// MinScore is the minimum score to qualify for this tier.
-	MinScore float64
+	MinScore float64 `json:"min_score"`
 
 	// MaxScore is the maximum score for this tier (exclusive upper bound for next tier).
-	MaxScore float64
+	MaxScore float64 `json:"max_score"`
 
 	// Confidence is the confidence value to use when storing.
-	Confidence float64
+	Confidence float64 `json:"confidence"`
 
 	// HumanGateRequired indicates if human review is required.
-	HumanGateRequired bool
+	HumanGateRequired bool `json:"human_gate_required"`
 
 	// HumanGateSampleRate is the percentage of entries to sample for review (0.0-1.0).
-	HumanGateSampleRate float64
+	HumanGateSampleRate float64 `json:"human_gate_sample_rate"`
 }
 
 // DefaultTierConfigs provides the default tier thresholds.
@@ -133,23 +137,23 @@ var DefaultTierConfigs = map[types.Tier]TierConfig{
 type RubricWeights struct {
 	// Specificity measures named entities, concrete values, code snippets.
 	// Weight: 0.30 (30%)
-	Specificity float64
+	Specificity float64 `json:"specificity"`
 
 	// Actionability measures imperative verbs, clear steps, before/after.
 	// Weight: 0.25 (25%)
-	Actionability float64
+	Actionability float64 `json:"actionability"`
 
 	// Novelty measures uniqueness vs. common knowledge.
 	// Weight: 0.20 (20%)
-	Novelty float64
+	Novelty float64 `json:"novelty"`
 
 	// Context measures quality of surrounding context.
 	// Weight: 0.15 (15%)
-	Context float64
+	Context float64 `json:"context"`
 
 	// Confidence measures assertion strength.
 	// Weight: 0.10 (10%)
-	Confidence float64
+	Confidence float64 `json:"confidence"`
 }
 
 // DefaultRubricWeights provides the standard scoring weights.
@@ -8,6 +8,10 @@ var (
 	// ErrSchemaVersionInvalid is returned when schema_version is less than 1.
 	ErrSchemaVersionInvalid = errors.New("schema_version must be >= 1")
 
+	// ErrSchemaVersionUnsupported is returned when schema_version is newer
+	// than MemRLPolicySchemaVersion.
+	ErrSchemaVersionUnsupported = errors.New("unsupported schema_version")
+
 	// ErrTieBreakRulesEmpty is returned when tie_break_rules is empty.
 	ErrTieBreakRulesEmpty = errors.New("tie_break_rules must not be empty")
 
@@ -22,4 +26,8 @@ var (
 
 	// ErrTriggerIDEmpty is returned when a rollback trigger id is empty.
 	ErrTriggerIDEmpty = errors.New("rollback trigger id must not be empty")
+
+	// ErrDuplicateID is returned when two rules or two rollback triggers
+	// share an id.
+	ErrDuplicateID = errors.New("duplicate id")
 )
@@ -2,6 +2,9 @@ package types
 
 import (
 	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"slices"
@@ -157,35 +160,38 @@ func isValidAttemptBucket(bucket MemRLAttemptBucket) bool {
 
 // MemRLPolicyRule maps mode x failure class x attempt bucket to an action.
 type MemRLPolicyRule struct {
-	RuleID        string             `json:"rule_id"`
-	Mode          MemRLMode          `json:"memrl_mode"`
-	FailureClass  MemRLFailureClass  `json:"failure_class"`
-	AttemptBucket MemRLAttemptBucket `json:"attempt_bucket"`
-	Action        MemRLAction        `json:"action"`
-	Priority      int                `json:"priority"`
+	RuleID        string             `json:"rule_id" yaml:"rule_id"`
+	Mode          MemRLMode          `json:"memrl_mode" yaml:"memrl_mode"`
+	FailureClass  MemRLFailureClass  `json:"failure_class" yaml:"failure_class"`
+	AttemptBucket MemRLAttemptBucket `json:"attempt_bucket" yaml:"attempt_bucket"`
+	Action        MemRLAction        `json:"action" yaml:"action"`
+	Priority      int                `json:"priority" yaml:"priority"`
 }
 
 // MemRLRollbackTrigger defines deterministic rollback guardrails.
 type MemRLRollbackTrigger struct {
-	TriggerID           string `json:"trigger_id"`
-	Metric              string `json:"metric"`
-	MetricSourceCommand string `json:"metric_source_command"`
-	LookbackWindow      string `json:"lookback_window"`
-	MinSampleSize       int    `json:"min_sample_size"`
-	Threshold           string `json:"threshold"`
-	OperatorAction      string `json:"operator_action"`
-	VerificationCommand string `json:"verification_command"`
+	TriggerID           string `json:"trigger_id" yaml:"trigger_id"`
+	Metric              string `json:"metric" yaml:"metric"`
+	MetricSourceCommand string `json:"metric_source_command" yaml:"metric_source_command"`
+	LookbackWindow      string `json:"lookback_window" yaml:"lookback_window"`
+	MinSampleSize       int    `json:"min_sample_size" yaml:"min_sample_size"`
+	Threshold           string `json:"threshold" yaml:"threshold"`
+	OperatorAction      string `json:"operator_action" yaml:"operator_action"`
+	VerificationCommand string `json:"verification_command" yaml:"verification_command"`
 }
 
+// MemRLPolicySchemaVersion is the newest contract schema this build reads.
+const MemRLPolicySchemaVersion = 1
+
 // MemRLPolicyContract is the canonical policy package exported for consumers.
 type MemRLPolicyContract struct {
-	SchemaVersion             int                    `json:"schema_version"`
-	DefaultMode               MemRLMode              `json:"default_mode"`
-	UnknownFailureClassAction MemRLAction            `json:"unknown_failure_class_action"`
-	MissingMetadataAction     MemRLAction            `json:"missing_metadata_action"`
-	TieBreakRules             []string               `json:"tie_break_rules"`
-	Rules                     []MemRLPolicyRule      `json:"rules"`
-	RollbackMatrix            []MemRLRollbackTrigger `json:"rollback_matrix"`
+	SchemaVersion             int                    `json:"schema_version" yaml:"schema_version"`
+	DefaultMode               MemRLMode              `json:"default_mode" yaml:"default_mode"`
+	UnknownFailureClassAction MemRLAction            `json:"unknown_failure_class_action" yaml:"unknown_failure_class_action"`
+	MissingMetadataAction     MemRLAction            `json:"missing_metadata_action" yaml:"missing_metadata_action"`
+	TieBreakRules             []string               `json:"tie_break_rules" yaml:"tie_break_rules"`
+	Rules                     []MemRLPolicyRule      `json:"rules" yaml:"rules"`
+	RollbackMatrix            []MemRLRollbackTrigger `json:"rollback_matrix" yaml:"rollback_matrix"`
 }
 
 // MemRLPolicyInput is the evaluator input contract.
@@ -207,6 +213,7 @@ type MemRLPolicyDecision struct {
 	RuleID          string             `json:"rule_id"`
 	Reason          string             `json:"reason"`
 	MetadataPresent bool               `json:"metadata_present"`
+	ContractHash    string             `json:"contract_hash,omitempty"`
 }
 
 // DefaultMemRLPolicyContract returns the canonical deterministic policy package.
@@ -309,6 +316,18 @@ func defaultMemRLRollbackMatrix() []MemRLRollbackTrigger {
 	}
 }
 
+// MemRLPolicyContractHash returns a short content hash identifying a
+// contract. Decisions carry it so they can be audited against the exact
+// policy that produced them.
+func MemRLPolicyContractHash(contract MemRLPolicyContract) string {
+	data, err := json.Marshal(contract)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
 // EvaluateDefaultMemRLPolicy evaluates against the canonical v1 policy package.
 func EvaluateDefaultMemRLPolicy(input MemRLPolicyInput) MemRLPolicyDecision {
 	return EvaluateMemRLPolicy(DefaultMemRLPolicyContract(), input)
@@ -323,6 +342,7 @@ func EvaluateMemRLPolicy(contract MemRLPolicyContract, input MemRLPolicyInput) M
 		FailureClass:    input.FailureClass,
 		AttemptBucket:   resolved.bucket,
 		MetadataPresent: resolved.metadataPresent,
+		ContractHash:    MemRLPolicyContractHash(contract),
 	}
 
 	if !resolved.metadataPresent || input.FailureClass == "" || resolved.bucket == "" {
@@ -462,6 +482,9 @@ func validateContractFields(contract MemRLPolicyContract) error {
 	if contract.SchemaVersion < 1 {
 		return ErrSchemaVersionInvalid
 	}
+	if contract.SchemaVersion > MemRLPolicySchemaVersion {
+		return fmt.Errorf("%w: %d (this build reads up to %d)", ErrSchemaVersionUnsupported, contract.SchemaVersion, MemRLPolicySchemaVersion)
+	}
 	if err := validateContractEnums(contract); err != nil {
 		return err
 	}
@@ -479,20 +502,30 @@ func validateContractFields(contract MemRLPolicyContract) error {
 
 // validateContractRules validates all policy rules in the contract.
 func validateContractRules(rules []MemRLPolicyRule) error {
+	seen := make(map[string]bool, len(rules))
 	for _, rule := range rules {
 		if err := validatePolicyRule(rule); err != nil {
 			return err
 		}
+		if seen[rule.RuleID] {
+			return fmt.Errorf("%w: rule %s", ErrDuplicateID, rule.RuleID)
+		}
+		seen[rule.RuleID] = true
 	}
 	return nil
 }
 
 // validateContractRollbacks validates all rollback triggers in the contract.
 func validateContractRollbacks(triggers []MemRLRollbackTrigger) error {
+	seen := make(map[string]bool, len(triggers))
 	for _, trigger := range triggers {
 		if err := validateRollbackTrigger(trigger); err != nil {
 			return err
 		}
+		if seen[trigger.TriggerID] {
+			return fmt.Errorf("%w: rollback trigger %s", ErrDuplicateID, trigger.TriggerID)
+		}
+		seen[trigger.TriggerID] = true
 	}
 	return nil
 }
@@ -219,6 +219,30 @@ func TestValidateMemRLPolicyContract_AllErrors(t *testing.T) {
 		}
 	})
 
+	t.Run("schema_version_unsupported", func(t *testing.T) {
+		c := valid
+		c.SchemaVersion = MemRLPolicySchemaVersion + 1
+		if err := ValidateMemRLPolicyContract(c); !errors.Is(err, ErrSchemaVersionUnsupported) {
+			t.Errorf("expected ErrSchemaVersionUnsupported, got %v", err)
+		}
+	})
+
+	t.Run("duplicate_rule_id", func(t *testing.T) {
+		c := valid
+		c.Rules = append(append([]MemRLPolicyRule{}, valid.Rules...), valid.Rules[0])
+		if err := ValidateMemRLPolicyContract(c); !errors.Is(err, ErrDuplicateID) {
+			t.Errorf("expected ErrDuplicateID, got %v", err)
+		}
+	})
+
+	t.Run("duplicate_trigger_id", func(t *testing.T) {
+		c := valid
+		c.RollbackMatrix = append(append([]MemRLRollbackTrigger{}, valid.RollbackMatrix...), valid.RollbackMatrix[0])
+		if err := ValidateMemRLPolicyContract(c); !errors.Is(err, ErrDuplicateID) {
+			t.Errorf("expected ErrDuplicateID, got %v", err)
+		}
+	})
+
 	t.Run("invalid_default_mode", func(t *testing.T) {
 		c := valid
 		c.DefaultMode = "invalid"
@@ -434,6 +458,24 @@ func TestValidateMemRLPolicyContract_AllErrors(t *testing.T) {
 	})
 }
 
+func TestMemRLPolicyContractHash(t *testing.T) {
+	a, b := DefaultMemRLPolicyContract(), DefaultMemRLPolicyContract()
+	if MemRLPolicyContractHash(a) != MemRLPolicyContractHash(b) {
+		t.Fatal("hash should be stable for identical contracts")
+	}
+	b.Rules[0].Priority++
+	if MemRLPolicyContractHash(a) == MemRLPolicyContractHash(b) {
+		t.Error("hash should change when a rule changes")
+	}
+
+	decision := EvaluateMemRLPolicy(b, MemRLPolicyInput{
+		Mode: MemRLModeEnforce, FailureClass: MemRLFailureClassVibeFail, Attempt: 1, MaxAttempts: 3,
+	})
+	if decision.ContractHash != MemRLPolicyContractHash(b) {
+		t.Errorf("decision contract hash = %q, want %q", decision.ContractHash, MemRLPolicyContractHash(b))
+	}
+}
+
 func TestBucketMemRLAttempt_AllPaths(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -287,6 +287,70 @@ type HumanReview struct {
 
 	// ReviewedAt is when the review occurred.
 	ReviewedAt time.Time `json:"reviewed_at,omitempty"`
+
+	// Reviews holds every individual review, including dissent. The fields
+	// above summarize the decision once quorum is reached; until then
+	// Reviewed stays false.
+	Reviews []Review `json:"reviews,omitempty"`
+}
+
+// Review is one reviewer's verdict on a candidate.
+type Review struct {
+	Reviewer   string    `json:"reviewer"`
+	Approved   bool      `json:"approved"`
+	Notes      string    `json:"notes,omitempty"`
+	ReviewedAt time.Time `json:"reviewed_at"`
+}
+
+// Approvals counts approving reviews. A review recorded before Reviews
+// existed counts as one.
+func (h *HumanReview) Approvals() int {
+	if h == nil {
+		return 0
+	}
+	if len(h.Reviews) == 0 {
+		if h.Reviewed && h.Approved {
+			return 1
+		}
+		return 0
+	}
+	n := 0
+	for _, r := range h.Reviews {
+		if r.Approved {
+			n++
+		}
+	}
+	return n
+}
+
+// Dissents counts rejecting reviews.
+func (h *HumanReview) Dissents() int {
+	if h == nil {
+		return 0
+	}
+	n := 0
+	for _, r := range h.Reviews {
+		if !r.Approved {
+			n++
+		}
+	}
+	return n
+}
+
+// HasReviewed reports whether reviewer already left a review.
+func (h *HumanReview) HasReviewed(reviewer string) bool {
+	if h == nil {
+		return false
+	}
+	if len(h.Reviews) == 0 {
+		return h.Reviewed && h.Reviewer == reviewer
+	}
+	for _, r := range h.Reviews {
+		if r.Reviewer == reviewer {
+			return true
+		}
+	}
+	return false
 }
 
 // PoolEntry represents an entry in a quality pool.
@@ -608,6 +672,11 @@ type CitationEvent struct {
 	// Query is the search query that surfaced this artifact (if applicable).
 	Query string `json:"query,omitempty"`
 
+	// Selection records how ao inject --explore chose the artifact:
+	// "exploit" (ranked into the packet) or "explore" (picked by the bandit).
+	// Empty when exploration was off.
+	Selection string `json:"selection,omitempty"`
+
 	// --- MemRL Feedback Tracking (Phase 5) ---
 
 	// FeedbackGiven indicates whether feedback has been recorded for this citation.
@@ -740,6 +809,25 @@ type FlywheelMetrics struct {
 	// GoldenSignals captures the four derived health indicators
 	// that distinguish knowledge compounding from noise accumulation.
 	GoldenSignals *GoldenSignals `json:"golden_signals,omitempty"`
+
+	// UtilityMovers lists the learnings whose utility rose or fell the most
+	// during the period, from the utility history log.
+	UtilityMovers *UtilityMovers `json:"utility_movers,omitempty"`
+}
+
+// UtilityMovers holds the biggest utility gains and losses of a period.
+type UtilityMovers struct {
+	MostImproved []UtilityMover `json:"most_improved,omitempty"`
+	MostDegraded []UtilityMover `json:"most_degraded,omitempty"`
+}
+
+// UtilityMover is one learning's net utility change over a period.
+type UtilityMover struct {
+	LearningID string  `json:"learning_id"`
+	From       float64 `json:"from"`
+	To         float64 `json:"to"`
+	Delta      float64 `json:"delta"`
+	Updates    int     `json:"updates"`
 }
 
 // GoldenSignals captures the four derived health indicators